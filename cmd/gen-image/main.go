@@ -0,0 +1,84 @@
+// Command gen-image writes an image of the analyzed core library and,
+// optionally, of other namespaces. Restoring files from an image
+// avoids reading, macroexpanding and analyzing them at startup.
+//
+// The core image embedded in the stdlib package is regenerated with
+// go generate. To build an image of your own namespaces, run:
+//
+//	go run github.com/glojurelang/glojure/cmd/gen-image \
+//	    -dir src -ns my.app,my.app.util -o app.img
+//
+// and add it with runtime.AddImage before requiring them.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	// Add the Go standard library to the pkgmap.
+	_ "github.com/glojurelang/glojure/pkg/gen/gljimports"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+var (
+	outFlag = flag.String("o", "core.img", "output file")
+	nsFlag  = flag.String(
+		"ns",
+		"",
+		"comma separated list of namespaces to include",
+	)
+	dirFlag = flag.String(
+		"dir",
+		"",
+		"directory to add to the load path",
+	)
+	coreFlag = flag.Bool(
+		"core",
+		false,
+		"include the core library along with the namespaces given with -ns",
+	)
+)
+
+func main() {
+	flag.Parse()
+
+	if *dirFlag != "" {
+		runtime.AddLoadPath(os.DirFS(*dirFlag))
+	}
+
+	kvs := make([]interface{}, 0, 8)
+	for _, vr := range []*value.Var{value.VarCurrentNS, value.VarWarnOnReflection, value.VarUncheckedMath, value.VarDataReaders} {
+		kvs = append(kvs, vr, vr.Deref())
+	}
+	value.PushThreadBindings(value.NewMap(kvs...))
+	defer value.PopThreadBindings()
+
+	img := runtime.NewImage()
+	runtime.NewEnvironment(runtime.WithImageRecorder(img))
+	coreFiles := img.Files()
+
+	if *nsFlag != "" {
+		require := value.FindNamespace(value.SymbolCoreNamespace).FindInternedVar(value.NewSymbol("require"))
+		for _, ns := range strings.Split(*nsFlag, ",") {
+			require.Invoke(value.NewSymbol(ns))
+		}
+		if !*coreFlag {
+			img.Remove(coreFiles...)
+		}
+	}
+
+	f, err := os.Create(*outFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := img.WriteTo(f); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -163,6 +163,7 @@ var defaultPackages = []string{
 
 	"github.com/glojurelang/glojure/pkg/runtime",
 	"github.com/glojurelang/glojure/pkg/lang",
+	"github.com/glojurelang/glojure/pkg/repl",
 }
 
 var packagesFlag = flag.String(
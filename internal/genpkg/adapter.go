@@ -0,0 +1,216 @@
+package genpkg
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+const langPackage = "github.com/glojurelang/glojure/pkg/lang"
+
+// adapter describes a generated struct type implementing an exported
+// interface by forwarding each method to a func field.
+type adapter struct {
+	globalName string
+	aliasName  string
+	typeName   string
+	methods    []adapterMethod
+}
+
+type adapterMethod struct {
+	name    string
+	params  []string
+	results []string
+	// variadic is true if the last parameter is variadic; its type in
+	// params is the element type prefixed with "...".
+	variadic bool
+}
+
+// createAdapterBuilder emits adapter types for the eligible interface
+// types among the exports, and a RegisterAdapters function that
+// registers their constructors with lang.RegisterInterfaceAdapter.
+func createAdapterBuilder(builder *strings.Builder, packageNames []string, packageExports map[string][]export) {
+	aliases := make(map[string]string, len(packageNames))
+	for _, packageName := range packageNames {
+		aliases[packageName] = replaceSpecChars(packageName)
+	}
+
+	var adapters []adapter
+	for _, packageName := range packageNames {
+		for _, exportedObject := range packageExports[packageName] {
+			if a, ok := newAdapter(exportedObject, packageName, aliases); ok {
+				adapters = append(adapters, a)
+			}
+		}
+	}
+
+	langAlias := replaceSpecChars(langPackage)
+	builder.WriteString(fmt.Sprintf("\nfunc RegisterAdapters(_register func(reflect.Type, %s.AdapterFunc)) {\n", langAlias))
+	for _, a := range adapters {
+		builder.WriteString(fmt.Sprintf("\t_register(reflect.TypeOf((*%s)(nil)).Elem(), func(m %s.Methods) interface{} {\n", a.aliasName, langAlias))
+		builder.WriteString(fmt.Sprintf("\t\ta := &%s{}\n", a.typeName))
+		for _, m := range a.methods {
+			builder.WriteString(fmt.Sprintf("\t\tm.Bind(&a._%s, %q)\n", m.name, m.name))
+		}
+		builder.WriteString("\t\treturn a\n")
+		builder.WriteString("\t})\n")
+	}
+	builder.WriteString("}\n")
+
+	for _, a := range adapters {
+		writeAdapterType(builder, a)
+	}
+}
+
+func writeAdapterType(builder *strings.Builder, a adapter) {
+	builder.WriteString(fmt.Sprintf("\n// %s adapts functions to %s.\n", a.typeName, a.globalName))
+	builder.WriteString(fmt.Sprintf("type %s struct {\n", a.typeName))
+	for _, m := range a.methods {
+		builder.WriteString(fmt.Sprintf("\t_%s func(%s)%s\n", m.name, strings.Join(m.params, ", "), resultList(m.results)))
+	}
+	builder.WriteString("}\n")
+
+	for _, m := range a.methods {
+		var params, args []string
+		for i, p := range m.params {
+			params = append(params, fmt.Sprintf("p%d %s", i, p))
+			arg := fmt.Sprintf("p%d", i)
+			if m.variadic && i == len(m.params)-1 {
+				arg += "..."
+			}
+			args = append(args, arg)
+		}
+		call := fmt.Sprintf("a._%s(%s)", m.name, strings.Join(args, ", "))
+		if len(m.results) > 0 {
+			call = "return " + call
+		}
+		builder.WriteString(fmt.Sprintf("\nfunc (a *%s) %s(%s)%s {\n\t%s\n}\n",
+			a.typeName, m.name, strings.Join(params, ", "), resultList(m.results), call))
+	}
+}
+
+func resultList(results []string) string {
+	switch len(results) {
+	case 0:
+		return ""
+	case 1:
+		return " " + results[0]
+	default:
+		return " (" + strings.Join(results, ", ") + ")"
+	}
+}
+
+// newAdapter returns the adapter for an exported interface type. Only
+// non-generic interfaces with at least one method, all of them
+// exported, and whose signatures refer only to exported types of the
+// imported packages, are eligible.
+func newAdapter(exportedObject export, packageName string, aliases map[string]string) (adapter, bool) {
+	typeName, ok := exportedObject.obj.(*types.TypeName)
+	if !ok || typeName.IsAlias() {
+		return adapter{}, false
+	}
+	named, ok := typeName.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return adapter{}, false
+	}
+	iface, ok := named.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 || !iface.IsMethodSet() {
+		return adapter{}, false
+	}
+
+	qualifier := func(pkg *types.Package) string {
+		return aliases[pkg.Path()]
+	}
+
+	packageAlias := aliases[packageName]
+	a := adapter{
+		globalName: packageName + "." + exportedObject.name,
+		aliasName:  packageAlias + "." + exportedObject.name,
+		typeName:   "_" + packageAlias + "_" + exportedObject.name,
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		if !method.Exported() {
+			return adapter{}, false
+		}
+		sig := method.Type().(*types.Signature)
+		m := adapterMethod{
+			name:     method.Name(),
+			variadic: sig.Variadic(),
+		}
+		for j := 0; j < sig.Params().Len(); j++ {
+			typ := sig.Params().At(j).Type()
+			if !isRepresentable(typ, aliases) {
+				return adapter{}, false
+			}
+			if m.variadic && j == sig.Params().Len()-1 {
+				m.params = append(m.params, "..."+types.TypeString(typ.(*types.Slice).Elem(), qualifier))
+			} else {
+				m.params = append(m.params, types.TypeString(typ, qualifier))
+			}
+		}
+		for j := 0; j < sig.Results().Len(); j++ {
+			typ := sig.Results().At(j).Type()
+			if !isRepresentable(typ, aliases) {
+				return adapter{}, false
+			}
+			m.results = append(m.results, types.TypeString(typ, qualifier))
+		}
+		a.methods = append(a.methods, m)
+	}
+	return a, true
+}
+
+// isRepresentable reports whether typ can be written in the generated
+// file, which imports only the packages in aliases.
+func isRepresentable(typ types.Type, aliases map[string]string) bool {
+	switch typ := typ.(type) {
+	case *types.Basic:
+		return typ.Kind() != types.UnsafePointer && typ.Kind() != types.Invalid
+	case *types.Named:
+		obj := typ.Obj()
+		if obj.Pkg() == nil {
+			// predeclared, e.g. error
+			return true
+		}
+		if _, ok := aliases[obj.Pkg().Path()]; !ok || !obj.Exported() {
+			return false
+		}
+		return typ.TypeArgs().Len() == 0
+	case *types.Pointer:
+		return isRepresentable(typ.Elem(), aliases)
+	case *types.Slice:
+		return isRepresentable(typ.Elem(), aliases)
+	case *types.Array:
+		return isRepresentable(typ.Elem(), aliases)
+	case *types.Chan:
+		return isRepresentable(typ.Elem(), aliases)
+	case *types.Map:
+		return isRepresentable(typ.Key(), aliases) && isRepresentable(typ.Elem(), aliases)
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{typ.Params(), typ.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				if !isRepresentable(tuple.At(i).Type(), aliases) {
+					return false
+				}
+			}
+		}
+		return true
+	case *types.Struct:
+		for i := 0; i < typ.NumFields(); i++ {
+			if !typ.Field(i).Exported() || !isRepresentable(typ.Field(i).Type(), aliases) {
+				return false
+			}
+		}
+		return true
+	case *types.Interface:
+		for i := 0; i < typ.NumMethods(); i++ {
+			if !typ.Method(i).Exported() || !isRepresentable(typ.Method(i).Type(), aliases) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -95,6 +95,7 @@ func isEligibleForExport(object types.Object, packageName string) bool {
 func printGeneratedCode(w io.Writer, packageNames []string, packageExports map[string][]export) {
 	builder := createHeaderBuilder(packageNames)
 	createFunctionBuilder(builder, packageNames, packageExports)
+	createAdapterBuilder(builder, packageNames, packageExports)
 
 	formattedCode, err := format.Source([]byte(builder.String()))
 	if err != nil {
@@ -120,6 +121,7 @@ func createHeaderBuilder(packageNames []string) *strings.Builder {
 
 	reflectImported := false
 	pkgMapImported := false
+	langImported := false
 
 	for _, packageName := range packageNames {
 		if packageName == "reflect" {
@@ -128,6 +130,9 @@ func createHeaderBuilder(packageNames []string) *strings.Builder {
 		if packageName == "github.com/glojurelang/glojure/pkg/pkgmap" {
 			pkgMapImported = true
 		}
+		if packageName == langPackage {
+			langImported = true
+		}
 		aliasName := strings.NewReplacer(".", "_", "/", "_", "-", "_").Replace(packageName)
 		builder.WriteString(fmt.Sprintf("\t%s \"%s\"\n", aliasName, packageName))
 	}
@@ -137,11 +142,15 @@ func createHeaderBuilder(packageNames []string) *strings.Builder {
 	if !pkgMapImported {
 		builder.WriteString("\t\"github.com/glojurelang/glojure/pkg/pkgmap\"\n")
 	}
+	if !langImported {
+		builder.WriteString(fmt.Sprintf("\t%s %q\n", replaceSpecChars(langPackage), langPackage))
+	}
 
 	builder.WriteString(")\n\n")
 
 	builder.WriteString(`func init() {
 	RegisterImports(pkgmap.Set)
+	RegisterAdapters(` + replaceSpecChars(langPackage) + `.RegisterInterfaceAdapter)
 }
 
 `)
@@ -197,6 +206,10 @@ func getDeclaration(object types.Object, globalName string, aliasName string) st
 	case *types.Const:
 		return getConstDeclaration(concreteObject, globalName, aliasName)
 	case *types.Func:
+		if concreteObject.Type().(*types.Signature).TypeParams().Len() > 0 {
+			// generic functions can't be referenced uninstantiated.
+			return ""
+		}
 		return fmt.Sprintf("_register(%q, %s)", globalName, aliasName)
 	case *types.Var:
 		return getVarDeclaration(concreteObject, globalName, aliasName)
@@ -0,0 +1,1163 @@
+// Package aot compiles Glojure source files ahead of time to Go.
+//
+// A file is compiled from the analyzed top-level forms recorded in an
+// image (see runtime.Image) while it was loaded. Each form becomes a
+// Go function that evaluates it as the interpreter would, calling
+// package lang for data structures and Go functions of the standard
+// library directly when their signatures are known. The generated
+// file registers itself with runtime.RegisterCompiledFile, after which
+// loading the file runs its compiled forms.
+//
+// Forms the generator doesn't translate, such as those whose
+// constants can't be encoded, are embedded as image units and
+// evaluated by the interpreter. The runtime remains linked into
+// compiled programs, so eval and macros work as usual.
+package aot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	goast "go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+	goruntime "runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/glojurelang/glojure/pkg/ast"
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/pkgmap"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+const (
+	langPath    = "github.com/glojurelang/glojure/pkg/lang"
+	runtimePath = "github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// Compile returns the Go source of the named file held by img, in
+// the package pkg.
+func Compile(img *runtime.Image, name, pkg string) ([]byte, error) {
+	units := img.Units(name)
+	if units == nil {
+		return nil, fmt.Errorf("%s is not held by the image", name)
+	}
+
+	fg := &fileGen{
+		prefix:  identPrefix(name),
+		imports: map[string]string{},
+		ns:      "user",
+	}
+	var forms []string
+	for i, unit := range units {
+		n, used, err := runtime.DecodeUnit(unit)
+		if err != nil {
+			return nil, err
+		}
+		form := ""
+		if n != nil {
+			if ns := runtime.InNamespace(n); ns != nil {
+				fg.ns = ns.Name()
+			}
+			form, err = fg.form(n, used, i)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if form == "" {
+			form = fmt.Sprintf("{Unit: %s}", strconv.Quote(string(unit)))
+		}
+		forms = append(forms, form)
+	}
+
+	symCounter, rtID := img.Counters()
+	var body strings.Builder
+	fmt.Fprintf(&body, "func init() {\nruntime.RegisterCompiledFile(&runtime.CompiledFile{\n")
+	fmt.Fprintf(&body, "Name: %q,\nHash: %q,\nVersion: %d,\nSymCounter: %d,\nRTID: %d,\n", name, img.FileHash(name), runtime.ImageVersion, symCounter, rtID)
+	fmt.Fprintf(&body, "Forms: []runtime.CompiledForm{\n%s,\n},\n})\n}\n\n", strings.Join(forms, ",\n"))
+	if len(fg.sites) > 0 {
+		fmt.Fprintf(&body, "var (\n%s)\n\n", strings.Join(fg.sites, ""))
+	}
+	body.WriteString(fg.decls.String())
+
+	fg.imports[langPath] = "lang"
+	fg.imports[runtimePath] = "runtime"
+	return formatFile(fmt.Sprintf("glj compile from %s", name), pkg, fg.imports, body.String())
+}
+
+// Main returns the source of a main package that loads the given
+// targets in order, each a file of the load path ending in .glj or
+// the name of a namespace.
+func Main(targets []string) ([]byte, error) {
+	var body strings.Builder
+	body.WriteString("func main() {\n")
+	body.WriteString("core := lang.FindNamespace(lang.NewSymbol(\"glojure.core\"))\n")
+	body.WriteString("core.FindInternedVar(lang.NewSymbol(\"*command-line-args*\")).BindRoot(lang.Seq(os.Args[1:]))\n\n")
+	for _, target := range targets {
+		if strings.HasSuffix(target, ".glj") {
+			fmt.Fprintf(&body, "if err := glj.Load(%q); err != nil {\nlog.Fatal(err)\n}\n", target)
+		} else {
+			fmt.Fprintf(&body, "if err := glj.Require(%q); err != nil {\nlog.Fatal(err)\n}\n", target)
+		}
+	}
+	body.WriteString("}\n")
+
+	return formatFile("glj compile", "main", map[string]string{
+		"log":                                    "log",
+		"os":                                     "os",
+		"github.com/glojurelang/glojure/pkg/glj": "glj",
+		langPath:                                 "lang",
+	}, body.String())
+}
+
+// FileName returns the name of the Go file of a compiled source file.
+func FileName(name string) string {
+	return sanitize(name) + ".go"
+}
+
+// formatFile returns the formatted source of a Go file with the
+// given body, importing those of imports, which maps import paths to
+// names, that the body uses.
+func formatFile(generator, pkg string, imports map[string]string, body string) ([]byte, error) {
+	used, err := usedPackages(body)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for path, name := range imports {
+		if used[name] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by %s. DO NOT EDIT.\n\npackage %s\n\n", generator, pkg)
+	if len(paths) > 0 {
+		buf.WriteString("import (\n")
+		for _, path := range paths {
+			name := imports[path]
+			if name == path[strings.LastIndex(path, "/")+1:] {
+				fmt.Fprintf(&buf, "%q\n", path)
+			} else {
+				fmt.Fprintf(&buf, "%s %q\n", name, path)
+			}
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.WriteString(body)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go: %w", err)
+	}
+	return src, nil
+}
+
+// usedPackages returns the names of the packages referred to by the
+// declarations in src.
+func usedPackages(src string) (map[string]bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", "package p\n\n"+src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go: %w", err)
+	}
+	used := map[string]bool{}
+	goast.Inspect(f, func(n goast.Node) bool {
+		if sel, ok := n.(*goast.SelectorExpr); ok {
+			if id, ok := sel.X.(*goast.Ident); ok && id.Obj == nil {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+	return used, nil
+}
+
+func identPrefix(name string) string {
+	prefix := sanitize(strings.TrimSuffix(name, ".glj"))
+	if prefix == "" || prefix[0] >= '0' && prefix[0] <= '9' {
+		prefix = "_" + prefix
+	}
+	return prefix
+}
+
+// sanitize replaces the characters of s that can't appear in Go
+// identifiers with underscores.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+type (
+	// fileGen generates the Go source of a file.
+	fileGen struct {
+		prefix string
+		// imports maps the import paths of Go packages called
+		// statically to their names.
+		imports map[string]string
+		decls   strings.Builder
+		sites   []string
+		// ns is the name of the namespace of the form being
+		// generated, as set by the in-ns and ns forms before it.
+		ns string
+	}
+
+	// formGen generates the Go function of a top-level form. Node
+	// values are computed by statements written to w, which leave the
+	// value in a Go expression without side effects: a constant, a
+	// temporary or a local.
+	formGen struct {
+		file   *fileGen
+		w      *strings.Builder
+		nextID int
+
+		consts   []interface{}
+		constIdx map[interface{}]int
+		sites    []string
+
+		locals []*local
+		// fns holds the scopes of the fns enclosing the node being
+		// generated, innermost last.
+		fns []*fnScope
+		// funcDepth is the number of Go function literals enclosing
+		// the node being generated.
+		funcDepth int
+		recur     *recurTarget
+		// frame is the stack frame of the code being generated, as
+		// returned by runtime.FnFrame.
+		frame lang.StackFrame
+		// defFn is the fn node that is the value of the def node being
+		// generated, and defName the name of its var.
+		defFn   *ast.Node
+		defName *lang.Symbol
+	}
+
+	local struct {
+		name  string
+		ident string
+		// mutable is set for the locals assigned by recur, which fns
+		// must copy when they are created.
+		mutable bool
+		// fnLevel is the number of fns enclosing the local's binding.
+		fnLevel int
+		used    bool
+	}
+
+	fnScope struct {
+		// captures holds the mutable locals of enclosing fns referred
+		// to by the fn.
+		captures []string
+		seen     map[string]bool
+	}
+
+	recurTarget struct {
+		idents    []string
+		label     string
+		funcDepth int
+		used      bool
+	}
+
+	// unsupportedError is raised with panic when a form can't be
+	// compiled.
+	unsupportedError struct {
+		msg string
+	}
+)
+
+func (e *unsupportedError) Error() string {
+	return e.msg
+}
+
+func unsupported(format string, args ...interface{}) {
+	panic(&unsupportedError{msg: fmt.Sprintf(format, args...)})
+}
+
+// form generates the Go function of the index'th top-level node of
+// the file, returning the runtime.CompiledForm that refers to it, or
+// "" if the node can't be compiled.
+func (fg *fileGen) form(n *ast.Node, used []*lang.Var, index int) (res string, err error) {
+	g := &formGen{
+		file:     fg,
+		constIdx: map[interface{}]int{},
+		frame:    lang.StackFrame{Namespace: fg.ns},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(*unsupportedError); !ok {
+				panic(r)
+			}
+			res, err = "", nil
+		}
+	}()
+
+	code := g.funcBody(func() (string, bool) {
+		return g.expr(n)
+	})
+	consts, err := runtime.EncodeConstants(g.consts)
+	if errors.Is(err, runtime.ErrUnsupportedValue) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	usedVals := make([]interface{}, len(used))
+	for i, v := range used {
+		usedVals[i] = v
+	}
+	usedVars, err := runtime.EncodeConstants(usedVals)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%sForm%d", fg.prefix, index)
+	fmt.Fprintf(&fg.decls, "func %s(k []interface{}) (interface{}, error) {\n%s}\n\n", name, code)
+	fg.sites = append(fg.sites, g.sites...)
+	return fmt.Sprintf("{Consts: %s, UsedVars: %s, Eval: %s}", strconv.Quote(consts), strconv.Quote(usedVars), name), nil
+}
+
+func (g *formGen) emit(format string, args ...interface{}) {
+	fmt.Fprintf(g.w, format+"\n", args...)
+}
+
+func (g *formGen) id(prefix string) string {
+	g.nextID++
+	return fmt.Sprintf("%s%d", prefix, g.nextID)
+}
+
+func (g *formGen) tmp() string {
+	return g.id("t")
+}
+
+// call writes a call of a function returning a value and an error,
+// returning the temporary holding the value.
+func (g *formGen) call(format string, args ...interface{}) string {
+	t := g.tmp()
+	g.emit("%s, err := "+format, append([]interface{}{t}, args...)...)
+	g.emit("if err != nil {\nreturn nil, err\n}")
+	return t
+}
+
+// sub generates f into a separate buffer, returning the code and the
+// results of f.
+func (g *formGen) sub(f func() (string, bool)) (code, x string, ok bool) {
+	w := g.w
+	g.w = &strings.Builder{}
+	defer func() { g.w = w }()
+	x, ok = f()
+	return g.w.String(), x, ok
+}
+
+// funcBody generates the body of a Go function returning the value
+// computed by f.
+func (g *formGen) funcBody(f func() (string, bool)) string {
+	g.funcDepth++
+	defer func() { g.funcDepth-- }()
+
+	code, x, ok := g.sub(f)
+	if ok {
+		code += fmt.Sprintf("return %s, nil\n", x)
+	}
+	return code
+}
+
+// funcLit returns a Go function literal that evaluates n.
+func (g *formGen) funcLit(n *ast.Node) string {
+	return "func() (interface{}, error) {\n" + g.funcBody(func() (string, bool) {
+		return g.expr(n)
+	}) + "}"
+}
+
+// discard writes a use of the value x, whose node is evaluated for
+// effect.
+func (g *formGen) discard(x string) {
+	if token.IsIdentifier(x) && x != "nil" && x != "true" && x != "false" {
+		g.emit("_ = %s", x)
+	}
+}
+
+func (g *formGen) constant(v interface{}) string {
+	switch v {
+	case nil:
+		return "nil"
+	case true:
+		return "true"
+	case false:
+		return "false"
+	}
+
+	key, dedup := v, false
+	switch v.(type) {
+	case lang.Keyword:
+		dedup = true
+	default:
+		dedup = reflect.TypeOf(v).Kind() == reflect.Pointer
+	}
+	if dedup {
+		if i, ok := g.constIdx[key]; ok {
+			return fmt.Sprintf("k[%d]", i)
+		}
+		g.constIdx[key] = len(g.consts)
+	}
+	g.consts = append(g.consts, v)
+	return fmt.Sprintf("k[%d]", len(g.consts)-1)
+}
+
+func (g *formGen) varRef(v *lang.Var) string {
+	return g.constant(v) + ".(*lang.Var)"
+}
+
+// site declares the call site of n, returning its name.
+func (g *formGen) site(n *ast.Node) string {
+	site := runtime.NodeCallSite(n, g.frame)
+	name := fmt.Sprintf("%sSite%d", g.file.prefix, len(g.file.sites)+len(g.sites))
+	g.sites = append(g.sites, fmt.Sprintf("%s = runtime.CallSite{Frame: %#v, Location: %q, GoTry: %t}\n", name, site.Frame, site.Location, site.GoTry))
+	return name
+}
+
+func (g *formGen) bind(name *lang.Symbol, ident string, mutable bool) *local {
+	l := &local{
+		name:    name.Name(),
+		ident:   ident,
+		mutable: mutable,
+		fnLevel: len(g.fns),
+	}
+	g.locals = append(g.locals, l)
+	return l
+}
+
+// bindNew binds a local to a new Go variable, returning its name.
+func (g *formGen) bindNew(name *lang.Symbol, mutable bool) string {
+	return g.bind(name, g.id("l")+"_"+sanitize(name.Name()), mutable).ident
+}
+
+func (g *formGen) lookup(name string) string {
+	for i := len(g.locals) - 1; i >= 0; i-- {
+		l := g.locals[i]
+		if l.name != name {
+			continue
+		}
+		l.used = true
+		if l.mutable {
+			for _, fn := range g.fns[l.fnLevel:] {
+				if !fn.seen[l.ident] {
+					fn.seen[l.ident] = true
+					fn.captures = append(fn.captures, l.ident)
+				}
+			}
+		}
+		return l.ident
+	}
+	unsupported("unable to resolve local symbol: %s", name)
+	return ""
+}
+
+// exprs generates nodes in order, returning their values, or false
+// if one of them doesn't complete.
+func (g *formGen) exprs(nodes []*ast.Node) ([]string, bool) {
+	xs := make([]string, len(nodes))
+	for i, n := range nodes {
+		x, ok := g.expr(n)
+		if !ok {
+			return nil, false
+		}
+		xs[i] = x
+	}
+	return xs, true
+}
+
+// expr generates n, returning the expression holding its value, or
+// false if evaluation doesn't continue past n, as after recur and
+// throw.
+func (g *formGen) expr(n *ast.Node) (string, bool) {
+	switch n.Op {
+	case ast.OpConst:
+		return g.constant(n.Sub.(*ast.ConstNode).Value), true
+	case ast.OpQuote:
+		return g.constant(n.Sub.(*ast.QuoteNode).Expr.Sub.(*ast.ConstNode).Value), true
+	case ast.OpTheVar:
+		return g.constant(n.Sub.(*ast.TheVarNode).Var), true
+	case ast.OpGoBuiltin:
+		return fmt.Sprintf("lang.Builtins[%q]", n.Sub.(*ast.GoBuiltinNode).Sym.Name()), true
+	case ast.OpVar:
+		return g.call("runtime.VarValue(%s)", g.varRef(n.Sub.(*ast.VarNode).Var)), true
+	case ast.OpLocal:
+		return g.lookup(n.Sub.(*ast.LocalNode).Name.Name()), true
+	case ast.OpMaybeClass:
+		return g.call("runtime.MaybeClass(%s.(*lang.Symbol))", g.constant(n.Sub.(*ast.MaybeClassNode).Class)), true
+	case ast.OpDef:
+		return g.def(n)
+	case ast.OpSetBang:
+		return g.setBang(n)
+	case ast.OpWithMeta:
+		return g.withMeta(n)
+	case ast.OpFn:
+		return g.fn(n)
+	case ast.OpMap:
+		return g.mapExpr(n)
+	case ast.OpVector:
+		return g.collection("lang.NewVector", n.Sub.(*ast.VectorNode).Items)
+	case ast.OpSet:
+		return g.collection("lang.NewSet", n.Sub.(*ast.SetNode).Items)
+	case ast.OpDo:
+		return g.do(n)
+	case ast.OpLet:
+		return g.let(n, false)
+	case ast.OpLoop:
+		return g.let(n, true)
+	case ast.OpLetFn:
+		return g.letFn(n)
+	case ast.OpInvoke:
+		return g.invoke(n)
+	case ast.OpGo:
+		return g.goExpr(n)
+	case ast.OpHostCall:
+		return g.hostCall(n)
+	case ast.OpHostInterop:
+		return g.hostInterop(n)
+	case ast.OpIf:
+		return g.ifExpr(n)
+	case ast.OpCase:
+		return g.caseExpr(n)
+	case ast.OpRecur:
+		return g.recurExpr(n)
+	case ast.OpNew:
+		return g.newExpr(n)
+	case ast.OpTry:
+		return g.try(n)
+	case ast.OpThrow:
+		return g.throw(n)
+	default:
+		unsupported("unsupported op %d", n.Op)
+		return "", false
+	}
+}
+
+func (g *formGen) def(n *ast.Node) (string, bool) {
+	defNode := n.Sub.(*ast.DefNode)
+	if defNode.Init == nil {
+		if defNode.Meta == nil {
+			return g.constant(defNode.Var), true
+		}
+		meta, ok := g.expr(defNode.Meta)
+		if !ok {
+			return "", false
+		}
+		t := g.tmp()
+		g.emit("%s := runtime.Declare(%s, %s)", t, g.varRef(defNode.Var), meta)
+		return t, true
+	}
+
+	g.defFn, g.defName = runtime.DefFn(n), defNode.Name
+	init, ok := g.expr(defNode.Init)
+	if !ok {
+		return "", false
+	}
+	meta := "nil"
+	if defNode.Meta != nil {
+		if meta, ok = g.expr(defNode.Meta); !ok {
+			return "", false
+		}
+	}
+	return g.call("runtime.Def(%s, %s, %s)", g.varRef(defNode.Var), init, meta), true
+}
+
+func (g *formGen) setBang(n *ast.Node) (string, bool) {
+	setBangNode := n.Sub.(*ast.SetBangNode)
+
+	target := setBangNode.Target
+	switch target.Op {
+	case ast.OpVar:
+		val, ok := g.expr(setBangNode.Val)
+		if !ok {
+			return "", false
+		}
+		t := g.tmp()
+		g.emit("%s := %s.Set(%s)", t, g.varRef(target.Sub.(*ast.VarNode).Var), val)
+		return t, true
+	case ast.OpHostInterop:
+		val, ok := g.expr(setBangNode.Val)
+		if !ok {
+			return "", false
+		}
+		interopNode := target.Sub.(*ast.HostInteropNode)
+		tgt, ok := g.expr(interopNode.Target)
+		if !ok {
+			return "", false
+		}
+		return g.call("runtime.SetHostField(%s, %s.(*lang.Symbol), %s)", tgt, g.constant(interopNode.MOrF), val), true
+	default:
+		unsupported("unsupported assign target: %v", target.Form)
+		return "", false
+	}
+}
+
+func (g *formGen) withMeta(n *ast.Node) (string, bool) {
+	wmNode := n.Sub.(*ast.WithMetaNode)
+	xs, ok := g.exprs([]*ast.Node{wmNode.Expr, wmNode.Meta})
+	if !ok {
+		return "", false
+	}
+	return g.call("lang.WithMeta(%s, %s.(lang.IPersistentMap))", xs[0], xs[1]), true
+}
+
+func (g *formGen) mapExpr(n *ast.Node) (string, bool) {
+	mapNode := n.Sub.(*ast.MapNode)
+
+	var kvs []string
+	for i, key := range mapNode.Keys {
+		xs, ok := g.exprs([]*ast.Node{key, mapNode.Vals[i]})
+		if !ok {
+			return "", false
+		}
+		kvs = append(kvs, xs...)
+	}
+	t := g.tmp()
+	g.emit("var %s lang.IPersistentMap = lang.NewMap()", t)
+	for i := 0; i < len(kvs); i += 2 {
+		g.emit("%s = lang.Assoc(%s, %s, %s).(lang.IPersistentMap)", t, t, kvs[i], kvs[i+1])
+	}
+	return t, true
+}
+
+func (g *formGen) collection(constructor string, items []*ast.Node) (string, bool) {
+	xs, ok := g.exprs(items)
+	if !ok {
+		return "", false
+	}
+	t := g.tmp()
+	g.emit("%s := %s(%s)", t, constructor, strings.Join(xs, ", "))
+	return t, true
+}
+
+func (g *formGen) do(n *ast.Node) (string, bool) {
+	doNode := n.Sub.(*ast.DoNode)
+	for _, stmt := range doNode.Statements {
+		x, ok := g.expr(stmt)
+		if !ok {
+			return "", false
+		}
+		g.discard(x)
+	}
+	return g.expr(doNode.Ret)
+}
+
+func (g *formGen) let(n *ast.Node, isLoop bool) (string, bool) {
+	letNode := n.Sub.(*ast.LetNode)
+
+	numLocals := len(g.locals)
+	defer func() { g.locals = g.locals[:numLocals] }()
+
+	var idents []string
+	for _, binding := range letNode.Bindings {
+		bindingNode := binding.Sub.(*ast.BindingNode)
+		init, ok := g.expr(bindingNode.Init)
+		if !ok {
+			return "", false
+		}
+		ident := g.bindNew(bindingNode.Name, isLoop)
+		g.emit("var %s interface{} = %s", ident, init)
+		g.emit("_ = %s", ident)
+		idents = append(idents, ident)
+	}
+	if !isLoop {
+		return g.expr(letNode.Body)
+	}
+
+	defer func(recur *recurTarget) { g.recur = recur }(g.recur)
+	target := &recurTarget{idents: idents, label: g.id("loop"), funcDepth: g.funcDepth}
+	g.recur = target
+
+	code, x, ok := g.sub(func() (string, bool) {
+		return g.expr(letNode.Body)
+	})
+	if !target.used {
+		g.w.WriteString(code)
+		return x, ok
+	}
+
+	t := ""
+	if ok {
+		t = g.tmp()
+		g.emit("var %s interface{}", t)
+	}
+	g.emit("%s:\nfor {", target.label)
+	g.w.WriteString(code)
+	if ok {
+		g.emit("%s = %s\nbreak", t, x)
+	}
+	g.emit("}")
+	return t, ok
+}
+
+func (g *formGen) letFn(n *ast.Node) (string, bool) {
+	letFnNode := n.Sub.(*ast.LetFnNode)
+
+	numLocals := len(g.locals)
+	defer func() { g.locals = g.locals[:numLocals] }()
+
+	idents := make([]string, len(letFnNode.Bindings))
+	for i, binding := range letFnNode.Bindings {
+		idents[i] = g.bindNew(binding.Sub.(*ast.BindingNode).Name, false)
+		g.emit("var %s interface{}", idents[i])
+	}
+	// the fns refer to the variables of each other, so they see the
+	// fns assigned after their creation.
+	for i, binding := range letFnNode.Bindings {
+		init, ok := g.expr(binding.Sub.(*ast.BindingNode).Init)
+		if !ok {
+			return "", false
+		}
+		g.emit("%s = %s", idents[i], init)
+	}
+	for _, ident := range idents {
+		g.emit("_ = %s", ident)
+	}
+	return g.expr(letFnNode.Body)
+}
+
+func (g *formGen) ifExpr(n *ast.Node) (string, bool) {
+	ifNode := n.Sub.(*ast.IfNode)
+
+	test, ok := g.expr(ifNode.Test)
+	if !ok {
+		return "", false
+	}
+	thenCode, thenX, thenOK := g.sub(func() (string, bool) { return g.expr(ifNode.Then) })
+	elseCode, elseX, elseOK := g.sub(func() (string, bool) { return g.expr(ifNode.Else) })
+
+	t := ""
+	if thenOK || elseOK {
+		t = g.tmp()
+		g.emit("var %s interface{}", t)
+	}
+	g.emit("if lang.IsTruthy(%s) {", test)
+	g.w.WriteString(thenCode)
+	if thenOK {
+		g.emit("%s = %s", t, thenX)
+	}
+	g.emit("} else {")
+	g.w.WriteString(elseCode)
+	if elseOK {
+		g.emit("%s = %s", t, elseX)
+	}
+	g.emit("}")
+	return t, thenOK || elseOK
+}
+
+func (g *formGen) caseExpr(n *ast.Node) (string, bool) {
+	caseNode := n.Sub.(*ast.CaseNode)
+
+	test, ok := g.expr(caseNode.Test)
+	if !ok {
+		return "", false
+	}
+
+	type clause struct {
+		conds []string
+		code  string
+		x     string
+		ok    bool
+	}
+	var clauses []clause
+	anyOK := false
+	for _, node := range caseNode.Nodes {
+		caseNodeNode := node.Sub.(*ast.CaseNodeNode)
+		var cl clause
+		for _, tn := range caseNodeNode.Tests {
+			var val interface{}
+			switch tn.Op {
+			case ast.OpConst:
+				val = tn.Sub.(*ast.ConstNode).Value
+			case ast.OpQuote:
+				val = tn.Sub.(*ast.QuoteNode).Expr.Sub.(*ast.ConstNode).Value
+			default:
+				unsupported("unsupported case test: %v", tn.Form)
+			}
+			cl.conds = append(cl.conds, fmt.Sprintf("lang.Equals(%s, %s)", test, g.constant(val)))
+		}
+		if len(cl.conds) == 0 {
+			continue
+		}
+		cl.code, cl.x, cl.ok = g.sub(func() (string, bool) { return g.expr(caseNodeNode.Then) })
+		anyOK = anyOK || cl.ok
+		clauses = append(clauses, cl)
+	}
+	defaultCode, defaultX, defaultOK := g.sub(func() (string, bool) { return g.expr(caseNode.Default) })
+	anyOK = anyOK || defaultOK
+
+	t := ""
+	if anyOK {
+		t = g.tmp()
+		g.emit("var %s interface{}", t)
+	}
+	g.emit("switch {")
+	for _, cl := range clauses {
+		g.emit("case %s:", strings.Join(cl.conds, ", "))
+		g.w.WriteString(cl.code)
+		if cl.ok {
+			g.emit("%s = %s", t, cl.x)
+		}
+	}
+	g.emit("default:")
+	g.w.WriteString(defaultCode)
+	if defaultOK {
+		g.emit("%s = %s", t, defaultX)
+	}
+	g.emit("}")
+	return t, anyOK
+}
+
+func (g *formGen) recurExpr(n *ast.Node) (string, bool) {
+	target := g.recur
+	if target == nil || target.funcDepth != g.funcDepth {
+		unsupported("recur outside of loop")
+	}
+	exprs := n.Sub.(*ast.RecurNode).Exprs
+	if len(exprs) != len(target.idents) {
+		unsupported("invalid recur, expected %d arguments, got %d", len(target.idents), len(exprs))
+	}
+
+	g.recur = nil
+	xs, ok := g.exprs(exprs)
+	g.recur = target
+	if !ok {
+		return "", false
+	}
+	if len(xs) > 0 {
+		g.emit("%s = %s", strings.Join(target.idents, ", "), strings.Join(xs, ", "))
+	}
+	g.emit("if err := lang.CheckCanceled(); err != nil {\nreturn nil, err\n}")
+	g.emit("continue %s", target.label)
+	target.used = true
+	return "", false
+}
+
+func (g *formGen) throw(n *ast.Node) (string, bool) {
+	x, ok := g.expr(n.Sub.(*ast.ThrowNode).Exception)
+	if !ok {
+		return "", false
+	}
+	g.emit("panic(%s)", x)
+	return "", false
+}
+
+func (g *formGen) newExpr(n *ast.Node) (string, bool) {
+	newNode := n.Sub.(*ast.NewNode)
+	if len(newNode.Args) > 0 {
+		unsupported("new with args unsupported")
+	}
+	class, ok := g.expr(newNode.Class)
+	if !ok {
+		return "", false
+	}
+	return g.call("runtime.NewValue(%s)", class), true
+}
+
+func (g *formGen) goExpr(n *ast.Node) (string, bool) {
+	invokeNode := n.Sub.(*ast.GoNode).Invoke.Sub.(*ast.InvokeNode)
+	xs, ok := g.exprs(append([]*ast.Node{invokeNode.Fn}, invokeNode.Args...))
+	if !ok {
+		return "", false
+	}
+	g.emit("go lang.Apply(%s, []interface{}{%s})", xs[0], strings.Join(xs[1:], ", "))
+	return "nil", true
+}
+
+func (g *formGen) hostCall(n *ast.Node) (string, bool) {
+	hostCallNode := n.Sub.(*ast.HostCallNode)
+	xs, ok := g.exprs(append([]*ast.Node{hostCallNode.Target}, hostCallNode.Args...))
+	if !ok {
+		return "", false
+	}
+	args := append([]string{xs[0], g.constant(hostCallNode.Method) + ".(*lang.Symbol)"}, xs[1:]...)
+	return g.call("%s.HostCall(%s)", g.site(n), strings.Join(args, ", ")), true
+}
+
+func (g *formGen) hostInterop(n *ast.Node) (string, bool) {
+	hostInteropNode := n.Sub.(*ast.HostInteropNode)
+	tgt, ok := g.expr(hostInteropNode.Target)
+	if !ok {
+		return "", false
+	}
+	return g.call("%s.HostInterop(%s, %s.(*lang.Symbol))", g.site(n), tgt, g.constant(hostInteropNode.MOrF)), true
+}
+
+// invoke generates an invoke node as a function literal evaluated by
+// its call site, which converts panics to errors as the interpreter
+// does.
+func (g *formGen) invoke(n *ast.Node) (string, bool) {
+	invokeNode := n.Sub.(*ast.InvokeNode)
+	site := g.site(n)
+	static := g.staticFunc(invokeNode.Fn, len(invokeNode.Args))
+
+	body := g.funcBody(func() (string, bool) {
+		if static != nil {
+			args, ok := g.exprs(invokeNode.Args)
+			if !ok {
+				return "", false
+			}
+			g.staticCall(site, invokeNode.GoTry, static, args)
+			return "", false
+		}
+		xs, ok := g.exprs(append([]*ast.Node{invokeNode.Fn}, invokeNode.Args...))
+		if !ok {
+			return "", false
+		}
+		g.emit("return %s.Apply(%s)", site, strings.Join(xs, ", "))
+		return "", false
+	})
+	return g.call("%s.Invoke(func() (interface{}, error) {\n%s})", site, body), true
+}
+
+// goFunc is a Go function that can be called statically.
+type goFunc struct {
+	expr   string
+	typ    reflect.Type
+	params []string
+}
+
+// staticFunc returns the Go function of fn if it names a function of
+// the standard library that can be called with nargs arguments
+// without reflection, or nil.
+func (g *formGen) staticFunc(fn *ast.Node, nargs int) *goFunc {
+	if fn.Op != ast.OpMaybeClass {
+		return nil
+	}
+	sym, ok := fn.Sub.(*ast.MaybeClassNode).Class.(*lang.Symbol)
+	if !ok {
+		return nil
+	}
+	v, ok := pkgmap.Get(sym.FullName())
+	if !ok || v == nil || reflect.TypeOf(v).Kind() != reflect.Func {
+		return nil
+	}
+	pkg, name := pkgmap.SplitExport(sym.FullName())
+	path := pkgmap.UnmungePkg(pkg)
+	if !isStdlib(path) {
+		return nil
+	}
+	// only functions declared in the package, not variables of func
+	// type, are called statically.
+	if f := goruntime.FuncForPC(reflect.ValueOf(v).Pointer()); f == nil || f.Name() != path+"."+name {
+		return nil
+	}
+	typ := reflect.TypeOf(v)
+	if typ.IsVariadic() || typ.NumIn() != nargs {
+		return nil
+	}
+
+	gf := &goFunc{
+		expr: g.file.importName(path) + "." + name,
+		typ:  typ,
+	}
+	for i := 0; i < typ.NumIn(); i++ {
+		param, ok := g.file.typeExpr(typ.In(i))
+		if !ok {
+			return nil
+		}
+		gf.params = append(gf.params, param)
+	}
+	return gf
+}
+
+// staticCall writes a call of fn with args, and the return of its
+// results packed as by lang.Apply.
+func (g *formGen) staticCall(site string, goTry bool, fn *goFunc, args []string) {
+	goArgs := make([]string, len(args))
+	for i, arg := range args {
+		goArgs[i] = fmt.Sprintf("lang.CoerceArg[%s](%d, %s)", fn.params[i], i, arg)
+	}
+	call := fmt.Sprintf("%s(%s)", fn.expr, strings.Join(goArgs, ", "))
+
+	numOut := fn.typ.NumOut()
+	if numOut == 0 {
+		g.emit("%s\nreturn nil, nil", call)
+		return
+	}
+	results := make([]string, numOut)
+	for i := range results {
+		results[i] = fmt.Sprintf("r%d", i)
+	}
+	g.emit("%s := %s", strings.Join(results, ", "), call)
+	if goTry && fn.typ.Out(numOut-1) == errorType {
+		errResult := results[numOut-1]
+		g.emit("if %s != nil {\nreturn nil, %s.GoError(%s)\n}", errResult, site, errResult)
+		results = results[:numOut-1]
+	}
+	switch len(results) {
+	case 0:
+		g.emit("return nil, nil")
+	case 1:
+		g.emit("return %s, nil", results[0])
+	default:
+		g.emit("return lang.NewVector(%s), nil", strings.Join(results, ", "))
+	}
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// isStdlib reports whether path is the import path of a package of
+// the Go standard library that may be imported.
+func isStdlib(path string) bool {
+	first := path
+	if i := strings.Index(path, "/"); i >= 0 {
+		first = path[:i]
+	}
+	return !strings.Contains(first, ".") && first != "internal" && first != "vendor" &&
+		!strings.Contains(path, "/internal/") && !strings.HasSuffix(path, "/internal")
+}
+
+// importName returns the name under which the package of the given
+// import path is imported.
+func (fg *fileGen) importName(path string) string {
+	if name, ok := fg.imports[path]; ok {
+		return name
+	}
+	name := "go_" + sanitize(path)
+	fg.imports[path] = name
+	return name
+}
+
+// typeExpr returns the Go expression of typ, if it can be written.
+func (fg *fileGen) typeExpr(typ reflect.Type) (string, bool) {
+	switch {
+	case typ == errorType:
+		return "error", true
+	case typ.Name() == "":
+		switch typ.Kind() {
+		case reflect.Interface:
+			if typ.NumMethod() == 0 {
+				return "interface{}", true
+			}
+		case reflect.Slice:
+			elem, ok := fg.typeExpr(typ.Elem())
+			return "[]" + elem, ok
+		case reflect.Pointer:
+			elem, ok := fg.typeExpr(typ.Elem())
+			return "*" + elem, ok
+		}
+		return "", false
+	case typ.PkgPath() == "":
+		// a predeclared type.
+		return typ.Name(), true
+	case isStdlib(typ.PkgPath()) && token.IsExported(typ.Name()) && token.IsIdentifier(typ.Name()):
+		return fg.importName(typ.PkgPath()) + "." + typ.Name(), true
+	default:
+		return "", false
+	}
+}
+
+func (g *formGen) try(n *ast.Node) (string, bool) {
+	tryNode := n.Sub.(*ast.TryNode)
+
+	var b strings.Builder
+	b.WriteString("func() (res interface{}, err error) {\n")
+	if tryNode.Finally != nil {
+		fmt.Fprintf(&b, "defer func() {\nif _, ferr := %s(); ferr != nil {\nerr = ferr\n}\n}()\n", g.funcLit(tryNode.Finally))
+	}
+	if len(tryNode.Catches) > 0 {
+		b.WriteString("defer func() {\nr := recover()\nif r == nil {\nreturn\n}\n")
+		for _, cn := range tryNode.Catches {
+			catch := cn.Sub.(*ast.CatchNode)
+			class := g.funcLit(catch.Class)
+
+			numLocals := len(g.locals)
+			ident := g.bindNew(catch.Local.Sub.(*ast.BindingNode).Name, false)
+			body := g.funcLit(catch.Body)
+			g.locals = g.locals[:numLocals]
+
+			fmt.Fprintf(&b, "if class, cerr := %s(); cerr != nil {\npanic(cerr)\n} else if runtime.CatchMatches(r, class) {\n", class)
+			fmt.Fprintf(&b, "var %s interface{} = r\n_ = %s\n", ident, ident)
+			fmt.Fprintf(&b, "res, err = %s()\nif err != nil {\npanic(err)\n}\nreturn\n}\n", body)
+		}
+		// re-throw if no catch matches
+		b.WriteString("panic(r)\n}()\n")
+	}
+	fmt.Fprintf(&b, "res, err = %s()\nif err != nil {\npanic(err)\n}\nreturn res, nil\n}", g.funcLit(tryNode.Body))
+	return g.call("%s()", b.String()), true
+}
+
+func (g *formGen) fn(n *ast.Node) (string, bool) {
+	fnNode := n.Sub.(*ast.FnNode)
+
+	scope := &fnScope{seen: map[string]bool{}}
+	g.fns = append(g.fns, scope)
+	var self *lang.Symbol
+	if fnNode.Local != nil {
+		self = fnNode.Local.Sub.(*ast.BindingNode).Name
+	}
+	var def *lang.Symbol
+	if n == g.defFn {
+		def = g.defName
+	}
+	methods := make([]string, len(fnNode.Methods))
+	for i, method := range fnNode.Methods {
+		methods[i] = g.fnMethod(method, self, runtime.FnFrame(g.frame, n, method, def))
+	}
+	g.fns = g.fns[:len(g.fns)-1]
+
+	t := g.tmp()
+	if len(scope.captures) == 0 {
+		g.emit("%s := runtime.NewCompiledFn(\n%s,\n)", t, strings.Join(methods, ",\n"))
+		return t, true
+	}
+	// copy the locals assigned by recur, so the fn sees their values
+	// at its creation.
+	g.emit("var %s interface{}\n{", t)
+	for _, ident := range scope.captures {
+		g.emit("%s := %s", ident, ident)
+	}
+	g.emit("%s = runtime.NewCompiledFn(\n%s,\n)\n}", t, strings.Join(methods, ",\n"))
+	return t, true
+}
+
+func (g *formGen) fnMethod(n *ast.Node, self *lang.Symbol, frame lang.StackFrame) string {
+	methodNode := n.Sub.(*ast.FnMethodNode)
+
+	numLocals, outerRecur, outerFrame := len(g.locals), g.recur, g.frame
+	defer func() {
+		g.locals, g.recur, g.frame = g.locals[:numLocals], outerRecur, outerFrame
+	}()
+	g.frame = frame
+
+	fnParam := "_"
+	var selfLocal *local
+	if self != nil {
+		selfLocal = g.bind(self, g.id("fn"), false)
+	}
+
+	var header strings.Builder
+	var idents []string
+	for i, param := range methodNode.Params {
+		ident := g.bindNew(param.Sub.(*ast.BindingNode).Name, true)
+		fmt.Fprintf(&header, "var %s interface{} = args[%d]\n_ = %s\n", ident, i, ident)
+		idents = append(idents, ident)
+	}
+
+	g.funcDepth++
+	target := &recurTarget{idents: idents, label: g.id("recur"), funcDepth: g.funcDepth}
+	g.recur = target
+	code, x, ok := g.sub(func() (string, bool) {
+		return g.expr(methodNode.Body)
+	})
+	g.funcDepth--
+	if ok {
+		code += fmt.Sprintf("return %s, nil\n", x)
+	}
+	if target.used {
+		code = fmt.Sprintf("%s:\nfor {\n%s}\n", target.label, code)
+	}
+	if selfLocal != nil && selfLocal.used {
+		fnParam = selfLocal.ident
+	}
+	return fmt.Sprintf("runtime.CompiledMethod{FixedArity: %d, Variadic: %t, Body: func(%s *runtime.Fn, args []interface{}) (interface{}, error) {\n%s%s}}",
+		methodNode.FixedArity, methodNode.IsVariadic, fnParam, header.String(), code)
+}
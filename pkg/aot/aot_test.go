@@ -0,0 +1,299 @@
+package aot_test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/glojurelang/glojure/pkg/aot"
+	_ "github.com/glojurelang/glojure/pkg/gen/gljimports"
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+const testSrc = `(ns aot.test.hello
+  (:require [glojure.string :as s]))
+
+(defn- add [a b] (+ a b))
+
+(defn evens [n]
+  (loop [i 0 acc []]
+    (if (< i n)
+      (recur (inc i) (if (even? i) (conj acc (fn [] i)) acc))
+      (map #(%) acc))))
+
+(println (add 1 2) (strings.ToUpper "hi") (s/upper-case "x"))
+(println (evens 6))
+(println (try (throw (errors.New "boom")) (catch go/any e (.Error e)) (finally (println "fin"))))
+(println (case (add 1 1) 1 :one 2 :two :other))
+(println (try (add 1 nil) (catch go/any e (map (memfn Name) (github.com$glojurelang$glojure$pkg$runtime.StackTrace e)))))
+`
+
+const testOut = `3 HI X
+(0 2 4)
+fin
+boom
+:two
+(glojure.core/+ [2] aot.test.hello/add [2] aot.test.hello)
+`
+
+var (
+	testImage     *runtime.Image
+	testImageOnce sync.Once
+)
+
+// recordImage returns an image of testSrc. The namespace can only be
+// loaded once, so the image is shared by the tests.
+func recordImage() *runtime.Image {
+	testImageOnce.Do(func() {
+		testImage = newTestImage()
+	})
+	return testImage
+}
+
+func newTestImage() *runtime.Image {
+	runtime.AddLoadPath(fstest.MapFS{
+		"aot/test/hello.glj": &fstest.MapFile{Data: []byte(testSrc)},
+	})
+
+	kvs := make([]interface{}, 0, 8)
+	for _, vr := range []*value.Var{value.VarCurrentNS, value.VarWarnOnReflection, value.VarUncheckedMath, value.VarDataReaders} {
+		kvs = append(kvs, vr, vr.Deref())
+	}
+	value.PushThreadBindings(value.NewMap(kvs...))
+	defer value.PopThreadBindings()
+
+	img := runtime.NewImage()
+	env := runtime.NewEnvironment(runtime.WithImageRecorder(img))
+	runtime.ReadEval(`(require 'aot.test.hello)`, runtime.WithEnv(env))
+	return img
+}
+
+func TestCompile(t *testing.T) {
+	img := recordImage()
+
+	src, err := aot.Compile(img, "aot/test/hello.glj", "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "hello.go", src, 0)
+	if err != nil {
+		t.Fatalf("generated code doesn't parse: %v\n%s", err, src)
+	}
+	if f.Name.Name != "hello" {
+		t.Errorf("expected package hello, got %s", f.Name.Name)
+	}
+	// the case form isn't held by the image as a node, so only the
+	// other forms are compiled.
+	if n := strings.Count(string(src), "Eval: "); n != 7 {
+		t.Errorf("expected 7 compiled forms, got %d:\n%s", n, src)
+	}
+
+	if _, err := aot.Compile(img, "aot/test/missing.glj", "hello"); err == nil {
+		t.Error("expected an error compiling a file not held by the image")
+	}
+	if name := aot.FileName("aot/test/hello.glj"); name != "aot_test_hello_glj.go" {
+		t.Errorf("unexpected file name %q", name)
+	}
+}
+
+// TestCompileRun builds the compiled file into a program and checks
+// that it prints what the interpreter does.
+func TestCompileRun(t *testing.T) {
+	img := recordImage()
+	src, err := aot.Compile(img, "aot/test/hello.glj", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mainSrc, err := aot.Main([]string{"aot.test.hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prog := buildProgram(t, map[string][]byte{
+		"main.go":                          mainSrc,
+		aot.FileName("aot/test/hello.glj"): src,
+	})
+	out, err := exec.Command(prog).CombinedOutput()
+	if err != nil {
+		t.Fatalf("compiled program failed: %v\n%s", err, out)
+	}
+	if string(out) != testOut {
+		t.Errorf("expected output\n%s\ngot\n%s", testOut, out)
+	}
+}
+
+// testResults is a fn that returns what it prints of the counters of
+// each test of the namespaces it's given, as they are after running
+// the test alone.
+const testResults = `(fn [namespaces]
+  (with-out-str
+    (doseq [ns namespaces
+            v (sort-by str (filter (comp :test meta) (vals (ns-interns ns))))]
+      (println v (binding [glojure.test/*report-counters* (ref glojure.test/*initial-report-counters*)
+                           glojure.test/*test-out* (new strings.Builder)]
+                   (glojure.test/test-vars [v])
+                   @glojure.test/*report-counters*)))))`
+
+// testMain is the main file of the program of the compiled tests,
+// which requires the namespaces given as arguments and prints the
+// results of their tests.
+var testMain = `package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/glojurelang/glojure/pkg/glj"
+)
+
+const testResults = ` + strconv.Quote(testResults) + `
+
+func main() {
+	for _, ns := range os.Args[1:] {
+		if err := glj.Require(ns); err != nil {
+			log.Fatal(err)
+		}
+	}
+	out, err := glj.Eval(context.Background(), "("+testResults+" '["+strings.Join(os.Args[1:], " ")+"])")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(out)
+}
+`
+
+// TestCompileTests compiles the namespaces of the .glj tests and
+// checks that their tests have the same results in the compiled
+// program as in the interpreter.
+func TestCompileTests(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go build in short mode")
+	}
+	testDir, err := filepath.Abs("../../test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var namespaces []string
+	err = filepath.WalkDir(testDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".glj" {
+			return err
+		}
+		rel, err := filepath.Rel(testDir, strings.TrimSuffix(path, ".glj"))
+		if err != nil {
+			return err
+		}
+		ns := strings.ReplaceAll(filepath.ToSlash(rel), "/", ".")
+		namespaces = append(namespaces, strings.ReplaceAll(ns, "_", "-"))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(namespaces)
+
+	// the namespaces are loaded with a recorder, then their tests are
+	// run by the interpreter.
+	runtime.AddLoadPath(os.DirFS(testDir))
+	kvs := make([]interface{}, 0, 8)
+	for _, vr := range []*value.Var{value.VarCurrentNS, value.VarWarnOnReflection, value.VarUncheckedMath, value.VarDataReaders} {
+		kvs = append(kvs, vr, vr.Deref())
+	}
+	value.PushThreadBindings(value.NewMap(kvs...))
+	defer value.PopThreadBindings()
+
+	img := runtime.NewImage()
+	env := runtime.NewEnvironment(runtime.WithImageRecorder(img))
+	img.Remove(img.Files()...)
+	nsList := "'[" + strings.Join(namespaces, " ") + "]"
+	runtime.ReadEval("(apply require "+nsList+")", runtime.WithEnv(env))
+	want := runtime.ReadEval("("+testResults+" "+nsList+")", runtime.WithEnv(env)).(string)
+
+	files := map[string][]byte{"main.go": []byte(testMain)}
+	core := runtime.CoreImage()
+	for _, name := range img.Files() {
+		if core != nil && core.FileHash(name) != "" {
+			continue
+		}
+		src, err := aot.Compile(img, name, "main")
+		if err != nil {
+			t.Fatalf("compiling %s: %v", name, err)
+		}
+		files[aot.FileName(name)] = src
+	}
+	prog := buildProgram(t, files)
+
+	// the program runs where the sources can't be loaded from, so that
+	// the compiled files are.
+	cmd := exec.Command(prog, namespaces...)
+	cmd.Dir = t.TempDir()
+	cmd.Stderr = os.Stderr
+	got, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("compiled program failed: %v", err)
+	}
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(string(got), "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			t.Errorf("interpreted: %s\ncompiled:    %s", w, g)
+		}
+	}
+}
+
+// buildProgram builds a program of the files of package main, named
+// by file name, in a module that requires this one, and returns its
+// path. It skips the test if the go tool isn't found.
+func buildProgram(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping go build in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	modRoot, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	goMod := "module hello\n\ngo 1.19\n\nrequire github.com/glojurelang/glojure v0.0.0\n\nreplace github.com/glojurelang/glojure => " + modRoot + "\n"
+	goSum, err := os.ReadFile(filepath.Join(modRoot, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["go.mod"] = []byte(goMod)
+	files["go.sum"] = goSum
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	build := exec.Command(goTool, "build", "-mod=mod", "-o", "prog")
+	build.Dir = dir
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build failed: %v\n%s", err, out)
+	}
+	return filepath.Join(dir, "prog")
+}
@@ -96,6 +96,9 @@ type (
 		Meta lang.IPersistentMap
 		Fn   *Node
 		Args []*Node
+		// GoTry is set when a trailing Go error result should be thrown
+		// instead of returned.
+		GoTry bool
 	}
 
 	IfNode struct {
@@ -146,6 +149,7 @@ type (
 		Target *Node
 		Method *lang.Symbol
 		Args   []*Node
+		GoTry  bool
 	}
 
 	HostFieldNode struct {
@@ -156,6 +160,7 @@ type (
 	HostInteropNode struct {
 		Target *Node
 		MOrF   *lang.Symbol
+		GoTry  bool
 	}
 
 	LetFnNode struct {
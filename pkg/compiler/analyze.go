@@ -28,7 +28,9 @@ type (
 	Env IPersistentMap
 
 	Analyzer struct {
-		Macroexpand1 func(form interface{}) (interface{}, error)
+		// Macroexpand1 expands form once if it is a macro call. env is
+		// the analyzer environment of the form; see MacroEnv.
+		Macroexpand1 func(form interface{}, env Env) (interface{}, error)
 		CreateVar    func(sym *Symbol, env Env) (interface{}, error)
 		IsVar        func(v interface{}) bool
 
@@ -44,6 +46,48 @@ func (a *Analyzer) Analyze(form interface{}, env Env) (*ast.Node, error) {
 	return a.analyzeForm(form, ctxEnv(env, ctxExpr).Assoc(KWTopLevel, true).(Env))
 }
 
+// MacroEnv returns the value of the hidden &env argument of macros
+// expanded in env: a map from the symbol of each local in scope to a
+// map describing its binding, in the shape of tools.analyzer's
+// :binding nodes. Type hints are available under :tag. MacroEnv
+// returns nil outside of any local scope.
+func MacroEnv(env Env) IPersistentMap {
+	locals, ok := Get(env, KWLocals).(IPersistentMap)
+	if !ok {
+		return nil
+	}
+	res := NewMap()
+	for seq := Seq(locals); seq != nil; seq = seq.Next() {
+		entry := seq.First().(IMapEntry)
+		node, ok := entry.Val().(*ast.Node)
+		if !ok {
+			continue
+		}
+		res = res.Assoc(entry.Key(), bindingInfo(node)).(IPersistentMap)
+	}
+	return res
+}
+
+func bindingInfo(n *ast.Node) IPersistentMap {
+	bn := n.Sub.(*ast.BindingNode)
+	info := NewMap(
+		KWOp, KWBinding,
+		KWName, bn.Name,
+		KWForm, n.Form,
+		KWLocal, bn.Local,
+	)
+	if tag := Get(bn.Name.Meta(), KWTag); tag != nil {
+		info = info.Assoc(KWTag, tag).(IPersistentMap)
+	}
+	if bn.Local == KWArg {
+		info = info.Assoc(KWArgId, bn.ArgID).Assoc(KWIsVariadic, bn.IsVariadic).(IPersistentMap)
+	}
+	if bn.Init != nil {
+		info = info.Assoc(KWInit, bn.Init.Form).(IPersistentMap)
+	}
+	return info
+}
+
 func (a *Analyzer) analyzeForm(form interface{}, env Env) (n *ast.Node, err error) {
 	switch v := form.(type) {
 	case *Symbol:
@@ -64,7 +108,7 @@ func (a *Analyzer) analyzeForm(form interface{}, env Env) (n *ast.Node, err erro
 // analyzeSymbol performs semantic analysis on the given symbol,
 // returning an AST.
 func (a *Analyzer) analyzeSymbol(form *Symbol, env Env) (*ast.Node, error) {
-	mform, err := a.Macroexpand1(form)
+	mform, err := a.Macroexpand1(form, env)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +142,9 @@ func (a *Analyzer) analyzeSymbol(form *Symbol, env Env) (*ast.Node, error) {
 
 		vr, ok := v.(*Var)
 		if ok {
+			if err := a.useVar(vr, env, true); err != nil {
+				return nil, err
+			}
 			m := vr.Meta()
 			n.Op = ast.OpVar
 			n.Sub = &ast.VarNode{
@@ -226,7 +273,7 @@ func (a *Analyzer) analyzeSeq(form ISeq, env Env) (*ast.Node, error) {
 	if op == nil {
 		return nil, exInfo("can't call nil", nil) // TODO: include form and source info
 	}
-	mform, err := a.Macroexpand1(form)
+	mform, err := a.Macroexpand1(form, env)
 	if err != nil {
 		return nil, err
 	}
@@ -425,6 +472,8 @@ func (a *Analyzer) parse(form interface{}, env Env) (*ast.Node, error) {
 		// go-specific forms
 	case "go/go":
 		return a.parseGo(form, env)
+	case "go/try":
+		return a.parseGoTry(form, env)
 	}
 
 	return a.parseInvoke(form, env)
@@ -452,9 +501,10 @@ func (a *Analyzer) parseInvoke(form interface{}, env Env) (*ast.Node, error) {
 	}
 	n := ast.MakeNode(ast.OpInvoke, form)
 	n.Sub = &ast.InvokeNode{
-		Meta: meta,
-		Fn:   fnExpr,
-		Args: argsExprs,
+		Meta:  meta,
+		Fn:    fnExpr,
+		Args:  argsExprs,
+		GoTry: a.goErrorsThrow(env),
 	}
 	return n, nil
 }
@@ -999,6 +1049,7 @@ func (a *Analyzer) parseDot(form interface{}, env Env) (*ast.Node, error) {
 			Target: targetExpr,
 			Method: NewSymbol(First(mOrF).(*Symbol).Name()),
 			Args:   argNodes,
+			GoTry:  a.goErrorsThrow(env),
 		}
 		return n, nil
 	case isField:
@@ -1017,6 +1068,7 @@ func (a *Analyzer) parseDot(form interface{}, env Env) (*ast.Node, error) {
 		n.Sub = &ast.HostInteropNode{
 			Target: targetExpr,
 			MOrF:   NewSymbol(mOrF.(*Symbol).Name()),
+			GoTry:  a.goErrorsThrow(env),
 		}
 		return n, nil
 	}
@@ -1463,6 +1515,54 @@ func (a *Analyzer) parseGo(form interface{}, env Env) (*ast.Node, error) {
 	return n, nil
 }
 
+// parseGoTry analyzes (go/try call), where call is a function
+// invocation or host interop form. The call's trailing Go error
+// result, if any, is thrown instead of returned.
+func (a *Analyzer) parseGoTry(form interface{}, env Env) (*ast.Node, error) {
+	if Count(form) != 2 {
+		return nil, exInfo(fmt.Sprintf("wrong number of args to go/try, had: %d", Count(form)-1), nil)
+	}
+	n, err := a.analyzeForm(second(form), env)
+	if err != nil {
+		return nil, err
+	}
+	if !setGoTry(n) {
+		return nil, exInfo("go/try only supports function calls and host interop", nil)
+	}
+	return withRawForm(n, form), nil
+}
+
+// setGoTry marks a call node so that a trailing Go error result is
+// thrown. It reports whether n is a node that supports this.
+func setGoTry(n *ast.Node) bool {
+	switch sub := n.Sub.(type) {
+	case *ast.InvokeNode:
+		sub.GoTry = true
+	case *ast.HostCallNode:
+		sub.GoTry = true
+	case *ast.HostInteropNode:
+		sub.GoTry = true
+	default:
+		return false
+	}
+	return true
+}
+
+// goErrorsThrow reports whether the namespace being analyzed has
+// opted in to throwing Go errors from all calls with
+// ^{:go-errors :throw} metadata.
+func (a *Analyzer) goErrorsThrow(env Env) bool {
+	nsSym, ok := Get(env, KWNS).(*Symbol)
+	if !ok || a.FindNamespace == nil {
+		return false
+	}
+	ns := a.FindNamespace(nsSym)
+	if ns == nil {
+		return false
+	}
+	return Equals(Get(ns.Meta(), KWGoErrors), KWThrow)
+}
+
 // (defn analyze-fn-method [[params & body :as form] {:keys [locals local] :as env}]
 //
 //	(when-not (vector? params)
@@ -1642,6 +1742,10 @@ func (a *Analyzer) parseVar(form interface{}, env Env) (*ast.Node, error) {
 	if !ok {
 		return nil, exInfo(fmt.Sprintf("expecting var, but %s is mapped to %v", vrSym, maybeVar), nil)
 	}
+	// #'ns/var may refer to private vars.
+	if err := a.useVar(vr, env, false); err != nil {
+		return nil, err
+	}
 	n := ast.MakeNode(ast.OpTheVar, form)
 	n.Env = env
 	n.Sub = &ast.TheVarNode{
@@ -1757,6 +1861,25 @@ func (a *Analyzer) resolveSym(symIfc interface{}, env Env) interface{} {
 	return Get(theNS.Mappings(), name)
 }
 
+// useVar records that the namespace being analyzed refers to vr. If
+// checkPublic is true, references to private vars of other namespaces
+// are rejected.
+func (a *Analyzer) useVar(vr *Var, env Env, checkPublic bool) error {
+	nsSym, _ := Get(env, KWNS).(*Symbol)
+	if nsSym == nil {
+		return nil
+	}
+	ns := a.FindNamespace(nsSym)
+	if ns == nil {
+		return nil
+	}
+	if checkPublic && vr.Namespace() != ns && !vr.IsPublic() {
+		return exInfo(fmt.Sprintf("var: %s is not public", vr), nil)
+	}
+	ns.AddUsedVar(vr)
+	return nil
+}
+
 // (defn validate-bindings
 //
 //	[[op bindings & _ :as form] env]
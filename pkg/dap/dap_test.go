@@ -0,0 +1,366 @@
+package dap_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glojurelang/glojure/pkg/dap"
+	_ "github.com/glojurelang/glojure/pkg/glj"
+	"github.com/glojurelang/glojure/pkg/lang"
+)
+
+const program = `(ns dap.prog)
+
+(defn square [x]
+  (let [y (* x x)]
+    (+ y 0)))
+
+(defn run [xs]
+  (let [total (reduce + (map square xs))]
+    (println "total" total)
+    total))
+
+(run [1 2 3])
+`
+
+// client is a debug adapter client for tests, with its own framing of
+// messages.
+type client struct {
+	t    *testing.T
+	w    io.WriteCloser
+	msgs chan map[string]interface{}
+	seq  int
+	// events holds the events received while waiting for responses.
+	events []map[string]interface{}
+	// output holds the output of the program, by category.
+	output map[string]string
+}
+
+// startServer starts a server for the client it returns, which has
+// initialized it and launched the program in src, written to a
+// temporary file whose path it returns.
+func startServer(t *testing.T, src string, launch map[string]interface{}) (*client, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "prog.glj")
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &client{t: t, w: inW, msgs: make(chan map[string]interface{}, 100), output: map[string]string{}}
+	go c.readAll(bufio.NewReader(outR))
+	done := make(chan error, 1)
+	go func() {
+		err := dap.NewServer(lang.GlobalEnv).Serve(inR, outW)
+		outW.Close()
+		done <- err
+	}()
+	t.Cleanup(func() {
+		inW.Close()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	})
+
+	res := c.request("initialize", map[string]interface{}{"adapterID": "glj"})
+	if res["supportsConfigurationDoneRequest"] != true {
+		t.Fatalf("unexpected capabilities %v", res)
+	}
+	c.event("initialized")
+	launch["program"] = path
+	c.request("launch", launch)
+	return c, path
+}
+
+func (c *client) write(msg map[string]interface{}) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// readAll reads the messages of the server until it ends.
+func (c *client) readAll(r *bufio.Reader) {
+	defer close(c.msgs)
+	for {
+		length := -1
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			if line == "" {
+				break
+			}
+			if strings.HasPrefix(line, "Content-Length: ") {
+				length, _ = strconv.Atoi(strings.TrimPrefix(line, "Content-Length: "))
+			}
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return
+		}
+		c.msgs <- msg
+	}
+}
+
+// next returns the next message of the server.
+func (c *client) next() map[string]interface{} {
+	c.t.Helper()
+	select {
+	case msg, ok := <-c.msgs:
+		if !ok {
+			c.t.Fatal("server closed the connection")
+		}
+		return msg
+	case <-time.After(30 * time.Second):
+		c.t.Fatal("timed out waiting for the server")
+	}
+	return nil
+}
+
+// send sends a request and returns its response.
+func (c *client) send(command string, args interface{}) map[string]interface{} {
+	c.t.Helper()
+	c.seq++
+	c.write(map[string]interface{}{"seq": c.seq, "type": "request", "command": command, "arguments": args})
+	for {
+		msg := c.next()
+		if msg["type"] == "event" {
+			c.events = append(c.events, msg)
+			continue
+		}
+		if msg["request_seq"] != float64(c.seq) {
+			c.t.Fatalf("unexpected message %v", msg)
+		}
+		return msg
+	}
+}
+
+// request sends a request that must succeed, and returns the body of
+// its response.
+func (c *client) request(command string, args interface{}) map[string]interface{} {
+	c.t.Helper()
+	resp := c.send(command, args)
+	if resp["success"] != true {
+		c.t.Fatalf("%s failed: %v", command, resp["message"])
+	}
+	body, _ := resp["body"].(map[string]interface{})
+	return body
+}
+
+// event returns the body of the next event with the given name,
+// skipping other events but output events, whose output it records.
+func (c *client) event(name string) map[string]interface{} {
+	c.t.Helper()
+	for {
+		var msg map[string]interface{}
+		if len(c.events) > 0 {
+			msg, c.events = c.events[0], c.events[1:]
+		} else {
+			msg = c.next()
+		}
+		if msg["type"] != "event" {
+			c.t.Fatalf("unexpected message %v", msg)
+		}
+		body, _ := msg["body"].(map[string]interface{})
+		if msg["event"] == name {
+			return body
+		}
+		if msg["event"] == "output" {
+			c.output[body["category"].(string)] += body["output"].(string)
+		}
+	}
+}
+
+// stopped waits for the program to stop for reason, and returns the
+// ID of the thread that stopped and its stack frames.
+func (c *client) stopped(reason string) (float64, []map[string]interface{}) {
+	c.t.Helper()
+	body := c.event("stopped")
+	if body["reason"] != reason {
+		c.t.Fatalf("expected to stop for %s, got %v", reason, body)
+	}
+	thread := body["threadId"].(float64)
+	var frames []map[string]interface{}
+	for _, f := range c.request("stackTrace", map[string]interface{}{"threadId": thread})["stackFrames"].([]interface{}) {
+		frames = append(frames, f.(map[string]interface{}))
+	}
+	return thread, frames
+}
+
+// variables returns the variables of a reference, as name=value.
+func (c *client) variables(ref interface{}) (string, map[string]float64) {
+	c.t.Helper()
+	var parts []string
+	refs := map[string]float64{}
+	for _, v := range c.request("variables", map[string]interface{}{"variablesReference": ref})["variables"].([]interface{}) {
+		v := v.(map[string]interface{})
+		parts = append(parts, fmt.Sprintf("%s=%s", v["name"], v["value"]))
+		refs[v["name"].(string)] = v["variablesReference"].(float64)
+	}
+	return strings.Join(parts, " "), refs
+}
+
+// locals returns the locals of a frame, as name=value.
+func (c *client) locals(frame map[string]interface{}) (string, map[string]float64) {
+	c.t.Helper()
+	scopes := c.request("scopes", map[string]interface{}{"frameId": frame["id"]})["scopes"].([]interface{})
+	return c.variables(scopes[0].(map[string]interface{})["variablesReference"])
+}
+
+func checkFrame(t *testing.T, frame map[string]interface{}, name string, line int) {
+	t.Helper()
+	if frame["name"] != name || frame["line"] != float64(line) {
+		t.Fatalf("expected to be in %s at line %d, got %v", name, line, frame)
+	}
+}
+
+func TestBreakpoints(t *testing.T) {
+	c, path := startServer(t, program, map[string]interface{}{})
+	res := c.request("setBreakpoints", map[string]interface{}{
+		"source":      map[string]interface{}{"path": path},
+		"breakpoints": []interface{}{map[string]interface{}{"line": 5}},
+	})
+	if bps := res["breakpoints"].([]interface{}); len(bps) != 1 || bps[0].(map[string]interface{})["verified"] != true {
+		t.Errorf("unexpected breakpoints %v", res)
+	}
+	c.request("setExceptionBreakpoints", map[string]interface{}{"filters": []string{}})
+	c.request("configurationDone", nil)
+
+	thread, frames := c.stopped("breakpoint")
+	threads := c.request("threads", nil)["threads"].([]interface{})
+	if len(threads) != 1 || threads[0].(map[string]interface{})["id"] != thread {
+		t.Errorf("unexpected threads %v", threads)
+	}
+	checkFrame(t, frames[0], "dap.prog/square", 5)
+	checkFrame(t, frames[1], "dap.prog/run", 8)
+	if source := frames[0]["source"].(map[string]interface{}); source["path"] != path {
+		t.Errorf("expected the source %s, got %v", path, source)
+	}
+	if locals, _ := c.locals(frames[0]); locals != "x=1 y=1" {
+		t.Errorf("unexpected locals of square: %s", locals)
+	}
+	locals, refs := c.locals(frames[1])
+	if locals != "xs=[1 2 3]" {
+		t.Errorf("unexpected locals of run: %s", locals)
+	}
+	if elems, _ := c.variables(refs["xs"]); elems != "0=1 1=2 2=3" {
+		t.Errorf("unexpected elements of xs: %s", elems)
+	}
+	res = c.request("evaluate", map[string]interface{}{"expression": "(* y 10)", "frameId": frames[0]["id"], "context": "repl"})
+	if res["result"] != "10" {
+		t.Errorf("expected 10, got %v", res)
+	}
+	res = c.request("evaluate", map[string]interface{}{"expression": "{:xs xs}", "frameId": frames[1]["id"], "context": "watch"})
+	if res["result"] != "{:xs [1 2 3]}" || res["variablesReference"] == float64(0) {
+		t.Errorf("unexpected result %v", res)
+	}
+	if resp := c.send("evaluate", map[string]interface{}{"expression": "(inc nil)", "frameId": frames[0]["id"]}); resp["success"] != false {
+		t.Errorf("expected the evaluation to fail, got %v", resp)
+	}
+
+	c.request("continue", map[string]interface{}{"threadId": thread})
+	_, frames = c.stopped("breakpoint")
+	if locals, _ := c.locals(frames[0]); locals != "x=2 y=4" {
+		t.Errorf("unexpected locals of square: %s", locals)
+	}
+	c.request("setBreakpoints", map[string]interface{}{"source": map[string]interface{}{"path": path}, "breakpoints": []interface{}{}})
+	c.request("continue", map[string]interface{}{"threadId": thread})
+
+	if exited := c.event("exited"); exited["exitCode"] != float64(0) {
+		t.Errorf("unexpected exit %v", exited)
+	}
+	if out := c.output["stdout"]; out != "total 14\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+	c.event("terminated")
+	c.request("disconnect", nil)
+}
+
+func TestStepping(t *testing.T) {
+	c, path := startServer(t, program, map[string]interface{}{"stopOnEntry": true})
+	c.request("configurationDone", nil)
+
+	thread, frames := c.stopped("entry")
+	checkFrame(t, frames[0], "user", 1)
+	c.request("next", map[string]interface{}{"threadId": thread})
+	_, frames = c.stopped("step")
+	checkFrame(t, frames[0], "dap.prog", 3)
+
+	c.request("setBreakpoints", map[string]interface{}{"source": map[string]interface{}{"path": path}, "lines": []int{8}})
+	c.request("continue", map[string]interface{}{"threadId": thread})
+	thread, frames = c.stopped("breakpoint")
+	checkFrame(t, frames[0], "dap.prog/run", 8)
+	c.request("stepIn", map[string]interface{}{"threadId": thread})
+	_, frames = c.stopped("step")
+	checkFrame(t, frames[0], "dap.prog/square", 4)
+	c.request("next", map[string]interface{}{"threadId": thread})
+	_, frames = c.stopped("step")
+	checkFrame(t, frames[0], "dap.prog/square", 5)
+	c.request("stepOut", map[string]interface{}{"threadId": thread})
+	_, frames = c.stopped("step")
+	checkFrame(t, frames[0], "dap.prog/run", 9)
+	if locals, _ := c.locals(frames[0]); locals != "xs=[1 2 3] total=14" {
+		t.Errorf("unexpected locals of run: %s", locals)
+	}
+
+	// the references to the frames of a thread are dropped when it
+	// resumes.
+	c.request("continue", map[string]interface{}{"threadId": thread})
+	if resp := c.send("scopes", map[string]interface{}{"frameId": frames[0]["id"]}); resp["success"] != false {
+		t.Errorf("expected scopes of a resumed frame to fail, got %v", resp)
+	}
+	c.event("terminated")
+}
+
+func TestErrors(t *testing.T) {
+	c, _ := startServer(t, `(ns dap.fail)
+
+(defn fail [n]
+  (throw (errors.New (str "boom " n))))
+
+(try (fail 1) (catch go/error e nil))
+(fail 2)
+`, map[string]interface{}{})
+	c.request("setExceptionBreakpoints", map[string]interface{}{"filters": []string{"uncaught"}})
+	c.request("configurationDone", nil)
+
+	// only the uncaught error stops the program.
+	thread, frames := c.stopped("exception")
+	checkFrame(t, frames[0], "dap.fail", 7)
+	c.request("continue", map[string]interface{}{"threadId": thread})
+	if exited := c.event("exited"); exited["exitCode"] != float64(1) {
+		t.Errorf("unexpected exit %v", exited)
+	}
+	if out := c.output["stderr"]; !strings.Contains(out, "boom 2") {
+		t.Errorf("expected the error on stderr, got %q", out)
+	}
+	c.event("terminated")
+
+	res := c.request("evaluate", map[string]interface{}{"expression": "(+ 1 2)", "context": "repl"})
+	if res["result"] != "3" {
+		t.Errorf("expected 3, got %v", res)
+	}
+	if resp := c.send("restartFrame", map[string]interface{}{}); resp["success"] != false {
+		t.Errorf("expected an unsupported command to fail, got %v", resp)
+	}
+	c.request("disconnect", nil)
+}
@@ -0,0 +1,41 @@
+package dap
+
+import "encoding/json"
+
+// message is a request received by the server.
+type message struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// response is the response to a request. Its body is omitted when it
+// is nil.
+type response struct {
+	Seq        int         `json:"seq"`
+	Type       string      `json:"type"`
+	RequestSeq int         `json:"request_seq"`
+	Success    bool        `json:"success"`
+	Command    string      `json:"command"`
+	Message    string      `json:"message,omitempty"`
+	Body       interface{} `json:"body,omitempty"`
+}
+
+// event is a message sent by the server that isn't a response.
+type event struct {
+	Seq   int         `json:"seq"`
+	Type  string      `json:"type"`
+	Event string      `json:"event"`
+	Body  interface{} `json:"body,omitempty"`
+}
+
+// errorBody is the body of the response to a request that failed.
+type errorBody struct {
+	Error errorMessage `json:"error"`
+}
+
+type errorMessage struct {
+	ID     int    `json:"id"`
+	Format string `json:"format"`
+}
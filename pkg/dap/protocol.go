@@ -0,0 +1,132 @@
+package dap
+
+// The types of the Debug Adapter Protocol used by the server, with
+// only the fields it reads or writes. See
+// https://microsoft.github.io/debug-adapter-protocol/specification.
+
+type (
+	// Capabilities are the features supported by the server, sent in
+	// response to initialize.
+	Capabilities struct {
+		SupportsConfigurationDoneRequest bool                        `json:"supportsConfigurationDoneRequest"`
+		SupportsEvaluateForHovers        bool                        `json:"supportsEvaluateForHovers"`
+		ExceptionBreakpointFilters       []ExceptionBreakpointFilter `json:"exceptionBreakpointFilters"`
+	}
+
+	// ExceptionBreakpointFilter is a kind of error the client can ask the
+	// debugger to break on.
+	ExceptionBreakpointFilter struct {
+		Filter  string `json:"filter"`
+		Label   string `json:"label"`
+		Default bool   `json:"default"`
+	}
+
+	// LaunchArguments are the arguments of the launch request. Either
+	// Program, the path of a file to load, or Main, the name of a
+	// namespace whose -main function to call, must be set.
+	LaunchArguments struct {
+		Program     string   `json:"program"`
+		Main        string   `json:"main"`
+		Args        []string `json:"args"`
+		Cwd         string   `json:"cwd"`
+		StopOnEntry bool     `json:"stopOnEntry"`
+	}
+
+	Source struct {
+		Name            string `json:"name,omitempty"`
+		Path            string `json:"path,omitempty"`
+		SourceReference int    `json:"sourceReference,omitempty"`
+	}
+
+	SourceBreakpoint struct {
+		Line int `json:"line"`
+	}
+
+	SetBreakpointsArguments struct {
+		Source      Source             `json:"source"`
+		Breakpoints []SourceBreakpoint `json:"breakpoints"`
+		// Lines is the deprecated form of Breakpoints.
+		Lines []int `json:"lines"`
+	}
+
+	Breakpoint struct {
+		Verified bool    `json:"verified"`
+		Line     int     `json:"line"`
+		Source   *Source `json:"source,omitempty"`
+	}
+
+	SetExceptionBreakpointsArguments struct {
+		Filters []string `json:"filters"`
+	}
+
+	Thread struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	StackTraceArguments struct {
+		ThreadID   int `json:"threadId"`
+		StartFrame int `json:"startFrame"`
+		Levels     int `json:"levels"`
+	}
+
+	StackFrame struct {
+		ID     int     `json:"id"`
+		Name   string  `json:"name"`
+		Source *Source `json:"source,omitempty"`
+		Line   int     `json:"line"`
+		Column int     `json:"column"`
+	}
+
+	ScopesArguments struct {
+		FrameID int `json:"frameId"`
+	}
+
+	Scope struct {
+		Name               string `json:"name"`
+		PresentationHint   string `json:"presentationHint,omitempty"`
+		VariablesReference int    `json:"variablesReference"`
+		Expensive          bool   `json:"expensive"`
+	}
+
+	VariablesArguments struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+
+	Variable struct {
+		Name               string `json:"name"`
+		Value              string `json:"value"`
+		Type               string `json:"type,omitempty"`
+		VariablesReference int    `json:"variablesReference"`
+	}
+
+	// ThreadArguments are the arguments of the requests that resume or
+	// pause a thread.
+	ThreadArguments struct {
+		ThreadID int `json:"threadId"`
+	}
+
+	EvaluateArguments struct {
+		Expression string `json:"expression"`
+		FrameID    int    `json:"frameId"`
+		Context    string `json:"context"`
+	}
+
+	SourceArguments struct {
+		Source          *Source `json:"source"`
+		SourceReference int     `json:"sourceReference"`
+	}
+
+	StoppedEvent struct {
+		Reason            string `json:"reason"`
+		Description       string `json:"description,omitempty"`
+		ThreadID          int    `json:"threadId"`
+		Text              string `json:"text,omitempty"`
+		AllThreadsStopped bool   `json:"allThreadsStopped"`
+	}
+
+	OutputEvent struct {
+		Category string `json:"category"`
+		Output   string `json:"output"`
+	}
+)
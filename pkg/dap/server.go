@@ -0,0 +1,608 @@
+// Package dap implements a Debug Adapter Protocol server
+// (https://microsoft.github.io/debug-adapter-protocol) for Glojure, so
+// that editors can run Glojure programs under the debugger of package
+// runtime.
+//
+// Messages are JSON requests, responses and events, framed by a
+// Content-Length header, exchanged over a pair of streams, typically
+// the standard input and output of glj dap. The server launches one
+// program, a file to load or a namespace whose -main function to call,
+// once the client is done configuring breakpoints. Its output to *out*
+// and *err* is sent to the client as output events.
+//
+// The server supports breakpoints by line, breaking on raised and
+// uncaught errors, pausing, stepping in, over and out, inspecting the
+// stacks and locals of stopped threads, and evaluating expressions in
+// their frames.
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/glojurelang/glojure/internal/framing"
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// The exception breakpoint filters of the server.
+const (
+	filterRaised   = "raised"
+	filterUncaught = "uncaught"
+)
+
+// Server is a debug adapter running a program in an environment.
+type Server struct {
+	env value.Environment
+	d   *runtime.Debugger
+
+	// mu guards the writes of messages to w, and seq, the sequence
+	// number of the last message.
+	mu  sync.Mutex
+	w   io.Writer
+	seq int
+
+	launch     *LaunchArguments
+	configured bool
+	started    bool
+	// entry is set until the program stops on entry, if it was
+	// launched to.
+	entry atomic.Bool
+
+	// refs maps the references to frames and variables sent to the
+	// client to what they refer to. The references to a thread are
+	// dropped when it is resumed.
+	refs    map[int]*ref
+	nextRef int
+	// sources holds the files the client can request the source of by
+	// reference, the reference of a file being its index plus one.
+	sources []string
+}
+
+// ref is a frame of a stopped thread, or the locals of such a frame
+// or a collection to show as variables.
+type ref struct {
+	thread *runtime.Thread
+	frame  int
+	// vars holds the named values of a variable reference.
+	vars func() []namedValue
+}
+
+type namedValue struct {
+	name string
+	val  interface{}
+}
+
+// NewServer returns a server that runs programs in env.
+func NewServer(env value.Environment) *Server {
+	s := &Server{env: env, refs: map[int]*ref{}}
+	s.d = runtime.NewDebugger(s.stopped)
+	return s
+}
+
+// Serve reads requests from r and writes responses and events to w
+// until the client disconnects or r ends, which it returns an error
+// for if it fails. The debugger of the server is attached to its
+// environment while it serves, and detached when it returns, which
+// resumes the threads it stopped.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.w = w
+	runtime.SetDebugger(s.env, s.d)
+	defer runtime.SetDebugger(s.env, nil)
+
+	br := bufio.NewReader(r)
+	for {
+		body, err := framing.Read(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return fmt.Errorf("invalid message: %w", err)
+		}
+		if msg.Type != "request" {
+			continue
+		}
+		if msg.Command == "evaluate" {
+			// an evaluation outside of a stopped frame may take long,
+			// or stop at a breakpoint, so it doesn't block other
+			// requests.
+			var args EvaluateArguments
+			if err := json.Unmarshal(msg.Arguments, &args); err == nil && args.FrameID == 0 {
+				go func(msg message) {
+					body, err := s.evaluateGlobal(args)
+					s.reply(&msg, body, err)
+				}(msg)
+				continue
+			}
+		}
+		result, err := s.handle(&msg)
+		if err := s.reply(&msg, result, err); err != nil {
+			return err
+		}
+		switch msg.Command {
+		case "initialize":
+			s.event("initialized", nil)
+		case "launch", "configurationDone":
+			s.start()
+		case "disconnect":
+			return nil
+		}
+	}
+}
+
+// reply writes the response to the request msg, which failed if err
+// is set.
+func (s *Server) reply(msg *message, body interface{}, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	resp := response{Seq: s.seq, Type: "response", RequestSeq: msg.Seq, Command: msg.Command, Success: err == nil, Body: body}
+	if err != nil {
+		resp.Message = err.Error()
+		resp.Body = errorBody{Error: errorMessage{ID: 1, Format: err.Error()}}
+	}
+	return framing.Write(s.w, resp)
+}
+
+// event writes an event to the client.
+func (s *Server) event(name string, body interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	return framing.Write(s.w, event{Seq: s.seq, Type: "event", Event: name, Body: body})
+}
+
+// handle handles a request, converting a panic to an error.
+func (s *Server) handle(msg *message) (body interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			body, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+
+	switch msg.Command {
+	case "initialize":
+		body = Capabilities{
+			SupportsConfigurationDoneRequest: true,
+			SupportsEvaluateForHovers:        true,
+			ExceptionBreakpointFilters: []ExceptionBreakpointFilter{
+				{Filter: filterRaised, Label: "Raised Errors"},
+				{Filter: filterUncaught, Label: "Uncaught Errors", Default: true},
+			},
+		}
+	case "launch":
+		var args LaunchArguments
+		if err = unmarshalArgs(msg.Arguments, &args); err == nil {
+			err = s.setLaunch(args)
+		}
+	case "configurationDone":
+		s.configured = true
+	case "setBreakpoints":
+		var args SetBreakpointsArguments
+		if err = unmarshalArgs(msg.Arguments, &args); err == nil {
+			body, err = s.setBreakpoints(args)
+		}
+	case "setExceptionBreakpoints":
+		var args SetExceptionBreakpointsArguments
+		if err = unmarshalArgs(msg.Arguments, &args); err == nil {
+			s.setExceptionBreakpoints(args)
+		}
+	case "threads":
+		body = s.threads()
+	case "stackTrace":
+		var args StackTraceArguments
+		if err = unmarshalArgs(msg.Arguments, &args); err == nil {
+			body, err = s.stackTrace(args)
+		}
+	case "scopes":
+		var args ScopesArguments
+		if err = unmarshalArgs(msg.Arguments, &args); err == nil {
+			body, err = s.scopes(args)
+		}
+	case "variables":
+		var args VariablesArguments
+		if err = unmarshalArgs(msg.Arguments, &args); err == nil {
+			body, err = s.variables(args)
+		}
+	case "continue", "next", "stepIn", "stepOut":
+		var args ThreadArguments
+		if err = unmarshalArgs(msg.Arguments, &args); err == nil {
+			err = s.resume(msg.Command, args)
+		}
+		if err == nil && msg.Command == "continue" {
+			body = map[string]interface{}{"allThreadsContinued": false}
+		}
+	case "pause":
+		s.d.Pause()
+	case "evaluate":
+		var args EvaluateArguments
+		if err = unmarshalArgs(msg.Arguments, &args); err == nil {
+			body, err = s.evaluate(args)
+		}
+	case "source":
+		var args SourceArguments
+		if err = unmarshalArgs(msg.Arguments, &args); err == nil {
+			body, err = s.source(args)
+		}
+	case "disconnect":
+	default:
+		err = fmt.Errorf("unsupported command %q", msg.Command)
+	}
+	return body, err
+}
+
+// unmarshalArgs decodes the arguments of a request into v.
+func unmarshalArgs(args json.RawMessage, v interface{}) error {
+	if len(args) == 0 {
+		return nil
+	}
+	return json.Unmarshal(args, v)
+}
+
+// setLaunch records the program to launch, once the configuration is
+// done, changing to its working directory.
+func (s *Server) setLaunch(args LaunchArguments) error {
+	switch {
+	case s.launch != nil:
+		return errors.New("a program was launched already")
+	case args.Program == "" && args.Main == "":
+		return errors.New("program or main must be set")
+	}
+	if args.Cwd != "" {
+		if err := os.Chdir(args.Cwd); err != nil {
+			return err
+		}
+	}
+	s.launch = &args
+	return nil
+}
+
+// start starts the program, in a new goroutine, once it was launched
+// and the configuration is done.
+func (s *Server) start() {
+	if s.launch == nil || !s.configured || s.started {
+		return
+	}
+	s.started = true
+	if s.launch.StopOnEntry {
+		s.entry.Store(true)
+		s.d.Pause()
+	}
+	go s.run(*s.launch)
+}
+
+// run runs the program, sending its output to the client, and tells
+// the client when it exits.
+func (s *Server) run(args LaunchArguments) {
+	stdout, stderr := &output{s: s, category: "stdout"}, &output{s: s, category: "stderr"}
+	kvs := []interface{}{value.VarOut, stdout, value.VarErr, stderr}
+	for _, vr := range []*value.Var{value.VarCurrentNS, value.VarWarnOnReflection, value.VarUncheckedMath, value.VarDataReaders} {
+		kvs = append(kvs, vr, vr.Deref())
+	}
+	value.PushThreadBindings(value.NewMap(kvs...))
+	defer value.PopThreadBindings()
+
+	exitCode := 0
+	if err := s.runProgram(args); err != nil {
+		runtime.PrintError(stderr, err)
+		exitCode = 1
+	}
+	s.event("exited", map[string]interface{}{"exitCode": exitCode})
+	s.event("terminated", nil)
+}
+
+// runProgram loads the file of the program, or calls the -main
+// function of its namespace, as glj does.
+func (s *Server) runProgram(args LaunchArguments) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if err, ok = r.(error); !ok {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+
+	if _, err := s.env.Eval(value.NewList(value.NewSymbol("ns"), value.NewSymbol("user"))); err != nil {
+		return err
+	}
+	var cmdArgs interface{}
+	if len(args.Args) > 0 {
+		cmdArgs = value.Seq(args.Args)
+	}
+	value.NSCore.FindInternedVar(value.NewSymbol("*command-line-args*")).BindRoot(cmdArgs)
+
+	if args.Main == "" {
+		f, err := os.Open(args.Program)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return runtime.LoadReader(s.env, f, args.Program)
+	}
+	sym := value.NewSymbol(args.Main)
+	if _, err := s.env.Eval(value.NewList(value.NewSymbol("require"), value.NewList(value.NewSymbol("quote"), sym))); err != nil {
+		return err
+	}
+	vr := value.FindNamespace(sym).FindInternedVar(value.NewSymbol("-main"))
+	if vr == nil || !vr.IsBound() {
+		return fmt.Errorf("namespace %s has no -main function", args.Main)
+	}
+	fnArgs := make([]interface{}, len(args.Args))
+	for i, arg := range args.Args {
+		fnArgs[i] = arg
+	}
+	value.Apply(vr, fnArgs)
+	return nil
+}
+
+// output is a writer sending what is written to it to the client as
+// output events of a category.
+type output struct {
+	s        *Server
+	category string
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	if err := o.s.event("output", OutputEvent{Category: o.category, Output: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// stopped tells the client that th stopped.
+func (s *Server) stopped(th *runtime.Thread) {
+	ev := StoppedEvent{Reason: th.Reason(), ThreadID: th.ID()}
+	switch th.Reason() {
+	case runtime.StopPause:
+		if s.entry.CompareAndSwap(true, false) {
+			ev.Reason = "entry"
+		}
+	case runtime.StopException:
+		ev.Description = "Paused on error"
+		ev.Text = th.Err().Error()
+	}
+	s.event("stopped", ev)
+}
+
+func (s *Server) setBreakpoints(args SetBreakpointsArguments) (interface{}, error) {
+	path := args.Source.Path
+	if path == "" {
+		if ref := args.Source.SourceReference; ref > 0 && ref <= len(s.sources) {
+			path = s.sources[ref-1]
+		} else {
+			return nil, errors.New("source has no path")
+		}
+	}
+	lines := args.Lines
+	if args.Breakpoints != nil {
+		lines = nil
+		for _, bp := range args.Breakpoints {
+			lines = append(lines, bp.Line)
+		}
+	}
+	s.d.SetBreakpoints(path, lines)
+
+	// Breakpoints can't be verified before the code is compiled.
+	breakpoints := make([]Breakpoint, 0, len(lines))
+	for _, line := range lines {
+		source := args.Source
+		breakpoints = append(breakpoints, Breakpoint{Verified: true, Line: line, Source: &source})
+	}
+	return map[string]interface{}{"breakpoints": breakpoints}, nil
+}
+
+func (s *Server) setExceptionBreakpoints(args SetExceptionBreakpointsArguments) {
+	var raised, uncaught bool
+	for _, filter := range args.Filters {
+		switch filter {
+		case filterRaised:
+			raised = true
+		case filterUncaught:
+			uncaught = true
+		}
+	}
+	s.d.SetBreakOnError(raised, uncaught)
+}
+
+func (s *Server) threads() interface{} {
+	threads := []Thread{}
+	for _, th := range s.d.Threads() {
+		threads = append(threads, Thread{ID: th.ID(), Name: fmt.Sprintf("thread %d", th.ID())})
+	}
+	return map[string]interface{}{"threads": threads}
+}
+
+// thread returns the stopped thread with the given ID.
+func (s *Server) thread(id int) (*runtime.Thread, error) {
+	th := s.d.Thread(id)
+	if th == nil {
+		return nil, fmt.Errorf("no thread %d", id)
+	}
+	return th, nil
+}
+
+// resume resumes a thread as the command asks, dropping the references
+// to its frames and variables.
+func (s *Server) resume(command string, args ThreadArguments) error {
+	th, err := s.thread(args.ThreadID)
+	if err != nil {
+		return err
+	}
+	for id, r := range s.refs {
+		if r.thread == th {
+			delete(s.refs, id)
+		}
+	}
+	switch command {
+	case "next":
+		return th.StepOver()
+	case "stepIn":
+		return th.StepIn()
+	case "stepOut":
+		return th.StepOut()
+	}
+	return th.Continue()
+}
+
+// newRef returns a new reference to r.
+func (s *Server) newRef(r *ref) int {
+	s.nextRef++
+	s.refs[s.nextRef] = r
+	return s.nextRef
+}
+
+func (s *Server) stackTrace(args StackTraceArguments) (interface{}, error) {
+	th, err := s.thread(args.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	stack := th.Stack()
+	frames := []StackFrame{}
+	for i := args.StartFrame; i < len(stack); i++ {
+		if args.Levels > 0 && len(frames) == args.Levels {
+			break
+		}
+		sf := stack[i]
+		name := sf.Namespace
+		if sf.FunctionName != "" {
+			name += "/" + sf.FunctionName
+		}
+		frames = append(frames, StackFrame{
+			ID:     s.newRef(&ref{thread: th, frame: i}),
+			Name:   name,
+			Source: s.sourceOf(sf.Filename),
+			Line:   sf.Line,
+			Column: sf.Column,
+		})
+	}
+	return map[string]interface{}{"stackFrames": frames, "totalFrames": len(stack)}, nil
+}
+
+// frame returns the frame with the given reference.
+func (s *Server) frame(id int) (*ref, error) {
+	r := s.refs[id]
+	if r == nil || r.vars != nil {
+		return nil, fmt.Errorf("no frame %d", id)
+	}
+	return r, nil
+}
+
+func (s *Server) scopes(args ScopesArguments) (interface{}, error) {
+	fr, err := s.frame(args.FrameID)
+	if err != nil {
+		return nil, err
+	}
+	th, frame := fr.thread, fr.frame
+	locals := &ref{thread: th, frame: frame, vars: func() []namedValue {
+		var vars []namedValue
+		for _, l := range th.Stack()[frame].Locals {
+			vars = append(vars, namedValue{name: l.Name, val: l.Value})
+		}
+		return vars
+	}}
+	return map[string]interface{}{"scopes": []Scope{
+		{Name: "Locals", PresentationHint: "locals", VariablesReference: s.newRef(locals)},
+	}}, nil
+}
+
+func (s *Server) variables(args VariablesArguments) (interface{}, error) {
+	r := s.refs[args.VariablesReference]
+	if r == nil || r.vars == nil {
+		return nil, fmt.Errorf("no variables %d", args.VariablesReference)
+	}
+	vars, err := s.toVariables(r.thread, r.frame, r.vars)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"variables": vars}, nil
+}
+
+func (s *Server) evaluate(args EvaluateArguments) (interface{}, error) {
+	fr, err := s.frame(args.FrameID)
+	if err != nil {
+		return nil, err
+	}
+	stack := fr.thread.Stack()
+	form, err := s.read(args.Expression, stack[fr.frame].Namespace)
+	if err != nil {
+		return nil, err
+	}
+	res, err := fr.thread.Eval(fr.frame, form)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := s.toVariables(fr.thread, fr.frame, func() []namedValue {
+		return []namedValue{{val: res}}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return evaluateBody(vars[0]), nil
+}
+
+// evaluateGlobal evaluates an expression in the user namespace, in
+// the goroutine calling it, outside of any frame.
+func (s *Server) evaluateGlobal(args EvaluateArguments) (body interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			body, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+
+	ns := value.FindOrCreateNamespace(value.NewSymbol("user"))
+	value.PushThreadBindings(value.NewMap(
+		value.VarCurrentNS, ns,
+		value.VarOut, &output{s: s, category: "stdout"},
+		value.VarErr, &output{s: s, category: "stderr"},
+	))
+	defer value.PopThreadBindings()
+
+	form, err := s.read(args.Expression, ns.Name().Name())
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.env.Eval(form)
+	if err != nil {
+		return nil, err
+	}
+	// values evaluated outside of a stopped thread can't be expanded,
+	// since realizing them could stop at a breakpoint.
+	return evaluateBody(Variable{Value: printValue(res), Type: typeName(res)}), nil
+}
+
+func evaluateBody(v Variable) interface{} {
+	return map[string]interface{}{
+		"result":             v.Value,
+		"type":               v.Type,
+		"variablesReference": v.VariablesReference,
+	}
+}
+
+// read reads the first form of expr, resolving the symbols of syntax
+// quotes in the namespace named ns.
+func (s *Server) read(expr, ns string) (interface{}, error) {
+	getNS := func() *value.Namespace {
+		if n := value.FindNamespace(value.NewSymbol(ns)); n != nil {
+			return n
+		}
+		return s.env.CurrentNamespace()
+	}
+	form, err := reader.New(strings.NewReader(expr), reader.WithGetCurrentNS(getNS)).ReadOne()
+	if err == reader.ErrEOF {
+		return nil, errors.New("no expression to evaluate")
+	}
+	return form, err
+}
@@ -0,0 +1,141 @@
+package dap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+const (
+	// maxChildren is the number of elements of a collection shown when
+	// it is expanded.
+	maxChildren = 100
+	// printLength and printLevel bound the printed values of
+	// variables, as *print-length* and *print-level*.
+	printLength = 20
+	printLevel  = 4
+)
+
+// toVariables returns the variables of the named values returned by
+// vars, called in the goroutine of th, the thread they belong to, so
+// that lazy sequences are realized as the thread would realize them.
+// The variables of collections reference their elements.
+func (s *Server) toVariables(th *runtime.Thread, frame int, vars func() []namedValue) ([]Variable, error) {
+	var (
+		named      []namedValue
+		variables  []Variable
+		expandable []bool
+	)
+	err := th.Do(func() {
+		named = vars()
+		for _, nv := range named {
+			variables = append(variables, Variable{Name: nv.name, Value: printValue(nv.val), Type: typeName(nv.val)})
+			expandable = append(expandable, isCollection(nv.val))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, nv := range named {
+		if !expandable[i] {
+			continue
+		}
+		coll := nv.val
+		variables[i].VariablesReference = s.newRef(&ref{thread: th, frame: frame, vars: func() []namedValue {
+			return elements(coll)
+		}})
+	}
+	return variables, nil
+}
+
+// printValue prints v readably, with the length and depth of its
+// collections bounded.
+func printValue(v interface{}) string {
+	kvs := []interface{}{value.VarPrintReadably, true}
+	for name, bound := range map[string]int64{"*print-length*": printLength, "*print-level*": printLevel} {
+		if vr := value.NSCore.FindInternedVar(value.NewSymbol(name)); vr != nil {
+			kvs = append(kvs, vr, bound)
+		}
+	}
+	value.PushThreadBindings(value.NewMap(kvs...))
+	defer value.PopThreadBindings()
+	return value.PrintString(v)
+}
+
+func typeName(v interface{}) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// isCollection reports whether v is a collection whose elements can
+// be shown.
+func isCollection(v interface{}) bool {
+	if value.IsNil(v) {
+		return false
+	}
+	_, ok := v.(value.IPersistentCollection)
+	return ok
+}
+
+// elements returns the first maxChildren elements of coll, named by
+// their keys for maps and by their indexes otherwise.
+func elements(coll interface{}) []namedValue {
+	_, isMap := coll.(value.IPersistentMap)
+	var elems []namedValue
+	for seq := value.Seq(coll); seq != nil && len(elems) < maxChildren; seq = seq.Next() {
+		if isMap {
+			e := seq.First().(value.IMapEntry)
+			elems = append(elems, namedValue{name: printValue(e.Key()), val: e.Val()})
+			continue
+		}
+		elems = append(elems, namedValue{name: strconv.Itoa(len(elems)), val: seq.First()})
+	}
+	return elems
+}
+
+// sourceOf returns the source of the file named by the :file metadata
+// of a form: a path if it is a file relative to the working directory
+// or absolute, or else a reference to its source on the load path.
+func (s *Server) sourceOf(file string) *Source {
+	if file == "" || file == "NO_SOURCE_FILE" {
+		return nil
+	}
+	name := filepath.Base(file)
+	if path, err := filepath.Abs(file); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			return &Source{Name: name, Path: path}
+		}
+	}
+	if _, err := runtime.ReadSourceFile(file); err != nil {
+		return &Source{Name: name}
+	}
+	for i, f := range s.sources {
+		if f == file {
+			return &Source{Name: name, SourceReference: i + 1}
+		}
+	}
+	s.sources = append(s.sources, file)
+	return &Source{Name: name, SourceReference: len(s.sources)}
+}
+
+func (s *Server) source(args SourceArguments) (interface{}, error) {
+	ref := args.SourceReference
+	if args.Source != nil && args.Source.SourceReference != 0 {
+		ref = args.Source.SourceReference
+	}
+	if ref <= 0 || ref > len(s.sources) {
+		return nil, errors.New("unknown source")
+	}
+	src, err := runtime.ReadSourceFile(s.sources[ref-1])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"content": string(src), "mimeType": "text/x-clojure"}, nil
+}
@@ -0,0 +1,402 @@
+// Package format formats Glojure source code in the default style of
+// cljfmt.
+package format
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/glojurelang/glojure/pkg/reader"
+)
+
+// Source formats src, reporting syntax errors with the positions of
+// filename.
+//
+// Formatting changes only whitespace: lines are indented by the rules
+// of cljfmt, whitespace after opening and before closing delimiters is
+// removed, a space is inserted between adjacent forms, trailing
+// whitespace is removed, and consecutive blank lines are collapsed to
+// one.
+func Source(filename string, src []byte) ([]byte, error) {
+	tree, err := reader.ReadSyntax(string(src), reader.WithFilename(filename))
+	if err != nil {
+		return nil, err
+	}
+	return Node(tree), nil
+}
+
+// Node returns the formatted source of the syntax tree n, as read by
+// reader.ReadSyntax.
+func Node(n *reader.SyntaxNode) []byte {
+	var f formatter
+	if n.Kind == reader.SyntaxFile {
+		f.elements(n.Children, &coll{node: n})
+	} else {
+		f.node(n, &coll{node: n}, 0)
+	}
+	return f.buf.Bytes()
+}
+
+type (
+	formatter struct {
+		buf bytes.Buffer
+		// col is the column of the next rune written, from 0.
+		col int
+	}
+
+	// coll is a collection being formatted, or the root of the tree.
+	coll struct {
+		node   *reader.SyntaxNode
+		parent *coll
+		// index is the index of the collection among the forms of its
+		// parent.
+		index int
+		// openCol is the column of the opening delimiter, and innerCol
+		// the column following it.
+		openCol, innerCol int
+		// forms are the forms formatted so far.
+		forms []form
+	}
+
+	form struct {
+		col        int
+		startsLine bool
+		node       *reader.SyntaxNode
+	}
+)
+
+func (f *formatter) write(s string) {
+	f.buf.WriteString(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		f.col = utf8.RuneCountInString(s[i+1:])
+	} else {
+		f.col += utf8.RuneCountInString(s)
+	}
+}
+
+// newline writes n newlines followed by indent spaces.
+func (f *formatter) newline(n, indent int) {
+	f.write(strings.Repeat("\n", n) + strings.Repeat(" ", indent))
+}
+
+func (f *formatter) node(n *reader.SyntaxNode, parent *coll, index int) {
+	switch n.Kind {
+	case reader.SyntaxComment:
+		f.write(strings.TrimRightFunc(n.Text, unicode.IsSpace))
+	case reader.SyntaxList, reader.SyntaxVector, reader.SyntaxMap, reader.SyntaxSet, reader.SyntaxFn:
+		c := &coll{node: n, parent: parent, index: index, openCol: f.col}
+		f.write(n.Children[0].Text)
+		c.innerCol = f.col
+		f.elements(n.Children[1:len(n.Children)-1], c)
+		f.write(n.Children[len(n.Children)-1].Text)
+	default:
+		if n.IsToken() {
+			f.write(n.Text)
+			return
+		}
+		f.prefixed(n, parent, index)
+	}
+}
+
+// elements formats the children of the collection c, between its
+// delimiters.
+func (f *formatter) elements(children []*reader.SyntaxNode, c *coll) {
+	var gap []*reader.SyntaxNode
+	var prev *reader.SyntaxNode
+	for _, child := range children {
+		if child.Kind == reader.SyntaxWhitespace {
+			gap = append(gap, child)
+			continue
+		}
+		startsLine := false
+		switch newlines := countNewlines(gap); {
+		case prev == nil:
+			// whitespace after the opening delimiter is removed.
+		case newlines > 0:
+			f.newline(min(newlines, 2), c.indent())
+			startsLine = true
+		case len(gap) > 0:
+			f.write(text(gap))
+		case prev.Kind != reader.SyntaxComma && child.Kind != reader.SyntaxComma:
+			f.write(" ")
+		}
+		col := f.col
+		f.node(child, c, len(c.forms))
+		if child.IsForm() {
+			c.forms = append(c.forms, form{col: col, startsLine: startsLine, node: child})
+		}
+		prev = child
+		gap = nil
+	}
+
+	// whitespace before the closing delimiter is removed, unless it
+	// ends a comment.
+	switch {
+	case c.node.Kind == reader.SyntaxFile:
+		if prev != nil && countNewlines(gap) > 0 {
+			f.write("\n")
+		}
+	case prev != nil && prev.Kind == reader.SyntaxComment:
+		f.newline(1, c.indent())
+	}
+}
+
+// prefixed formats a form made of a prefix token and the forms it
+// applies to, such as a quoted form or a form with metadata. Forms
+// nested in it are indented as if they were in its parent.
+func (f *formatter) prefixed(n *reader.SyntaxNode, parent *coll, index int) {
+	startCol := f.col
+	f.write(n.Children[0].Text)
+	innerCol := f.col
+	var gap []*reader.SyntaxNode
+	forms := 0
+	for _, child := range n.Children[1:] {
+		if child.Kind == reader.SyntaxWhitespace {
+			gap = append(gap, child)
+			continue
+		}
+		if newlines := countNewlines(gap); newlines > 0 {
+			indent := innerCol
+			if n.Kind == reader.SyntaxMeta && forms == 1 {
+				// a form on the line after its metadata is indented as
+				// the element of the parent following the metadata.
+				p := *parent
+				p.forms = append(p.forms[:len(p.forms):len(p.forms)], form{col: startCol, node: n})
+				indent = p.indent()
+			}
+			f.newline(min(newlines, 2), indent)
+		} else {
+			f.write(text(gap))
+		}
+		f.node(child, parent, index)
+		if child.IsForm() {
+			forms++
+		}
+		gap = nil
+	}
+}
+
+// indent returns the column at which a line starting with the next
+// element of c is indented.
+func (c *coll) indent() int {
+	switch c.node.Kind {
+	case reader.SyntaxFile:
+		return 0
+	case reader.SyntaxList, reader.SyntaxFn:
+		if indent, ok := c.ruleIndent(); ok {
+			return indent
+		}
+		return c.listIndent()
+	}
+	return c.innerCol
+}
+
+// listIndent returns the indentation of a list that no rule applies
+// to: the element is aligned with the second element if it follows
+// it, as the arguments of a call, or with the first otherwise.
+func (c *coll) listIndent() int {
+	if len(c.forms) > 1 {
+		return c.forms[1].col
+	}
+	return c.innerCol
+}
+
+// innerIndent returns the indentation of the body of a list, such as
+// that of a fn: two columns right of its opening parenthesis.
+func (c *coll) innerIndent() int {
+	return c.innerCol + 1
+}
+
+// ruleIndent returns the indentation of the next element of the list c
+// given by the rules of the symbols at the heads of c and the lists it
+// is nested in, if any applies.
+func (c *coll) ruleIndent() (int, bool) {
+	index := len(c.forms)
+	if index == 0 {
+		return 0, false
+	}
+	for depth, top := 0, c; top != nil; depth, top = depth+1, top.parent {
+		sym := top.head()
+		if sym == "" {
+			continue
+		}
+		for _, r := range rulesFor(sym) {
+			switch {
+			case r.block >= 0 && depth == 0:
+				// the body of a block is indented as that of a fn if it
+				// starts on its own line, after the block's arguments.
+				if index > r.block && (index == r.block+1 || c.forms[r.block+1].startsLine) {
+					return c.innerIndent(), true
+				}
+				return c.listIndent(), true
+			case r.block < 0 && r.depth == depth:
+				if r.index >= 0 && (depth == 0 || c.ancestor(depth-1).index != r.index+1) {
+					continue
+				}
+				return c.innerIndent(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// head returns the symbol at the head of the list c, or "".
+func (c *coll) head() string {
+	switch c.node.Kind {
+	case reader.SyntaxList, reader.SyntaxFn:
+	default:
+		return ""
+	}
+	if len(c.forms) == 0 || c.forms[0].node.Kind != reader.SyntaxSymbol {
+		return ""
+	}
+	return c.forms[0].node.Text
+}
+
+// ancestor returns the collection n levels above c.
+func (c *coll) ancestor(n int) *coll {
+	for ; n > 0; n-- {
+		c = c.parent
+	}
+	return c
+}
+
+func countNewlines(gap []*reader.SyntaxNode) int {
+	n := 0
+	for _, ws := range gap {
+		n += strings.Count(ws.Text, "\n")
+	}
+	return n
+}
+
+func text(nodes []*reader.SyntaxNode) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		sb.WriteString(n.Text)
+	}
+	return sb.String()
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// rule is an indentation rule of cljfmt. A block rule, [:block n],
+// indents the elements after the first n arguments of a form as a
+// body. An inner rule, [:inner depth index], indents the elements of
+// the lists nested depth levels in a form as a body, optionally only
+// within its index-th argument.
+type rule struct {
+	// block is the n of a block rule, or -1 for an inner rule.
+	block        int
+	depth, index int
+}
+
+func block(n int) rule              { return rule{block: n} }
+func inner(depth int) rule          { return rule{block: -1, depth: depth, index: -1} }
+func innerAt(depth, index int) rule { return rule{block: -1, depth: depth, index: index} }
+
+// rules are the default indentation rules of cljfmt.
+var rules = map[string][]rule{
+	"alt!":            {block(0)},
+	"alt!!":           {block(0)},
+	"are":             {block(2)},
+	"as->":            {block(2)},
+	"binding":         {block(1)},
+	"bound-fn":        {inner(0)},
+	"case":            {block(1)},
+	"catch":           {block(2)},
+	"comment":         {block(0)},
+	"cond":            {block(0)},
+	"condp":           {block(2)},
+	"cond->":          {block(1)},
+	"cond->>":         {block(1)},
+	"def":             {inner(0)},
+	"defmacro":        {inner(0)},
+	"defmethod":       {inner(0)},
+	"defmulti":        {inner(0)},
+	"defn":            {inner(0)},
+	"defn-":           {inner(0)},
+	"defonce":         {inner(0)},
+	"defprotocol":     {block(1), inner(1)},
+	"defrecord":       {block(2), inner(1)},
+	"defstruct":       {block(1)},
+	"deftest":         {inner(0)},
+	"deftype":         {block(2), inner(1)},
+	"do":              {block(0)},
+	"doseq":           {block(1)},
+	"dotimes":         {block(1)},
+	"doto":            {block(1)},
+	"extend":          {block(1)},
+	"extend-protocol": {block(1), inner(1)},
+	"extend-type":     {block(1), inner(1)},
+	"finally":         {block(0)},
+	"fn":              {inner(0)},
+	"for":             {block(1)},
+	"future":          {block(0)},
+	"go":              {block(0)},
+	"go-loop":         {block(1)},
+	"if":              {block(1)},
+	"if-let":          {block(1)},
+	"if-not":          {block(1)},
+	"if-some":         {block(1)},
+	"let":             {block(1)},
+	"letfn":           {block(1), innerAt(2, 0)},
+	"locking":         {block(1)},
+	"loop":            {block(1)},
+	"match":           {block(1)},
+	"ns":              {block(1)},
+	"proxy":           {block(2), inner(1)},
+	"reify":           {inner(0), inner(1)},
+	"struct-map":      {block(1)},
+	"testing":         {block(1)},
+	"thread":          {block(0)},
+	"try":             {block(0)},
+	"use-fixtures":    {inner(0)},
+	"when":            {block(1)},
+	"when-first":      {block(1)},
+	"when-let":        {block(1)},
+	"when-not":        {block(1)},
+	"when-some":       {block(1)},
+	"while":           {block(1)},
+	"with-local-vars": {block(1)},
+	"with-open":       {block(1)},
+	"with-out-str":    {block(0)},
+	"with-precision":  {block(1)},
+	"with-redefs":     {block(1)},
+}
+
+// patternRules are the default rules of cljfmt for the symbols
+// matching a pattern, which apply to symbols without rules.
+var patternRules = []struct {
+	pattern *regexp.Regexp
+	rules   []rule
+}{
+	{regexp.MustCompile(`^def`), []rule{inner(0)}},
+}
+
+// rulesFor returns the indentation rules of the symbol sym, which may
+// be qualified by a namespace or alias.
+func rulesFor(sym string) []rule {
+	if r, ok := rules[sym]; ok {
+		return r
+	}
+	if i := strings.LastIndexByte(sym, '/'); i > 0 && i < len(sym)-1 {
+		sym = sym[i+1:]
+		if r, ok := rules[sym]; ok {
+			return r
+		}
+	}
+	for _, p := range patternRules {
+		if p.pattern.MatchString(sym) {
+			return p.rules
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package format
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/kylelemons/godebug/diff"
+)
+
+// set GLJ_FORMAT_TEST_WRITE_OUTPUT=1 to write the output of the
+// formatter as the gold output on a failure.
+func TestSource(t *testing.T) {
+	paths, err := filepath.Glob("testdata/*.glj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			src, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			outPath := strings.TrimSuffix(path, ".glj") + ".out"
+			want, err := os.ReadFile(outPath)
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			got, err := Source(path, src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("diff (-want,+got):\n%s", diff.Diff(string(want), string(got)))
+				if os.Getenv("GLJ_FORMAT_TEST_WRITE_OUTPUT") != "" {
+					if err := os.WriteFile(outPath, got, 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if again, err := Source(outPath, got); err != nil || string(again) != string(got) {
+				t.Errorf("formatting is not idempotent:\n%s", again)
+			}
+		})
+	}
+}
+
+// TestSourcePreservesForms checks that formatting the standard
+// library doesn't change the forms it holds.
+func TestSourcePreservesForms(t *testing.T) {
+	ns := value.FindOrCreateNamespace(value.NewSymbol("user"))
+	readAll := func(src []byte) []string {
+		forms, err := reader.New(strings.NewReader(string(src)), reader.WithGetCurrentNS(func() *value.Namespace {
+			return ns
+		})).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		strs := make([]string, len(forms))
+		for i, form := range forms {
+			strs[i] = value.PrintString(form)
+		}
+		return strs
+	}
+
+	err := filepath.WalkDir("../stdlib", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !strings.HasSuffix(path, ".glj") {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		res, err := Source(path, src)
+		if err != nil {
+			return err
+		}
+		if d := diff.Diff(strings.Join(readAll(src), "\n"), strings.Join(readAll(res), "\n")); d != "" {
+			t.Errorf("%s: formatting changed forms:\n%s", path, d)
+		}
+		if again, err := Source(path, res); err != nil || string(again) != string(res) {
+			t.Errorf("%s: formatting is not idempotent", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -65,6 +65,7 @@ import (
 	fmt "fmt"
 	github_com_glojurelang_glojure_pkg_lang "github.com/glojurelang/glojure/pkg/lang"
 	"github.com/glojurelang/glojure/pkg/pkgmap"
+	github_com_glojurelang_glojure_pkg_repl "github.com/glojurelang/glojure/pkg/repl"
 	github_com_glojurelang_glojure_pkg_runtime "github.com/glojurelang/glojure/pkg/runtime"
 	go_ast "go/ast"
 	go_build "go/build"
@@ -160,6 +161,7 @@ import (
 
 func init() {
 	RegisterImports(pkgmap.Set)
+	RegisterAdapters(github_com_glojurelang_glojure_pkg_lang.RegisterInterfaceAdapter)
 }
 
 func RegisterImports(_register func(string, interface{})) {
@@ -3413,6 +3415,7 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.APersistentVector", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.APersistentVector)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.ASeq", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ASeq)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.Abs", github_com_glojurelang_glojure_pkg_lang.Abs)
+	_register("github.com/glojurelang/glojure/pkg/lang.AdapterFunc", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.AdapterFunc)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.Add", github_com_glojurelang_glojure_pkg_lang.Add)
 	_register("github.com/glojurelang/glojure/pkg/lang.AddP", github_com_glojurelang_glojure_pkg_lang.AddP)
 	_register("github.com/glojurelang/glojure/pkg/lang.Agent", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Agent)(nil)).Elem())
@@ -3420,6 +3423,7 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.AgentSubmit", github_com_glojurelang_glojure_pkg_lang.AgentSubmit)
 	_register("github.com/glojurelang/glojure/pkg/lang.AppendWriter", github_com_glojurelang_glojure_pkg_lang.AppendWriter)
 	_register("github.com/glojurelang/glojure/pkg/lang.Apply", github_com_glojurelang_glojure_pkg_lang.Apply)
+	_register("github.com/glojurelang/glojure/pkg/lang.ApplyGoTry", github_com_glojurelang_glojure_pkg_lang.ApplyGoTry)
 	_register("github.com/glojurelang/glojure/pkg/lang.ArithmeticError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ArithmeticError)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*ArithmeticError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ArithmeticError)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.ArrayNode", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ArrayNode)(nil)).Elem())
@@ -3452,6 +3456,8 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.BuiltinTypes", github_com_glojurelang_glojure_pkg_lang.BuiltinTypes)
 	_register("github.com/glojurelang/glojure/pkg/lang.Builtins", github_com_glojurelang_glojure_pkg_lang.Builtins)
 	_register("github.com/glojurelang/glojure/pkg/lang.ByteCast", github_com_glojurelang_glojure_pkg_lang.ByteCast)
+	_register("github.com/glojurelang/glojure/pkg/lang.CanceledError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.CanceledError)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/lang.*CanceledError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.CanceledError)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.Category", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Category)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.CategoryDecimal", github_com_glojurelang_glojure_pkg_lang.CategoryDecimal)
 	_register("github.com/glojurelang/glojure/pkg/lang.CategoryFloating", github_com_glojurelang_glojure_pkg_lang.CategoryFloating)
@@ -3461,11 +3467,14 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.CharAt", github_com_glojurelang_glojure_pkg_lang.CharAt)
 	_register("github.com/glojurelang/glojure/pkg/lang.CharCast", github_com_glojurelang_glojure_pkg_lang.CharCast)
 	_register("github.com/glojurelang/glojure/pkg/lang.CharLiteralFromRune", github_com_glojurelang_glojure_pkg_lang.CharLiteralFromRune)
+	_register("github.com/glojurelang/glojure/pkg/lang.CheckCanceled", github_com_glojurelang_glojure_pkg_lang.CheckCanceled)
 	_register("github.com/glojurelang/glojure/pkg/lang.ChunkBuffer", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ChunkBuffer)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*ChunkBuffer", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ChunkBuffer)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.ChunkedCons", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ChunkedCons)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*ChunkedCons", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ChunkedCons)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.CloneThreadBindingFrame", github_com_glojurelang_glojure_pkg_lang.CloneThreadBindingFrame)
+
+	_register("github.com/glojurelang/glojure/pkg/lang.Compare", github_com_glojurelang_glojure_pkg_lang.Compare)
 	_register("github.com/glojurelang/glojure/pkg/lang.Comparer", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Comparer)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.ConcatStrings", github_com_glojurelang_glojure_pkg_lang.ConcatStrings)
 	_register("github.com/glojurelang/glojure/pkg/lang.Conj", github_com_glojurelang_glojure_pkg_lang.Conj)
@@ -3486,6 +3495,8 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.CreatePersistentTreeSetWithComparator", github_com_glojurelang_glojure_pkg_lang.CreatePersistentTreeSetWithComparator)
 	_register("github.com/glojurelang/glojure/pkg/lang.Cycle", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Cycle)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*Cycle", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Cycle)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/lang.Delay", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Delay)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/lang.*Delay", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Delay)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.Dissoc", github_com_glojurelang_glojure_pkg_lang.Dissoc)
 	_register("github.com/glojurelang/glojure/pkg/lang.Divide", github_com_glojurelang_glojure_pkg_lang.Divide)
 	_register("github.com/glojurelang/glojure/pkg/lang.EmptyList", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.EmptyList)(nil)).Elem())
@@ -3505,11 +3516,15 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.FindOrCreateNamespace", github_com_glojurelang_glojure_pkg_lang.FindOrCreateNamespace)
 	_register("github.com/glojurelang/glojure/pkg/lang.First", github_com_glojurelang_glojure_pkg_lang.First)
 	_register("github.com/glojurelang/glojure/pkg/lang.FloatCast", github_com_glojurelang_glojure_pkg_lang.FloatCast)
+	_register("github.com/glojurelang/glojure/pkg/lang.FlushWriter", github_com_glojurelang_glojure_pkg_lang.FlushWriter)
+	_register("github.com/glojurelang/glojure/pkg/lang.ForceDelay", github_com_glojurelang_glojure_pkg_lang.ForceDelay)
 	_register("github.com/glojurelang/glojure/pkg/lang.Future", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Future)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.GT", github_com_glojurelang_glojure_pkg_lang.GT)
 	_register("github.com/glojurelang/glojure/pkg/lang.Get", github_com_glojurelang_glojure_pkg_lang.Get)
 	_register("github.com/glojurelang/glojure/pkg/lang.GetDefault", github_com_glojurelang_glojure_pkg_lang.GetDefault)
+	_register("github.com/glojurelang/glojure/pkg/lang.GetThreadBindings", github_com_glojurelang_glojure_pkg_lang.GetThreadBindings)
 	_register("github.com/glojurelang/glojure/pkg/lang.GlobalEnv", github_com_glojurelang_glojure_pkg_lang.GlobalEnv)
+	_register("github.com/glojurelang/glojure/pkg/lang.Go", github_com_glojurelang_glojure_pkg_lang.Go)
 	_register("github.com/glojurelang/glojure/pkg/lang.GoAppend", github_com_glojurelang_glojure_pkg_lang.GoAppend)
 	_register("github.com/glojurelang/glojure/pkg/lang.GoCap", github_com_glojurelang_glojure_pkg_lang.GoCap)
 	_register("github.com/glojurelang/glojure/pkg/lang.GoChanOf", github_com_glojurelang_glojure_pkg_lang.GoChanOf)
@@ -3534,6 +3549,7 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.GoSendChanOf", github_com_glojurelang_glojure_pkg_lang.GoSendChanOf)
 	_register("github.com/glojurelang/glojure/pkg/lang.GoSetMapIndex", github_com_glojurelang_glojure_pkg_lang.GoSetMapIndex)
 	_register("github.com/glojurelang/glojure/pkg/lang.GoSlice", github_com_glojurelang_glojure_pkg_lang.GoSlice)
+	_register("github.com/glojurelang/glojure/pkg/lang.HasStepLimit", github_com_glojurelang_glojure_pkg_lang.HasStepLimit)
 	_register("github.com/glojurelang/glojure/pkg/lang.HasType", github_com_glojurelang_glojure_pkg_lang.HasType)
 	_register("github.com/glojurelang/glojure/pkg/lang.Hash", github_com_glojurelang_glojure_pkg_lang.Hash)
 	_register("github.com/glojurelang/glojure/pkg/lang.HashCollisionNode", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.HashCollisionNode)(nil)).Elem())
@@ -3574,10 +3590,13 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.ITransientVector", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ITransientVector)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.Identical", github_com_glojurelang_glojure_pkg_lang.Identical)
 	_register("github.com/glojurelang/glojure/pkg/lang.IdentityHash", github_com_glojurelang_glojure_pkg_lang.IdentityHash)
+	_register("github.com/glojurelang/glojure/pkg/lang.IllegalAccessError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IllegalAccessError)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/lang.*IllegalAccessError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IllegalAccessError)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.IllegalArgumentError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IllegalArgumentError)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*IllegalArgumentError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IllegalArgumentError)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.IllegalStateError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IllegalStateError)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*IllegalStateError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IllegalStateError)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/lang.Implement", github_com_glojurelang_glojure_pkg_lang.Implement)
 	_register("github.com/glojurelang/glojure/pkg/lang.Import", github_com_glojurelang_glojure_pkg_lang.Import)
 	_register("github.com/glojurelang/glojure/pkg/lang.Inc", github_com_glojurelang_glojure_pkg_lang.Inc)
 	_register("github.com/glojurelang/glojure/pkg/lang.IncP", github_com_glojurelang_glojure_pkg_lang.IncP)
@@ -3643,6 +3662,7 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.KWFn", github_com_glojurelang_glojure_pkg_lang.KWFn)
 	_register("github.com/glojurelang/glojure/pkg/lang.KWFnMethod", github_com_glojurelang_glojure_pkg_lang.KWFnMethod)
 	_register("github.com/glojurelang/glojure/pkg/lang.KWForm", github_com_glojurelang_glojure_pkg_lang.KWForm)
+	_register("github.com/glojurelang/glojure/pkg/lang.KWGoErrors", github_com_glojurelang_glojure_pkg_lang.KWGoErrors)
 	_register("github.com/glojurelang/glojure/pkg/lang.KWHashEquiv", github_com_glojurelang_glojure_pkg_lang.KWHashEquiv)
 	_register("github.com/glojurelang/glojure/pkg/lang.KWHashIdentity", github_com_glojurelang_glojure_pkg_lang.KWHashIdentity)
 	_register("github.com/glojurelang/glojure/pkg/lang.KWHostCall", github_com_glojurelang_glojure_pkg_lang.KWHostCall)
@@ -3748,6 +3768,7 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.Matcher", github_com_glojurelang_glojure_pkg_lang.Matcher)
 	_register("github.com/glojurelang/glojure/pkg/lang.Max", github_com_glojurelang_glojure_pkg_lang.Max)
 	_register("github.com/glojurelang/glojure/pkg/lang.Merge", github_com_glojurelang_glojure_pkg_lang.Merge)
+	_register("github.com/glojurelang/glojure/pkg/lang.Methods", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Methods)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.Min", github_com_glojurelang_glojure_pkg_lang.Min)
 	_register("github.com/glojurelang/glojure/pkg/lang.MultiFn", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.MultiFn)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*MultiFn", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.MultiFn)(nil)))
@@ -3772,13 +3793,16 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.NewBigIntFromInt64", github_com_glojurelang_glojure_pkg_lang.NewBigIntFromInt64)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewBigIntWithBase", github_com_glojurelang_glojure_pkg_lang.NewBigIntWithBase)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewBox", github_com_glojurelang_glojure_pkg_lang.NewBox)
+	_register("github.com/glojurelang/glojure/pkg/lang.NewCanceledError", github_com_glojurelang_glojure_pkg_lang.NewCanceledError)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewChar", github_com_glojurelang_glojure_pkg_lang.NewChar)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewChunkBuffer", github_com_glojurelang_glojure_pkg_lang.NewChunkBuffer)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewChunkedCons", github_com_glojurelang_glojure_pkg_lang.NewChunkedCons)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewCons", github_com_glojurelang_glojure_pkg_lang.NewCons)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewCycle", github_com_glojurelang_glojure_pkg_lang.NewCycle)
+	_register("github.com/glojurelang/glojure/pkg/lang.NewDelay", github_com_glojurelang_glojure_pkg_lang.NewDelay)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewError", github_com_glojurelang_glojure_pkg_lang.NewError)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewGoMapSeq", github_com_glojurelang_glojure_pkg_lang.NewGoMapSeq)
+	_register("github.com/glojurelang/glojure/pkg/lang.NewIllegalAccessError", github_com_glojurelang_glojure_pkg_lang.NewIllegalAccessError)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewIllegalArgumentError", github_com_glojurelang_glojure_pkg_lang.NewIllegalArgumentError)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewIllegalStateError", github_com_glojurelang_glojure_pkg_lang.NewIllegalStateError)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewIndexOutOfBoundsError", github_com_glojurelang_glojure_pkg_lang.NewIndexOutOfBoundsError)
@@ -3796,6 +3820,7 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.NewNamespace", github_com_glojurelang_glojure_pkg_lang.NewNamespace)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewPersistentArrayMapAsIfByAssoc", github_com_glojurelang_glojure_pkg_lang.NewPersistentArrayMapAsIfByAssoc)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewPersistentHashMap", github_com_glojurelang_glojure_pkg_lang.NewPersistentHashMap)
+	_register("github.com/glojurelang/glojure/pkg/lang.NewProtocol", github_com_glojurelang_glojure_pkg_lang.NewProtocol)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewRange", github_com_glojurelang_glojure_pkg_lang.NewRange)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewRatio", github_com_glojurelang_glojure_pkg_lang.NewRatio)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewRatioBigInt", github_com_glojurelang_glojure_pkg_lang.NewRatioBigInt)
@@ -3806,6 +3831,7 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.NewRegexpMatcher", github_com_glojurelang_glojure_pkg_lang.NewRegexpMatcher)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewRepeat", github_com_glojurelang_glojure_pkg_lang.NewRepeat)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewRepeatN", github_com_glojurelang_glojure_pkg_lang.NewRepeatN)
+	_register("github.com/glojurelang/glojure/pkg/lang.NewResourceLimitError", github_com_glojurelang_glojure_pkg_lang.NewResourceLimitError)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewSet", github_com_glojurelang_glojure_pkg_lang.NewSet)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewSliceChunk", github_com_glojurelang_glojure_pkg_lang.NewSliceChunk)
 	_register("github.com/glojurelang/glojure/pkg/lang.NewSliceSeq", github_com_glojurelang_glojure_pkg_lang.NewSliceSeq)
@@ -3845,15 +3871,22 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.PersistentVector", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.PersistentVector)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*PersistentVector", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.PersistentVector)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.Pop", github_com_glojurelang_glojure_pkg_lang.Pop)
+	_register("github.com/glojurelang/glojure/pkg/lang.PopContext", github_com_glojurelang_glojure_pkg_lang.PopContext)
 	_register("github.com/glojurelang/glojure/pkg/lang.PopThreadBindings", github_com_glojurelang_glojure_pkg_lang.PopThreadBindings)
 	_register("github.com/glojurelang/glojure/pkg/lang.Print", github_com_glojurelang_glojure_pkg_lang.Print)
 	_register("github.com/glojurelang/glojure/pkg/lang.PrintString", github_com_glojurelang_glojure_pkg_lang.PrintString)
 	_register("github.com/glojurelang/glojure/pkg/lang.PrintWriter", github_com_glojurelang_glojure_pkg_lang.PrintWriter)
+	_register("github.com/glojurelang/glojure/pkg/lang.Protocol", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Protocol)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/lang.*Protocol", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Protocol)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/lang.ProtocolFn", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ProtocolFn)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/lang.*ProtocolFn", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ProtocolFn)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/lang.PushContext", github_com_glojurelang_glojure_pkg_lang.PushContext)
 	_register("github.com/glojurelang/glojure/pkg/lang.PushThreadBindings", github_com_glojurelang_glojure_pkg_lang.PushThreadBindings)
 	_register("github.com/glojurelang/glojure/pkg/lang.Range", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Range)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*Range", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Range)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.Ratio", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Ratio)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*Ratio", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Ratio)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/lang.ReadLine", github_com_glojurelang_glojure_pkg_lang.ReadLine)
 	_register("github.com/glojurelang/glojure/pkg/lang.RecurError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.RecurError)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*RecurError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.RecurError)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.RecurTarget", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.RecurTarget)(nil)).Elem())
@@ -3867,10 +3900,13 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.*Ref", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Ref)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.RegexpMatcher", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.RegexpMatcher)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*RegexpMatcher", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.RegexpMatcher)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/lang.RegisterInterfaceAdapter", github_com_glojurelang_glojure_pkg_lang.RegisterInterfaceAdapter)
 	_register("github.com/glojurelang/glojure/pkg/lang.RemoveNamespace", github_com_glojurelang_glojure_pkg_lang.RemoveNamespace)
 	_register("github.com/glojurelang/glojure/pkg/lang.Repeat", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Repeat)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*Repeat", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Repeat)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.ResetThreadBindingFrame", github_com_glojurelang_glojure_pkg_lang.ResetThreadBindingFrame)
+	_register("github.com/glojurelang/glojure/pkg/lang.ResourceLimitError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ResourceLimitError)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/lang.*ResourceLimitError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.ResourceLimitError)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.Rest", github_com_glojurelang_glojure_pkg_lang.Rest)
 	_register("github.com/glojurelang/glojure/pkg/lang.Reversible", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Reversible)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.RuneFromCharLiteral", github_com_glojurelang_glojure_pkg_lang.RuneFromCharLiteral)
@@ -3881,6 +3917,9 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.Set", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Set)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*Set", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Set)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.SetField", github_com_glojurelang_glojure_pkg_lang.SetField)
+	_register("github.com/glojurelang/glojure/pkg/lang.SetMaxCollectionSize", github_com_glojurelang_glojure_pkg_lang.SetMaxCollectionSize)
+	_register("github.com/glojurelang/glojure/pkg/lang.SetMaxGoroutines", github_com_glojurelang_glojure_pkg_lang.SetMaxGoroutines)
+	_register("github.com/glojurelang/glojure/pkg/lang.SetRootContext", github_com_glojurelang_glojure_pkg_lang.SetRootContext)
 	_register("github.com/glojurelang/glojure/pkg/lang.ShortCast", github_com_glojurelang_glojure_pkg_lang.ShortCast)
 	_register("github.com/glojurelang/glojure/pkg/lang.ShutdownAgents", github_com_glojurelang_glojure_pkg_lang.ShutdownAgents)
 	_register("github.com/glojurelang/glojure/pkg/lang.SliceChunk", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.SliceChunk)(nil)).Elem())
@@ -3888,6 +3927,7 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.SliceSeq", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.SliceSeq)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*SliceSeq", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.SliceSeq)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.SliceSet", github_com_glojurelang_glojure_pkg_lang.SliceSet)
+	_register("github.com/glojurelang/glojure/pkg/lang.SortSlice", github_com_glojurelang_glojure_pkg_lang.SortSlice)
 	_register("github.com/glojurelang/glojure/pkg/lang.StackFrame", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.StackFrame)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*StackFrame", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.StackFrame)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.Stacker", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Stacker)(nil)).Elem())
@@ -3927,8 +3967,10 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.VarAgent", github_com_glojurelang_glojure_pkg_lang.VarAgent)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarAssert", github_com_glojurelang_glojure_pkg_lang.VarAssert)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarCompileFiles", github_com_glojurelang_glojure_pkg_lang.VarCompileFiles)
+	_register("github.com/glojurelang/glojure/pkg/lang.VarContext", github_com_glojurelang_glojure_pkg_lang.VarContext)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarCurrentNS", github_com_glojurelang_glojure_pkg_lang.VarCurrentNS)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarDataReaders", github_com_glojurelang_glojure_pkg_lang.VarDataReaders)
+	_register("github.com/glojurelang/glojure/pkg/lang.VarErr", github_com_glojurelang_glojure_pkg_lang.VarErr)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarFile", github_com_glojurelang_glojure_pkg_lang.VarFile)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarIn", github_com_glojurelang_glojure_pkg_lang.VarIn)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarInNS", github_com_glojurelang_glojure_pkg_lang.VarInNS)
@@ -3936,6 +3978,7 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.VarOut", github_com_glojurelang_glojure_pkg_lang.VarOut)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarParents", github_com_glojurelang_glojure_pkg_lang.VarParents)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarPrOn", github_com_glojurelang_glojure_pkg_lang.VarPrOn)
+	_register("github.com/glojurelang/glojure/pkg/lang.VarPrintGoStack", github_com_glojurelang_glojure_pkg_lang.VarPrintGoStack)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarPrintInitialized", github_com_glojurelang_glojure_pkg_lang.VarPrintInitialized)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarPrintReadably", github_com_glojurelang_glojure_pkg_lang.VarPrintReadably)
 	_register("github.com/glojurelang/glojure/pkg/lang.VarUncheckedMath", github_com_glojurelang_glojure_pkg_lang.VarUncheckedMath)
@@ -3945,20 +3988,91 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/lang.Volatile", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Volatile)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/lang.*Volatile", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Volatile)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/lang.WithMeta", github_com_glojurelang_glojure_pkg_lang.WithMeta)
+	_register("github.com/glojurelang/glojure/pkg/lang.WithStepLimit", github_com_glojurelang_glojure_pkg_lang.WithStepLimit)
 	_register("github.com/glojurelang/glojure/pkg/lang.WriteWriter", github_com_glojurelang_glojure_pkg_lang.WriteWriter)
 
+	// package github.com/glojurelang/glojure/pkg/repl
+	////////////////////////////////////////
+	_register("github.com/glojurelang/glojure/pkg/repl.Complete", github_com_glojurelang_glojure_pkg_repl.Complete)
+	_register("github.com/glojurelang/glojure/pkg/repl.Completion", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_repl.Completion)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/repl.*Completion", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_repl.Completion)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/repl.CoreVar", github_com_glojurelang_glojure_pkg_repl.CoreVar)
+	_register("github.com/glojurelang/glojure/pkg/repl.ErrorMap", github_com_glojurelang_glojure_pkg_repl.ErrorMap)
+	_register("github.com/glojurelang/glojure/pkg/repl.Loop", github_com_glojurelang_glojure_pkg_repl.Loop)
+	_register("github.com/glojurelang/glojure/pkg/repl.NamespaceNamed", github_com_glojurelang_glojure_pkg_repl.NamespaceNamed)
+	_register("github.com/glojurelang/glojure/pkg/repl.Option", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_repl.Option)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/repl.Prepl", github_com_glojurelang_glojure_pkg_repl.Prepl)
+	_register("github.com/glojurelang/glojure/pkg/repl.ResolveVar", github_com_glojurelang_glojure_pkg_repl.ResolveVar)
+	_register("github.com/glojurelang/glojure/pkg/repl.Source", github_com_glojurelang_glojure_pkg_repl.Source)
+	_register("github.com/glojurelang/glojure/pkg/repl.Start", github_com_glojurelang_glojure_pkg_repl.Start)
+	_register("github.com/glojurelang/glojure/pkg/repl.StartServer", github_com_glojurelang_glojure_pkg_repl.StartServer)
+	_register("github.com/glojurelang/glojure/pkg/repl.StopServer", github_com_glojurelang_glojure_pkg_repl.StopServer)
+	_register("github.com/glojurelang/glojure/pkg/repl.StopServers", github_com_glojurelang_glojure_pkg_repl.StopServers)
+	_register("github.com/glojurelang/glojure/pkg/repl.WithEnvironment", github_com_glojurelang_glojure_pkg_repl.WithEnvironment)
+	_register("github.com/glojurelang/glojure/pkg/repl.WithHistoryFile", github_com_glojurelang_glojure_pkg_repl.WithHistoryFile)
+	_register("github.com/glojurelang/glojure/pkg/repl.WithStdin", github_com_glojurelang_glojure_pkg_repl.WithStdin)
+	_register("github.com/glojurelang/glojure/pkg/repl.WithStdout", github_com_glojurelang_glojure_pkg_repl.WithStdout)
+
 	// package github.com/glojurelang/glojure/pkg/runtime
 	////////////////////////////////////////
+	_register("github.com/glojurelang/glojure/pkg/runtime.AddImage", github_com_glojurelang_glojure_pkg_runtime.AddImage)
 	_register("github.com/glojurelang/glojure/pkg/runtime.AddLoadPath", github_com_glojurelang_glojure_pkg_runtime.AddLoadPath)
+	_register("github.com/glojurelang/glojure/pkg/runtime.Analyze", github_com_glojurelang_glojure_pkg_runtime.Analyze)
+	_register("github.com/glojurelang/glojure/pkg/runtime.CallFrame", github_com_glojurelang_glojure_pkg_runtime.CallFrame)
+	_register("github.com/glojurelang/glojure/pkg/runtime.CallSite", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.CallSite)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*CallSite", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.CallSite)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.CatchMatches", github_com_glojurelang_glojure_pkg_runtime.CatchMatches)
+	_register("github.com/glojurelang/glojure/pkg/runtime.ChangedNamespaces", github_com_glojurelang_glojure_pkg_runtime.ChangedNamespaces)
+	_register("github.com/glojurelang/glojure/pkg/runtime.CompiledFile", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.CompiledFile)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*CompiledFile", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.CompiledFile)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.CompiledForm", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.CompiledForm)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*CompiledForm", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.CompiledForm)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.CompiledMethod", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.CompiledMethod)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*CompiledMethod", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.CompiledMethod)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/runtime.Compiler", github_com_glojurelang_glojure_pkg_runtime.Compiler)
-	_register("github.com/glojurelang/glojure/pkg/runtime.Debug", github_com_glojurelang_glojure_pkg_runtime.Debug)
+	_register("github.com/glojurelang/glojure/pkg/runtime.CoreImage", github_com_glojurelang_glojure_pkg_runtime.CoreImage)
+	_register("github.com/glojurelang/glojure/pkg/runtime.CoverBlock", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.CoverBlock)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*CoverBlock", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.CoverBlock)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.Coverage", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Coverage)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*Coverage", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Coverage)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.DebugFrame", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.DebugFrame)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*DebugFrame", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.DebugFrame)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.Debugger", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Debugger)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*Debugger", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Debugger)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.Declare", github_com_glojurelang_glojure_pkg_runtime.Declare)
+	_register("github.com/glojurelang/glojure/pkg/runtime.DecodeConstants", github_com_glojurelang_glojure_pkg_runtime.DecodeConstants)
+	_register("github.com/glojurelang/glojure/pkg/runtime.DecodeUnit", github_com_glojurelang_glojure_pkg_runtime.DecodeUnit)
+	_register("github.com/glojurelang/glojure/pkg/runtime.Def", github_com_glojurelang_glojure_pkg_runtime.Def)
+	_register("github.com/glojurelang/glojure/pkg/runtime.DefFn", github_com_glojurelang_glojure_pkg_runtime.DefFn)
+	_register("github.com/glojurelang/glojure/pkg/runtime.EncodeConstants", github_com_glojurelang_glojure_pkg_runtime.EncodeConstants)
+	_register("github.com/glojurelang/glojure/pkg/runtime.ErrNotStopped", github_com_glojurelang_glojure_pkg_runtime.ErrNotStopped)
+	_register("github.com/glojurelang/glojure/pkg/runtime.ErrUnsupportedValue", github_com_glojurelang_glojure_pkg_runtime.ErrUnsupportedValue)
+	_register("github.com/glojurelang/glojure/pkg/runtime.ErrorMessage", github_com_glojurelang_glojure_pkg_runtime.ErrorMessage)
 	_register("github.com/glojurelang/glojure/pkg/runtime.EvalError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.EvalError)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/runtime.*EvalError", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.EvalError)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/runtime.EvalOption", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.EvalOption)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/runtime.Fn", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Fn)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/runtime.*Fn", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Fn)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.FnFrame", github_com_glojurelang_glojure_pkg_runtime.FnFrame)
+	_register("github.com/glojurelang/glojure/pkg/runtime.Image", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Image)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*Image", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Image)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.ImageVersion", github_com_glojurelang_glojure_pkg_runtime.ImageVersion)
+	_register("github.com/glojurelang/glojure/pkg/runtime.InNamespace", github_com_glojurelang_glojure_pkg_runtime.InNamespace)
+	_register("github.com/glojurelang/glojure/pkg/runtime.LabelFn", github_com_glojurelang_glojure_pkg_runtime.LabelFn)
+	_register("github.com/glojurelang/glojure/pkg/runtime.LabelNS", github_com_glojurelang_glojure_pkg_runtime.LabelNS)
+	_register("github.com/glojurelang/glojure/pkg/runtime.LoadReader", github_com_glojurelang_glojure_pkg_runtime.LoadReader)
+	_register("github.com/glojurelang/glojure/pkg/runtime.Local", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Local)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*Local", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Local)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.MaybeClass", github_com_glojurelang_glojure_pkg_runtime.MaybeClass)
+	_register("github.com/glojurelang/glojure/pkg/runtime.NewCompiledFn", github_com_glojurelang_glojure_pkg_runtime.NewCompiledFn)
+	_register("github.com/glojurelang/glojure/pkg/runtime.NewDebugger", github_com_glojurelang_glojure_pkg_runtime.NewDebugger)
 	_register("github.com/glojurelang/glojure/pkg/runtime.NewEnvironment", github_com_glojurelang_glojure_pkg_runtime.NewEnvironment)
 	_register("github.com/glojurelang/glojure/pkg/runtime.NewFn", github_com_glojurelang_glojure_pkg_runtime.NewFn)
+	_register("github.com/glojurelang/glojure/pkg/runtime.NewImage", github_com_glojurelang_glojure_pkg_runtime.NewImage)
+	_register("github.com/glojurelang/glojure/pkg/runtime.NewValue", github_com_glojurelang_glojure_pkg_runtime.NewValue)
+	_register("github.com/glojurelang/glojure/pkg/runtime.NodeCallSite", github_com_glojurelang_glojure_pkg_runtime.NodeCallSite)
+	_register("github.com/glojurelang/glojure/pkg/runtime.PrintError", github_com_glojurelang_glojure_pkg_runtime.PrintError)
+	_register("github.com/glojurelang/glojure/pkg/runtime.PrintStackTrace", github_com_glojurelang_glojure_pkg_runtime.PrintStackTrace)
 	_register("github.com/glojurelang/glojure/pkg/runtime.Program", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Program)(nil)).Elem())
 	_register("github.com/glojurelang/glojure/pkg/runtime.*Program", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Program)(nil)))
 	_register("github.com/glojurelang/glojure/pkg/runtime.RT", github_com_glojurelang_glojure_pkg_runtime.RT)
@@ -3967,6 +4081,25 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/runtime.RTReadString", github_com_glojurelang_glojure_pkg_runtime.RTReadString)
 	_register("github.com/glojurelang/glojure/pkg/runtime.ReadEval", github_com_glojurelang_glojure_pkg_runtime.ReadEval)
 	_register("github.com/glojurelang/glojure/pkg/runtime.ReadEvalOption", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.ReadEvalOption)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.ReadImage", github_com_glojurelang_glojure_pkg_runtime.ReadImage)
+	_register("github.com/glojurelang/glojure/pkg/runtime.ReadSourceFile", github_com_glojurelang_glojure_pkg_runtime.ReadSourceFile)
+	_register("github.com/glojurelang/glojure/pkg/runtime.RefreshPlan", github_com_glojurelang_glojure_pkg_runtime.RefreshPlan)
+	_register("github.com/glojurelang/glojure/pkg/runtime.RegisterCompiledFile", github_com_glojurelang_glojure_pkg_runtime.RegisterCompiledFile)
+	_register("github.com/glojurelang/glojure/pkg/runtime.Sandbox", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Sandbox)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*Sandbox", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Sandbox)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.SetCoverage", github_com_glojurelang_glojure_pkg_runtime.SetCoverage)
+	_register("github.com/glojurelang/glojure/pkg/runtime.SetDebugger", github_com_glojurelang_glojure_pkg_runtime.SetDebugger)
+	_register("github.com/glojurelang/glojure/pkg/runtime.SetHostField", github_com_glojurelang_glojure_pkg_runtime.SetHostField)
+	_register("github.com/glojurelang/glojure/pkg/runtime.SetProfileLabels", github_com_glojurelang_glojure_pkg_runtime.SetProfileLabels)
+	_register("github.com/glojurelang/glojure/pkg/runtime.SetSandbox", github_com_glojurelang_glojure_pkg_runtime.SetSandbox)
+	_register("github.com/glojurelang/glojure/pkg/runtime.SourceHash", github_com_glojurelang_glojure_pkg_runtime.SourceHash)
+	_register("github.com/glojurelang/glojure/pkg/runtime.StackTrace", github_com_glojurelang_glojure_pkg_runtime.StackTrace)
+	_register("github.com/glojurelang/glojure/pkg/runtime.StartCPUProfile", github_com_glojurelang_glojure_pkg_runtime.StartCPUProfile)
+	_register("github.com/glojurelang/glojure/pkg/runtime.StopBreakpoint", github_com_glojurelang_glojure_pkg_runtime.StopBreakpoint)
+	_register("github.com/glojurelang/glojure/pkg/runtime.StopCPUProfile", github_com_glojurelang_glojure_pkg_runtime.StopCPUProfile)
+	_register("github.com/glojurelang/glojure/pkg/runtime.StopException", github_com_glojurelang_glojure_pkg_runtime.StopException)
+	_register("github.com/glojurelang/glojure/pkg/runtime.StopPause", github_com_glojurelang_glojure_pkg_runtime.StopPause)
+	_register("github.com/glojurelang/glojure/pkg/runtime.StopStep", github_com_glojurelang_glojure_pkg_runtime.StopStep)
 	_register("github.com/glojurelang/glojure/pkg/runtime.SymInNS", github_com_glojurelang_glojure_pkg_runtime.SymInNS)
 	_register("github.com/glojurelang/glojure/pkg/runtime.SymNS", github_com_glojurelang_glojure_pkg_runtime.SymNS)
 	_register("github.com/glojurelang/glojure/pkg/runtime.SymbolDot", github_com_glojurelang_glojure_pkg_runtime.SymbolDot)
@@ -3975,11 +4108,20 @@ func RegisterImports(_register func(string, interface{})) {
 	_register("github.com/glojurelang/glojure/pkg/runtime.SymbolSpliceUnquote", github_com_glojurelang_glojure_pkg_runtime.SymbolSpliceUnquote)
 	_register("github.com/glojurelang/glojure/pkg/runtime.SymbolUnquote", github_com_glojurelang_glojure_pkg_runtime.SymbolUnquote)
 	_register("github.com/glojurelang/glojure/pkg/runtime.SymbolUserNamespace", github_com_glojurelang_glojure_pkg_runtime.SymbolUserNamespace)
+	_register("github.com/glojurelang/glojure/pkg/runtime.Thread", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Thread)(nil)).Elem())
+	_register("github.com/glojurelang/glojure/pkg/runtime.*Thread", reflect.TypeOf((*github_com_glojurelang_glojure_pkg_runtime.Thread)(nil)))
+	_register("github.com/glojurelang/glojure/pkg/runtime.VarValue", github_com_glojurelang_glojure_pkg_runtime.VarValue)
+	_register("github.com/glojurelang/glojure/pkg/runtime.WithContext", github_com_glojurelang_glojure_pkg_runtime.WithContext)
+	_register("github.com/glojurelang/glojure/pkg/runtime.WithCoverage", github_com_glojurelang_glojure_pkg_runtime.WithCoverage)
 	_register("github.com/glojurelang/glojure/pkg/runtime.WithEnv", github_com_glojurelang_glojure_pkg_runtime.WithEnv)
 	_register("github.com/glojurelang/glojure/pkg/runtime.WithFilename", github_com_glojurelang_glojure_pkg_runtime.WithFilename)
+	_register("github.com/glojurelang/glojure/pkg/runtime.WithImageRecorder", github_com_glojurelang_glojure_pkg_runtime.WithImageRecorder)
 	_register("github.com/glojurelang/glojure/pkg/runtime.WithLoadPath", github_com_glojurelang_glojure_pkg_runtime.WithLoadPath)
+	_register("github.com/glojurelang/glojure/pkg/runtime.WithSandbox", github_com_glojurelang_glojure_pkg_runtime.WithSandbox)
 	_register("github.com/glojurelang/glojure/pkg/runtime.WithStderr", github_com_glojurelang_glojure_pkg_runtime.WithStderr)
 	_register("github.com/glojurelang/glojure/pkg/runtime.WithStdout", github_com_glojurelang_glojure_pkg_runtime.WithStdout)
+	_register("github.com/glojurelang/glojure/pkg/runtime.WithoutImage", github_com_glojurelang_glojure_pkg_runtime.WithoutImage)
+	_register("github.com/glojurelang/glojure/pkg/runtime.WriteHeapProfile", github_com_glojurelang_glojure_pkg_runtime.WriteHeapProfile)
 
 	// package go/ast
 	////////////////////////////////////////
@@ -9155,3 +9297,3288 @@ func RegisterImports(_register func(string, interface{})) {
 	////////////////////////////////////////
 	_register("unsafe.Pointer", reflect.TypeOf((*unsafe.Pointer)(nil)).Elem())
 }
+
+func RegisterAdapters(_register func(reflect.Type, github_com_glojurelang_glojure_pkg_lang.AdapterFunc)) {
+	_register(reflect.TypeOf((*compress_flate.Reader)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_compress_flate_Reader{}
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._ReadByte, "ReadByte")
+		return a
+	})
+	_register(reflect.TypeOf((*compress_flate.Resetter)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_compress_flate_Resetter{}
+		m.Bind(&a._Reset, "Reset")
+		return a
+	})
+	_register(reflect.TypeOf((*compress_zlib.Resetter)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_compress_zlib_Resetter{}
+		m.Bind(&a._Reset, "Reset")
+		return a
+	})
+	_register(reflect.TypeOf((*crypto.Decrypter)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_crypto_Decrypter{}
+		m.Bind(&a._Decrypt, "Decrypt")
+		m.Bind(&a._Public, "Public")
+		return a
+	})
+	_register(reflect.TypeOf((*crypto.Signer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_crypto_Signer{}
+		m.Bind(&a._Public, "Public")
+		m.Bind(&a._Sign, "Sign")
+		return a
+	})
+	_register(reflect.TypeOf((*crypto.SignerOpts)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_crypto_SignerOpts{}
+		m.Bind(&a._HashFunc, "HashFunc")
+		return a
+	})
+	_register(reflect.TypeOf((*crypto_cipher.AEAD)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_crypto_cipher_AEAD{}
+		m.Bind(&a._NonceSize, "NonceSize")
+		m.Bind(&a._Open, "Open")
+		m.Bind(&a._Overhead, "Overhead")
+		m.Bind(&a._Seal, "Seal")
+		return a
+	})
+	_register(reflect.TypeOf((*crypto_cipher.Block)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_crypto_cipher_Block{}
+		m.Bind(&a._BlockSize, "BlockSize")
+		m.Bind(&a._Decrypt, "Decrypt")
+		m.Bind(&a._Encrypt, "Encrypt")
+		return a
+	})
+	_register(reflect.TypeOf((*crypto_cipher.BlockMode)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_crypto_cipher_BlockMode{}
+		m.Bind(&a._BlockSize, "BlockSize")
+		m.Bind(&a._CryptBlocks, "CryptBlocks")
+		return a
+	})
+	_register(reflect.TypeOf((*crypto_cipher.Stream)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_crypto_cipher_Stream{}
+		m.Bind(&a._XORKeyStream, "XORKeyStream")
+		return a
+	})
+	_register(reflect.TypeOf((*crypto_elliptic.Curve)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_crypto_elliptic_Curve{}
+		m.Bind(&a._Add, "Add")
+		m.Bind(&a._Double, "Double")
+		m.Bind(&a._IsOnCurve, "IsOnCurve")
+		m.Bind(&a._Params, "Params")
+		m.Bind(&a._ScalarBaseMult, "ScalarBaseMult")
+		m.Bind(&a._ScalarMult, "ScalarMult")
+		return a
+	})
+	_register(reflect.TypeOf((*crypto_tls.ClientSessionCache)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_crypto_tls_ClientSessionCache{}
+		m.Bind(&a._Get, "Get")
+		m.Bind(&a._Put, "Put")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql.Result)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_Result{}
+		m.Bind(&a._LastInsertId, "LastInsertId")
+		m.Bind(&a._RowsAffected, "RowsAffected")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.ColumnConverter)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_ColumnConverter{}
+		m.Bind(&a._ColumnConverter, "ColumnConverter")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Conn)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Conn{}
+		m.Bind(&a._Begin, "Begin")
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Prepare, "Prepare")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.ConnBeginTx)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_ConnBeginTx{}
+		m.Bind(&a._BeginTx, "BeginTx")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.ConnPrepareContext)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_ConnPrepareContext{}
+		m.Bind(&a._PrepareContext, "PrepareContext")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Connector)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Connector{}
+		m.Bind(&a._Connect, "Connect")
+		m.Bind(&a._Driver, "Driver")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Driver)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Driver{}
+		m.Bind(&a._Open, "Open")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.DriverContext)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_DriverContext{}
+		m.Bind(&a._OpenConnector, "OpenConnector")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Execer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Execer{}
+		m.Bind(&a._Exec, "Exec")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.ExecerContext)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_ExecerContext{}
+		m.Bind(&a._ExecContext, "ExecContext")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.NamedValueChecker)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_NamedValueChecker{}
+		m.Bind(&a._CheckNamedValue, "CheckNamedValue")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Pinger)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Pinger{}
+		m.Bind(&a._Ping, "Ping")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Queryer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Queryer{}
+		m.Bind(&a._Query, "Query")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.QueryerContext)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_QueryerContext{}
+		m.Bind(&a._QueryContext, "QueryContext")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Result)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Result{}
+		m.Bind(&a._LastInsertId, "LastInsertId")
+		m.Bind(&a._RowsAffected, "RowsAffected")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Rows)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Rows{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Columns, "Columns")
+		m.Bind(&a._Next, "Next")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.RowsColumnTypeDatabaseTypeName)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_RowsColumnTypeDatabaseTypeName{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._ColumnTypeDatabaseTypeName, "ColumnTypeDatabaseTypeName")
+		m.Bind(&a._Columns, "Columns")
+		m.Bind(&a._Next, "Next")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.RowsColumnTypeLength)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_RowsColumnTypeLength{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._ColumnTypeLength, "ColumnTypeLength")
+		m.Bind(&a._Columns, "Columns")
+		m.Bind(&a._Next, "Next")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.RowsColumnTypeNullable)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_RowsColumnTypeNullable{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._ColumnTypeNullable, "ColumnTypeNullable")
+		m.Bind(&a._Columns, "Columns")
+		m.Bind(&a._Next, "Next")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.RowsColumnTypePrecisionScale)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_RowsColumnTypePrecisionScale{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._ColumnTypePrecisionScale, "ColumnTypePrecisionScale")
+		m.Bind(&a._Columns, "Columns")
+		m.Bind(&a._Next, "Next")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.RowsColumnTypeScanType)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_RowsColumnTypeScanType{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._ColumnTypeScanType, "ColumnTypeScanType")
+		m.Bind(&a._Columns, "Columns")
+		m.Bind(&a._Next, "Next")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.RowsNextResultSet)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_RowsNextResultSet{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Columns, "Columns")
+		m.Bind(&a._HasNextResultSet, "HasNextResultSet")
+		m.Bind(&a._Next, "Next")
+		m.Bind(&a._NextResultSet, "NextResultSet")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.SessionResetter)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_SessionResetter{}
+		m.Bind(&a._ResetSession, "ResetSession")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Stmt)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Stmt{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Exec, "Exec")
+		m.Bind(&a._NumInput, "NumInput")
+		m.Bind(&a._Query, "Query")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.StmtExecContext)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_StmtExecContext{}
+		m.Bind(&a._ExecContext, "ExecContext")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.StmtQueryContext)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_StmtQueryContext{}
+		m.Bind(&a._QueryContext, "QueryContext")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Tx)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Tx{}
+		m.Bind(&a._Commit, "Commit")
+		m.Bind(&a._Rollback, "Rollback")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Validator)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Validator{}
+		m.Bind(&a._IsValid, "IsValid")
+		return a
+	})
+	_register(reflect.TypeOf((*database_sql_driver.Valuer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_database_sql_driver_Valuer{}
+		m.Bind(&a._Value, "Value")
+		return a
+	})
+	_register(reflect.TypeOf((*debug_dwarf.Type)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_debug_dwarf_Type{}
+		m.Bind(&a._Common, "Common")
+		m.Bind(&a._Size, "Size")
+		m.Bind(&a._String, "String")
+		return a
+	})
+	_register(reflect.TypeOf((*debug_macho.Load)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_debug_macho_Load{}
+		m.Bind(&a._Raw, "Raw")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_BinaryMarshaler{}
+		m.Bind(&a._MarshalBinary, "MarshalBinary")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_BinaryUnmarshaler{}
+		m.Bind(&a._UnmarshalBinary, "UnmarshalBinary")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_TextMarshaler{}
+		m.Bind(&a._MarshalText, "MarshalText")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_TextUnmarshaler{}
+		m.Bind(&a._UnmarshalText, "UnmarshalText")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding_binary.AppendByteOrder)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_binary_AppendByteOrder{}
+		m.Bind(&a._AppendUint16, "AppendUint16")
+		m.Bind(&a._AppendUint32, "AppendUint32")
+		m.Bind(&a._AppendUint64, "AppendUint64")
+		m.Bind(&a._String, "String")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding_binary.ByteOrder)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_binary_ByteOrder{}
+		m.Bind(&a._PutUint16, "PutUint16")
+		m.Bind(&a._PutUint32, "PutUint32")
+		m.Bind(&a._PutUint64, "PutUint64")
+		m.Bind(&a._String, "String")
+		m.Bind(&a._Uint16, "Uint16")
+		m.Bind(&a._Uint32, "Uint32")
+		m.Bind(&a._Uint64, "Uint64")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding_gob.GobDecoder)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_gob_GobDecoder{}
+		m.Bind(&a._GobDecode, "GobDecode")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding_gob.GobEncoder)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_gob_GobEncoder{}
+		m.Bind(&a._GobEncode, "GobEncode")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding_xml.Marshaler)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_xml_Marshaler{}
+		m.Bind(&a._MarshalXML, "MarshalXML")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding_xml.MarshalerAttr)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_xml_MarshalerAttr{}
+		m.Bind(&a._MarshalXMLAttr, "MarshalXMLAttr")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding_xml.TokenReader)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_xml_TokenReader{}
+		m.Bind(&a._Token, "Token")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding_xml.Unmarshaler)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_xml_Unmarshaler{}
+		m.Bind(&a._UnmarshalXML, "UnmarshalXML")
+		return a
+	})
+	_register(reflect.TypeOf((*encoding_xml.UnmarshalerAttr)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_encoding_xml_UnmarshalerAttr{}
+		m.Bind(&a._UnmarshalXMLAttr, "UnmarshalXMLAttr")
+		return a
+	})
+	_register(reflect.TypeOf((*expvar.Var)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_expvar_Var{}
+		m.Bind(&a._String, "String")
+		return a
+	})
+	_register(reflect.TypeOf((*flag.Value)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_flag_Value{}
+		m.Bind(&a._Set, "Set")
+		m.Bind(&a._String, "String")
+		return a
+	})
+	_register(reflect.TypeOf((*fmt.Formatter)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_fmt_Formatter{}
+		m.Bind(&a._Format, "Format")
+		return a
+	})
+	_register(reflect.TypeOf((*fmt.GoStringer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_fmt_GoStringer{}
+		m.Bind(&a._GoString, "GoString")
+		return a
+	})
+	_register(reflect.TypeOf((*fmt.ScanState)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_fmt_ScanState{}
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._ReadRune, "ReadRune")
+		m.Bind(&a._SkipSpace, "SkipSpace")
+		m.Bind(&a._Token, "Token")
+		m.Bind(&a._UnreadRune, "UnreadRune")
+		m.Bind(&a._Width, "Width")
+		return a
+	})
+	_register(reflect.TypeOf((*fmt.Scanner)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_fmt_Scanner{}
+		m.Bind(&a._Scan, "Scan")
+		return a
+	})
+	_register(reflect.TypeOf((*fmt.State)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_fmt_State{}
+		m.Bind(&a._Flag, "Flag")
+		m.Bind(&a._Precision, "Precision")
+		m.Bind(&a._Width, "Width")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*fmt.Stringer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_fmt_Stringer{}
+		m.Bind(&a._String, "String")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Counted)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_Counted{}
+		m.Bind(&a._Count, "Count")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Environment)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_Environment{}
+		m.Bind(&a._BindLocal, "BindLocal")
+		m.Bind(&a._Context, "Context")
+		m.Bind(&a._CurrentNamespace, "CurrentNamespace")
+		m.Bind(&a._DefVar, "DefVar")
+		m.Bind(&a._Errorf, "Errorf")
+		m.Bind(&a._Eval, "Eval")
+		m.Bind(&a._EvalAST, "EvalAST")
+		m.Bind(&a._PushLoadPaths, "PushLoadPaths")
+		m.Bind(&a._PushScope, "PushScope")
+		m.Bind(&a._ResolveFile, "ResolveFile")
+		m.Bind(&a._SetCurrentNamespace, "SetCurrentNamespace")
+		m.Bind(&a._Stderr, "Stderr")
+		m.Bind(&a._Stdout, "Stdout")
+		m.Bind(&a._WithRecurTarget, "WithRecurTarget")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Hasher)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_Hasher{}
+		m.Bind(&a._Hash, "Hash")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IDrop)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_IDrop{}
+		m.Bind(&a._Drop, "Drop")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IEditableCollection)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_IEditableCollection{}
+		m.Bind(&a._AsTransient, "AsTransient")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IHashEq)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_IHashEq{}
+		m.Bind(&a._HashEq, "HashEq")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IMeta)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_IMeta{}
+		m.Bind(&a._Meta, "Meta")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.IPending)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_IPending{}
+		m.Bind(&a._IsRealized, "IsRealized")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.MapIterator)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_MapIterator{}
+		m.Bind(&a._HasNext, "HasNext")
+		m.Bind(&a._Next, "Next")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Named)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_Named{}
+		m.Bind(&a._Name, "Name")
+		m.Bind(&a._Namespace, "Namespace")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Nther)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_Nther{}
+		m.Bind(&a._Nth, "Nth")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Reversible)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_Reversible{}
+		m.Bind(&a._RSeq, "RSeq")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Seqable)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_Seqable{}
+		m.Bind(&a._Seq, "Seq")
+		return a
+	})
+	_register(reflect.TypeOf((*github_com_glojurelang_glojure_pkg_lang.Stacker)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_github_com_glojurelang_glojure_pkg_lang_Stacker{}
+		m.Bind(&a._Stack, "Stack")
+		return a
+	})
+	_register(reflect.TypeOf((*go_ast.Node)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_go_ast_Node{}
+		m.Bind(&a._End, "End")
+		m.Bind(&a._Pos, "Pos")
+		return a
+	})
+	_register(reflect.TypeOf((*go_ast.Visitor)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_go_ast_Visitor{}
+		m.Bind(&a._Visit, "Visit")
+		return a
+	})
+	_register(reflect.TypeOf((*go_types.Importer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_go_types_Importer{}
+		m.Bind(&a._Import, "Import")
+		return a
+	})
+	_register(reflect.TypeOf((*go_types.ImporterFrom)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_go_types_ImporterFrom{}
+		m.Bind(&a._Import, "Import")
+		m.Bind(&a._ImportFrom, "ImportFrom")
+		return a
+	})
+	_register(reflect.TypeOf((*go_types.Sizes)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_go_types_Sizes{}
+		m.Bind(&a._Alignof, "Alignof")
+		m.Bind(&a._Offsetsof, "Offsetsof")
+		m.Bind(&a._Sizeof, "Sizeof")
+		return a
+	})
+	_register(reflect.TypeOf((*go_types.Type)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_go_types_Type{}
+		m.Bind(&a._String, "String")
+		m.Bind(&a._Underlying, "Underlying")
+		return a
+	})
+	_register(reflect.TypeOf((*hash.Hash)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_hash_Hash{}
+		m.Bind(&a._BlockSize, "BlockSize")
+		m.Bind(&a._Reset, "Reset")
+		m.Bind(&a._Size, "Size")
+		m.Bind(&a._Sum, "Sum")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*hash.Hash32)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_hash_Hash32{}
+		m.Bind(&a._BlockSize, "BlockSize")
+		m.Bind(&a._Reset, "Reset")
+		m.Bind(&a._Size, "Size")
+		m.Bind(&a._Sum, "Sum")
+		m.Bind(&a._Sum32, "Sum32")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*hash.Hash64)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_hash_Hash64{}
+		m.Bind(&a._BlockSize, "BlockSize")
+		m.Bind(&a._Reset, "Reset")
+		m.Bind(&a._Size, "Size")
+		m.Bind(&a._Sum, "Sum")
+		m.Bind(&a._Sum64, "Sum64")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*image.Image)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_Image{}
+		m.Bind(&a._At, "At")
+		m.Bind(&a._Bounds, "Bounds")
+		m.Bind(&a._ColorModel, "ColorModel")
+		return a
+	})
+	_register(reflect.TypeOf((*image.PalettedImage)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_PalettedImage{}
+		m.Bind(&a._At, "At")
+		m.Bind(&a._Bounds, "Bounds")
+		m.Bind(&a._ColorIndexAt, "ColorIndexAt")
+		m.Bind(&a._ColorModel, "ColorModel")
+		return a
+	})
+	_register(reflect.TypeOf((*image.RGBA64Image)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_RGBA64Image{}
+		m.Bind(&a._At, "At")
+		m.Bind(&a._Bounds, "Bounds")
+		m.Bind(&a._ColorModel, "ColorModel")
+		m.Bind(&a._RGBA64At, "RGBA64At")
+		return a
+	})
+	_register(reflect.TypeOf((*image_color.Color)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_color_Color{}
+		m.Bind(&a._RGBA, "RGBA")
+		return a
+	})
+	_register(reflect.TypeOf((*image_color.Model)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_color_Model{}
+		m.Bind(&a._Convert, "Convert")
+		return a
+	})
+	_register(reflect.TypeOf((*image_draw.Drawer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_draw_Drawer{}
+		m.Bind(&a._Draw, "Draw")
+		return a
+	})
+	_register(reflect.TypeOf((*image_draw.Image)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_draw_Image{}
+		m.Bind(&a._At, "At")
+		m.Bind(&a._Bounds, "Bounds")
+		m.Bind(&a._ColorModel, "ColorModel")
+		m.Bind(&a._Set, "Set")
+		return a
+	})
+	_register(reflect.TypeOf((*image_draw.Quantizer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_draw_Quantizer{}
+		m.Bind(&a._Quantize, "Quantize")
+		return a
+	})
+	_register(reflect.TypeOf((*image_draw.RGBA64Image)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_draw_RGBA64Image{}
+		m.Bind(&a._At, "At")
+		m.Bind(&a._Bounds, "Bounds")
+		m.Bind(&a._ColorModel, "ColorModel")
+		m.Bind(&a._RGBA64At, "RGBA64At")
+		m.Bind(&a._Set, "Set")
+		m.Bind(&a._SetRGBA64, "SetRGBA64")
+		return a
+	})
+	_register(reflect.TypeOf((*image_jpeg.Reader)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_jpeg_Reader{}
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._ReadByte, "ReadByte")
+		return a
+	})
+	_register(reflect.TypeOf((*image_png.EncoderBufferPool)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_image_png_EncoderBufferPool{}
+		m.Bind(&a._Get, "Get")
+		m.Bind(&a._Put, "Put")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ByteReader)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ByteReader{}
+		m.Bind(&a._ReadByte, "ReadByte")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ByteScanner)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ByteScanner{}
+		m.Bind(&a._ReadByte, "ReadByte")
+		m.Bind(&a._UnreadByte, "UnreadByte")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ByteWriter)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ByteWriter{}
+		m.Bind(&a._WriteByte, "WriteByte")
+		return a
+	})
+	_register(reflect.TypeOf((*io.Closer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_Closer{}
+		m.Bind(&a._Close, "Close")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ReadCloser)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ReadCloser{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Read, "Read")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ReadSeekCloser)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ReadSeekCloser{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._Seek, "Seek")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ReadSeeker)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ReadSeeker{}
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._Seek, "Seek")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ReadWriteCloser)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ReadWriteCloser{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ReadWriteSeeker)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ReadWriteSeeker{}
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._Seek, "Seek")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ReadWriter)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ReadWriter{}
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*io.Reader)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_Reader{}
+		m.Bind(&a._Read, "Read")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ReaderAt)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ReaderAt{}
+		m.Bind(&a._ReadAt, "ReadAt")
+		return a
+	})
+	_register(reflect.TypeOf((*io.ReaderFrom)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_ReaderFrom{}
+		m.Bind(&a._ReadFrom, "ReadFrom")
+		return a
+	})
+	_register(reflect.TypeOf((*io.RuneReader)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_RuneReader{}
+		m.Bind(&a._ReadRune, "ReadRune")
+		return a
+	})
+	_register(reflect.TypeOf((*io.RuneScanner)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_RuneScanner{}
+		m.Bind(&a._ReadRune, "ReadRune")
+		m.Bind(&a._UnreadRune, "UnreadRune")
+		return a
+	})
+	_register(reflect.TypeOf((*io.Seeker)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_Seeker{}
+		m.Bind(&a._Seek, "Seek")
+		return a
+	})
+	_register(reflect.TypeOf((*io.StringWriter)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_StringWriter{}
+		m.Bind(&a._WriteString, "WriteString")
+		return a
+	})
+	_register(reflect.TypeOf((*io.WriteCloser)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_WriteCloser{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*io.WriteSeeker)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_WriteSeeker{}
+		m.Bind(&a._Seek, "Seek")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*io.Writer)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_Writer{}
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*io.WriterAt)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_WriterAt{}
+		m.Bind(&a._WriteAt, "WriteAt")
+		return a
+	})
+	_register(reflect.TypeOf((*io.WriterTo)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_WriterTo{}
+		m.Bind(&a._WriteTo, "WriteTo")
+		return a
+	})
+	_register(reflect.TypeOf((*io_fs.DirEntry)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_fs_DirEntry{}
+		m.Bind(&a._Info, "Info")
+		m.Bind(&a._IsDir, "IsDir")
+		m.Bind(&a._Name, "Name")
+		m.Bind(&a._Type, "Type")
+		return a
+	})
+	_register(reflect.TypeOf((*io_fs.FS)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_fs_FS{}
+		m.Bind(&a._Open, "Open")
+		return a
+	})
+	_register(reflect.TypeOf((*io_fs.File)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_fs_File{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._Stat, "Stat")
+		return a
+	})
+	_register(reflect.TypeOf((*io_fs.GlobFS)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_fs_GlobFS{}
+		m.Bind(&a._Glob, "Glob")
+		m.Bind(&a._Open, "Open")
+		return a
+	})
+	_register(reflect.TypeOf((*io_fs.ReadDirFS)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_fs_ReadDirFS{}
+		m.Bind(&a._Open, "Open")
+		m.Bind(&a._ReadDir, "ReadDir")
+		return a
+	})
+	_register(reflect.TypeOf((*io_fs.ReadDirFile)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_fs_ReadDirFile{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._ReadDir, "ReadDir")
+		m.Bind(&a._Stat, "Stat")
+		return a
+	})
+	_register(reflect.TypeOf((*io_fs.ReadFileFS)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_fs_ReadFileFS{}
+		m.Bind(&a._Open, "Open")
+		m.Bind(&a._ReadFile, "ReadFile")
+		return a
+	})
+	_register(reflect.TypeOf((*io_fs.StatFS)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_fs_StatFS{}
+		m.Bind(&a._Open, "Open")
+		m.Bind(&a._Stat, "Stat")
+		return a
+	})
+	_register(reflect.TypeOf((*io_fs.SubFS)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_io_fs_SubFS{}
+		m.Bind(&a._Open, "Open")
+		m.Bind(&a._Sub, "Sub")
+		return a
+	})
+	_register(reflect.TypeOf((*math_rand.Source)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_math_rand_Source{}
+		m.Bind(&a._Int63, "Int63")
+		m.Bind(&a._Seed, "Seed")
+		return a
+	})
+	_register(reflect.TypeOf((*math_rand.Source64)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_math_rand_Source64{}
+		m.Bind(&a._Int63, "Int63")
+		m.Bind(&a._Seed, "Seed")
+		m.Bind(&a._Uint64, "Uint64")
+		return a
+	})
+	_register(reflect.TypeOf((*mime_multipart.File)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_mime_multipart_File{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._ReadAt, "ReadAt")
+		m.Bind(&a._Seek, "Seek")
+		return a
+	})
+	_register(reflect.TypeOf((*net.Addr)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_Addr{}
+		m.Bind(&a._Network, "Network")
+		m.Bind(&a._String, "String")
+		return a
+	})
+	_register(reflect.TypeOf((*net.Conn)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_Conn{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._LocalAddr, "LocalAddr")
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._RemoteAddr, "RemoteAddr")
+		m.Bind(&a._SetDeadline, "SetDeadline")
+		m.Bind(&a._SetReadDeadline, "SetReadDeadline")
+		m.Bind(&a._SetWriteDeadline, "SetWriteDeadline")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*net.Error)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_Error{}
+		m.Bind(&a._Error, "Error")
+		m.Bind(&a._Temporary, "Temporary")
+		m.Bind(&a._Timeout, "Timeout")
+		return a
+	})
+	_register(reflect.TypeOf((*net.Listener)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_Listener{}
+		m.Bind(&a._Accept, "Accept")
+		m.Bind(&a._Addr, "Addr")
+		m.Bind(&a._Close, "Close")
+		return a
+	})
+	_register(reflect.TypeOf((*net.PacketConn)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_PacketConn{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._LocalAddr, "LocalAddr")
+		m.Bind(&a._ReadFrom, "ReadFrom")
+		m.Bind(&a._SetDeadline, "SetDeadline")
+		m.Bind(&a._SetReadDeadline, "SetReadDeadline")
+		m.Bind(&a._SetWriteDeadline, "SetWriteDeadline")
+		m.Bind(&a._WriteTo, "WriteTo")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http.CloseNotifier)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_CloseNotifier{}
+		m.Bind(&a._CloseNotify, "CloseNotify")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http.CookieJar)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_CookieJar{}
+		m.Bind(&a._Cookies, "Cookies")
+		m.Bind(&a._SetCookies, "SetCookies")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http.File)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_File{}
+		m.Bind(&a._Close, "Close")
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._Readdir, "Readdir")
+		m.Bind(&a._Seek, "Seek")
+		m.Bind(&a._Stat, "Stat")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http.FileSystem)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_FileSystem{}
+		m.Bind(&a._Open, "Open")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http.Flusher)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_Flusher{}
+		m.Bind(&a._Flush, "Flush")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http.Handler)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_Handler{}
+		m.Bind(&a._ServeHTTP, "ServeHTTP")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http.Hijacker)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_Hijacker{}
+		m.Bind(&a._Hijack, "Hijack")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http.Pusher)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_Pusher{}
+		m.Bind(&a._Push, "Push")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http.ResponseWriter)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_ResponseWriter{}
+		m.Bind(&a._Header, "Header")
+		m.Bind(&a._Write, "Write")
+		m.Bind(&a._WriteHeader, "WriteHeader")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http.RoundTripper)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_RoundTripper{}
+		m.Bind(&a._RoundTrip, "RoundTrip")
+		return a
+	})
+	_register(reflect.TypeOf((*net_http_cookiejar.PublicSuffixList)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_http_cookiejar_PublicSuffixList{}
+		m.Bind(&a._PublicSuffix, "PublicSuffix")
+		m.Bind(&a._String, "String")
+		return a
+	})
+	_register(reflect.TypeOf((*net_smtp.Auth)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_net_smtp_Auth{}
+		m.Bind(&a._Next, "Next")
+		m.Bind(&a._Start, "Start")
+		return a
+	})
+	_register(reflect.TypeOf((*os.Signal)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_os_Signal{}
+		m.Bind(&a._Signal, "Signal")
+		m.Bind(&a._String, "String")
+		return a
+	})
+	_register(reflect.TypeOf((*runtime.Error)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_runtime_Error{}
+		m.Bind(&a._Error, "Error")
+		m.Bind(&a._RuntimeError, "RuntimeError")
+		return a
+	})
+	_register(reflect.TypeOf((*sort.Interface)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_sort_Interface{}
+		m.Bind(&a._Len, "Len")
+		m.Bind(&a._Less, "Less")
+		m.Bind(&a._Swap, "Swap")
+		return a
+	})
+	_register(reflect.TypeOf((*sync.Locker)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_sync_Locker{}
+		m.Bind(&a._Lock, "Lock")
+		m.Bind(&a._Unlock, "Unlock")
+		return a
+	})
+	_register(reflect.TypeOf((*syscall.Conn)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_syscall_Conn{}
+		m.Bind(&a._SyscallConn, "SyscallConn")
+		return a
+	})
+	_register(reflect.TypeOf((*syscall.RawConn)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_syscall_RawConn{}
+		m.Bind(&a._Control, "Control")
+		m.Bind(&a._Read, "Read")
+		m.Bind(&a._Write, "Write")
+		return a
+	})
+	_register(reflect.TypeOf((*testing_quick.Generator)(nil)).Elem(), func(m github_com_glojurelang_glojure_pkg_lang.Methods) interface{} {
+		a := &_testing_quick_Generator{}
+		m.Bind(&a._Generate, "Generate")
+		return a
+	})
+}
+
+// _compress_flate_Reader adapts functions to compress/flate.Reader.
+type _compress_flate_Reader struct {
+	_Read     func([]byte) (int, error)
+	_ReadByte func() (byte, error)
+}
+
+func (a *_compress_flate_Reader) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_compress_flate_Reader) ReadByte() (byte, error) {
+	return a._ReadByte()
+}
+
+// _compress_flate_Resetter adapts functions to compress/flate.Resetter.
+type _compress_flate_Resetter struct {
+	_Reset func(io.Reader, []byte) error
+}
+
+func (a *_compress_flate_Resetter) Reset(p0 io.Reader, p1 []byte) error {
+	return a._Reset(p0, p1)
+}
+
+// _compress_zlib_Resetter adapts functions to compress/zlib.Resetter.
+type _compress_zlib_Resetter struct {
+	_Reset func(io.Reader, []byte) error
+}
+
+func (a *_compress_zlib_Resetter) Reset(p0 io.Reader, p1 []byte) error {
+	return a._Reset(p0, p1)
+}
+
+// _crypto_Decrypter adapts functions to crypto.Decrypter.
+type _crypto_Decrypter struct {
+	_Decrypt func(io.Reader, []byte, crypto.DecrypterOpts) ([]byte, error)
+	_Public  func() crypto.PublicKey
+}
+
+func (a *_crypto_Decrypter) Decrypt(p0 io.Reader, p1 []byte, p2 crypto.DecrypterOpts) ([]byte, error) {
+	return a._Decrypt(p0, p1, p2)
+}
+
+func (a *_crypto_Decrypter) Public() crypto.PublicKey {
+	return a._Public()
+}
+
+// _crypto_Signer adapts functions to crypto.Signer.
+type _crypto_Signer struct {
+	_Public func() crypto.PublicKey
+	_Sign   func(io.Reader, []byte, crypto.SignerOpts) ([]byte, error)
+}
+
+func (a *_crypto_Signer) Public() crypto.PublicKey {
+	return a._Public()
+}
+
+func (a *_crypto_Signer) Sign(p0 io.Reader, p1 []byte, p2 crypto.SignerOpts) ([]byte, error) {
+	return a._Sign(p0, p1, p2)
+}
+
+// _crypto_SignerOpts adapts functions to crypto.SignerOpts.
+type _crypto_SignerOpts struct {
+	_HashFunc func() crypto.Hash
+}
+
+func (a *_crypto_SignerOpts) HashFunc() crypto.Hash {
+	return a._HashFunc()
+}
+
+// _crypto_cipher_AEAD adapts functions to crypto/cipher.AEAD.
+type _crypto_cipher_AEAD struct {
+	_NonceSize func() int
+	_Open      func([]byte, []byte, []byte, []byte) ([]byte, error)
+	_Overhead  func() int
+	_Seal      func([]byte, []byte, []byte, []byte) []byte
+}
+
+func (a *_crypto_cipher_AEAD) NonceSize() int {
+	return a._NonceSize()
+}
+
+func (a *_crypto_cipher_AEAD) Open(p0 []byte, p1 []byte, p2 []byte, p3 []byte) ([]byte, error) {
+	return a._Open(p0, p1, p2, p3)
+}
+
+func (a *_crypto_cipher_AEAD) Overhead() int {
+	return a._Overhead()
+}
+
+func (a *_crypto_cipher_AEAD) Seal(p0 []byte, p1 []byte, p2 []byte, p3 []byte) []byte {
+	return a._Seal(p0, p1, p2, p3)
+}
+
+// _crypto_cipher_Block adapts functions to crypto/cipher.Block.
+type _crypto_cipher_Block struct {
+	_BlockSize func() int
+	_Decrypt   func([]byte, []byte)
+	_Encrypt   func([]byte, []byte)
+}
+
+func (a *_crypto_cipher_Block) BlockSize() int {
+	return a._BlockSize()
+}
+
+func (a *_crypto_cipher_Block) Decrypt(p0 []byte, p1 []byte) {
+	a._Decrypt(p0, p1)
+}
+
+func (a *_crypto_cipher_Block) Encrypt(p0 []byte, p1 []byte) {
+	a._Encrypt(p0, p1)
+}
+
+// _crypto_cipher_BlockMode adapts functions to crypto/cipher.BlockMode.
+type _crypto_cipher_BlockMode struct {
+	_BlockSize   func() int
+	_CryptBlocks func([]byte, []byte)
+}
+
+func (a *_crypto_cipher_BlockMode) BlockSize() int {
+	return a._BlockSize()
+}
+
+func (a *_crypto_cipher_BlockMode) CryptBlocks(p0 []byte, p1 []byte) {
+	a._CryptBlocks(p0, p1)
+}
+
+// _crypto_cipher_Stream adapts functions to crypto/cipher.Stream.
+type _crypto_cipher_Stream struct {
+	_XORKeyStream func([]byte, []byte)
+}
+
+func (a *_crypto_cipher_Stream) XORKeyStream(p0 []byte, p1 []byte) {
+	a._XORKeyStream(p0, p1)
+}
+
+// _crypto_elliptic_Curve adapts functions to crypto/elliptic.Curve.
+type _crypto_elliptic_Curve struct {
+	_Add            func(*math_big.Int, *math_big.Int, *math_big.Int, *math_big.Int) (*math_big.Int, *math_big.Int)
+	_Double         func(*math_big.Int, *math_big.Int) (*math_big.Int, *math_big.Int)
+	_IsOnCurve      func(*math_big.Int, *math_big.Int) bool
+	_Params         func() *crypto_elliptic.CurveParams
+	_ScalarBaseMult func([]byte) (*math_big.Int, *math_big.Int)
+	_ScalarMult     func(*math_big.Int, *math_big.Int, []byte) (*math_big.Int, *math_big.Int)
+}
+
+func (a *_crypto_elliptic_Curve) Add(p0 *math_big.Int, p1 *math_big.Int, p2 *math_big.Int, p3 *math_big.Int) (*math_big.Int, *math_big.Int) {
+	return a._Add(p0, p1, p2, p3)
+}
+
+func (a *_crypto_elliptic_Curve) Double(p0 *math_big.Int, p1 *math_big.Int) (*math_big.Int, *math_big.Int) {
+	return a._Double(p0, p1)
+}
+
+func (a *_crypto_elliptic_Curve) IsOnCurve(p0 *math_big.Int, p1 *math_big.Int) bool {
+	return a._IsOnCurve(p0, p1)
+}
+
+func (a *_crypto_elliptic_Curve) Params() *crypto_elliptic.CurveParams {
+	return a._Params()
+}
+
+func (a *_crypto_elliptic_Curve) ScalarBaseMult(p0 []byte) (*math_big.Int, *math_big.Int) {
+	return a._ScalarBaseMult(p0)
+}
+
+func (a *_crypto_elliptic_Curve) ScalarMult(p0 *math_big.Int, p1 *math_big.Int, p2 []byte) (*math_big.Int, *math_big.Int) {
+	return a._ScalarMult(p0, p1, p2)
+}
+
+// _crypto_tls_ClientSessionCache adapts functions to crypto/tls.ClientSessionCache.
+type _crypto_tls_ClientSessionCache struct {
+	_Get func(string) (*crypto_tls.ClientSessionState, bool)
+	_Put func(string, *crypto_tls.ClientSessionState)
+}
+
+func (a *_crypto_tls_ClientSessionCache) Get(p0 string) (*crypto_tls.ClientSessionState, bool) {
+	return a._Get(p0)
+}
+
+func (a *_crypto_tls_ClientSessionCache) Put(p0 string, p1 *crypto_tls.ClientSessionState) {
+	a._Put(p0, p1)
+}
+
+// _database_sql_Result adapts functions to database/sql.Result.
+type _database_sql_Result struct {
+	_LastInsertId func() (int64, error)
+	_RowsAffected func() (int64, error)
+}
+
+func (a *_database_sql_Result) LastInsertId() (int64, error) {
+	return a._LastInsertId()
+}
+
+func (a *_database_sql_Result) RowsAffected() (int64, error) {
+	return a._RowsAffected()
+}
+
+// _database_sql_driver_ColumnConverter adapts functions to database/sql/driver.ColumnConverter.
+type _database_sql_driver_ColumnConverter struct {
+	_ColumnConverter func(int) database_sql_driver.ValueConverter
+}
+
+func (a *_database_sql_driver_ColumnConverter) ColumnConverter(p0 int) database_sql_driver.ValueConverter {
+	return a._ColumnConverter(p0)
+}
+
+// _database_sql_driver_Conn adapts functions to database/sql/driver.Conn.
+type _database_sql_driver_Conn struct {
+	_Begin   func() (database_sql_driver.Tx, error)
+	_Close   func() error
+	_Prepare func(string) (database_sql_driver.Stmt, error)
+}
+
+func (a *_database_sql_driver_Conn) Begin() (database_sql_driver.Tx, error) {
+	return a._Begin()
+}
+
+func (a *_database_sql_driver_Conn) Close() error {
+	return a._Close()
+}
+
+func (a *_database_sql_driver_Conn) Prepare(p0 string) (database_sql_driver.Stmt, error) {
+	return a._Prepare(p0)
+}
+
+// _database_sql_driver_ConnBeginTx adapts functions to database/sql/driver.ConnBeginTx.
+type _database_sql_driver_ConnBeginTx struct {
+	_BeginTx func(context.Context, database_sql_driver.TxOptions) (database_sql_driver.Tx, error)
+}
+
+func (a *_database_sql_driver_ConnBeginTx) BeginTx(p0 context.Context, p1 database_sql_driver.TxOptions) (database_sql_driver.Tx, error) {
+	return a._BeginTx(p0, p1)
+}
+
+// _database_sql_driver_ConnPrepareContext adapts functions to database/sql/driver.ConnPrepareContext.
+type _database_sql_driver_ConnPrepareContext struct {
+	_PrepareContext func(context.Context, string) (database_sql_driver.Stmt, error)
+}
+
+func (a *_database_sql_driver_ConnPrepareContext) PrepareContext(p0 context.Context, p1 string) (database_sql_driver.Stmt, error) {
+	return a._PrepareContext(p0, p1)
+}
+
+// _database_sql_driver_Connector adapts functions to database/sql/driver.Connector.
+type _database_sql_driver_Connector struct {
+	_Connect func(context.Context) (database_sql_driver.Conn, error)
+	_Driver  func() database_sql_driver.Driver
+}
+
+func (a *_database_sql_driver_Connector) Connect(p0 context.Context) (database_sql_driver.Conn, error) {
+	return a._Connect(p0)
+}
+
+func (a *_database_sql_driver_Connector) Driver() database_sql_driver.Driver {
+	return a._Driver()
+}
+
+// _database_sql_driver_Driver adapts functions to database/sql/driver.Driver.
+type _database_sql_driver_Driver struct {
+	_Open func(string) (database_sql_driver.Conn, error)
+}
+
+func (a *_database_sql_driver_Driver) Open(p0 string) (database_sql_driver.Conn, error) {
+	return a._Open(p0)
+}
+
+// _database_sql_driver_DriverContext adapts functions to database/sql/driver.DriverContext.
+type _database_sql_driver_DriverContext struct {
+	_OpenConnector func(string) (database_sql_driver.Connector, error)
+}
+
+func (a *_database_sql_driver_DriverContext) OpenConnector(p0 string) (database_sql_driver.Connector, error) {
+	return a._OpenConnector(p0)
+}
+
+// _database_sql_driver_Execer adapts functions to database/sql/driver.Execer.
+type _database_sql_driver_Execer struct {
+	_Exec func(string, []database_sql_driver.Value) (database_sql_driver.Result, error)
+}
+
+func (a *_database_sql_driver_Execer) Exec(p0 string, p1 []database_sql_driver.Value) (database_sql_driver.Result, error) {
+	return a._Exec(p0, p1)
+}
+
+// _database_sql_driver_ExecerContext adapts functions to database/sql/driver.ExecerContext.
+type _database_sql_driver_ExecerContext struct {
+	_ExecContext func(context.Context, string, []database_sql_driver.NamedValue) (database_sql_driver.Result, error)
+}
+
+func (a *_database_sql_driver_ExecerContext) ExecContext(p0 context.Context, p1 string, p2 []database_sql_driver.NamedValue) (database_sql_driver.Result, error) {
+	return a._ExecContext(p0, p1, p2)
+}
+
+// _database_sql_driver_NamedValueChecker adapts functions to database/sql/driver.NamedValueChecker.
+type _database_sql_driver_NamedValueChecker struct {
+	_CheckNamedValue func(*database_sql_driver.NamedValue) error
+}
+
+func (a *_database_sql_driver_NamedValueChecker) CheckNamedValue(p0 *database_sql_driver.NamedValue) error {
+	return a._CheckNamedValue(p0)
+}
+
+// _database_sql_driver_Pinger adapts functions to database/sql/driver.Pinger.
+type _database_sql_driver_Pinger struct {
+	_Ping func(context.Context) error
+}
+
+func (a *_database_sql_driver_Pinger) Ping(p0 context.Context) error {
+	return a._Ping(p0)
+}
+
+// _database_sql_driver_Queryer adapts functions to database/sql/driver.Queryer.
+type _database_sql_driver_Queryer struct {
+	_Query func(string, []database_sql_driver.Value) (database_sql_driver.Rows, error)
+}
+
+func (a *_database_sql_driver_Queryer) Query(p0 string, p1 []database_sql_driver.Value) (database_sql_driver.Rows, error) {
+	return a._Query(p0, p1)
+}
+
+// _database_sql_driver_QueryerContext adapts functions to database/sql/driver.QueryerContext.
+type _database_sql_driver_QueryerContext struct {
+	_QueryContext func(context.Context, string, []database_sql_driver.NamedValue) (database_sql_driver.Rows, error)
+}
+
+func (a *_database_sql_driver_QueryerContext) QueryContext(p0 context.Context, p1 string, p2 []database_sql_driver.NamedValue) (database_sql_driver.Rows, error) {
+	return a._QueryContext(p0, p1, p2)
+}
+
+// _database_sql_driver_Result adapts functions to database/sql/driver.Result.
+type _database_sql_driver_Result struct {
+	_LastInsertId func() (int64, error)
+	_RowsAffected func() (int64, error)
+}
+
+func (a *_database_sql_driver_Result) LastInsertId() (int64, error) {
+	return a._LastInsertId()
+}
+
+func (a *_database_sql_driver_Result) RowsAffected() (int64, error) {
+	return a._RowsAffected()
+}
+
+// _database_sql_driver_Rows adapts functions to database/sql/driver.Rows.
+type _database_sql_driver_Rows struct {
+	_Close   func() error
+	_Columns func() []string
+	_Next    func([]database_sql_driver.Value) error
+}
+
+func (a *_database_sql_driver_Rows) Close() error {
+	return a._Close()
+}
+
+func (a *_database_sql_driver_Rows) Columns() []string {
+	return a._Columns()
+}
+
+func (a *_database_sql_driver_Rows) Next(p0 []database_sql_driver.Value) error {
+	return a._Next(p0)
+}
+
+// _database_sql_driver_RowsColumnTypeDatabaseTypeName adapts functions to database/sql/driver.RowsColumnTypeDatabaseTypeName.
+type _database_sql_driver_RowsColumnTypeDatabaseTypeName struct {
+	_Close                      func() error
+	_ColumnTypeDatabaseTypeName func(int) string
+	_Columns                    func() []string
+	_Next                       func([]database_sql_driver.Value) error
+}
+
+func (a *_database_sql_driver_RowsColumnTypeDatabaseTypeName) Close() error {
+	return a._Close()
+}
+
+func (a *_database_sql_driver_RowsColumnTypeDatabaseTypeName) ColumnTypeDatabaseTypeName(p0 int) string {
+	return a._ColumnTypeDatabaseTypeName(p0)
+}
+
+func (a *_database_sql_driver_RowsColumnTypeDatabaseTypeName) Columns() []string {
+	return a._Columns()
+}
+
+func (a *_database_sql_driver_RowsColumnTypeDatabaseTypeName) Next(p0 []database_sql_driver.Value) error {
+	return a._Next(p0)
+}
+
+// _database_sql_driver_RowsColumnTypeLength adapts functions to database/sql/driver.RowsColumnTypeLength.
+type _database_sql_driver_RowsColumnTypeLength struct {
+	_Close            func() error
+	_ColumnTypeLength func(int) (int64, bool)
+	_Columns          func() []string
+	_Next             func([]database_sql_driver.Value) error
+}
+
+func (a *_database_sql_driver_RowsColumnTypeLength) Close() error {
+	return a._Close()
+}
+
+func (a *_database_sql_driver_RowsColumnTypeLength) ColumnTypeLength(p0 int) (int64, bool) {
+	return a._ColumnTypeLength(p0)
+}
+
+func (a *_database_sql_driver_RowsColumnTypeLength) Columns() []string {
+	return a._Columns()
+}
+
+func (a *_database_sql_driver_RowsColumnTypeLength) Next(p0 []database_sql_driver.Value) error {
+	return a._Next(p0)
+}
+
+// _database_sql_driver_RowsColumnTypeNullable adapts functions to database/sql/driver.RowsColumnTypeNullable.
+type _database_sql_driver_RowsColumnTypeNullable struct {
+	_Close              func() error
+	_ColumnTypeNullable func(int) (bool, bool)
+	_Columns            func() []string
+	_Next               func([]database_sql_driver.Value) error
+}
+
+func (a *_database_sql_driver_RowsColumnTypeNullable) Close() error {
+	return a._Close()
+}
+
+func (a *_database_sql_driver_RowsColumnTypeNullable) ColumnTypeNullable(p0 int) (bool, bool) {
+	return a._ColumnTypeNullable(p0)
+}
+
+func (a *_database_sql_driver_RowsColumnTypeNullable) Columns() []string {
+	return a._Columns()
+}
+
+func (a *_database_sql_driver_RowsColumnTypeNullable) Next(p0 []database_sql_driver.Value) error {
+	return a._Next(p0)
+}
+
+// _database_sql_driver_RowsColumnTypePrecisionScale adapts functions to database/sql/driver.RowsColumnTypePrecisionScale.
+type _database_sql_driver_RowsColumnTypePrecisionScale struct {
+	_Close                    func() error
+	_ColumnTypePrecisionScale func(int) (int64, int64, bool)
+	_Columns                  func() []string
+	_Next                     func([]database_sql_driver.Value) error
+}
+
+func (a *_database_sql_driver_RowsColumnTypePrecisionScale) Close() error {
+	return a._Close()
+}
+
+func (a *_database_sql_driver_RowsColumnTypePrecisionScale) ColumnTypePrecisionScale(p0 int) (int64, int64, bool) {
+	return a._ColumnTypePrecisionScale(p0)
+}
+
+func (a *_database_sql_driver_RowsColumnTypePrecisionScale) Columns() []string {
+	return a._Columns()
+}
+
+func (a *_database_sql_driver_RowsColumnTypePrecisionScale) Next(p0 []database_sql_driver.Value) error {
+	return a._Next(p0)
+}
+
+// _database_sql_driver_RowsColumnTypeScanType adapts functions to database/sql/driver.RowsColumnTypeScanType.
+type _database_sql_driver_RowsColumnTypeScanType struct {
+	_Close              func() error
+	_ColumnTypeScanType func(int) reflect.Type
+	_Columns            func() []string
+	_Next               func([]database_sql_driver.Value) error
+}
+
+func (a *_database_sql_driver_RowsColumnTypeScanType) Close() error {
+	return a._Close()
+}
+
+func (a *_database_sql_driver_RowsColumnTypeScanType) ColumnTypeScanType(p0 int) reflect.Type {
+	return a._ColumnTypeScanType(p0)
+}
+
+func (a *_database_sql_driver_RowsColumnTypeScanType) Columns() []string {
+	return a._Columns()
+}
+
+func (a *_database_sql_driver_RowsColumnTypeScanType) Next(p0 []database_sql_driver.Value) error {
+	return a._Next(p0)
+}
+
+// _database_sql_driver_RowsNextResultSet adapts functions to database/sql/driver.RowsNextResultSet.
+type _database_sql_driver_RowsNextResultSet struct {
+	_Close            func() error
+	_Columns          func() []string
+	_HasNextResultSet func() bool
+	_Next             func([]database_sql_driver.Value) error
+	_NextResultSet    func() error
+}
+
+func (a *_database_sql_driver_RowsNextResultSet) Close() error {
+	return a._Close()
+}
+
+func (a *_database_sql_driver_RowsNextResultSet) Columns() []string {
+	return a._Columns()
+}
+
+func (a *_database_sql_driver_RowsNextResultSet) HasNextResultSet() bool {
+	return a._HasNextResultSet()
+}
+
+func (a *_database_sql_driver_RowsNextResultSet) Next(p0 []database_sql_driver.Value) error {
+	return a._Next(p0)
+}
+
+func (a *_database_sql_driver_RowsNextResultSet) NextResultSet() error {
+	return a._NextResultSet()
+}
+
+// _database_sql_driver_SessionResetter adapts functions to database/sql/driver.SessionResetter.
+type _database_sql_driver_SessionResetter struct {
+	_ResetSession func(context.Context) error
+}
+
+func (a *_database_sql_driver_SessionResetter) ResetSession(p0 context.Context) error {
+	return a._ResetSession(p0)
+}
+
+// _database_sql_driver_Stmt adapts functions to database/sql/driver.Stmt.
+type _database_sql_driver_Stmt struct {
+	_Close    func() error
+	_Exec     func([]database_sql_driver.Value) (database_sql_driver.Result, error)
+	_NumInput func() int
+	_Query    func([]database_sql_driver.Value) (database_sql_driver.Rows, error)
+}
+
+func (a *_database_sql_driver_Stmt) Close() error {
+	return a._Close()
+}
+
+func (a *_database_sql_driver_Stmt) Exec(p0 []database_sql_driver.Value) (database_sql_driver.Result, error) {
+	return a._Exec(p0)
+}
+
+func (a *_database_sql_driver_Stmt) NumInput() int {
+	return a._NumInput()
+}
+
+func (a *_database_sql_driver_Stmt) Query(p0 []database_sql_driver.Value) (database_sql_driver.Rows, error) {
+	return a._Query(p0)
+}
+
+// _database_sql_driver_StmtExecContext adapts functions to database/sql/driver.StmtExecContext.
+type _database_sql_driver_StmtExecContext struct {
+	_ExecContext func(context.Context, []database_sql_driver.NamedValue) (database_sql_driver.Result, error)
+}
+
+func (a *_database_sql_driver_StmtExecContext) ExecContext(p0 context.Context, p1 []database_sql_driver.NamedValue) (database_sql_driver.Result, error) {
+	return a._ExecContext(p0, p1)
+}
+
+// _database_sql_driver_StmtQueryContext adapts functions to database/sql/driver.StmtQueryContext.
+type _database_sql_driver_StmtQueryContext struct {
+	_QueryContext func(context.Context, []database_sql_driver.NamedValue) (database_sql_driver.Rows, error)
+}
+
+func (a *_database_sql_driver_StmtQueryContext) QueryContext(p0 context.Context, p1 []database_sql_driver.NamedValue) (database_sql_driver.Rows, error) {
+	return a._QueryContext(p0, p1)
+}
+
+// _database_sql_driver_Tx adapts functions to database/sql/driver.Tx.
+type _database_sql_driver_Tx struct {
+	_Commit   func() error
+	_Rollback func() error
+}
+
+func (a *_database_sql_driver_Tx) Commit() error {
+	return a._Commit()
+}
+
+func (a *_database_sql_driver_Tx) Rollback() error {
+	return a._Rollback()
+}
+
+// _database_sql_driver_Validator adapts functions to database/sql/driver.Validator.
+type _database_sql_driver_Validator struct {
+	_IsValid func() bool
+}
+
+func (a *_database_sql_driver_Validator) IsValid() bool {
+	return a._IsValid()
+}
+
+// _database_sql_driver_Valuer adapts functions to database/sql/driver.Valuer.
+type _database_sql_driver_Valuer struct {
+	_Value func() (database_sql_driver.Value, error)
+}
+
+func (a *_database_sql_driver_Valuer) Value() (database_sql_driver.Value, error) {
+	return a._Value()
+}
+
+// _debug_dwarf_Type adapts functions to debug/dwarf.Type.
+type _debug_dwarf_Type struct {
+	_Common func() *debug_dwarf.CommonType
+	_Size   func() int64
+	_String func() string
+}
+
+func (a *_debug_dwarf_Type) Common() *debug_dwarf.CommonType {
+	return a._Common()
+}
+
+func (a *_debug_dwarf_Type) Size() int64 {
+	return a._Size()
+}
+
+func (a *_debug_dwarf_Type) String() string {
+	return a._String()
+}
+
+// _debug_macho_Load adapts functions to debug/macho.Load.
+type _debug_macho_Load struct {
+	_Raw func() []byte
+}
+
+func (a *_debug_macho_Load) Raw() []byte {
+	return a._Raw()
+}
+
+// _encoding_BinaryMarshaler adapts functions to encoding.BinaryMarshaler.
+type _encoding_BinaryMarshaler struct {
+	_MarshalBinary func() ([]byte, error)
+}
+
+func (a *_encoding_BinaryMarshaler) MarshalBinary() ([]byte, error) {
+	return a._MarshalBinary()
+}
+
+// _encoding_BinaryUnmarshaler adapts functions to encoding.BinaryUnmarshaler.
+type _encoding_BinaryUnmarshaler struct {
+	_UnmarshalBinary func([]byte) error
+}
+
+func (a *_encoding_BinaryUnmarshaler) UnmarshalBinary(p0 []byte) error {
+	return a._UnmarshalBinary(p0)
+}
+
+// _encoding_TextMarshaler adapts functions to encoding.TextMarshaler.
+type _encoding_TextMarshaler struct {
+	_MarshalText func() ([]byte, error)
+}
+
+func (a *_encoding_TextMarshaler) MarshalText() ([]byte, error) {
+	return a._MarshalText()
+}
+
+// _encoding_TextUnmarshaler adapts functions to encoding.TextUnmarshaler.
+type _encoding_TextUnmarshaler struct {
+	_UnmarshalText func([]byte) error
+}
+
+func (a *_encoding_TextUnmarshaler) UnmarshalText(p0 []byte) error {
+	return a._UnmarshalText(p0)
+}
+
+// _encoding_binary_AppendByteOrder adapts functions to encoding/binary.AppendByteOrder.
+type _encoding_binary_AppendByteOrder struct {
+	_AppendUint16 func([]byte, uint16) []byte
+	_AppendUint32 func([]byte, uint32) []byte
+	_AppendUint64 func([]byte, uint64) []byte
+	_String       func() string
+}
+
+func (a *_encoding_binary_AppendByteOrder) AppendUint16(p0 []byte, p1 uint16) []byte {
+	return a._AppendUint16(p0, p1)
+}
+
+func (a *_encoding_binary_AppendByteOrder) AppendUint32(p0 []byte, p1 uint32) []byte {
+	return a._AppendUint32(p0, p1)
+}
+
+func (a *_encoding_binary_AppendByteOrder) AppendUint64(p0 []byte, p1 uint64) []byte {
+	return a._AppendUint64(p0, p1)
+}
+
+func (a *_encoding_binary_AppendByteOrder) String() string {
+	return a._String()
+}
+
+// _encoding_binary_ByteOrder adapts functions to encoding/binary.ByteOrder.
+type _encoding_binary_ByteOrder struct {
+	_PutUint16 func([]byte, uint16)
+	_PutUint32 func([]byte, uint32)
+	_PutUint64 func([]byte, uint64)
+	_String    func() string
+	_Uint16    func([]byte) uint16
+	_Uint32    func([]byte) uint32
+	_Uint64    func([]byte) uint64
+}
+
+func (a *_encoding_binary_ByteOrder) PutUint16(p0 []byte, p1 uint16) {
+	a._PutUint16(p0, p1)
+}
+
+func (a *_encoding_binary_ByteOrder) PutUint32(p0 []byte, p1 uint32) {
+	a._PutUint32(p0, p1)
+}
+
+func (a *_encoding_binary_ByteOrder) PutUint64(p0 []byte, p1 uint64) {
+	a._PutUint64(p0, p1)
+}
+
+func (a *_encoding_binary_ByteOrder) String() string {
+	return a._String()
+}
+
+func (a *_encoding_binary_ByteOrder) Uint16(p0 []byte) uint16 {
+	return a._Uint16(p0)
+}
+
+func (a *_encoding_binary_ByteOrder) Uint32(p0 []byte) uint32 {
+	return a._Uint32(p0)
+}
+
+func (a *_encoding_binary_ByteOrder) Uint64(p0 []byte) uint64 {
+	return a._Uint64(p0)
+}
+
+// _encoding_gob_GobDecoder adapts functions to encoding/gob.GobDecoder.
+type _encoding_gob_GobDecoder struct {
+	_GobDecode func([]byte) error
+}
+
+func (a *_encoding_gob_GobDecoder) GobDecode(p0 []byte) error {
+	return a._GobDecode(p0)
+}
+
+// _encoding_gob_GobEncoder adapts functions to encoding/gob.GobEncoder.
+type _encoding_gob_GobEncoder struct {
+	_GobEncode func() ([]byte, error)
+}
+
+func (a *_encoding_gob_GobEncoder) GobEncode() ([]byte, error) {
+	return a._GobEncode()
+}
+
+// _encoding_xml_Marshaler adapts functions to encoding/xml.Marshaler.
+type _encoding_xml_Marshaler struct {
+	_MarshalXML func(*encoding_xml.Encoder, encoding_xml.StartElement) error
+}
+
+func (a *_encoding_xml_Marshaler) MarshalXML(p0 *encoding_xml.Encoder, p1 encoding_xml.StartElement) error {
+	return a._MarshalXML(p0, p1)
+}
+
+// _encoding_xml_MarshalerAttr adapts functions to encoding/xml.MarshalerAttr.
+type _encoding_xml_MarshalerAttr struct {
+	_MarshalXMLAttr func(encoding_xml.Name) (encoding_xml.Attr, error)
+}
+
+func (a *_encoding_xml_MarshalerAttr) MarshalXMLAttr(p0 encoding_xml.Name) (encoding_xml.Attr, error) {
+	return a._MarshalXMLAttr(p0)
+}
+
+// _encoding_xml_TokenReader adapts functions to encoding/xml.TokenReader.
+type _encoding_xml_TokenReader struct {
+	_Token func() (encoding_xml.Token, error)
+}
+
+func (a *_encoding_xml_TokenReader) Token() (encoding_xml.Token, error) {
+	return a._Token()
+}
+
+// _encoding_xml_Unmarshaler adapts functions to encoding/xml.Unmarshaler.
+type _encoding_xml_Unmarshaler struct {
+	_UnmarshalXML func(*encoding_xml.Decoder, encoding_xml.StartElement) error
+}
+
+func (a *_encoding_xml_Unmarshaler) UnmarshalXML(p0 *encoding_xml.Decoder, p1 encoding_xml.StartElement) error {
+	return a._UnmarshalXML(p0, p1)
+}
+
+// _encoding_xml_UnmarshalerAttr adapts functions to encoding/xml.UnmarshalerAttr.
+type _encoding_xml_UnmarshalerAttr struct {
+	_UnmarshalXMLAttr func(encoding_xml.Attr) error
+}
+
+func (a *_encoding_xml_UnmarshalerAttr) UnmarshalXMLAttr(p0 encoding_xml.Attr) error {
+	return a._UnmarshalXMLAttr(p0)
+}
+
+// _expvar_Var adapts functions to expvar.Var.
+type _expvar_Var struct {
+	_String func() string
+}
+
+func (a *_expvar_Var) String() string {
+	return a._String()
+}
+
+// _flag_Value adapts functions to flag.Value.
+type _flag_Value struct {
+	_Set    func(string) error
+	_String func() string
+}
+
+func (a *_flag_Value) Set(p0 string) error {
+	return a._Set(p0)
+}
+
+func (a *_flag_Value) String() string {
+	return a._String()
+}
+
+// _fmt_Formatter adapts functions to fmt.Formatter.
+type _fmt_Formatter struct {
+	_Format func(fmt.State, rune)
+}
+
+func (a *_fmt_Formatter) Format(p0 fmt.State, p1 rune) {
+	a._Format(p0, p1)
+}
+
+// _fmt_GoStringer adapts functions to fmt.GoStringer.
+type _fmt_GoStringer struct {
+	_GoString func() string
+}
+
+func (a *_fmt_GoStringer) GoString() string {
+	return a._GoString()
+}
+
+// _fmt_ScanState adapts functions to fmt.ScanState.
+type _fmt_ScanState struct {
+	_Read       func([]byte) (int, error)
+	_ReadRune   func() (rune, int, error)
+	_SkipSpace  func()
+	_Token      func(bool, func(rune) bool) ([]byte, error)
+	_UnreadRune func() error
+	_Width      func() (int, bool)
+}
+
+func (a *_fmt_ScanState) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_fmt_ScanState) ReadRune() (rune, int, error) {
+	return a._ReadRune()
+}
+
+func (a *_fmt_ScanState) SkipSpace() {
+	a._SkipSpace()
+}
+
+func (a *_fmt_ScanState) Token(p0 bool, p1 func(rune) bool) ([]byte, error) {
+	return a._Token(p0, p1)
+}
+
+func (a *_fmt_ScanState) UnreadRune() error {
+	return a._UnreadRune()
+}
+
+func (a *_fmt_ScanState) Width() (int, bool) {
+	return a._Width()
+}
+
+// _fmt_Scanner adapts functions to fmt.Scanner.
+type _fmt_Scanner struct {
+	_Scan func(fmt.ScanState, rune) error
+}
+
+func (a *_fmt_Scanner) Scan(p0 fmt.ScanState, p1 rune) error {
+	return a._Scan(p0, p1)
+}
+
+// _fmt_State adapts functions to fmt.State.
+type _fmt_State struct {
+	_Flag      func(int) bool
+	_Precision func() (int, bool)
+	_Width     func() (int, bool)
+	_Write     func([]byte) (int, error)
+}
+
+func (a *_fmt_State) Flag(p0 int) bool {
+	return a._Flag(p0)
+}
+
+func (a *_fmt_State) Precision() (int, bool) {
+	return a._Precision()
+}
+
+func (a *_fmt_State) Width() (int, bool) {
+	return a._Width()
+}
+
+func (a *_fmt_State) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _fmt_Stringer adapts functions to fmt.Stringer.
+type _fmt_Stringer struct {
+	_String func() string
+}
+
+func (a *_fmt_Stringer) String() string {
+	return a._String()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_Counted adapts functions to github.com/glojurelang/glojure/pkg/lang.Counted.
+type _github_com_glojurelang_glojure_pkg_lang_Counted struct {
+	_Count func() int
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Counted) Count() int {
+	return a._Count()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_Environment adapts functions to github.com/glojurelang/glojure/pkg/lang.Environment.
+type _github_com_glojurelang_glojure_pkg_lang_Environment struct {
+	_BindLocal           func(*github_com_glojurelang_glojure_pkg_lang.Symbol, interface{})
+	_Context             func() context.Context
+	_CurrentNamespace    func() *github_com_glojurelang_glojure_pkg_lang.Namespace
+	_DefVar              func(*github_com_glojurelang_glojure_pkg_lang.Symbol, interface{}) *github_com_glojurelang_glojure_pkg_lang.Var
+	_Errorf              func(interface{}, string, ...interface{}) error
+	_Eval                func(interface{}) (interface{}, error)
+	_EvalAST             func(interface{}) (interface{}, error)
+	_PushLoadPaths       func([]string) github_com_glojurelang_glojure_pkg_lang.Environment
+	_PushScope           func() github_com_glojurelang_glojure_pkg_lang.Environment
+	_ResolveFile         func(string) (string, bool)
+	_SetCurrentNamespace func(*github_com_glojurelang_glojure_pkg_lang.Namespace)
+	_Stderr              func() io.Writer
+	_Stdout              func() io.Writer
+	_WithRecurTarget     func(interface{}) github_com_glojurelang_glojure_pkg_lang.Environment
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) BindLocal(p0 *github_com_glojurelang_glojure_pkg_lang.Symbol, p1 interface{}) {
+	a._BindLocal(p0, p1)
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) Context() context.Context {
+	return a._Context()
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) CurrentNamespace() *github_com_glojurelang_glojure_pkg_lang.Namespace {
+	return a._CurrentNamespace()
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) DefVar(p0 *github_com_glojurelang_glojure_pkg_lang.Symbol, p1 interface{}) *github_com_glojurelang_glojure_pkg_lang.Var {
+	return a._DefVar(p0, p1)
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) Errorf(p0 interface{}, p1 string, p2 ...interface{}) error {
+	return a._Errorf(p0, p1, p2...)
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) Eval(p0 interface{}) (interface{}, error) {
+	return a._Eval(p0)
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) EvalAST(p0 interface{}) (interface{}, error) {
+	return a._EvalAST(p0)
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) PushLoadPaths(p0 []string) github_com_glojurelang_glojure_pkg_lang.Environment {
+	return a._PushLoadPaths(p0)
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) PushScope() github_com_glojurelang_glojure_pkg_lang.Environment {
+	return a._PushScope()
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) ResolveFile(p0 string) (string, bool) {
+	return a._ResolveFile(p0)
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) SetCurrentNamespace(p0 *github_com_glojurelang_glojure_pkg_lang.Namespace) {
+	a._SetCurrentNamespace(p0)
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) Stderr() io.Writer {
+	return a._Stderr()
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) Stdout() io.Writer {
+	return a._Stdout()
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Environment) WithRecurTarget(p0 interface{}) github_com_glojurelang_glojure_pkg_lang.Environment {
+	return a._WithRecurTarget(p0)
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_Hasher adapts functions to github.com/glojurelang/glojure/pkg/lang.Hasher.
+type _github_com_glojurelang_glojure_pkg_lang_Hasher struct {
+	_Hash func() uint32
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Hasher) Hash() uint32 {
+	return a._Hash()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_IDrop adapts functions to github.com/glojurelang/glojure/pkg/lang.IDrop.
+type _github_com_glojurelang_glojure_pkg_lang_IDrop struct {
+	_Drop func(int) github_com_glojurelang_glojure_pkg_lang.Sequential
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_IDrop) Drop(p0 int) github_com_glojurelang_glojure_pkg_lang.Sequential {
+	return a._Drop(p0)
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_IEditableCollection adapts functions to github.com/glojurelang/glojure/pkg/lang.IEditableCollection.
+type _github_com_glojurelang_glojure_pkg_lang_IEditableCollection struct {
+	_AsTransient func() github_com_glojurelang_glojure_pkg_lang.ITransientCollection
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_IEditableCollection) AsTransient() github_com_glojurelang_glojure_pkg_lang.ITransientCollection {
+	return a._AsTransient()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_IHashEq adapts functions to github.com/glojurelang/glojure/pkg/lang.IHashEq.
+type _github_com_glojurelang_glojure_pkg_lang_IHashEq struct {
+	_HashEq func() uint32
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_IHashEq) HashEq() uint32 {
+	return a._HashEq()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_IMeta adapts functions to github.com/glojurelang/glojure/pkg/lang.IMeta.
+type _github_com_glojurelang_glojure_pkg_lang_IMeta struct {
+	_Meta func() github_com_glojurelang_glojure_pkg_lang.IPersistentMap
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_IMeta) Meta() github_com_glojurelang_glojure_pkg_lang.IPersistentMap {
+	return a._Meta()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_IPending adapts functions to github.com/glojurelang/glojure/pkg/lang.IPending.
+type _github_com_glojurelang_glojure_pkg_lang_IPending struct {
+	_IsRealized func() bool
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_IPending) IsRealized() bool {
+	return a._IsRealized()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_MapIterator adapts functions to github.com/glojurelang/glojure/pkg/lang.MapIterator.
+type _github_com_glojurelang_glojure_pkg_lang_MapIterator struct {
+	_HasNext func() bool
+	_Next    func() *github_com_glojurelang_glojure_pkg_lang.Pair
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_MapIterator) HasNext() bool {
+	return a._HasNext()
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_MapIterator) Next() *github_com_glojurelang_glojure_pkg_lang.Pair {
+	return a._Next()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_Named adapts functions to github.com/glojurelang/glojure/pkg/lang.Named.
+type _github_com_glojurelang_glojure_pkg_lang_Named struct {
+	_Name      func() string
+	_Namespace func() string
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Named) Name() string {
+	return a._Name()
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Named) Namespace() string {
+	return a._Namespace()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_Nther adapts functions to github.com/glojurelang/glojure/pkg/lang.Nther.
+type _github_com_glojurelang_glojure_pkg_lang_Nther struct {
+	_Nth func(int) (interface{}, bool)
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Nther) Nth(p0 int) (interface{}, bool) {
+	return a._Nth(p0)
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_Reversible adapts functions to github.com/glojurelang/glojure/pkg/lang.Reversible.
+type _github_com_glojurelang_glojure_pkg_lang_Reversible struct {
+	_RSeq func() github_com_glojurelang_glojure_pkg_lang.ISeq
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Reversible) RSeq() github_com_glojurelang_glojure_pkg_lang.ISeq {
+	return a._RSeq()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_Seqable adapts functions to github.com/glojurelang/glojure/pkg/lang.Seqable.
+type _github_com_glojurelang_glojure_pkg_lang_Seqable struct {
+	_Seq func() github_com_glojurelang_glojure_pkg_lang.ISeq
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Seqable) Seq() github_com_glojurelang_glojure_pkg_lang.ISeq {
+	return a._Seq()
+}
+
+// _github_com_glojurelang_glojure_pkg_lang_Stacker adapts functions to github.com/glojurelang/glojure/pkg/lang.Stacker.
+type _github_com_glojurelang_glojure_pkg_lang_Stacker struct {
+	_Stack func() []github_com_glojurelang_glojure_pkg_lang.StackFrame
+}
+
+func (a *_github_com_glojurelang_glojure_pkg_lang_Stacker) Stack() []github_com_glojurelang_glojure_pkg_lang.StackFrame {
+	return a._Stack()
+}
+
+// _go_ast_Node adapts functions to go/ast.Node.
+type _go_ast_Node struct {
+	_End func() go_token.Pos
+	_Pos func() go_token.Pos
+}
+
+func (a *_go_ast_Node) End() go_token.Pos {
+	return a._End()
+}
+
+func (a *_go_ast_Node) Pos() go_token.Pos {
+	return a._Pos()
+}
+
+// _go_ast_Visitor adapts functions to go/ast.Visitor.
+type _go_ast_Visitor struct {
+	_Visit func(go_ast.Node) go_ast.Visitor
+}
+
+func (a *_go_ast_Visitor) Visit(p0 go_ast.Node) go_ast.Visitor {
+	return a._Visit(p0)
+}
+
+// _go_types_Importer adapts functions to go/types.Importer.
+type _go_types_Importer struct {
+	_Import func(string) (*go_types.Package, error)
+}
+
+func (a *_go_types_Importer) Import(p0 string) (*go_types.Package, error) {
+	return a._Import(p0)
+}
+
+// _go_types_ImporterFrom adapts functions to go/types.ImporterFrom.
+type _go_types_ImporterFrom struct {
+	_Import     func(string) (*go_types.Package, error)
+	_ImportFrom func(string, string, go_types.ImportMode) (*go_types.Package, error)
+}
+
+func (a *_go_types_ImporterFrom) Import(p0 string) (*go_types.Package, error) {
+	return a._Import(p0)
+}
+
+func (a *_go_types_ImporterFrom) ImportFrom(p0 string, p1 string, p2 go_types.ImportMode) (*go_types.Package, error) {
+	return a._ImportFrom(p0, p1, p2)
+}
+
+// _go_types_Sizes adapts functions to go/types.Sizes.
+type _go_types_Sizes struct {
+	_Alignof   func(go_types.Type) int64
+	_Offsetsof func([]*go_types.Var) []int64
+	_Sizeof    func(go_types.Type) int64
+}
+
+func (a *_go_types_Sizes) Alignof(p0 go_types.Type) int64 {
+	return a._Alignof(p0)
+}
+
+func (a *_go_types_Sizes) Offsetsof(p0 []*go_types.Var) []int64 {
+	return a._Offsetsof(p0)
+}
+
+func (a *_go_types_Sizes) Sizeof(p0 go_types.Type) int64 {
+	return a._Sizeof(p0)
+}
+
+// _go_types_Type adapts functions to go/types.Type.
+type _go_types_Type struct {
+	_String     func() string
+	_Underlying func() go_types.Type
+}
+
+func (a *_go_types_Type) String() string {
+	return a._String()
+}
+
+func (a *_go_types_Type) Underlying() go_types.Type {
+	return a._Underlying()
+}
+
+// _hash_Hash adapts functions to hash.Hash.
+type _hash_Hash struct {
+	_BlockSize func() int
+	_Reset     func()
+	_Size      func() int
+	_Sum       func([]byte) []byte
+	_Write     func([]byte) (int, error)
+}
+
+func (a *_hash_Hash) BlockSize() int {
+	return a._BlockSize()
+}
+
+func (a *_hash_Hash) Reset() {
+	a._Reset()
+}
+
+func (a *_hash_Hash) Size() int {
+	return a._Size()
+}
+
+func (a *_hash_Hash) Sum(p0 []byte) []byte {
+	return a._Sum(p0)
+}
+
+func (a *_hash_Hash) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _hash_Hash32 adapts functions to hash.Hash32.
+type _hash_Hash32 struct {
+	_BlockSize func() int
+	_Reset     func()
+	_Size      func() int
+	_Sum       func([]byte) []byte
+	_Sum32     func() uint32
+	_Write     func([]byte) (int, error)
+}
+
+func (a *_hash_Hash32) BlockSize() int {
+	return a._BlockSize()
+}
+
+func (a *_hash_Hash32) Reset() {
+	a._Reset()
+}
+
+func (a *_hash_Hash32) Size() int {
+	return a._Size()
+}
+
+func (a *_hash_Hash32) Sum(p0 []byte) []byte {
+	return a._Sum(p0)
+}
+
+func (a *_hash_Hash32) Sum32() uint32 {
+	return a._Sum32()
+}
+
+func (a *_hash_Hash32) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _hash_Hash64 adapts functions to hash.Hash64.
+type _hash_Hash64 struct {
+	_BlockSize func() int
+	_Reset     func()
+	_Size      func() int
+	_Sum       func([]byte) []byte
+	_Sum64     func() uint64
+	_Write     func([]byte) (int, error)
+}
+
+func (a *_hash_Hash64) BlockSize() int {
+	return a._BlockSize()
+}
+
+func (a *_hash_Hash64) Reset() {
+	a._Reset()
+}
+
+func (a *_hash_Hash64) Size() int {
+	return a._Size()
+}
+
+func (a *_hash_Hash64) Sum(p0 []byte) []byte {
+	return a._Sum(p0)
+}
+
+func (a *_hash_Hash64) Sum64() uint64 {
+	return a._Sum64()
+}
+
+func (a *_hash_Hash64) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _image_Image adapts functions to image.Image.
+type _image_Image struct {
+	_At         func(int, int) image_color.Color
+	_Bounds     func() image.Rectangle
+	_ColorModel func() image_color.Model
+}
+
+func (a *_image_Image) At(p0 int, p1 int) image_color.Color {
+	return a._At(p0, p1)
+}
+
+func (a *_image_Image) Bounds() image.Rectangle {
+	return a._Bounds()
+}
+
+func (a *_image_Image) ColorModel() image_color.Model {
+	return a._ColorModel()
+}
+
+// _image_PalettedImage adapts functions to image.PalettedImage.
+type _image_PalettedImage struct {
+	_At           func(int, int) image_color.Color
+	_Bounds       func() image.Rectangle
+	_ColorIndexAt func(int, int) uint8
+	_ColorModel   func() image_color.Model
+}
+
+func (a *_image_PalettedImage) At(p0 int, p1 int) image_color.Color {
+	return a._At(p0, p1)
+}
+
+func (a *_image_PalettedImage) Bounds() image.Rectangle {
+	return a._Bounds()
+}
+
+func (a *_image_PalettedImage) ColorIndexAt(p0 int, p1 int) uint8 {
+	return a._ColorIndexAt(p0, p1)
+}
+
+func (a *_image_PalettedImage) ColorModel() image_color.Model {
+	return a._ColorModel()
+}
+
+// _image_RGBA64Image adapts functions to image.RGBA64Image.
+type _image_RGBA64Image struct {
+	_At         func(int, int) image_color.Color
+	_Bounds     func() image.Rectangle
+	_ColorModel func() image_color.Model
+	_RGBA64At   func(int, int) image_color.RGBA64
+}
+
+func (a *_image_RGBA64Image) At(p0 int, p1 int) image_color.Color {
+	return a._At(p0, p1)
+}
+
+func (a *_image_RGBA64Image) Bounds() image.Rectangle {
+	return a._Bounds()
+}
+
+func (a *_image_RGBA64Image) ColorModel() image_color.Model {
+	return a._ColorModel()
+}
+
+func (a *_image_RGBA64Image) RGBA64At(p0 int, p1 int) image_color.RGBA64 {
+	return a._RGBA64At(p0, p1)
+}
+
+// _image_color_Color adapts functions to image/color.Color.
+type _image_color_Color struct {
+	_RGBA func() (uint32, uint32, uint32, uint32)
+}
+
+func (a *_image_color_Color) RGBA() (uint32, uint32, uint32, uint32) {
+	return a._RGBA()
+}
+
+// _image_color_Model adapts functions to image/color.Model.
+type _image_color_Model struct {
+	_Convert func(image_color.Color) image_color.Color
+}
+
+func (a *_image_color_Model) Convert(p0 image_color.Color) image_color.Color {
+	return a._Convert(p0)
+}
+
+// _image_draw_Drawer adapts functions to image/draw.Drawer.
+type _image_draw_Drawer struct {
+	_Draw func(image_draw.Image, image.Rectangle, image.Image, image.Point)
+}
+
+func (a *_image_draw_Drawer) Draw(p0 image_draw.Image, p1 image.Rectangle, p2 image.Image, p3 image.Point) {
+	a._Draw(p0, p1, p2, p3)
+}
+
+// _image_draw_Image adapts functions to image/draw.Image.
+type _image_draw_Image struct {
+	_At         func(int, int) image_color.Color
+	_Bounds     func() image.Rectangle
+	_ColorModel func() image_color.Model
+	_Set        func(int, int, image_color.Color)
+}
+
+func (a *_image_draw_Image) At(p0 int, p1 int) image_color.Color {
+	return a._At(p0, p1)
+}
+
+func (a *_image_draw_Image) Bounds() image.Rectangle {
+	return a._Bounds()
+}
+
+func (a *_image_draw_Image) ColorModel() image_color.Model {
+	return a._ColorModel()
+}
+
+func (a *_image_draw_Image) Set(p0 int, p1 int, p2 image_color.Color) {
+	a._Set(p0, p1, p2)
+}
+
+// _image_draw_Quantizer adapts functions to image/draw.Quantizer.
+type _image_draw_Quantizer struct {
+	_Quantize func(image_color.Palette, image.Image) image_color.Palette
+}
+
+func (a *_image_draw_Quantizer) Quantize(p0 image_color.Palette, p1 image.Image) image_color.Palette {
+	return a._Quantize(p0, p1)
+}
+
+// _image_draw_RGBA64Image adapts functions to image/draw.RGBA64Image.
+type _image_draw_RGBA64Image struct {
+	_At         func(int, int) image_color.Color
+	_Bounds     func() image.Rectangle
+	_ColorModel func() image_color.Model
+	_RGBA64At   func(int, int) image_color.RGBA64
+	_Set        func(int, int, image_color.Color)
+	_SetRGBA64  func(int, int, image_color.RGBA64)
+}
+
+func (a *_image_draw_RGBA64Image) At(p0 int, p1 int) image_color.Color {
+	return a._At(p0, p1)
+}
+
+func (a *_image_draw_RGBA64Image) Bounds() image.Rectangle {
+	return a._Bounds()
+}
+
+func (a *_image_draw_RGBA64Image) ColorModel() image_color.Model {
+	return a._ColorModel()
+}
+
+func (a *_image_draw_RGBA64Image) RGBA64At(p0 int, p1 int) image_color.RGBA64 {
+	return a._RGBA64At(p0, p1)
+}
+
+func (a *_image_draw_RGBA64Image) Set(p0 int, p1 int, p2 image_color.Color) {
+	a._Set(p0, p1, p2)
+}
+
+func (a *_image_draw_RGBA64Image) SetRGBA64(p0 int, p1 int, p2 image_color.RGBA64) {
+	a._SetRGBA64(p0, p1, p2)
+}
+
+// _image_jpeg_Reader adapts functions to image/jpeg.Reader.
+type _image_jpeg_Reader struct {
+	_Read     func([]byte) (int, error)
+	_ReadByte func() (byte, error)
+}
+
+func (a *_image_jpeg_Reader) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_image_jpeg_Reader) ReadByte() (byte, error) {
+	return a._ReadByte()
+}
+
+// _image_png_EncoderBufferPool adapts functions to image/png.EncoderBufferPool.
+type _image_png_EncoderBufferPool struct {
+	_Get func() *image_png.EncoderBuffer
+	_Put func(*image_png.EncoderBuffer)
+}
+
+func (a *_image_png_EncoderBufferPool) Get() *image_png.EncoderBuffer {
+	return a._Get()
+}
+
+func (a *_image_png_EncoderBufferPool) Put(p0 *image_png.EncoderBuffer) {
+	a._Put(p0)
+}
+
+// _io_ByteReader adapts functions to io.ByteReader.
+type _io_ByteReader struct {
+	_ReadByte func() (byte, error)
+}
+
+func (a *_io_ByteReader) ReadByte() (byte, error) {
+	return a._ReadByte()
+}
+
+// _io_ByteScanner adapts functions to io.ByteScanner.
+type _io_ByteScanner struct {
+	_ReadByte   func() (byte, error)
+	_UnreadByte func() error
+}
+
+func (a *_io_ByteScanner) ReadByte() (byte, error) {
+	return a._ReadByte()
+}
+
+func (a *_io_ByteScanner) UnreadByte() error {
+	return a._UnreadByte()
+}
+
+// _io_ByteWriter adapts functions to io.ByteWriter.
+type _io_ByteWriter struct {
+	_WriteByte func(byte) error
+}
+
+func (a *_io_ByteWriter) WriteByte(p0 byte) error {
+	return a._WriteByte(p0)
+}
+
+// _io_Closer adapts functions to io.Closer.
+type _io_Closer struct {
+	_Close func() error
+}
+
+func (a *_io_Closer) Close() error {
+	return a._Close()
+}
+
+// _io_ReadCloser adapts functions to io.ReadCloser.
+type _io_ReadCloser struct {
+	_Close func() error
+	_Read  func([]byte) (int, error)
+}
+
+func (a *_io_ReadCloser) Close() error {
+	return a._Close()
+}
+
+func (a *_io_ReadCloser) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+// _io_ReadSeekCloser adapts functions to io.ReadSeekCloser.
+type _io_ReadSeekCloser struct {
+	_Close func() error
+	_Read  func([]byte) (int, error)
+	_Seek  func(int64, int) (int64, error)
+}
+
+func (a *_io_ReadSeekCloser) Close() error {
+	return a._Close()
+}
+
+func (a *_io_ReadSeekCloser) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_io_ReadSeekCloser) Seek(p0 int64, p1 int) (int64, error) {
+	return a._Seek(p0, p1)
+}
+
+// _io_ReadSeeker adapts functions to io.ReadSeeker.
+type _io_ReadSeeker struct {
+	_Read func([]byte) (int, error)
+	_Seek func(int64, int) (int64, error)
+}
+
+func (a *_io_ReadSeeker) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_io_ReadSeeker) Seek(p0 int64, p1 int) (int64, error) {
+	return a._Seek(p0, p1)
+}
+
+// _io_ReadWriteCloser adapts functions to io.ReadWriteCloser.
+type _io_ReadWriteCloser struct {
+	_Close func() error
+	_Read  func([]byte) (int, error)
+	_Write func([]byte) (int, error)
+}
+
+func (a *_io_ReadWriteCloser) Close() error {
+	return a._Close()
+}
+
+func (a *_io_ReadWriteCloser) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_io_ReadWriteCloser) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _io_ReadWriteSeeker adapts functions to io.ReadWriteSeeker.
+type _io_ReadWriteSeeker struct {
+	_Read  func([]byte) (int, error)
+	_Seek  func(int64, int) (int64, error)
+	_Write func([]byte) (int, error)
+}
+
+func (a *_io_ReadWriteSeeker) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_io_ReadWriteSeeker) Seek(p0 int64, p1 int) (int64, error) {
+	return a._Seek(p0, p1)
+}
+
+func (a *_io_ReadWriteSeeker) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _io_ReadWriter adapts functions to io.ReadWriter.
+type _io_ReadWriter struct {
+	_Read  func([]byte) (int, error)
+	_Write func([]byte) (int, error)
+}
+
+func (a *_io_ReadWriter) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_io_ReadWriter) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _io_Reader adapts functions to io.Reader.
+type _io_Reader struct {
+	_Read func([]byte) (int, error)
+}
+
+func (a *_io_Reader) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+// _io_ReaderAt adapts functions to io.ReaderAt.
+type _io_ReaderAt struct {
+	_ReadAt func([]byte, int64) (int, error)
+}
+
+func (a *_io_ReaderAt) ReadAt(p0 []byte, p1 int64) (int, error) {
+	return a._ReadAt(p0, p1)
+}
+
+// _io_ReaderFrom adapts functions to io.ReaderFrom.
+type _io_ReaderFrom struct {
+	_ReadFrom func(io.Reader) (int64, error)
+}
+
+func (a *_io_ReaderFrom) ReadFrom(p0 io.Reader) (int64, error) {
+	return a._ReadFrom(p0)
+}
+
+// _io_RuneReader adapts functions to io.RuneReader.
+type _io_RuneReader struct {
+	_ReadRune func() (rune, int, error)
+}
+
+func (a *_io_RuneReader) ReadRune() (rune, int, error) {
+	return a._ReadRune()
+}
+
+// _io_RuneScanner adapts functions to io.RuneScanner.
+type _io_RuneScanner struct {
+	_ReadRune   func() (rune, int, error)
+	_UnreadRune func() error
+}
+
+func (a *_io_RuneScanner) ReadRune() (rune, int, error) {
+	return a._ReadRune()
+}
+
+func (a *_io_RuneScanner) UnreadRune() error {
+	return a._UnreadRune()
+}
+
+// _io_Seeker adapts functions to io.Seeker.
+type _io_Seeker struct {
+	_Seek func(int64, int) (int64, error)
+}
+
+func (a *_io_Seeker) Seek(p0 int64, p1 int) (int64, error) {
+	return a._Seek(p0, p1)
+}
+
+// _io_StringWriter adapts functions to io.StringWriter.
+type _io_StringWriter struct {
+	_WriteString func(string) (int, error)
+}
+
+func (a *_io_StringWriter) WriteString(p0 string) (int, error) {
+	return a._WriteString(p0)
+}
+
+// _io_WriteCloser adapts functions to io.WriteCloser.
+type _io_WriteCloser struct {
+	_Close func() error
+	_Write func([]byte) (int, error)
+}
+
+func (a *_io_WriteCloser) Close() error {
+	return a._Close()
+}
+
+func (a *_io_WriteCloser) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _io_WriteSeeker adapts functions to io.WriteSeeker.
+type _io_WriteSeeker struct {
+	_Seek  func(int64, int) (int64, error)
+	_Write func([]byte) (int, error)
+}
+
+func (a *_io_WriteSeeker) Seek(p0 int64, p1 int) (int64, error) {
+	return a._Seek(p0, p1)
+}
+
+func (a *_io_WriteSeeker) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _io_Writer adapts functions to io.Writer.
+type _io_Writer struct {
+	_Write func([]byte) (int, error)
+}
+
+func (a *_io_Writer) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _io_WriterAt adapts functions to io.WriterAt.
+type _io_WriterAt struct {
+	_WriteAt func([]byte, int64) (int, error)
+}
+
+func (a *_io_WriterAt) WriteAt(p0 []byte, p1 int64) (int, error) {
+	return a._WriteAt(p0, p1)
+}
+
+// _io_WriterTo adapts functions to io.WriterTo.
+type _io_WriterTo struct {
+	_WriteTo func(io.Writer) (int64, error)
+}
+
+func (a *_io_WriterTo) WriteTo(p0 io.Writer) (int64, error) {
+	return a._WriteTo(p0)
+}
+
+// _io_fs_DirEntry adapts functions to io/fs.DirEntry.
+type _io_fs_DirEntry struct {
+	_Info  func() (io_fs.FileInfo, error)
+	_IsDir func() bool
+	_Name  func() string
+	_Type  func() io_fs.FileMode
+}
+
+func (a *_io_fs_DirEntry) Info() (io_fs.FileInfo, error) {
+	return a._Info()
+}
+
+func (a *_io_fs_DirEntry) IsDir() bool {
+	return a._IsDir()
+}
+
+func (a *_io_fs_DirEntry) Name() string {
+	return a._Name()
+}
+
+func (a *_io_fs_DirEntry) Type() io_fs.FileMode {
+	return a._Type()
+}
+
+// _io_fs_FS adapts functions to io/fs.FS.
+type _io_fs_FS struct {
+	_Open func(string) (io_fs.File, error)
+}
+
+func (a *_io_fs_FS) Open(p0 string) (io_fs.File, error) {
+	return a._Open(p0)
+}
+
+// _io_fs_File adapts functions to io/fs.File.
+type _io_fs_File struct {
+	_Close func() error
+	_Read  func([]byte) (int, error)
+	_Stat  func() (io_fs.FileInfo, error)
+}
+
+func (a *_io_fs_File) Close() error {
+	return a._Close()
+}
+
+func (a *_io_fs_File) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_io_fs_File) Stat() (io_fs.FileInfo, error) {
+	return a._Stat()
+}
+
+// _io_fs_GlobFS adapts functions to io/fs.GlobFS.
+type _io_fs_GlobFS struct {
+	_Glob func(string) ([]string, error)
+	_Open func(string) (io_fs.File, error)
+}
+
+func (a *_io_fs_GlobFS) Glob(p0 string) ([]string, error) {
+	return a._Glob(p0)
+}
+
+func (a *_io_fs_GlobFS) Open(p0 string) (io_fs.File, error) {
+	return a._Open(p0)
+}
+
+// _io_fs_ReadDirFS adapts functions to io/fs.ReadDirFS.
+type _io_fs_ReadDirFS struct {
+	_Open    func(string) (io_fs.File, error)
+	_ReadDir func(string) ([]io_fs.DirEntry, error)
+}
+
+func (a *_io_fs_ReadDirFS) Open(p0 string) (io_fs.File, error) {
+	return a._Open(p0)
+}
+
+func (a *_io_fs_ReadDirFS) ReadDir(p0 string) ([]io_fs.DirEntry, error) {
+	return a._ReadDir(p0)
+}
+
+// _io_fs_ReadDirFile adapts functions to io/fs.ReadDirFile.
+type _io_fs_ReadDirFile struct {
+	_Close   func() error
+	_Read    func([]byte) (int, error)
+	_ReadDir func(int) ([]io_fs.DirEntry, error)
+	_Stat    func() (io_fs.FileInfo, error)
+}
+
+func (a *_io_fs_ReadDirFile) Close() error {
+	return a._Close()
+}
+
+func (a *_io_fs_ReadDirFile) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_io_fs_ReadDirFile) ReadDir(p0 int) ([]io_fs.DirEntry, error) {
+	return a._ReadDir(p0)
+}
+
+func (a *_io_fs_ReadDirFile) Stat() (io_fs.FileInfo, error) {
+	return a._Stat()
+}
+
+// _io_fs_ReadFileFS adapts functions to io/fs.ReadFileFS.
+type _io_fs_ReadFileFS struct {
+	_Open     func(string) (io_fs.File, error)
+	_ReadFile func(string) ([]byte, error)
+}
+
+func (a *_io_fs_ReadFileFS) Open(p0 string) (io_fs.File, error) {
+	return a._Open(p0)
+}
+
+func (a *_io_fs_ReadFileFS) ReadFile(p0 string) ([]byte, error) {
+	return a._ReadFile(p0)
+}
+
+// _io_fs_StatFS adapts functions to io/fs.StatFS.
+type _io_fs_StatFS struct {
+	_Open func(string) (io_fs.File, error)
+	_Stat func(string) (io_fs.FileInfo, error)
+}
+
+func (a *_io_fs_StatFS) Open(p0 string) (io_fs.File, error) {
+	return a._Open(p0)
+}
+
+func (a *_io_fs_StatFS) Stat(p0 string) (io_fs.FileInfo, error) {
+	return a._Stat(p0)
+}
+
+// _io_fs_SubFS adapts functions to io/fs.SubFS.
+type _io_fs_SubFS struct {
+	_Open func(string) (io_fs.File, error)
+	_Sub  func(string) (io_fs.FS, error)
+}
+
+func (a *_io_fs_SubFS) Open(p0 string) (io_fs.File, error) {
+	return a._Open(p0)
+}
+
+func (a *_io_fs_SubFS) Sub(p0 string) (io_fs.FS, error) {
+	return a._Sub(p0)
+}
+
+// _math_rand_Source adapts functions to math/rand.Source.
+type _math_rand_Source struct {
+	_Int63 func() int64
+	_Seed  func(int64)
+}
+
+func (a *_math_rand_Source) Int63() int64 {
+	return a._Int63()
+}
+
+func (a *_math_rand_Source) Seed(p0 int64) {
+	a._Seed(p0)
+}
+
+// _math_rand_Source64 adapts functions to math/rand.Source64.
+type _math_rand_Source64 struct {
+	_Int63  func() int64
+	_Seed   func(int64)
+	_Uint64 func() uint64
+}
+
+func (a *_math_rand_Source64) Int63() int64 {
+	return a._Int63()
+}
+
+func (a *_math_rand_Source64) Seed(p0 int64) {
+	a._Seed(p0)
+}
+
+func (a *_math_rand_Source64) Uint64() uint64 {
+	return a._Uint64()
+}
+
+// _mime_multipart_File adapts functions to mime/multipart.File.
+type _mime_multipart_File struct {
+	_Close  func() error
+	_Read   func([]byte) (int, error)
+	_ReadAt func([]byte, int64) (int, error)
+	_Seek   func(int64, int) (int64, error)
+}
+
+func (a *_mime_multipart_File) Close() error {
+	return a._Close()
+}
+
+func (a *_mime_multipart_File) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_mime_multipart_File) ReadAt(p0 []byte, p1 int64) (int, error) {
+	return a._ReadAt(p0, p1)
+}
+
+func (a *_mime_multipart_File) Seek(p0 int64, p1 int) (int64, error) {
+	return a._Seek(p0, p1)
+}
+
+// _net_Addr adapts functions to net.Addr.
+type _net_Addr struct {
+	_Network func() string
+	_String  func() string
+}
+
+func (a *_net_Addr) Network() string {
+	return a._Network()
+}
+
+func (a *_net_Addr) String() string {
+	return a._String()
+}
+
+// _net_Conn adapts functions to net.Conn.
+type _net_Conn struct {
+	_Close            func() error
+	_LocalAddr        func() net.Addr
+	_Read             func([]byte) (int, error)
+	_RemoteAddr       func() net.Addr
+	_SetDeadline      func(time.Time) error
+	_SetReadDeadline  func(time.Time) error
+	_SetWriteDeadline func(time.Time) error
+	_Write            func([]byte) (int, error)
+}
+
+func (a *_net_Conn) Close() error {
+	return a._Close()
+}
+
+func (a *_net_Conn) LocalAddr() net.Addr {
+	return a._LocalAddr()
+}
+
+func (a *_net_Conn) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_net_Conn) RemoteAddr() net.Addr {
+	return a._RemoteAddr()
+}
+
+func (a *_net_Conn) SetDeadline(p0 time.Time) error {
+	return a._SetDeadline(p0)
+}
+
+func (a *_net_Conn) SetReadDeadline(p0 time.Time) error {
+	return a._SetReadDeadline(p0)
+}
+
+func (a *_net_Conn) SetWriteDeadline(p0 time.Time) error {
+	return a._SetWriteDeadline(p0)
+}
+
+func (a *_net_Conn) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+// _net_Error adapts functions to net.Error.
+type _net_Error struct {
+	_Error     func() string
+	_Temporary func() bool
+	_Timeout   func() bool
+}
+
+func (a *_net_Error) Error() string {
+	return a._Error()
+}
+
+func (a *_net_Error) Temporary() bool {
+	return a._Temporary()
+}
+
+func (a *_net_Error) Timeout() bool {
+	return a._Timeout()
+}
+
+// _net_Listener adapts functions to net.Listener.
+type _net_Listener struct {
+	_Accept func() (net.Conn, error)
+	_Addr   func() net.Addr
+	_Close  func() error
+}
+
+func (a *_net_Listener) Accept() (net.Conn, error) {
+	return a._Accept()
+}
+
+func (a *_net_Listener) Addr() net.Addr {
+	return a._Addr()
+}
+
+func (a *_net_Listener) Close() error {
+	return a._Close()
+}
+
+// _net_PacketConn adapts functions to net.PacketConn.
+type _net_PacketConn struct {
+	_Close            func() error
+	_LocalAddr        func() net.Addr
+	_ReadFrom         func([]byte) (int, net.Addr, error)
+	_SetDeadline      func(time.Time) error
+	_SetReadDeadline  func(time.Time) error
+	_SetWriteDeadline func(time.Time) error
+	_WriteTo          func([]byte, net.Addr) (int, error)
+}
+
+func (a *_net_PacketConn) Close() error {
+	return a._Close()
+}
+
+func (a *_net_PacketConn) LocalAddr() net.Addr {
+	return a._LocalAddr()
+}
+
+func (a *_net_PacketConn) ReadFrom(p0 []byte) (int, net.Addr, error) {
+	return a._ReadFrom(p0)
+}
+
+func (a *_net_PacketConn) SetDeadline(p0 time.Time) error {
+	return a._SetDeadline(p0)
+}
+
+func (a *_net_PacketConn) SetReadDeadline(p0 time.Time) error {
+	return a._SetReadDeadline(p0)
+}
+
+func (a *_net_PacketConn) SetWriteDeadline(p0 time.Time) error {
+	return a._SetWriteDeadline(p0)
+}
+
+func (a *_net_PacketConn) WriteTo(p0 []byte, p1 net.Addr) (int, error) {
+	return a._WriteTo(p0, p1)
+}
+
+// _net_http_CloseNotifier adapts functions to net/http.CloseNotifier.
+type _net_http_CloseNotifier struct {
+	_CloseNotify func() <-chan bool
+}
+
+func (a *_net_http_CloseNotifier) CloseNotify() <-chan bool {
+	return a._CloseNotify()
+}
+
+// _net_http_CookieJar adapts functions to net/http.CookieJar.
+type _net_http_CookieJar struct {
+	_Cookies    func(*net_url.URL) []*net_http.Cookie
+	_SetCookies func(*net_url.URL, []*net_http.Cookie)
+}
+
+func (a *_net_http_CookieJar) Cookies(p0 *net_url.URL) []*net_http.Cookie {
+	return a._Cookies(p0)
+}
+
+func (a *_net_http_CookieJar) SetCookies(p0 *net_url.URL, p1 []*net_http.Cookie) {
+	a._SetCookies(p0, p1)
+}
+
+// _net_http_File adapts functions to net/http.File.
+type _net_http_File struct {
+	_Close   func() error
+	_Read    func([]byte) (int, error)
+	_Readdir func(int) ([]io_fs.FileInfo, error)
+	_Seek    func(int64, int) (int64, error)
+	_Stat    func() (io_fs.FileInfo, error)
+}
+
+func (a *_net_http_File) Close() error {
+	return a._Close()
+}
+
+func (a *_net_http_File) Read(p0 []byte) (int, error) {
+	return a._Read(p0)
+}
+
+func (a *_net_http_File) Readdir(p0 int) ([]io_fs.FileInfo, error) {
+	return a._Readdir(p0)
+}
+
+func (a *_net_http_File) Seek(p0 int64, p1 int) (int64, error) {
+	return a._Seek(p0, p1)
+}
+
+func (a *_net_http_File) Stat() (io_fs.FileInfo, error) {
+	return a._Stat()
+}
+
+// _net_http_FileSystem adapts functions to net/http.FileSystem.
+type _net_http_FileSystem struct {
+	_Open func(string) (net_http.File, error)
+}
+
+func (a *_net_http_FileSystem) Open(p0 string) (net_http.File, error) {
+	return a._Open(p0)
+}
+
+// _net_http_Flusher adapts functions to net/http.Flusher.
+type _net_http_Flusher struct {
+	_Flush func()
+}
+
+func (a *_net_http_Flusher) Flush() {
+	a._Flush()
+}
+
+// _net_http_Handler adapts functions to net/http.Handler.
+type _net_http_Handler struct {
+	_ServeHTTP func(net_http.ResponseWriter, *net_http.Request)
+}
+
+func (a *_net_http_Handler) ServeHTTP(p0 net_http.ResponseWriter, p1 *net_http.Request) {
+	a._ServeHTTP(p0, p1)
+}
+
+// _net_http_Hijacker adapts functions to net/http.Hijacker.
+type _net_http_Hijacker struct {
+	_Hijack func() (net.Conn, *bufio.ReadWriter, error)
+}
+
+func (a *_net_http_Hijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return a._Hijack()
+}
+
+// _net_http_Pusher adapts functions to net/http.Pusher.
+type _net_http_Pusher struct {
+	_Push func(string, *net_http.PushOptions) error
+}
+
+func (a *_net_http_Pusher) Push(p0 string, p1 *net_http.PushOptions) error {
+	return a._Push(p0, p1)
+}
+
+// _net_http_ResponseWriter adapts functions to net/http.ResponseWriter.
+type _net_http_ResponseWriter struct {
+	_Header      func() net_http.Header
+	_Write       func([]byte) (int, error)
+	_WriteHeader func(int)
+}
+
+func (a *_net_http_ResponseWriter) Header() net_http.Header {
+	return a._Header()
+}
+
+func (a *_net_http_ResponseWriter) Write(p0 []byte) (int, error) {
+	return a._Write(p0)
+}
+
+func (a *_net_http_ResponseWriter) WriteHeader(p0 int) {
+	a._WriteHeader(p0)
+}
+
+// _net_http_RoundTripper adapts functions to net/http.RoundTripper.
+type _net_http_RoundTripper struct {
+	_RoundTrip func(*net_http.Request) (*net_http.Response, error)
+}
+
+func (a *_net_http_RoundTripper) RoundTrip(p0 *net_http.Request) (*net_http.Response, error) {
+	return a._RoundTrip(p0)
+}
+
+// _net_http_cookiejar_PublicSuffixList adapts functions to net/http/cookiejar.PublicSuffixList.
+type _net_http_cookiejar_PublicSuffixList struct {
+	_PublicSuffix func(string) string
+	_String       func() string
+}
+
+func (a *_net_http_cookiejar_PublicSuffixList) PublicSuffix(p0 string) string {
+	return a._PublicSuffix(p0)
+}
+
+func (a *_net_http_cookiejar_PublicSuffixList) String() string {
+	return a._String()
+}
+
+// _net_smtp_Auth adapts functions to net/smtp.Auth.
+type _net_smtp_Auth struct {
+	_Next  func([]byte, bool) ([]byte, error)
+	_Start func(*net_smtp.ServerInfo) (string, []byte, error)
+}
+
+func (a *_net_smtp_Auth) Next(p0 []byte, p1 bool) ([]byte, error) {
+	return a._Next(p0, p1)
+}
+
+func (a *_net_smtp_Auth) Start(p0 *net_smtp.ServerInfo) (string, []byte, error) {
+	return a._Start(p0)
+}
+
+// _os_Signal adapts functions to os.Signal.
+type _os_Signal struct {
+	_Signal func()
+	_String func() string
+}
+
+func (a *_os_Signal) Signal() {
+	a._Signal()
+}
+
+func (a *_os_Signal) String() string {
+	return a._String()
+}
+
+// _runtime_Error adapts functions to runtime.Error.
+type _runtime_Error struct {
+	_Error        func() string
+	_RuntimeError func()
+}
+
+func (a *_runtime_Error) Error() string {
+	return a._Error()
+}
+
+func (a *_runtime_Error) RuntimeError() {
+	a._RuntimeError()
+}
+
+// _sort_Interface adapts functions to sort.Interface.
+type _sort_Interface struct {
+	_Len  func() int
+	_Less func(int, int) bool
+	_Swap func(int, int)
+}
+
+func (a *_sort_Interface) Len() int {
+	return a._Len()
+}
+
+func (a *_sort_Interface) Less(p0 int, p1 int) bool {
+	return a._Less(p0, p1)
+}
+
+func (a *_sort_Interface) Swap(p0 int, p1 int) {
+	a._Swap(p0, p1)
+}
+
+// _sync_Locker adapts functions to sync.Locker.
+type _sync_Locker struct {
+	_Lock   func()
+	_Unlock func()
+}
+
+func (a *_sync_Locker) Lock() {
+	a._Lock()
+}
+
+func (a *_sync_Locker) Unlock() {
+	a._Unlock()
+}
+
+// _syscall_Conn adapts functions to syscall.Conn.
+type _syscall_Conn struct {
+	_SyscallConn func() (syscall.RawConn, error)
+}
+
+func (a *_syscall_Conn) SyscallConn() (syscall.RawConn, error) {
+	return a._SyscallConn()
+}
+
+// _syscall_RawConn adapts functions to syscall.RawConn.
+type _syscall_RawConn struct {
+	_Control func(func(fd uintptr)) error
+	_Read    func(func(fd uintptr) (done bool)) error
+	_Write   func(func(fd uintptr) (done bool)) error
+}
+
+func (a *_syscall_RawConn) Control(p0 func(fd uintptr)) error {
+	return a._Control(p0)
+}
+
+func (a *_syscall_RawConn) Read(p0 func(fd uintptr) (done bool)) error {
+	return a._Read(p0)
+}
+
+func (a *_syscall_RawConn) Write(p0 func(fd uintptr) (done bool)) error {
+	return a._Write(p0)
+}
+
+// _testing_quick_Generator adapts functions to testing/quick.Generator.
+type _testing_quick_Generator struct {
+	_Generate func(*math_rand.Rand, int) reflect.Value
+}
+
+func (a *_testing_quick_Generator) Generate(p0 *math_rand.Rand, p1 int) reflect.Value {
+	return a._Generate(p0, p1)
+}
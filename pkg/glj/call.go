@@ -0,0 +1,81 @@
+package glj
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+)
+
+// Call applies fn, which may be a Glojure function, a var, or a Go
+// function, to args and converts the result to T. Panics raised by
+// the call are returned as errors. The result is converted as by As.
+func Call[T any](fn interface{}, args ...interface{}) (res T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError(r)
+		}
+	}()
+
+	return As[T](value.Apply(fn, args))
+}
+
+// As converts a Glojure value to T. A nil value yields the zero
+// value of T; numeric values are converted to numeric types that can
+// represent them, with floats rounded to the precision of float
+// types. Conversions that would truncate or overflow return an error.
+func As[T any](v interface{}) (T, error) {
+	var zero T
+	if v == nil {
+		return zero, nil
+	}
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	val := reflect.ValueOf(v)
+	if isNumeric(val.Kind()) && isNumeric(typ.Kind()) {
+		res := val.Convert(typ)
+		if !convertsExactly(val, res) {
+			return zero, fmt.Errorf("cannot convert %T %v to %s without loss", v, v, typ)
+		}
+		return res.Interface().(T), nil
+	}
+	return zero, fmt.Errorf("cannot convert %T to %s", v, typ)
+}
+
+// convertsExactly reports whether res, the conversion of the number
+// val, represents it: it converts back to val with the same sign, or
+// both are floats and res is finite if val is.
+func convertsExactly(val, res reflect.Value) bool {
+	if isFloat(val.Kind()) && isFloat(res.Kind()) {
+		return !math.IsInf(res.Float(), 0) || math.IsInf(val.Float(), 0)
+	}
+	back := res.Convert(val.Type())
+	return back.Interface() == val.Interface() && isNegative(val) == isNegative(res)
+}
+
+func isNegative(v reflect.Value) bool {
+	switch {
+	case isFloat(v.Kind()):
+		return v.Float() < 0
+	case v.CanInt():
+		return v.Int() < 0
+	}
+	return false
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func isNumeric(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
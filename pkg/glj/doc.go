@@ -1,3 +1,14 @@
-// Package glj provides a minimal interface to bootstrap Glojure
-// access from Go.
+// Package glj provides an interface for embedding Glojure in Go
+// programs.
+//
+// Importing the package bootstraps a global Glojure runtime. Code can
+// then be evaluated with Eval, libraries loaded with Require (from
+// filesystems registered with LoadFS), and Glojure functions called
+// from Go with Var and Call. Go functions and values are exposed to
+// Glojure with Intern.
+//
+// Evaluation functions accept Options that bind *out*, *err*, *in*
+// and *ns* for the duration of a single call, and they report
+// failures as errors rather than panics. Errors raised while reading
+// or evaluating source carry its position; see Error.
 package glj
@@ -0,0 +1,75 @@
+package glj
+
+import (
+	"errors"
+	"fmt"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/reader"
+)
+
+// Error is an error raised while reading or evaluating Glojure
+// source. It records the position of the top-level form that failed,
+// if it has one; Line and Column are 0 otherwise. The underlying
+// error, available through Unwrap, may carry more detail, such as the
+// Glojure call stack.
+type Error struct {
+	Filename string
+	Line     int
+	Column   int
+	Err      error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line > 0:
+		return fmt.Sprintf("%s:%d:%d: %v", e.Filename, e.Line, e.Column, e.Err)
+	case e.Filename != "":
+		return fmt.Sprintf("%s: %v", e.Filename, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// readError converts an error from the reader to an *Error.
+func readError(err error) error {
+	var rerr *reader.Error
+	if !errors.As(err, &rerr) {
+		return err
+	}
+	filename, line, col := rerr.Position()
+	return &Error{
+		Filename: filename,
+		Line:     line,
+		Column:   col,
+		Err:      rerr.Unwrap(),
+	}
+}
+
+// formError annotates err with the source position of form.
+func formError(form interface{}, err error) error {
+	var meta value.IPersistentMap
+	if m, ok := form.(value.IMeta); ok {
+		meta = m.Meta()
+	}
+	line, _ := value.Get(meta, value.KWLine).(int)
+	col, _ := value.Get(meta, value.KWColumn).(int)
+	filename, _ := value.Get(meta, value.KWFile).(string)
+	return &Error{
+		Filename: filename,
+		Line:     line,
+		Column:   col,
+		Err:      err,
+	}
+}
+
+// panicError converts a recovered panic value to an error.
+func panicError(r interface{}) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", r)
+}
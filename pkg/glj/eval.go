@@ -0,0 +1,89 @@
+package glj
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+var (
+	requireVar = value.InternVarName(value.NSCore.Name(), value.NewSymbol("require"))
+)
+
+// Eval reads and evaluates each form in src, returning the value of
+// the last. ctx is bound to *context*, and evaluation stops with a
+// *lang.CanceledError once it is done. Read and evaluation errors are
+// returned as *Error values.
+func Eval(ctx context.Context, src string, opts ...Option) (interface{}, error) {
+	o := newOptions(opts)
+	o.ctx = ctx
+
+	var res interface{}
+	err := o.run(func() error {
+		env := value.GlobalEnv
+		rdrOpts := []reader.Option{
+			reader.WithGetCurrentNS(env.CurrentNamespace),
+		}
+		if o.filename != "" {
+			rdrOpts = append(rdrOpts, reader.WithFilename(o.filename))
+		}
+		rdr := reader.New(strings.NewReader(src), rdrOpts...)
+		for {
+			if err := value.CheckCanceled(); err != nil {
+				return err
+			}
+			form, err := rdr.ReadOne()
+			if err == reader.ErrEOF {
+				return nil
+			}
+			if err != nil {
+				return readError(err)
+			}
+			if res, err = evalForm(env, form); err != nil {
+				return formError(form, err)
+			}
+		}
+	})
+	return res, err
+}
+
+func evalForm(env value.Environment, form interface{}) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError(r)
+		}
+	}()
+	return env.Eval(form)
+}
+
+// Require loads the named namespace, as with glojure.core/require,
+// unless it has already been loaded. Namespaces are found on the load
+// path, which includes the standard library and any filesystems
+// added with LoadFS.
+func Require(ns string, opts ...Option) error {
+	return newOptions(opts).run(func() error {
+		requireVar.Invoke(value.NewSymbol(ns))
+		return nil
+	})
+}
+
+// Load evaluates the named file of the load path, such as
+// "app/main.glj", whether or not it has been loaded before. A file
+// compiled with glj compile is evaluated from its compiled forms.
+func Load(name string, opts ...Option) error {
+	return newOptions(opts).run(func() error {
+		runtime.RT.Load(strings.TrimSuffix(name, ".glj"))
+		return nil
+	})
+}
+
+// LoadFS adds fsys to the load path, making the namespaces it
+// contains available to Require. A namespace a.b-c is loaded from the
+// file a/b_c.glj.
+func LoadFS(fsys fs.FS) {
+	runtime.AddLoadPath(fsys)
+}
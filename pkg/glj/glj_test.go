@@ -1,9 +1,16 @@
 package glj
 
 import (
+	"context"
+	"errors"
+	"math"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
 )
 
 func TestGLJ(t *testing.T) {
@@ -14,3 +21,252 @@ func TestGLJ(t *testing.T) {
 		t.Errorf("Expected (2 3 4), got %v", res)
 	}
 }
+
+func TestEval(t *testing.T) {
+	res, err := Eval(context.Background(), "(def x 20) (+ x 22)", WithNamespace("glj.test-eval"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != int64(42) {
+		t.Errorf("Expected 42, got %v", res)
+	}
+
+	// The namespace is preserved across calls.
+	res, err = Eval(context.Background(), "x", WithNamespace("glj.test-eval"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != int64(20) {
+		t.Errorf("Expected 20, got %v", res)
+	}
+}
+
+func TestEvalOutput(t *testing.T) {
+	var out, errOut strings.Builder
+	_, err := Eval(context.Background(), `(print "hello") (binding [*out* *err*] (print "oops"))`,
+		WithStdout(&out), WithStderr(&errOut))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "hello" {
+		t.Errorf("Expected stdout %q, got %q", "hello", out.String())
+	}
+	if errOut.String() != "oops" {
+		t.Errorf("Expected stderr %q, got %q", "oops", errOut.String())
+	}
+}
+
+func TestEvalError(t *testing.T) {
+	_, err := Eval(context.Background(), "(+ 1 2)\n  (throw (errors.New \"boom\"))",
+		WithFilename("rules.glj"))
+	var gljErr *Error
+	if !errors.As(err, &gljErr) {
+		t.Fatalf("Expected *Error, got %T: %v", err, err)
+	}
+	if gljErr.Filename != "rules.glj" || gljErr.Line != 2 || gljErr.Column != 3 {
+		t.Errorf("Unexpected position %s:%d:%d", gljErr.Filename, gljErr.Line, gljErr.Column)
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected error to mention boom, got %v", err)
+	}
+
+	// forms without a position are reported without one.
+	err = formError(value.NewSymbol("x"), errors.New("boom"))
+	if err.Error() != "boom" {
+		t.Errorf("Expected an error without a position, got %v", err)
+	}
+
+	_, err = Eval(context.Background(), "(+ 1 2", WithFilename("bad.glj"))
+	if !errors.As(err, &gljErr) || gljErr.Filename != "bad.glj" {
+		t.Errorf("Expected read error with position, got %v", err)
+	}
+}
+
+func TestEvalCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Eval(ctx, "(+ 1 2)"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	// runaway evaluation stops at the deadline.
+	for _, src := range []string{
+		"(loop [i 0] (recur (inc i)))",
+		"((fn f [i] (if (neg? i) i (f (inc i)))) 0)",
+		"(count ((fn nums [n] (lazy-seq (cons n (nums (inc n))))) 0))",
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		_, err := Eval(ctx, src)
+		cancel()
+		var canceled *value.CanceledError
+		if !errors.As(err, &canceled) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s: expected a canceled error, got %v", src, err)
+		}
+	}
+}
+
+func TestIntern(t *testing.T) {
+	Intern("glj.test-intern", "greet", func(name string) string {
+		return "hello, " + name
+	})
+	res, err := Eval(context.Background(), `(glj.test-intern/greet "glojure")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != "hello, glojure" {
+		t.Errorf("Expected greeting, got %v", res)
+	}
+}
+
+func TestCall(t *testing.T) {
+	n, err := Call[int](Var("glojure.core", "+"), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3, got %v", n)
+	}
+
+	if _, err := Call[string](Var("glojure.core", "+"), 1, 2); err == nil {
+		t.Error("Expected conversion error")
+	}
+	if _, err := Call[int](Var("glojure.core", "/"), 1, 0); err == nil {
+		t.Error("Expected error from divide by zero")
+	}
+}
+
+func TestAs(t *testing.T) {
+	if n, err := As[int8](int64(100)); n != 100 || err != nil {
+		t.Errorf("As[int8](100) = %v, %v; want 100", n, err)
+	}
+	if f, err := As[float64](int64(3)); f != 3 || err != nil {
+		t.Errorf("As[float64](3) = %v, %v; want 3", f, err)
+	}
+	if f, err := As[float32](0.1); f != float32(0.1) || err != nil {
+		t.Errorf("As[float32](0.1) = %v, %v; want 0.1", f, err)
+	}
+
+	// lossy conversions are errors.
+	for _, test := range []struct {
+		name string
+		as   func() error
+	}{
+		{"As[int8](300)", func() error { _, err := As[int8](int64(300)); return err }},
+		{"As[int](1.5)", func() error { _, err := As[int](1.5); return err }},
+		{"As[uint](-1)", func() error { _, err := As[uint](int64(-1)); return err }},
+		{"As[int64](uint64 max)", func() error { _, err := As[int64](uint64(math.MaxUint64)); return err }},
+		{"As[float32](1e300)", func() error { _, err := As[float32](1e300); return err }},
+		{"As[int](NaN)", func() error { _, err := As[int](math.NaN()); return err }},
+	} {
+		if err := test.as(); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestRequireLoadFS(t *testing.T) {
+	LoadFS(fstest.MapFS{
+		"glj/test_require.glj": &fstest.MapFile{
+			Data: []byte(`(ns glj.test-require) (defn double [x] (* 2 x))`),
+		},
+	})
+	if err := Require("glj.test-require"); err != nil {
+		t.Fatal(err)
+	}
+	n, err := Call[int](Var("glj.test-require", "double"), 21)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 42 {
+		t.Errorf("Expected 42, got %v", n)
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	if _, err := Eval(context.Background(), `(ns sandbox.test) (defn shout [s] (strings.ToUpper s))`); err != nil {
+		t.Fatal(err)
+	}
+	SetSandbox(&runtime.Sandbox{
+		Allow:             []string{"strings", "fmt.Sprint"},
+		MaxSteps:          10000,
+		MaxCollectionSize: 100,
+		MaxGoroutines:     -1,
+	})
+	defer SetSandbox(nil)
+
+	for src, want := range map[string]interface{}{
+		`(shout "hi")`:           "HI",
+		`(strings.ToLower "HI")`: "hi",
+		`(fmt.Sprint 1)`:         "1",
+		`(count (for [i (range 3) :let [j (inc i)]] j))`: 3,
+		`(defn f [x] (let [[a b] x] (+ a b))) (f [1 2])`: int64(3),
+		`*read-eval*`: false,
+	} {
+		got, err := Eval(context.Background(), src, WithNamespace("sandbox.test"))
+		if err != nil || !value.Equiv(got, want) {
+			t.Errorf("%s = %v, %v; want %v", src, got, err, want)
+		}
+	}
+
+	var illegal *value.IllegalAccessError
+	for _, src := range []string{
+		`(os.Getenv "HOME")`,
+		`(fmt.Println 1)`,
+		`(import 'os.Getenv)`,
+		`(fn [] (os$exec.Command "ls"))`,
+		`(set! (.Len (strings.NewReader "")) 1)`,
+		// the runtime can look up any package export.
+		`((first (github.com$glojurelang$glojure$pkg$runtime.MaybeClass 'os$exec.Command)) "echo" "pwned")`,
+		// pkg/lang can change the limits and the state of the process.
+		`(github.com$glojurelang$glojure$pkg$lang.SetMaxCollectionSize 0)`,
+		`(github.com$glojurelang$glojure$pkg$lang.SetMaxGoroutines 0)`,
+		`(github.com$glojurelang$glojure$pkg$lang.SetRootContext nil)`,
+		`(github.com$glojurelang$glojure$pkg$lang.SetField (strings.NewReader "") "s" "pwned")`,
+		`(github.com$glojurelang$glojure$pkg$lang.PushContext nil)`,
+		`(github.com$glojurelang$glojure$pkg$lang.PopContext)`,
+		`(github.com$glojurelang$glojure$pkg$lang.ResetThreadBindingFrame nil)`,
+	} {
+		if _, err := Eval(context.Background(), src); !errors.As(err, &illegal) {
+			t.Errorf("%s: expected an illegal access error, got %v", src, err)
+		}
+	}
+
+	// the limits are unchanged.
+	var limit *value.ResourceLimitError
+	for _, src := range []string{
+		`(loop [] (recur))`,
+		`(vec (range 1000))`,
+		`(into #{} (range 1000))`,
+		`(zipmap (range 1000) (range 1000))`,
+		`(go/make (go/slice-of go/int) 1000)`,
+		`@(future 1)`,
+		`(go/go (identity 1))`,
+	} {
+		if _, err := Eval(context.Background(), src); !errors.As(err, &limit) {
+			t.Errorf("%s: expected a resource limit error, got %v", src, err)
+		}
+	}
+
+	// deny takes precedence over allow.
+	SetSandbox(&runtime.Sandbox{Deny: []string{"os/exec", "strings.Repeat"}})
+	if _, err := Eval(context.Background(), `(strings.Repeat "x" 2)`); !errors.As(err, &illegal) {
+		t.Errorf("expected strings.Repeat to be denied, got %v", err)
+	}
+	if _, err := Eval(context.Background(), `(strings.ToUpper "x")`); err != nil {
+		t.Errorf("expected strings.ToUpper to be allowed, got %v", err)
+	}
+
+	// denied packages can't be reached through the runtime either.
+	SetSandbox(&runtime.Sandbox{Deny: []string{"os", "os/exec"}})
+	for _, src := range []string{
+		`((first (github.com$glojurelang$glojure$pkg$runtime.MaybeClass 'os$exec.Command)) "echo" "pwned")`,
+		`(github.com$glojurelang$glojure$pkg$runtime.MaybeClass 'os.Getenv)`,
+	} {
+		if _, err := Eval(context.Background(), src); !errors.As(err, &illegal) {
+			t.Errorf("%s: expected an illegal access error, got %v", src, err)
+		}
+	}
+	if _, err := runtime.MaybeClass(value.NewSymbol("os.Getenv")); !errors.As(err, &illegal) {
+		t.Errorf("expected MaybeClass to check the sandbox, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package glj
+
+import (
+	"context"
+	"io"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+type (
+	// Option configures a single call to Eval, Require, or Load.
+	Option func(*options)
+
+	options struct {
+		// ctx, if set, is bound to *context* for the duration of the
+		// call.
+		ctx       context.Context
+		stdout    io.Writer
+		stderr    io.Writer
+		stdin     io.Reader
+		namespace string
+		filename  string
+	}
+)
+
+// WithStdout binds *out* to w for the duration of the call.
+func WithStdout(w io.Writer) Option {
+	return func(o *options) {
+		o.stdout = w
+	}
+}
+
+// WithStderr binds *err* to w for the duration of the call.
+func WithStderr(w io.Writer) Option {
+	return func(o *options) {
+		o.stderr = w
+	}
+}
+
+// WithStdin binds *in* to r for the duration of the call.
+func WithStdin(r io.Reader) Option {
+	return func(o *options) {
+		o.stdin = r
+	}
+}
+
+// WithNamespace sets the namespace in which code is evaluated. The
+// namespace is created if it does not exist. The default is "user".
+func WithNamespace(ns string) Option {
+	return func(o *options) {
+		o.namespace = ns
+	}
+}
+
+// WithFilename sets the filename reported in source positions for
+// code passed to Eval.
+func WithFilename(filename string) Option {
+	return func(o *options) {
+		o.filename = filename
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		namespace: "user",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// run calls f with the thread bindings configured by o established,
+// and with *ns* set to the configured namespace. Panics raised by f
+// are returned as errors.
+func (o *options) run(f func() error) (err error) {
+	value.PushThreadBindings(o.bindings())
+	defer value.PopThreadBindings()
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError(r)
+		}
+	}()
+
+	nsSym := value.NewSymbol(o.namespace)
+	if ns := value.FindNamespace(nsSym); ns != nil {
+		value.VarCurrentNS.Set(ns)
+	} else if _, err := value.GlobalEnv.Eval(value.NewList(runtime.SymbolNamespace, nsSym)); err != nil {
+		return err
+	}
+	// the namespace is set up regardless of the context, so that a
+	// canceled call doesn't leave it half-created.
+	if o.ctx != nil {
+		value.PushContext(o.ctx)
+		defer value.PopContext()
+	}
+	return f()
+}
+
+// bindings returns the thread bindings to establish for a call.
+func (o *options) bindings() value.IPersistentMap {
+	kvs := []interface{}{
+		value.VarCurrentNS, value.VarCurrentNS.Deref(),
+		value.VarWarnOnReflection, value.VarWarnOnReflection.Deref(),
+		value.VarUncheckedMath, value.VarUncheckedMath.Deref(),
+		value.VarDataReaders, value.VarDataReaders.Deref(),
+	}
+	if o.stdout != nil {
+		kvs = append(kvs, value.VarOut, o.stdout)
+	}
+	if o.stderr != nil {
+		kvs = append(kvs, value.VarErr, o.stderr)
+	}
+	if o.stdin != nil {
+		kvs = append(kvs, value.VarIn, o.stdin)
+	}
+	if o.filename != "" {
+		kvs = append(kvs, value.VarFile, o.filename)
+	}
+	return value.NewMap(kvs...)
+}
@@ -0,0 +1,13 @@
+package glj
+
+import (
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// SetSandbox restricts the code evaluated from now on to what sb
+// allows, or lifts the restrictions if sb is nil. Namespaces required
+// before it is set are not restricted. See runtime.Sandbox.
+func SetSandbox(sb *runtime.Sandbox) {
+	runtime.SetSandbox(value.GlobalEnv, sb)
+}
@@ -7,6 +7,15 @@ func Var(ns, name interface{}) value.IFn {
 	return value.InternVarName(asSym(ns), asSym(name))
 }
 
+// Intern binds the var named name in namespace ns to val, creating
+// the namespace and var if they do not exist. Go functions may be
+// interned directly; Glojure arguments are converted to the
+// function's parameter types when it is called.
+func Intern(ns, name interface{}, val interface{}) *value.Var {
+	nsObj := value.FindOrCreateNamespace(asSym(ns))
+	return value.InternVarReplaceRoot(nsObj, asSym(name), val)
+}
+
 func asSym(x interface{}) *value.Symbol {
 	if str, ok := x.(string); ok {
 		return value.NewSymbol(str)
@@ -0,0 +1,80 @@
+package gljmain
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/glojurelang/glojure/pkg/aot"
+	"github.com/glojurelang/glojure/pkg/glj"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// compile implements glj compile, which compiles files and namespaces
+// to Go source. The targets are loaded, as they would be by a program,
+// to record their analyzed forms, so compiling a file runs it.
+func compile(args []string) {
+	flags := flag.NewFlagSet("compile", flag.ExitOnError)
+	outDir := flags.String("o", ".", "output directory")
+	pkg := flags.String("pkg", "main", "package name of the generated Go files")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: glj compile [flags] file.glj|namespace...\n\n")
+		fmt.Fprintf(flags.Output(), "Compiles the given files and namespaces, and the files they load, to Go.\n")
+		fmt.Fprintf(flags.Output(), "With -pkg main, also writes a main.go that loads them in order.\n\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	img := runtime.NewImage()
+	runtime.NewEnvironment(runtime.WithImageRecorder(img))
+	img.Remove(img.Files()...)
+
+	var targets []string
+	for _, target := range flags.Args() {
+		var err error
+		if strings.HasSuffix(target, ".glj") {
+			target = filepath.ToSlash(filepath.Clean(target))
+			err = glj.Load(target)
+		} else {
+			err = glj.Require(target)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		targets = append(targets, target)
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		log.Fatal(err)
+	}
+	core := runtime.CoreImage()
+	for _, name := range img.Files() {
+		// the standard library is restored from the core image.
+		if core != nil && core.FileHash(name) != "" {
+			continue
+		}
+		src, err := aot.Compile(img, name, *pkg)
+		if err != nil {
+			log.Fatalf("compiling %s: %v", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(*outDir, aot.FileName(name)), src, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *pkg == "main" {
+		src, err := aot.Main(targets)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(*outDir, "main.go"), src, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
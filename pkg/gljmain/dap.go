@@ -0,0 +1,34 @@
+package gljmain
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/glojurelang/glojure/pkg/dap"
+	"github.com/glojurelang/glojure/pkg/lang"
+)
+
+// serveDAP implements glj dap, which runs a debug adapter on the
+// standard input and output until the client disconnects.
+func serveDAP(args []string) {
+	flags := flag.NewFlagSet("dap", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: glj dap\n\n")
+		fmt.Fprintf(flags.Output(), "Runs a Debug Adapter Protocol server for editors on standard input\n")
+		fmt.Fprintf(flags.Output(), "and output. The program to debug is given by the launch request.\n")
+	}
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err := dap.NewServer(lang.GlobalEnv).Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "glj dap:", err)
+		os.Exit(1)
+	}
+	// the program may still be running, in which case disconnecting
+	// ends it.
+	os.Exit(0)
+}
@@ -0,0 +1,101 @@
+package gljmain
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/glojurelang/glojure/pkg/format"
+)
+
+// formatFiles implements glj fmt, which formats files in the default
+// style of cljfmt, as gofmt does for Go files.
+func formatFiles(args []string) {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := flags.Bool("w", false, "write the result to the files instead of stdout")
+	list := flags.Bool("l", false, "list the files whose formatting differs")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: glj fmt [flags] [path...]\n\n")
+		fmt.Fprintf(flags.Output(), "Formats the given files, and the .glj files in the given directories.\n")
+		fmt.Fprintf(flags.Output(), "With no paths, formats standard input.\n\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "glj fmt: cannot use -w with standard input")
+			os.Exit(2)
+		}
+		src, err := io.ReadAll(os.Stdin)
+		if err == nil {
+			err = formatFile("<standard input>", src, os.Stdout, false, *list)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	failed := false
+	for _, root := range flags.Args() {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// files named explicitly are formatted whatever their
+			// extension.
+			if d.IsDir() || (path != root && !strings.HasSuffix(path, ".glj")) {
+				return nil
+			}
+			src, err := os.ReadFile(path)
+			if err == nil {
+				err = formatFile(path, src, os.Stdout, *write, *list)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				failed = true
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// formatFile formats src, the content of the file at path. It writes
+// the result to the file if write is set, lists path if list is set
+// and the result differs from src, and otherwise writes the result to
+// out.
+func formatFile(path string, src []byte, out io.Writer, write, list bool) error {
+	res, err := format.Source(path, src)
+	if err != nil {
+		return err
+	}
+	changed := !bytes.Equal(src, res)
+	if list && changed {
+		fmt.Fprintln(out, path)
+	}
+	if write && changed {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, res, info.Mode().Perm())
+	}
+	if !list && !write {
+		_, err = out.Write(res)
+	}
+	return err
+}
@@ -1,9 +1,10 @@
 package gljmain
 
 import (
-	"bufio"
-	"log"
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	// bootstrap the runtime
 	_ "github.com/glojurelang/glojure/pkg/glj"
@@ -14,36 +15,272 @@ import (
 	"github.com/glojurelang/glojure/pkg/runtime"
 )
 
+const usage = `usage: glj [profile-opt*] [server-opt*] [init-opt*] [main-opt] [arg*]
+       glj compile|dap|fmt|lint|lsp|nrepl|test [flags] ...
+
+With no options or args, runs an interactive Read-Eval-Print Loop.
+
+profile options:
+  --cpuprofile path   Write a CPU profile to a file, with the samples
+                      labeled by Glojure fn as glojure.fn
+  --memprofile path   Write a heap profile to a file on exit
+
+server options:
+  -Dglj.server.NAME=OPTS
+                      Start a socket server named NAME with the options
+                      of glojure.core.server/start-server in the EDN map
+                      OPTS; GLJ_SERVER_NAME=OPTS in the environment also
+                      starts one
+
+init options:
+  -i, --init path     Load a file
+  -e, --eval string   Evaluate expressions in string; print non-nil values
+  --load-path dir     Add a directory to the load path
+
+main options:
+  -m, --main ns-name  Call the -main function of a namespace with args
+  -r, --repl          Run a REPL
+  path                Run a script from a file
+  -                   Run a script from standard input
+  -h, -?, --help      Print this help message and exit
+
+operation:
+  - Starts the profiles
+  - Establishes thread-local bindings for *ns*, *warn-on-reflection*,
+    *unchecked-math* and *data-readers*
+  - Enters the user namespace
+  - Starts the socket servers
+  - Binds *command-line-args* to a seq of the args following the
+    main option, or nil
+  - Runs all init options in order
+  - Calls a -main function, runs a script or a REPL, or exits
+
+The init options may be repeated and mixed freely, but must appear
+before any main option. Paths may be absolute or relative to the
+current directory. The current directory is on the load path.
+
+glj exits with status 1 if an error is not caught.
+`
+
 func Main(args []string) {
 	runtime.AddLoadPath(os.DirFS("."))
 
+	if len(args) > 0 {
+		switch args[0] {
+		case "compile":
+			compile(args[1:])
+			return
+		case "dap":
+			serveDAP(args[1:])
+			return
+		case "fmt":
+			formatFiles(args[1:])
+			return
+		case "lint":
+			lintFiles(args[1:])
+			return
+		case "lsp":
+			serveLSP(args[1:])
+			return
+		case "nrepl":
+			serveNREPL(args[1:])
+			return
+		case "test":
+			testNamespaces(args[1:])
+			return
+		}
+	}
+	os.Exit(run(args))
+}
+
+// run runs glj with the options of clojure.main and returns its exit
+// status.
+func run(args []string) int {
+	env := lang.GlobalEnv
+
+	// profile options
+	prof, args, err := parseProfileOptions(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "glj: %v\n\n%s", err, usage)
+		return 2
+	}
+	if err := prof.start(); err != nil {
+		fmt.Fprintln(os.Stderr, "glj:", err)
+		return 1
+	}
+	defer func() {
+		if err := prof.stop(); err != nil {
+			fmt.Fprintln(os.Stderr, "glj:", err)
+		}
+	}()
+
+	kvs := make([]interface{}, 0, 8)
+	for _, vr := range []*lang.Var{lang.VarCurrentNS, lang.VarWarnOnReflection, lang.VarUncheckedMath, lang.VarDataReaders} {
+		kvs = append(kvs, vr, vr.Deref())
+	}
+	lang.PushThreadBindings(lang.NewMap(kvs...))
+	defer lang.PopThreadBindings()
+	if err := try(func() error {
+		_, err := env.Eval(lang.NewList(lang.NewSymbol("ns"), lang.NewSymbol("user")))
+		return err
+	}); err != nil {
+		return fail(err)
+	}
+
+	// server options
+	var props []string
+	for len(args) > 0 && strings.HasPrefix(args[0], "-D") {
+		props = append(props, args[0][len("-D"):])
+		args = args[1:]
+	}
+	configs, err := serverConfigs(props)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "glj: %v\n\n%s", err, usage)
+		return 2
+	}
+	if err := try(func() error { return startServers(configs) }); err != nil {
+		return fail(err)
+	}
+
+	// init options
+	bindArgs(nil)
+	inits := len(args) > 0
+	for len(args) > 0 {
+		var init func(string) error
+		switch args[0] {
+		case "-i", "--init":
+			init = loadFile
+		case "-e", "--eval":
+			init = evalString
+		case "--load-path":
+			init = func(dir string) error {
+				runtime.AddLoadPath(os.DirFS(dir))
+				return nil
+			}
+		}
+		if init == nil {
+			break
+		}
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, "glj: missing argument to %s\n\n%s", args[0], usage)
+			return 2
+		}
+		if err := try(func() error { return init(args[1]) }); err != nil {
+			return fail(err)
+		}
+		args = args[2:]
+	}
 	if len(args) == 0 {
-		repl.Start()
-	} else {
-		file, err := os.Open(os.Args[1])
+		// with only init options, glj exits once they have run.
+		if !inits {
+			repl.Start(repl.WithEnvironment(env))
+		}
+		return 0
+	}
+
+	// main option
+	switch opt := args[0]; opt {
+	case "-h", "-?", "--help":
+		fmt.Print(usage)
+	case "-r", "--repl":
+		bindArgs(args[1:])
+		repl.Start(repl.WithEnvironment(env))
+	case "-m", "--main":
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, "glj: missing argument to %s\n\n%s", opt, usage)
+			return 2
+		}
+		bindArgs(args[2:])
+		err = try(func() error { return callMain(args[1], args[2:]) })
+	case "-":
+		bindArgs(args[1:])
+		err = try(func() error { return runtime.LoadReader(env, os.Stdin, "NO_SOURCE_FILE") })
+	default:
+		bindArgs(args[1:])
+		err = try(func() error { return loadFile(opt) })
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// bindArgs sets *command-line-args* to a seq of args.
+func bindArgs(args []string) {
+	var seq interface{}
+	if len(args) > 0 {
+		seq = lang.Seq(args)
+	}
+	lang.NSCore.FindInternedVar(lang.NewSymbol("*command-line-args*")).BindRoot(seq)
+}
+
+// loadFile evaluates the forms of the file at path, as load-file does.
+func loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return runtime.LoadReader(lang.GlobalEnv, f, path)
+}
+
+// evalString evaluates the forms of src, printing the values that
+// aren't nil.
+func evalString(src string) error {
+	env := lang.GlobalEnv
+	rdr := reader.New(strings.NewReader(src), reader.WithFilename("NO_SOURCE_FILE"), reader.WithGetCurrentNS(env.CurrentNamespace))
+	for {
+		form, err := rdr.ReadOne()
+		if err == reader.ErrEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		val, err := env.Eval(form)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		if val != nil {
+			fmt.Fprintln(lang.VarOut.Deref().(io.Writer), lang.PrintString(val))
 		}
-		env := lang.GlobalEnv
+	}
+}
 
-		core := lang.FindNamespace(lang.NewSymbol("glojure.core"))
-		core.FindInternedVar(lang.NewSymbol("*command-line-args*")).BindRoot(lang.Seq(os.Args[2:]))
+// callMain requires the namespace ns and applies its -main function
+// to args.
+func callMain(ns string, args []string) error {
+	sym := lang.NewSymbol(ns)
+	if _, err := lang.GlobalEnv.Eval(lang.NewList(lang.NewSymbol("require"), lang.NewList(lang.NewSymbol("quote"), sym))); err != nil {
+		return err
+	}
+	vr := lang.FindNamespace(sym).FindInternedVar(lang.NewSymbol("-main"))
+	if vr == nil || !vr.IsBound() {
+		return fmt.Errorf("namespace %s has no -main function", ns)
+	}
+	fnArgs := make([]interface{}, len(args))
+	for i, arg := range args {
+		fnArgs[i] = arg
+	}
+	lang.Apply(vr, fnArgs)
+	return nil
+}
 
-		rdr := reader.New(bufio.NewReader(file), reader.WithGetCurrentNS(func() *lang.Namespace {
-			return env.CurrentNamespace()
-		}))
-		for {
-			val, err := rdr.ReadOne()
-			if err == reader.ErrEOF {
-				break
-			}
-			if err != nil {
-				log.Fatal(err)
-			}
-			_, err = env.Eval(val)
-			if err != nil {
-				log.Fatal(err)
+// try calls fn, returning a panic it raises as an error.
+func try(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if err, ok = r.(error); !ok {
+				err = fmt.Errorf("%v", r)
 			}
 		}
-	}
+	}()
+	return fn()
+}
+
+// fail reports an uncaught error and returns the exit status for it.
+func fail(err error) int {
+	runtime.PrintError(os.Stderr, err)
+	return 1
 }
@@ -0,0 +1,82 @@
+package gljmain
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glojurelang/glojure/pkg/lang"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		status int
+		out    string
+	}{{
+		name: "eval",
+		args: []string{"-e", `(+ 1 2) nil (str *ns*)`, "-e", `(println *command-line-args*)`},
+		out:  "3\n\"user\"\nnil\n",
+	}, {
+		name: "init and script",
+		args: []string{"-i", "testdata/script.glj", "testdata/script.glj", "a", "b"},
+		out:  "script testdata/script.glj nil\nscript testdata/script.glj (a b)\n",
+	}, {
+		name: "init restores ns",
+		args: []string{"-i", "testdata/script.glj", "-e", `(str *ns*)`},
+		out:  "script testdata/script.glj nil\n\"user\"\n",
+	}, {
+		name: "main",
+		args: []string{"--load-path", "testdata", "-m", "cli.main", "x", "y"},
+		out:  "main (x y) true\n",
+	}, {
+		name:   "uncaught error",
+		args:   []string{"-e", `(println "before")`, "-e", `(/ 1 0)`, "-e", `(println "after")`},
+		status: 1,
+		out:    "before\n",
+	}, {
+		name:   "missing file",
+		args:   []string{"testdata/missing.glj"},
+		status: 1,
+	}, {
+		name:   "missing argument",
+		args:   []string{"-e"},
+		status: 2,
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			lang.PushThreadBindings(lang.NewMap(lang.VarOut, &out))
+			defer lang.PopThreadBindings()
+
+			if status := run(tt.args); status != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, status)
+			}
+			if out.String() != tt.out {
+				t.Errorf("expected output %q, got %q", tt.out, out.String())
+			}
+		})
+	}
+}
+
+func TestRunProfiles(t *testing.T) {
+	lang.PushThreadBindings(lang.NewMap(lang.VarOut, &bytes.Buffer{}))
+	defer lang.PopThreadBindings()
+
+	dir := t.TempDir()
+	cpu, mem := filepath.Join(dir, "cpu.pprof"), filepath.Join(dir, "mem.pprof")
+	if status := run([]string{"--cpuprofile", cpu, "--memprofile", mem, "-e", "(defn f [] 1) (f)"}); status != 0 {
+		t.Fatalf("expected status 0, got %d", status)
+	}
+	for _, path := range []string{cpu, mem} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("expected a profile in %s: %v", path, err)
+		}
+	}
+	if status := run([]string{"--cpuprofile"}); status != 2 {
+		t.Errorf("expected status 2 for a missing argument, got %d", status)
+	}
+}
@@ -0,0 +1,69 @@
+package gljmain
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/lint"
+)
+
+// lintFiles implements glj lint, which reports likely mistakes in
+// files without evaluating them, and exits with status 1 if it finds
+// any.
+func lintFiles(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	jsonOut := flags.Bool("json", false, "print the diagnostics as a JSON array")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: glj lint [flags] path...\n\n")
+		fmt.Fprintf(flags.Output(), "Analyzes the given files, and the .glj files in the given directories,\n")
+		fmt.Fprintf(flags.Output(), "and prints the problems found as file:line:col: message.\n\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	diags := []lint.Diagnostic{}
+	for _, root := range flags.Args() {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// files named explicitly are linted whatever their extension.
+			if d.IsDir() || (path != root && !strings.HasSuffix(path, ".glj")) {
+				return nil
+			}
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			diags = append(diags, lint.Source(lang.GlobalEnv, path, string(src))...)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(diags)
+	} else {
+		for _, d := range diags {
+			fmt.Println(d)
+		}
+	}
+	if len(diags) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,35 @@
+package gljmain
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/lsp"
+)
+
+// serveLSP implements glj lsp, which runs a language server on the
+// standard input and output until the client exits.
+func serveLSP(args []string) {
+	flags := flag.NewFlagSet("lsp", flag.ExitOnError)
+	// Editors commonly pass --stdio to language servers; it is the
+	// only transport.
+	flags.Bool("stdio", true, "communicate over standard input and output")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: glj lsp [flags]\n\n")
+		fmt.Fprintf(flags.Output(), "Runs a Language Server Protocol server for editors on standard input\n")
+		fmt.Fprintf(flags.Output(), "and output.\n\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err := lsp.NewServer(lang.GlobalEnv).Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "glj lsp:", err)
+		os.Exit(1)
+	}
+}
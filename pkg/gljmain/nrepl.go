@@ -0,0 +1,63 @@
+package gljmain
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/nrepl"
+)
+
+// nreplPortFile is the file in which glj nrepl records its port, where
+// editors look for it.
+const nreplPortFile = ".nrepl-port"
+
+// serveNREPL implements glj nrepl, which runs an nREPL server until it
+// is interrupted.
+func serveNREPL(args []string) {
+	flags := flag.NewFlagSet("nrepl", flag.ExitOnError)
+	port := flags.Int("port", 0, "port to listen on; by default, any free port")
+	host := flags.String("host", "127.0.0.1", "address to listen on")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: glj nrepl [flags]\n\n")
+		fmt.Fprintf(flags.Output(), "Runs an nREPL server for editors, writing its port to %s in the\n", nreplPortFile)
+		fmt.Fprintf(flags.Output(), "current directory.\n\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(*host, strconv.Itoa(*port)))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "glj nrepl:", err)
+		os.Exit(1)
+	}
+	addr := ln.Addr().(*net.TCPAddr)
+	if err := os.WriteFile(nreplPortFile, []byte(strconv.Itoa(addr.Port)), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "glj nrepl:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("nREPL server started on port %d on host %s - nrepl://%s\n", addr.Port, *host, addr)
+
+	srv := nrepl.NewServer(lang.GlobalEnv)
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		srv.Close()
+	}()
+	err = srv.Serve(ln)
+	os.Remove(nreplPortFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "glj nrepl:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,80 @@
+package gljmain
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// profiles are the profiles that the profile options of glj ask for.
+type profiles struct {
+	cpu, mem string
+	cpuFile  *os.File
+}
+
+// parseProfileOptions returns the profiles asked for by the profile
+// options at the start of args, and the args that follow them.
+func parseProfileOptions(args []string) (*profiles, []string, error) {
+	p := &profiles{}
+	for len(args) > 0 {
+		var path *string
+		switch args[0] {
+		case "--cpuprofile":
+			path = &p.cpu
+		case "--memprofile":
+			path = &p.mem
+		}
+		if path == nil {
+			break
+		}
+		if len(args) < 2 {
+			return nil, nil, fmt.Errorf("missing argument to %s", args[0])
+		}
+		*path = args[1]
+		args = args[2:]
+	}
+	return p, args, nil
+}
+
+// start starts writing the CPU profile, if one is asked for.
+func (p *profiles) start() error {
+	if p.cpu == "" {
+		return nil
+	}
+	f, err := os.Create(p.cpu)
+	if err != nil {
+		return err
+	}
+	if err := runtime.StartCPUProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	p.cpuFile = f
+	return nil
+}
+
+// stop stops writing the CPU profile, and writes the heap profile, if
+// they are asked for.
+func (p *profiles) stop() error {
+	if p.cpuFile != nil {
+		runtime.StopCPUProfile()
+		err := p.cpuFile.Close()
+		p.cpuFile = nil
+		if err != nil {
+			return err
+		}
+	}
+	if p.mem == "" {
+		return nil
+	}
+	f, err := os.Create(p.mem)
+	if err != nil {
+		return err
+	}
+	if err := runtime.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
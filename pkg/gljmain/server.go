@@ -0,0 +1,71 @@
+package gljmain
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/repl"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// The prefixes of the -D properties and environment variables that
+// configure the socket servers started by glj, as the clojure.server.*
+// system properties do for Clojure: -Dglj.server.NAME=OPTS or
+// GLJ_SERVER_NAME=OPTS starts a server named NAME with the options of
+// the EDN map OPTS.
+const (
+	serverProperty = "glj.server."
+	serverEnv      = "GLJ_SERVER_"
+)
+
+// serverConfigs returns the options of the servers to start by name,
+// from the GLJ_SERVER_* environment variables and then the given -D
+// properties, without their -D. Names of environment variables are
+// lower-cased, with underscores replaced by hyphens.
+func serverConfigs(props []string) (map[string]string, error) {
+	configs := map[string]string{}
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		if name := strings.TrimPrefix(k, serverEnv); name != k && name != "" {
+			configs[strings.ReplaceAll(strings.ToLower(name), "_", "-")] = v
+		}
+	}
+	for _, prop := range props {
+		k, v, ok := strings.Cut(prop, "=")
+		name := strings.TrimPrefix(k, serverProperty)
+		if !ok || name == k || name == "" {
+			return nil, fmt.Errorf("unknown property %q; expected %sNAME=OPTS", prop, serverProperty)
+		}
+		configs[name] = v
+	}
+	return configs, nil
+}
+
+// startServers starts the servers configured in configs, in order of
+// name, requiring glojure.core.server first.
+func startServers(configs map[string]string) error {
+	if len(configs) == 0 {
+		return nil
+	}
+	if _, err := lang.GlobalEnv.Eval(lang.NewList(lang.NewSymbol("require"), lang.NewList(lang.NewSymbol("quote"), lang.NewSymbol("glojure.core.server")))); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opts, ok := runtime.RTReadString(configs[name]).(lang.IPersistentMap)
+		if !ok {
+			return fmt.Errorf("server %s: options must be a map, got %s", name, configs[name])
+		}
+		if _, err := repl.StartServer(lang.Assoc(opts, lang.NewKeyword("name"), name).(lang.IPersistentMap)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
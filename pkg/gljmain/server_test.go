@@ -0,0 +1,30 @@
+package gljmain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServerConfigs(t *testing.T) {
+	t.Setenv("GLJ_SERVER_ENV_REPL", "{:port 5555}")
+	t.Setenv("GLJ_SERVER_PREPL", "{:port 5556}")
+
+	configs, err := serverConfigs([]string{"glj.server.repl={:port 5557}", "glj.server.prepl={:port 5558}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"env-repl": "{:port 5555}",
+		"prepl":    "{:port 5558}",
+		"repl":     "{:port 5557}",
+	}
+	if !reflect.DeepEqual(configs, expected) {
+		t.Errorf("expected %v, got %v", expected, configs)
+	}
+
+	for _, prop := range []string{"glj.server.repl", "glj.server.={}", "user.dir=."} {
+		if _, err := serverConfigs([]string{prop}); err == nil {
+			t.Errorf("expected an error for -D%s", prop)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package gljmain
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/glojurelang/glojure/pkg/glj"
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// testOptions are the options of glj test.
+type testOptions struct {
+	dirs     []string
+	ns, vr   *regexp.Regexp
+	format   string
+	out      string
+	parallel bool
+	// cover records the coverage of the namespaces under test, which
+	// is written as LCOV to coverProfile and as HTML to coverHTML if
+	// they are set.
+	cover        bool
+	coverProfile string
+	coverHTML    string
+}
+
+// testNamespaces implements glj test, which loads the test namespaces
+// of directories and runs their tests, exiting with status 1 if any
+// fail.
+func testNamespaces(args []string) {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	nsPattern := flags.String("namespace", "", "run only the namespaces whose names match this regexp")
+	varPattern := flags.String("var", "", "run only the tests whose namespace-qualified names match this regexp")
+	format := flags.String("format", "text", "report format: text, junit or tap")
+	out := flags.String("o", "", "write the report to this file instead of standard output")
+	parallel := flags.Bool("parallel", false, "test namespaces in parallel")
+	cover := flags.Bool("cover", false, "record the coverage of the forms of the namespaces under test and print a summary")
+	coverProfile := flags.String("coverprofile", "", "write an LCOV coverage report to this file; implies -cover")
+	coverHTML := flags.String("coverhtml", "", "write an HTML coverage report to this file; implies -cover")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: glj test [flags] [dir...]\n\n")
+		fmt.Fprintf(flags.Output(), "Adds the given directories, or test, to the load path, loads the test\n")
+		fmt.Fprintf(flags.Output(), "namespaces in them, and runs their tests with glojure.test. A test\n")
+		fmt.Fprintf(flags.Output(), "namespace has a name with a part starting with test- or ending in -test,\n")
+		fmt.Fprintf(flags.Output(), "as do those of the files test_util.glj and util_test.glj. The coverage\n")
+		fmt.Fprintf(flags.Output(), "of -cover excludes test namespaces and those under glojure.\n\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	opts := testOptions{
+		dirs:         flags.Args(),
+		format:       *format,
+		out:          *out,
+		parallel:     *parallel,
+		cover:        *cover || *coverProfile != "" || *coverHTML != "",
+		coverProfile: *coverProfile,
+		coverHTML:    *coverHTML,
+	}
+	if len(opts.dirs) == 0 {
+		opts.dirs = []string{"test"}
+	}
+	var err error
+	if opts.ns, err = regexp.Compile(*nsPattern); err != nil {
+		fmt.Fprintln(os.Stderr, "glj test: -namespace:", err)
+		os.Exit(2)
+	}
+	if *varPattern != "" {
+		if opts.vr, err = regexp.Compile(*varPattern); err != nil {
+			fmt.Fprintln(os.Stderr, "glj test: -var:", err)
+			os.Exit(2)
+		}
+	}
+	switch opts.format {
+	case "text", "junit", "tap":
+	default:
+		fmt.Fprintf(os.Stderr, "glj test: unknown format %q\n", opts.format)
+		os.Exit(2)
+	}
+	os.Exit(runTests(opts))
+}
+
+// runTests runs the tests selected by opts and returns the exit status
+// of glj test.
+func runTests(opts testOptions) int {
+	lang.PushThreadBindings(lang.NewMap(lang.VarCurrentNS, lang.VarCurrentNS.Deref()))
+	defer lang.PopThreadBindings()
+
+	var cov *runtime.Coverage
+	if opts.cover {
+		cov = &runtime.Coverage{Include: func(ns string) bool {
+			return ns != "glojure" && !strings.HasPrefix(ns, "glojure.") && !isTestNamespace(ns)
+		}}
+		runtime.SetCoverage(lang.GlobalEnv, cov)
+		defer runtime.SetCoverage(lang.GlobalEnv, nil)
+	}
+
+	// the files of test namespaces are loaded, then all the test
+	// namespaces they define are tested.
+	loaded := map[*lang.Namespace]bool{}
+	for _, ns := range lang.Namespaces() {
+		loaded[ns] = true
+	}
+	for _, dir := range opts.dirs {
+		runtime.AddLoadPath(os.DirFS(dir))
+		names, err := findTestNamespaces(dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "glj test:", err)
+			return 2
+		}
+		for _, name := range names {
+			if !opts.ns.MatchString(name) {
+				continue
+			}
+			if err := glj.Require(name); err != nil {
+				runtime.PrintError(os.Stderr, err)
+				return 1
+			}
+		}
+	}
+	var namespaces []*lang.Namespace
+	for _, ns := range lang.Namespaces() {
+		name := ns.Name().Name()
+		if !loaded[ns] && isTestNamespace(name) && opts.ns.MatchString(name) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name().Name() < namespaces[j].Name().Name()
+	})
+
+	if err := glj.Require("glojure.test.runner"); err != nil {
+		runtime.PrintError(os.Stderr, err)
+		return 1
+	}
+	if opts.out != "" {
+		f, err := os.Create(opts.out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "glj test:", err)
+			return 2
+		}
+		defer f.Close()
+		lang.PushThreadBindings(lang.NewMap(glj.Var("glojure.test", "*test-out*"), f))
+		defer lang.PopThreadBindings()
+	}
+
+	runOpts := lang.NewMap(
+		lang.NewKeyword("parallel"), opts.parallel,
+		lang.NewKeyword("format"), lang.NewKeyword(opts.format),
+	)
+	if opts.vr != nil {
+		runOpts = lang.Assoc(runOpts, lang.NewKeyword("var"), opts.vr).(lang.IPersistentMap)
+	}
+	nss := make([]interface{}, len(namespaces))
+	for i, ns := range namespaces {
+		nss[i] = ns
+	}
+	summary, err := glj.Call[interface{}](glj.Var("glojure.test.runner", "run"), lang.NewVector(nss...), runOpts)
+	if err != nil {
+		runtime.PrintError(os.Stderr, err)
+		return 1
+	}
+	if cov != nil {
+		if err := reportCoverage(cov, opts); err != nil {
+			fmt.Fprintln(os.Stderr, "glj test:", err)
+			return 2
+		}
+	}
+	if ok, err := glj.Call[bool](glj.Var("glojure.test", "successful?"), summary); err != nil || !ok {
+		return 1
+	}
+	return 0
+}
+
+// reportCoverage prints the percentage of the forms of cov that were
+// evaluated, after the test report unless it is written to standard
+// output in another format than text, and writes the coverage reports
+// that opts asks for.
+func reportCoverage(cov *runtime.Coverage, opts testOptions) error {
+	blocks := cov.Blocks()
+	covered := 0
+	for _, b := range blocks {
+		if b.Count > 0 {
+			covered++
+		}
+	}
+	w := os.Stdout
+	if opts.format != "text" && opts.out == "" {
+		w = os.Stderr
+	}
+	if len(blocks) == 0 {
+		fmt.Fprintln(w, "coverage: [no forms]")
+	} else {
+		fmt.Fprintf(w, "coverage: %.1f%% of forms\n", 100*float64(covered)/float64(len(blocks)))
+	}
+
+	for _, report := range []struct {
+		path  string
+		write func(io.Writer) error
+	}{
+		{opts.coverProfile, cov.WriteLCOV},
+		{opts.coverHTML, cov.WriteHTML},
+	} {
+		if report.path == "" {
+			continue
+		}
+		f, err := os.Create(report.path)
+		if err != nil {
+			return err
+		}
+		if err := report.write(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findTestNamespaces returns the names of the test namespaces of the
+// .glj files in dir, derived from their paths.
+func findTestNamespaces(dir string) ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".glj") {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.ToSlash(rel), ".glj")
+		name = strings.ReplaceAll(strings.ReplaceAll(name, "/", "."), "_", "-")
+		if isTestNamespace(name) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	return names, err
+}
+
+// isTestNamespace reports whether name is that of a test namespace: a
+// part of it starts with test- or ends in -test.
+func isTestNamespace(name string) bool {
+	for _, part := range strings.Split(name, ".") {
+		if strings.HasPrefix(part, "test-") || strings.HasSuffix(part, "-test") {
+			return true
+		}
+	}
+	return false
+}
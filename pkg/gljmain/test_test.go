@@ -0,0 +1,118 @@
+package gljmain
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFindTestNamespaces(t *testing.T) {
+	names, err := findTestNamespaces("testdata/tests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(names)
+	expected := []string{"sample.failing-test", "sample.passing-test", "sample.xml-test"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   testOptions
+		status int
+		out    string
+	}{{
+		name: "text",
+		opts: testOptions{ns: regexp.MustCompile(`passing`), format: "text"},
+		out:  "\nTesting sample.passing-test\n\nRan 1 tests containing 1 assertions.\n0 failures, 0 errors.\n",
+	}, {
+		name: "tap with var filter",
+		opts: testOptions{
+			ns:     regexp.MustCompile(`failing`),
+			vr:     regexp.MustCompile(`/subtracts$`),
+			format: "tap",
+		},
+		status: 1,
+		out: "not ok (subtracts) (sample/failing_test.glj:8)\n" +
+			"# expected:(= 1 (- 3 1))\n" +
+			"#   actual:(not (= 1 2))\n",
+	}, {
+		// the control character of the failure is replaced, as XML
+		// disallows it.
+		name:   "junit with a control character",
+		opts:   testOptions{ns: regexp.MustCompile(`xml`), format: "junit"},
+		status: 1,
+		out:    "(not (= &quot;ding&quot; &quot;ding\uFFFD&quot;))",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.dirs = []string{"testdata/tests"}
+			tt.opts.out = filepath.Join(t.TempDir(), "report")
+			if status := runTests(tt.opts); status != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, status)
+			}
+			out, err := os.ReadFile(tt.opts.out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(out), tt.out) {
+				t.Errorf("expected output containing %q, got %q", tt.out, out)
+			}
+			if tt.opts.format == "junit" {
+				dec := xml.NewDecoder(bytes.NewReader(out))
+				for {
+					if _, err := dec.Token(); err == io.EOF {
+						break
+					} else if err != nil {
+						t.Fatalf("expected valid XML, got %v:\n%s", err, out)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRunTestsCoverage(t *testing.T) {
+	dir := t.TempDir()
+	opts := testOptions{
+		dirs:         []string{"testdata/cover"},
+		ns:           regexp.MustCompile(``),
+		format:       "text",
+		out:          filepath.Join(dir, "report"),
+		cover:        true,
+		coverProfile: filepath.Join(dir, "lcov.info"),
+		coverHTML:    filepath.Join(dir, "coverage.html"),
+	}
+	if status := runTests(opts); status != 0 {
+		t.Fatalf("expected status 0, got %d", status)
+	}
+
+	// the test namespace isn't covered, and the branch of clamp that
+	// returns hi and sign aren't evaluated.
+	lcov, err := os.ReadFile(opts.coverProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "TN:\nSF:calc/math.glj\nDA:1,1\nDA:3,1\nDA:4,2\nDA:5,2\nDA:6,1\nDA:9,1\nDA:10,0\nLF:7\nLH:6\nend_of_record\n"
+	if string(lcov) != expected {
+		t.Errorf("expected LCOV\n%s\ngot\n%s", expected, lcov)
+	}
+	page, err := os.ReadFile(opts.coverHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), "calc/math.glj (75.0%)") {
+		t.Errorf("expected the coverage of calc/math.glj, got\n%s", page)
+	}
+}
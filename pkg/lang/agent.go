@@ -62,9 +62,9 @@ func AgentSubmit(fn IFn) IBlockingDeref {
 	fut := &future{
 		done: make(chan struct{}),
 	}
-	go func() {
+	Go(func() {
 		fut.res = fn.Invoke()
 		close(fut.done)
-	}()
+	})
 	return fut
 }
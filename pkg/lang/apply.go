@@ -3,6 +3,7 @@ package lang
 import (
 	"fmt"
 	"reflect"
+	"sync"
 )
 
 func Apply(fn interface{}, args []interface{}) interface{} {
@@ -19,42 +20,145 @@ func Apply(fn interface{}, args []interface{}) interface{} {
 	}
 
 	goVal := reflect.ValueOf(fn)
+	if goVal.Kind() == reflect.Slice {
+		return applySlice(goVal, args)
+	}
 
-	gvKind := goVal.Kind()
+	return packResults(applyFunc(goVal, args))
+}
+
+// ApplyGoTry is like Apply, but if fn is a Go function whose last
+// result is an error, that error is returned separately rather than
+// as part of the result. The remaining results are packed as by
+// Apply: no results yield nil, one yields the value itself, and
+// several yield a vector.
+func ApplyGoTry(fn interface{}, args []interface{}) (interface{}, error) {
+	switch fn.(type) {
+	case nil, IFn, reflect.Type:
+		return Apply(fn, args), nil
+	}
+
+	goVal := reflect.ValueOf(fn)
 	gvType := goVal.Type()
+	if gvType.Kind() != reflect.Func || gvType.NumOut() == 0 || gvType.Out(gvType.NumOut()-1) != errorType {
+		return Apply(fn, args), nil
+	}
 
-	if gvKind == reflect.Slice {
-		return applySlice(goVal, args)
+	reflectRes := applyFunc(goVal, args)
+	errVal := reflectRes[len(reflectRes)-1]
+	if !errVal.IsNil() {
+		return nil, errVal.Interface().(error)
 	}
+	return packResults(reflectRes[:len(reflectRes)-1]), nil
+}
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+func applyFunc(goVal reflect.Value, args []interface{}) []reflect.Value {
+	gvKind := goVal.Kind()
+	gvType := goVal.Type()
 
 	if gvKind != reflect.Func {
-		panic(fmt.Errorf("cannot apply non-function %T", fn))
-	}
-	if gvType.NumIn() != len(args) && !gvType.IsVariadic() {
-		panic(fmt.Errorf("wrong number of arguments: expected %d, got %d", gvType.NumIn(), len(args)))
+		panic(fmt.Errorf("cannot apply non-function %s", gvType))
 	}
+	return goVal.Call(goFuncOf(gvType).args(args))
+}
+
+// goFunc holds the argument converters of a Go function type, which
+// are derived once per type rather than on every call.
+type goFunc struct {
+	numIn    int
+	variadic bool
+	// convs holds a converter per parameter; the last converts to the
+	// element type of a variadic parameter.
+	convs []argConverter
+}
+
+// argConverter converts an argument to a parameter type, as
+// coerceGoValue does.
+type argConverter func(v interface{}) (reflect.Value, error)
 
-	var goArgs []reflect.Value
-	for i := 0; i < len(args); i++ {
-		if i >= gvType.NumIn() && !gvType.IsVariadic() {
-			panic(fmt.Sprintf("too many arguments: expected %d, got %d", gvType.NumIn(), len(args)))
+var goFuncs sync.Map // reflect.Type -> *goFunc
+
+func goFuncOf(typ reflect.Type) *goFunc {
+	if gf, ok := goFuncs.Load(typ); ok {
+		return gf.(*goFunc)
+	}
+	gf := &goFunc{
+		numIn:    typ.NumIn(),
+		variadic: typ.IsVariadic(),
+		convs:    make([]argConverter, typ.NumIn()),
+	}
+	for i := range gf.convs {
+		paramType := typ.In(i)
+		if gf.variadic && i == gf.numIn-1 {
+			paramType = paramType.Elem()
 		}
+		gf.convs[i] = converterTo(paramType)
+	}
+	actual, _ := goFuncs.LoadOrStore(typ, gf)
+	return actual.(*goFunc)
+}
 
-		var targetType reflect.Type
-		if i < gvType.NumIn()-1 || !gvType.IsVariadic() {
-			targetType = gvType.In(i)
-		} else {
-			targetType = gvType.In(gvType.NumIn() - 1).Elem()
+func converterTo(typ reflect.Type) argConverter {
+	if typ.Kind() == reflect.Interface && typ.NumMethod() == 0 {
+		return func(v interface{}) (reflect.Value, error) {
+			if v == nil {
+				return reflect.Zero(typ), nil
+			}
+			return reflect.ValueOf(v), nil
 		}
+	}
+	return func(v interface{}) (reflect.Value, error) {
+		if v != nil && reflect.TypeOf(v) == typ {
+			return reflect.ValueOf(v), nil
+		}
+		return coerceGoValue(typ, v)
+	}
+}
 
-		argGoVal, err := coerceGoValue(targetType, args[i])
+// args converts args to the parameter types of the function. It
+// panics if there are too few or too many, or one can't be converted.
+func (gf *goFunc) args(args []interface{}) []reflect.Value {
+	if gf.numIn != len(args) && !gf.variadic {
+		panic(fmt.Errorf("wrong number of arguments: expected %d, got %d", gf.numIn, len(args)))
+	}
+
+	goArgs := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		conv := gf.convs[len(gf.convs)-1]
+		if i < len(gf.convs) {
+			conv = gf.convs[i]
+		}
+		argGoVal, err := conv(arg)
 		if err != nil {
 			panic(fmt.Errorf("argument %d: %s", i, err))
 		}
-		goArgs = append(goArgs, argGoVal)
+		goArgs[i] = argGoVal
 	}
+	return goArgs
+}
 
-	reflectRes := goVal.Call(goArgs)
+// CoerceArg coerces v to the type of the i'th parameter of a Go
+// function, as Apply does for the arguments of a call. It panics if v
+// can't be coerced.
+func CoerceArg[T any](i int, v interface{}) T {
+	if t, ok := v.(T); ok {
+		return t
+	}
+	var t T
+	tv := reflect.ValueOf(&t).Elem()
+	argGoVal, err := coerceGoValue(tv.Type(), v)
+	if err != nil {
+		panic(fmt.Errorf("argument %d: %s", i, err))
+	}
+	tv.Set(argGoVal)
+	return t
+}
+
+func packResults(reflectRes []reflect.Value) interface{} {
 	res := make([]interface{}, len(reflectRes))
 	for i, val := range reflectRes {
 		res[i] = val.Interface()
@@ -125,10 +229,10 @@ func coerceGoValue(targetType reflect.Type, val interface{}) (reflect.Value, err
 			val = []byte(val.(string))
 		}
 
-		if iseq, ok := val.(ISeq); ok {
-			var slc []interface{}
-			for iseq = Seq(iseq); iseq != nil; iseq = iseq.Next() {
-				slc = append(slc, iseq.First())
+		if seqable, ok := val.(Seqable); ok {
+			slc := []interface{}{}
+			for seq := Seq(seqable); seq != nil; seq = seq.Next() {
+				slc = append(slc, seq.First())
 			}
 			val = slc
 		}
@@ -156,6 +260,11 @@ func coerceGoValue(targetType reflect.Type, val interface{}) (reflect.Value, err
 				return val, nil
 			}
 		}
+	case reflect.Interface:
+		if canImplement(targetType, val) {
+			return reflect.ValueOf(Implement(targetType, val)), nil
+		}
+		fallthrough
 	default:
 		iseqType := reflect.TypeOf((*ISeq)(nil)).Elem()
 		if targetType == iseqType {
@@ -182,6 +291,13 @@ func isNilableKind(k reflect.Kind) bool {
 	return false
 }
 
+// reflectFuncFromIFn returns the implementation of a Go function of
+// type targetType that calls applyer. A function with several results
+// takes them from a vector of as many elements as it has results, if
+// they convert to their types. Any other value, including a vector
+// of another length or one that is the first result, as for a
+// ([]string, error) function, supplies the first result, the rest,
+// typically an error, being zero. nil yields zero results.
 func reflectFuncFromIFn(targetType reflect.Type, applyer IFn) func(args []reflect.Value) []reflect.Value {
 	return func(args []reflect.Value) []reflect.Value {
 		var glojureArgs []interface{}
@@ -206,29 +322,34 @@ func reflectFuncFromIFn(targetType reflect.Type, applyer IFn) func(args []reflec
 			return nil
 		}
 
-		ret := make([]reflect.Value, targetType.NumOut())
-		if targetType.NumOut() == 1 {
-			// convert return value to expected type of target type
-			coerced, err := coerceGoValue(targetType.Out(0), res)
-			if err != nil {
-				panic(err)
-			}
-			ret[0] = coerced
-		} else {
-			i := 0
-			for s := Seq(res); s != nil && i < len(ret); s = s.Next() {
-				coerced, err := coerceGoValue(targetType.Out(i), s.First())
-				if err != nil {
-					panic(err)
-				}
-				ret[i] = coerced
-				i++
-			}
-			for ; i < len(ret); i++ {
-				ret[i] = reflect.Zero(targetType.Out(i))
+		if vec, ok := res.(IPersistentVector); ok && targetType.NumOut() > 1 && vec.Count() == targetType.NumOut() {
+			if ret, ok := spreadResults(targetType, vec); ok {
+				return ret
 			}
 		}
-
+		ret := make([]reflect.Value, targetType.NumOut())
+		coerced, err := coerceGoValue(targetType.Out(0), res)
+		if err != nil {
+			panic(err)
+		}
+		ret[0] = coerced
+		for i := 1; i < len(ret); i++ {
+			ret[i] = reflect.Zero(targetType.Out(i))
+		}
 		return ret
 	}
 }
+
+// spreadResults converts the elements of vec to the results of a
+// function of type fnType, and reports whether they all convert.
+func spreadResults(fnType reflect.Type, vec IPersistentVector) ([]reflect.Value, bool) {
+	ret := make([]reflect.Value, fnType.NumOut())
+	for i := range ret {
+		coerced, err := coerceGoValue(fnType.Out(i), vec.Nth(i))
+		if err != nil {
+			return nil, false
+		}
+		ret[i] = coerced
+	}
+	return ret, true
+}
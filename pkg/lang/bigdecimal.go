@@ -44,6 +44,11 @@ func (n *BigDecimal) ToBigInteger() *big.Int {
 	return res
 }
 
+// ToBigFloat returns a copy of the value as a big.Float.
+func (n *BigDecimal) ToBigFloat() *big.Float {
+	return new(big.Float).Copy(n.val)
+}
+
 func (n *BigDecimal) String() string {
 	return n.val.String()
 }
@@ -69,6 +69,9 @@ var (
 		"set-map-index": GoSetMapIndex, // setmapindex(m, key, val) -> m[key] = val
 		"send":          GoSend,        // send(ch, val) -> ch <- val
 		"recv":          GoRecv,        // recv(ch) -> val, ok <- ch
+
+		// Interface adapters
+		"implement": Implement, // implement(T, {:Method fn ...}) -> T
 	}
 )
 
@@ -112,9 +115,9 @@ func GoMake(typ reflect.Type, args ...interface{}) interface{} {
 		case 0:
 			return reflect.MakeSlice(typ, 0, 0).Interface()
 		case 1:
-			return reflect.MakeSlice(typ, MustAsInt(args[0]), MustAsInt(args[0])).Interface()
+			return reflect.MakeSlice(typ, arraySize(args[0]), arraySize(args[0])).Interface()
 		case 2:
-			return reflect.MakeSlice(typ, MustAsInt(args[0]), MustAsInt(args[1])).Interface()
+			return reflect.MakeSlice(typ, MustAsInt(args[0]), arraySize(args[1])).Interface()
 		default:
 			panic(fmt.Errorf("make: invalid argument count %d", len(args)))
 		}
@@ -122,7 +125,7 @@ func GoMake(typ reflect.Type, args ...interface{}) interface{} {
 		if len(args) == 0 {
 			return reflect.MakeMap(typ).Interface()
 		} else if len(args) == 1 {
-			return reflect.MakeMapWithSize(typ, MustAsInt(args[0])).Interface()
+			return reflect.MakeMapWithSize(typ, arraySize(args[0])).Interface()
 		} else {
 			panic(fmt.Errorf("make: invalid argument count %d", len(args)))
 		}
@@ -130,7 +133,7 @@ func GoMake(typ reflect.Type, args ...interface{}) interface{} {
 		if len(args) == 0 {
 			return reflect.MakeChan(typ, 0).Interface()
 		} else if len(args) == 1 {
-			return reflect.MakeChan(typ, MustAsInt(args[0])).Interface()
+			return reflect.MakeChan(typ, arraySize(args[0])).Interface()
 		} else {
 			panic(fmt.Errorf("make: invalid argument count %d", len(args)))
 		}
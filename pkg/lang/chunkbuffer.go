@@ -12,6 +12,7 @@ var (
 )
 
 func NewChunkBuffer(capacity int) *ChunkBuffer {
+	checkCollectionSize(capacity)
 	return &ChunkBuffer{
 		buffer: make([]interface{}, capacity),
 		end:    0,
@@ -0,0 +1,93 @@
+package lang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Compare returns a negative number, zero or a positive number when x
+// is less than, equal to or greater than y, as compare does. nil is
+// less than any other value; numbers compare numerically; strings
+// and characters lexically; symbols and keywords by namespace, with
+// none first, and then by name; false is less than true; vectors
+// compare by length and then element by element; and other values
+// by their Compare method if they implement Comparer. Compare panics
+// if x and y are not comparable.
+func Compare(x, y any) int {
+	switch {
+	case IsNil(x):
+		if IsNil(y) {
+			return 0
+		}
+		return -1
+	case IsNil(y):
+		return 1
+	}
+	if IsNumber(x) && IsNumber(y) {
+		switch {
+		case Numbers.Lt(x, y):
+			return -1
+		case Numbers.Equiv(x, y):
+			return 0
+		}
+		return 1
+	}
+	switch x := x.(type) {
+	case string:
+		if y, ok := y.(string); ok {
+			return strings.Compare(x, y)
+		}
+	case Char:
+		if y, ok := y.(Char); ok {
+			return int(x) - int(y)
+		}
+	case bool:
+		if y, ok := y.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case x:
+				return 1
+			}
+			return -1
+		}
+	case *Symbol:
+		if y, ok := y.(*Symbol); ok {
+			return compareNames(x.Namespace(), x.Name(), y.Namespace(), y.Name())
+		}
+	case Keyword:
+		if y, ok := y.(Keyword); ok {
+			return compareNames(x.Namespace(), x.Name(), y.Namespace(), y.Name())
+		}
+	case IPersistentVector:
+		if y, ok := y.(IPersistentVector); ok {
+			if x.Count() != y.Count() {
+				return x.Count() - y.Count()
+			}
+			for i := 0; i < x.Count(); i++ {
+				if c := Compare(x.Nth(i), y.Nth(i)); c != 0 {
+					return c
+				}
+			}
+			return 0
+		}
+	case Comparer:
+		return x.Compare(y)
+	}
+	panic(fmt.Errorf("cannot compare %T to %T", x, y))
+}
+
+// compareNames compares the namespaces and names of two symbols or
+// keywords.
+func compareNames(ns1, name1, ns2, name2 string) int {
+	if ns1 != ns2 {
+		switch {
+		case ns1 == "":
+			return -1
+		case ns2 == "":
+			return 1
+		}
+		return strings.Compare(ns1, ns2)
+	}
+	return strings.Compare(name1, name2)
+}
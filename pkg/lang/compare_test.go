@@ -0,0 +1,91 @@
+package lang
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	// each pair is in increasing order.
+	less := [][2]any{
+		{nil, 0},
+		{1, 2},
+		{1, 2.5},
+		{"a", "b"},
+		{Char('a'), Char('b')},
+		{false, true},
+		{NewKeyword("a"), NewKeyword("b")},
+		{NewKeyword("a"), NewKeyword("a/b")},
+		{NewSymbol("a/b"), NewSymbol("b/a")},
+		{NewVector(1, 2), NewVector(1, 3)},
+		{NewVector(9), NewVector(1, 2)},
+	}
+	for _, pair := range less {
+		if c := Compare(pair[0], pair[1]); c >= 0 {
+			t.Errorf("Compare(%v, %v) = %d, expected a negative number", pair[0], pair[1], c)
+		}
+		if c := Compare(pair[1], pair[0]); c <= 0 {
+			t.Errorf("Compare(%v, %v) = %d, expected a positive number", pair[1], pair[0], c)
+		}
+	}
+
+	equal := [][2]any{
+		{nil, nil},
+		{1, 1.0},
+		{"a", "a"},
+		{NewKeyword("a/b"), NewKeyword("a/b")},
+		{NewVector(1, NewVector(2)), NewVector(1, NewVector(2))},
+	}
+	for _, pair := range equal {
+		if c := Compare(pair[0], pair[1]); c != 0 {
+			t.Errorf("Compare(%v, %v) = %d, expected 0", pair[0], pair[1], c)
+		}
+	}
+
+	for _, pair := range [][2]any{{1, "a"}, {NewKeyword("a"), NewSymbol("a")}, {NewMap(), NewMap()}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected Compare(%v, %v) to panic", pair[0], pair[1])
+				}
+			}()
+			Compare(pair[0], pair[1])
+		}()
+	}
+}
+
+func TestSortSlice(t *testing.T) {
+	tests := []struct {
+		name string
+		comp IFn
+		want string
+	}{
+		{"comparator", IFnFunc(func(args ...any) any { return Compare(args[0], args[1]) }), "[1 2 2 3]"},
+		{"predicate", IFnFunc(func(args ...any) any { return Compare(args[0], args[1]) > 0 }), "[3 2 2 1]"},
+	}
+	for _, test := range tests {
+		s := []any{2, 3, 1, 2}
+		SortSlice(s, test.comp)
+		if got := fmt.Sprint(s); got != test.want {
+			t.Errorf("%s: expected %s, got %s", test.name, test.want, got)
+		}
+	}
+
+	// the sort is stable.
+	s := []any{NewVector(2, "c"), NewVector(1, "b"), NewVector(1, "a")}
+	SortSlice(s, IFnFunc(func(args ...any) any {
+		return Compare(args[0].(IPersistentVector).Nth(0), args[1].(IPersistentVector).Nth(0))
+	}))
+	if got := PrintString(NewVector(s...)); got != `[[1 "b"] [1 "a"] [2 "c"]]` {
+		t.Errorf("expected the elements with equal keys to keep their order, got %s", got)
+	}
+}
+
+func TestToSliceSeqable(t *testing.T) {
+	if got := fmt.Sprint(ToSlice(NewVector(1, 2, 3))); got != "[1 2 3]" {
+		t.Errorf("expected the elements of the vector, got %s", got)
+	}
+	if got := ToSlice(NewMap()); len(got) != 0 {
+		t.Errorf("expected no elements of an empty map, got %v", got)
+	}
+}
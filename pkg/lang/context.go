@@ -0,0 +1,135 @@
+package lang
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// VarContext is *context*, the context of the current evaluation.
+// Evaluation checks it in loops, recur, fn invocation and lazy seq
+// realization, and stops with a *CanceledError once it is done. Go
+// functions that take a context can be passed *context* so that they
+// stop with it.
+//
+// It is interned in init, as CheckCanceled refers to it.
+var VarContext *Var
+
+func init() {
+	VarContext = InternVarReplaceRoot(NSCore, NewSymbol("*context*"), context.Background()).SetDynamic()
+}
+
+// cancelableContexts counts the contexts that can be canceled bound to
+// *context*, by SetRootContext and by the binding frames of goroutines,
+// so that CheckCanceled is an atomic load when there are none. Frames
+// are counted as they are pushed, popped and installed with
+// ResetThreadBindingFrame, as by binding and PushContext.
+var (
+	cancelableContexts atomic.Int32
+	rootCancelable     atomic.Bool
+)
+
+// SetRootContext sets the root binding of *context* to ctx.
+func SetRootContext(ctx context.Context) {
+	VarContext.BindRoot(ctx)
+	cancelable := ctx.Done() != nil
+	if rootCancelable.Swap(cancelable) != cancelable {
+		if cancelable {
+			cancelableContexts.Add(1)
+		} else {
+			cancelableContexts.Add(-1)
+		}
+	}
+}
+
+// cancelableBindings returns 1 if store binds *context* to a context
+// that can be canceled, and 0 otherwise.
+func cancelableBindings(store varBindings) int32 {
+	b, ok := store[VarContext]
+	if !ok {
+		return 0
+	}
+	return cancelable(b.val)
+}
+
+// cancelable returns 1 if val is a context that can be canceled, and
+// 0 otherwise.
+func cancelable(val interface{}) int32 {
+	if ctx, ok := val.(context.Context); ok && ctx.Done() != nil {
+		return 1
+	}
+	return 0
+}
+
+// PushContext binds *context* to ctx in the current goroutine until
+// the matching PopContext.
+func PushContext(ctx context.Context) {
+	PushThreadBindings(NewMap(VarContext, ctx))
+}
+
+// PopContext pops the binding of *context* pushed by PushContext.
+func PopContext() {
+	PopThreadBindings()
+}
+
+// CheckCanceled returns a *CanceledError if the context bound to
+// *context* is done.
+func CheckCanceled() error {
+	if cancelableContexts.Load() == 0 {
+		return nil
+	}
+	ctx, ok := VarContext.Deref().(context.Context)
+	if !ok {
+		return nil
+	}
+	if sc, ok := ctx.(*stepLimitContext); ok {
+		sc.step()
+	}
+	select {
+	case <-ctx.Done():
+		return NewCanceledError(ctx.Err())
+	default:
+		return nil
+	}
+}
+
+// stepLimitContext is a context that is canceled once it has been
+// checked a number of times.
+type stepLimitContext struct {
+	context.Context
+	cancel context.CancelFunc
+	steps  atomic.Int64
+	err    atomic.Pointer[error]
+}
+
+// WithStepLimit returns a context derived from parent that is done
+// once evaluation under it has taken n steps, where a step is a fn
+// invocation, a loop iteration or the realization of a lazy seq. Its
+// error is then a *ResourceLimitError.
+func WithStepLimit(parent context.Context, n int64) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+	sc := &stepLimitContext{Context: ctx, cancel: cancel}
+	sc.steps.Store(n)
+	return sc
+}
+
+// HasStepLimit reports whether the context bound to *context* was
+// made by WithStepLimit.
+func HasStepLimit() bool {
+	_, ok := VarContext.Deref().(*stepLimitContext)
+	return ok
+}
+
+func (c *stepLimitContext) step() {
+	if c.steps.Add(-1) == -1 {
+		err := NewResourceLimitError("step limit exceeded")
+		c.err.Store(&err)
+		c.cancel()
+	}
+}
+
+func (c *stepLimitContext) Err() error {
+	if err := c.err.Load(); err != nil {
+		return *err
+	}
+	return c.Context.Err()
+}
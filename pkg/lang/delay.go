@@ -0,0 +1,60 @@
+package lang
+
+import "sync"
+
+// Delay is the value of a fn, computed the first time it is
+// dereferenced. If the fn panics, dereferencing the Delay panics with
+// the same value each time.
+type Delay struct {
+	once  sync.Once
+	fn    IFn
+	val   interface{}
+	panic interface{}
+	done  bool
+	mtx   sync.RWMutex
+}
+
+var (
+	_ IDeref   = (*Delay)(nil)
+	_ IPending = (*Delay)(nil)
+)
+
+// NewDelay returns a Delay of fn, a fn of no arguments.
+func NewDelay(fn IFn) *Delay {
+	return &Delay{fn: fn}
+}
+
+func (d *Delay) Deref() interface{} {
+	d.once.Do(func() {
+		defer func() {
+			r := recover()
+			d.mtx.Lock()
+			d.panic, d.done, d.fn = r, true, nil
+			d.mtx.Unlock()
+		}()
+		val := d.fn.Invoke()
+		d.mtx.Lock()
+		d.val = val
+		d.mtx.Unlock()
+	})
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+	if d.panic != nil {
+		panic(d.panic)
+	}
+	return d.val
+}
+
+func (d *Delay) IsRealized() bool {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+	return d.done
+}
+
+// ForceDelay returns the value of x if it is a Delay, or else x.
+func ForceDelay(x interface{}) interface{} {
+	if d, ok := x.(*Delay); ok {
+		return d.Deref()
+	}
+	return x
+}
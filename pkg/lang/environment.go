@@ -67,6 +67,11 @@ type (
 		Context() context.Context
 
 		Errorf(form interface{}, format string, args ...interface{}) error
+
+		// CheckExport returns an error if code evaluated in the
+		// environment may not refer to the Go package export, named as
+		// "pkg.Name".
+		CheckExport(export string) error
 	}
 
 	// RecurError is an error returned by a recur form.
@@ -100,6 +105,9 @@ func Import(args ...interface{}) {
 	}
 
 	export := args[0].(string)
+	if err := GlobalEnv.CheckExport(export); err != nil {
+		panic(err)
+	}
 	v, ok := pkgmap.Get(export)
 	if !ok {
 		// TODO: panic
@@ -25,6 +25,25 @@ type (
 		msg string
 	}
 
+	// IllegalAccessError is raised when code refers to something a
+	// sandbox doesn't allow.
+	IllegalAccessError struct {
+		msg string
+	}
+
+	// ResourceLimitError is raised when evaluation exceeds a limit on
+	// the resources it can use, such as the size of collections.
+	ResourceLimitError struct {
+		msg string
+	}
+
+	// CanceledError is raised when evaluation is interrupted because
+	// the context bound to *context* is done. It wraps the context's
+	// error.
+	CanceledError struct {
+		err error
+	}
+
 	// Stacker is an interface for retrieving stack traces.
 	Stacker interface {
 		Stack() []StackFrame
@@ -36,11 +55,23 @@ type (
 		stack []StackFrame
 	}
 
+	// StackFrame is an entry of a Glojure stack trace: a form being
+	// evaluated, and the fn whose body it is in.
 	StackFrame struct {
+		// Namespace is the name of the namespace the form was compiled
+		// in.
+		Namespace string
+		// FunctionName is the name of the fn, qualified by those of the
+		// fns it is defined in as outer/inner, or empty if the form is
+		// a top-level form.
 		FunctionName string
-		Filename     string
-		Line         int
-		Column       int
+		// Arity is the number of fixed parameters of the method of the
+		// fn, which also takes rest parameters if Variadic is set.
+		Arity    int
+		Variadic bool
+		Filename string
+		Line     int
+		Column   int
 	}
 )
 
@@ -111,6 +142,49 @@ func (e *IllegalStateError) Is(other error) bool {
 	return ok
 }
 
+func NewIllegalAccessError(msg string) error {
+	return &IllegalAccessError{msg: msg}
+}
+
+func (e *IllegalAccessError) Error() string {
+	return e.msg
+}
+
+func (e *IllegalAccessError) Is(other error) bool {
+	_, ok := other.(*IllegalAccessError)
+	return ok
+}
+
+func NewResourceLimitError(msg string) error {
+	return &ResourceLimitError{msg: msg}
+}
+
+func (e *ResourceLimitError) Error() string {
+	return e.msg
+}
+
+func (e *ResourceLimitError) Is(other error) bool {
+	_, ok := other.(*ResourceLimitError)
+	return ok
+}
+
+func NewCanceledError(err error) error {
+	return &CanceledError{err: err}
+}
+
+func (e *CanceledError) Error() string {
+	return "evaluation canceled: " + e.err.Error()
+}
+
+func (e *CanceledError) Is(other error) bool {
+	_, ok := other.(*CanceledError)
+	return ok
+}
+
+func (e *CanceledError) Unwrap() error {
+	return e.err
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // TODO: Revisit
 
@@ -128,22 +202,48 @@ func (e *Error) Error() string {
 	builder.WriteString(e.err.Error())
 	builder.WriteString("\nStack trace (most recent call first):\n")
 	for _, frame := range e.stack {
-		builder.WriteString(frame.FunctionName)
-		builder.WriteString("\n\t")
-		filename := frame.Filename
-		line := strconv.Itoa(frame.Line)
-		column := strconv.Itoa(frame.Column)
-		if filename == "" {
-			filename = "<unknown>"
-			line = "?"
-			column = "?"
-		}
-		builder.WriteString(fmt.Sprintf("%s:%s:%s", filename, line, column))
+		builder.WriteString(frame.String())
 		builder.WriteRune('\n')
 	}
 	return builder.String()
 }
 
+// Name returns the namespace-qualified name of the fn of the frame,
+// with its arity, as "ns/outer/inner [1]", or the namespace name for
+// a top-level form. The arity of a variadic method is suffixed by
+// "+".
+func (f StackFrame) Name() string {
+	if f.FunctionName == "" {
+		return f.Namespace
+	}
+	variadic := ""
+	if f.Variadic {
+		variadic = "+"
+	}
+	return fmt.Sprintf("%s/%s [%d%s]", f.Namespace, f.FunctionName, f.Arity, variadic)
+}
+
+// Position returns the file:line:column position of the form of the
+// frame, with "?" for unknown parts.
+func (f StackFrame) Position() string {
+	filename := f.Filename
+	if filename == "" {
+		filename = "?"
+	}
+	line, column := "?", "?"
+	if f.Line > 0 {
+		line = strconv.Itoa(f.Line)
+	}
+	if f.Column > 0 {
+		column = strconv.Itoa(f.Column)
+	}
+	return filename + ":" + line + ":" + column
+}
+
+func (f StackFrame) String() string {
+	return f.Name() + " (" + f.Position() + ")"
+}
+
 // Stack returns the stack trace.
 func (e *Error) Stack() []StackFrame {
 	return e.stack
@@ -13,5 +13,5 @@ func (f IFnFunc) Invoke(args ...interface{}) interface{} {
 }
 
 func (f IFnFunc) ApplyTo(args ISeq) interface{} {
-	return f(seqToSlice(args))
+	return f(seqToSlice(args)...)
 }
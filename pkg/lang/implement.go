@@ -0,0 +1,157 @@
+package lang
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unicode"
+)
+
+type (
+	// Methods maps Go method names to the functions that implement
+	// them. It is passed to interface adapter constructors.
+	Methods map[string]IFn
+
+	// AdapterFunc constructs a value implementing a Go interface type
+	// whose methods dispatch to the given functions.
+	AdapterFunc func(Methods) interface{}
+)
+
+var (
+	interfaceAdapters   = map[reflect.Type]AdapterFunc{}
+	interfaceAdaptersMu sync.RWMutex
+)
+
+// RegisterInterfaceAdapter registers the constructor of adapters for
+// the interface type typ. Adapters for the interfaces in packages
+// imported with gen-import-interop are registered by the generated
+// code.
+func RegisterInterfaceAdapter(typ reflect.Type, newAdapter AdapterFunc) {
+	interfaceAdaptersMu.Lock()
+	defer interfaceAdaptersMu.Unlock()
+
+	interfaceAdapters[typ] = newAdapter
+}
+
+func lookupInterfaceAdapter(typ reflect.Type) (AdapterFunc, bool) {
+	interfaceAdaptersMu.RLock()
+	defer interfaceAdaptersMu.RUnlock()
+
+	newAdapter, ok := interfaceAdapters[typ]
+	return newAdapter, ok
+}
+
+// Bind sets the func pointed to by fnPtr to call the method named
+// name. Arguments and results are converted according to the func's
+// type. If m has no such method, calling the func panics.
+func (m Methods) Bind(fnPtr interface{}, name string) {
+	fnVal := reflect.ValueOf(fnPtr).Elem()
+	fnType := fnVal.Type()
+
+	fn, ok := m[name]
+	if !ok {
+		fnVal.Set(reflect.MakeFunc(fnType, func([]reflect.Value) []reflect.Value {
+			panic(NewIllegalStateError(fmt.Sprintf("method %s not implemented", name)))
+		}))
+		return
+	}
+	fnVal.Set(reflect.MakeFunc(fnType, reflectFuncFromIFn(fnType, fn)))
+}
+
+// Implement returns a value implementing the Go interface type typ.
+// impl is either a map from method names to functions or, for
+// interfaces with a single method, a function. Method names may be
+// keywords, symbols or strings; a lowercase first letter is
+// capitalized, as in interop calls.
+//
+// Values that already implement typ are returned as is. Interfaces
+// other than the empty interface require an adapter; see
+// RegisterInterfaceAdapter.
+func Implement(typ reflect.Type, impl interface{}) interface{} {
+	if typ.Kind() != reflect.Interface {
+		panic(NewIllegalArgumentError(fmt.Sprintf("cannot implement non-interface type %s", typ)))
+	}
+	if impl != nil && reflect.TypeOf(impl).Implements(typ) {
+		return impl
+	}
+
+	methods, err := implMethods(typ, impl)
+	if err != nil {
+		panic(err)
+	}
+
+	newAdapter, ok := lookupInterfaceAdapter(typ)
+	if !ok {
+		panic(NewIllegalArgumentError(fmt.Sprintf("no adapter for interface %s; generate one with gen-import-interop", typ)))
+	}
+	return newAdapter(methods)
+}
+
+func implMethods(typ reflect.Type, impl interface{}) (Methods, error) {
+	methods := Methods{}
+	switch impl := impl.(type) {
+	case IPersistentMap:
+		for seq := Seq(impl); seq != nil; seq = seq.Next() {
+			entry := seq.First().(IMapEntry)
+			name, err := methodName(entry.Key())
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := typ.MethodByName(name); !ok {
+				return nil, NewIllegalArgumentError(fmt.Sprintf("%s has no method %s", typ, name))
+			}
+			fn, ok := entry.Val().(IFn)
+			if !ok {
+				return nil, NewIllegalArgumentError(fmt.Sprintf("implementation of %s.%s is not a function: %T", typ, name, entry.Val()))
+			}
+			methods[name] = fn
+		}
+	case IFn:
+		if typ.NumMethod() != 1 {
+			return nil, NewIllegalArgumentError(fmt.Sprintf("a function can only implement single-method interfaces, %s has %d methods", typ, typ.NumMethod()))
+		}
+		methods[typ.Method(0).Name] = impl
+	default:
+		return nil, NewIllegalArgumentError(fmt.Sprintf("cannot implement %s with %T", typ, impl))
+	}
+	return methods, nil
+}
+
+func methodName(k interface{}) (string, error) {
+	var name string
+	switch k := k.(type) {
+	case Keyword:
+		name = k.Name()
+	case *Symbol:
+		name = k.Name()
+	case string:
+		name = k
+	default:
+		return "", NewIllegalArgumentError(fmt.Sprintf("method name must be a keyword, symbol or string, got %T", k))
+	}
+	if name == "" {
+		return "", NewIllegalArgumentError("empty method name")
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes), nil
+}
+
+// canImplement reports whether val can be adapted to the interface
+// type typ with Implement.
+func canImplement(typ reflect.Type, val interface{}) bool {
+	if typ.Kind() != reflect.Interface || typ.NumMethod() == 0 {
+		return false
+	}
+	switch val.(type) {
+	case IPersistentMap:
+	case IFn:
+		if typ.NumMethod() != 1 {
+			return false
+		}
+	default:
+		return false
+	}
+	_, ok := lookupInterfaceAdapter(typ)
+	return ok
+}
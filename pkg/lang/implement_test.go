@@ -0,0 +1,126 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testGreeter interface {
+	Greet(name string) string
+	Count() (int, error)
+}
+
+type testGreeterAdapter struct {
+	_Greet func(string) string
+	_Count func() (int, error)
+}
+
+func (a *testGreeterAdapter) Greet(name string) string { return a._Greet(name) }
+func (a *testGreeterAdapter) Count() (int, error)      { return a._Count() }
+
+var testGreeterType = reflect.TypeOf((*testGreeter)(nil)).Elem()
+
+func init() {
+	RegisterInterfaceAdapter(testGreeterType, func(m Methods) interface{} {
+		a := &testGreeterAdapter{}
+		m.Bind(&a._Greet, "Greet")
+		m.Bind(&a._Count, "Count")
+		return a
+	})
+}
+
+func TestImplement(t *testing.T) {
+	g := Implement(testGreeterType, NewMap(
+		NewKeyword("greet"), IFnFunc(func(args ...interface{}) interface{} {
+			return "hello " + args[0].(string)
+		}),
+		NewSymbol("Count"), IFnFunc(func(args ...interface{}) interface{} {
+			return 3
+		}),
+	)).(testGreeter)
+
+	if got := g.Greet("glojure"); got != "hello glojure" {
+		t.Errorf("Greet() = %q, want %q", got, "hello glojure")
+	}
+	if n, err := g.Count(); n != 3 || err != nil {
+		t.Errorf("Count() = %v, %v, want 3, nil", n, err)
+	}
+}
+
+func TestImplementMissingMethod(t *testing.T) {
+	g := Implement(testGreeterType, NewMap()).(testGreeter)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic calling unimplemented method")
+		}
+	}()
+	g.Greet("glojure")
+}
+
+func TestImplementInvalid(t *testing.T) {
+	tests := map[string]interface{}{
+		"unknown method": NewMap(NewKeyword("Wave"), IFnFunc(func(args ...interface{}) interface{} { return nil })),
+		"multi-method":   IFnFunc(func(args ...interface{}) interface{} { return nil }),
+		"not a function": NewMap(NewKeyword("Greet"), 1),
+	}
+	for name, impl := range tests {
+		impl := impl
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected panic implementing %s with %v", testGreeterType, impl)
+				}
+			}()
+			Implement(testGreeterType, impl)
+		})
+	}
+}
+
+func TestCoerceSeq(t *testing.T) {
+	// collections that can't implement an interface are converted as
+	// before, as vectors are to seqs.
+	iseqType := reflect.TypeOf((*ISeq)(nil)).Elem()
+	for _, val := range []interface{}{NewVector(1, 2, 3), []interface{}{1, 2, 3}} {
+		got, err := coerceGoValue(iseqType, val)
+		if err != nil {
+			t.Fatalf("coerceGoValue(ISeq, %v): %v", val, err)
+		}
+		if seq := got.Interface().(ISeq); !Equiv(seq, NewList(1, 2, 3)) {
+			t.Errorf("coerceGoValue(ISeq, %v) = %v, want (1 2 3)", val, PrintString(seq))
+		}
+	}
+}
+
+func TestReflectFuncResults(t *testing.T) {
+	errBoom := errors.New("boom")
+	tests := []struct {
+		name string
+		fn   interface{}
+		res  interface{}
+		want string
+	}{
+		{"vector of the results", (func() (int, error))(nil), NewVector(3, errBoom), "[3 boom]"},
+		{"first result", (func() (int, error))(nil), 3, "[3 <nil>]"},
+		{"vector first result", (func() ([]string, error))(nil), NewVector("a", "b"), "[[a b] <nil>]"},
+		{"longer vector first result", (func() ([]string, error))(nil), NewVector("a", "b", "c"), "[[a b c] <nil>]"},
+		{"empty vector first result", (func() ([]string, error))(nil), NewVector(), "[[] <nil>]"},
+		{"nil", (func() (int, error))(nil), nil, "[0 <nil>]"},
+	}
+	for _, test := range tests {
+		fnType := reflect.TypeOf(test.fn)
+		res := test.res
+		fn := reflect.MakeFunc(fnType, reflectFuncFromIFn(fnType, IFnFunc(func(args ...interface{}) interface{} {
+			return res
+		})))
+		var got []interface{}
+		for _, v := range fn.Call(nil) {
+			got = append(got, v.Interface())
+		}
+		if s := fmt.Sprint(got); s != test.want {
+			t.Errorf("%s: expected %s, got %s", test.name, test.want, s)
+		}
+	}
+}
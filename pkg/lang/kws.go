@@ -121,4 +121,6 @@ var (
 	KWPrivate = NewKeyword("private")
 	KWDynamic = NewKeyword("dynamic")
 	KWNS      = NewKeyword("ns")
+
+	KWGoErrors = NewKeyword("go-errors")
 )
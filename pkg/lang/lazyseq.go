@@ -99,6 +99,9 @@ func (s *LazySeq) realize() interface{} {
 	defer s.realizeMtx.Unlock()
 
 	if s.fn != nil {
+		if err := CheckCanceled(); err != nil {
+			panic(err)
+		}
 		s.sv = s.fn()
 		s.fn = nil
 	}
@@ -0,0 +1,85 @@
+package lang
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+var (
+	// maxCollectionSize, if positive, is the largest number of
+	// elements of a collection.
+	maxCollectionSize atomic.Int64
+
+	// maxGoroutines, if positive, is the largest number of goroutines
+	// started with Go that can run at once. If negative, none can be
+	// started.
+	maxGoroutines     atomic.Int64
+	runningGoroutines atomic.Int64
+)
+
+// SetMaxCollectionSize limits the number of elements of vectors, maps,
+// sets, arrays and slices made with go/make. Growing a collection
+// beyond n elements panics with a *ResourceLimitError. If n is not
+// positive, collections are not limited.
+func SetMaxCollectionSize(n int) {
+	maxCollectionSize.Store(int64(n))
+}
+
+// checkCollectionSize panics with a *ResourceLimitError if a
+// collection of n elements exceeds the limit set with
+// SetMaxCollectionSize.
+func checkCollectionSize(n int) {
+	if max := maxCollectionSize.Load(); max > 0 && int64(n) > max {
+		panic(NewResourceLimitError(fmt.Sprintf("collection of %d elements exceeds the limit of %d", n, max)))
+	}
+}
+
+// assocUnchecked associates key with val in m, ignoring the
+// collection size limit. It is used for the maps of namespaces, which
+// grow with the code loaded rather than with the data it handles.
+func assocUnchecked(m IPersistentMap, key, val any) IPersistentMap {
+	if hm, ok := m.(*PersistentHashMap); ok {
+		return hm.assoc(key, val)
+	}
+	return m.Assoc(key, val).(IPersistentMap)
+}
+
+// arraySize returns n, an array size, as an int, checking it against
+// the collection size limit.
+func arraySize(n any) int {
+	size := MustAsInt(n)
+	checkCollectionSize(size)
+	return size
+}
+
+// SetMaxGoroutines limits the number of goroutines started with Go,
+// as by go/go and future, that can run at once. If n is negative, no
+// goroutines can be started, and if it is zero, they are not limited.
+func SetMaxGoroutines(n int) {
+	maxGoroutines.Store(int64(n))
+}
+
+// Go calls f in a new goroutine that conveys the binding of *context*
+// of the calling goroutine. It panics with a *ResourceLimitError if
+// the limit set with SetMaxGoroutines has been reached.
+func Go(f func()) {
+	max := maxGoroutines.Load()
+	if max < 0 {
+		panic(NewResourceLimitError("goroutines are not allowed"))
+	}
+	if n := runningGoroutines.Add(1); max > 0 && n > max {
+		runningGoroutines.Add(-1)
+		panic(NewResourceLimitError(fmt.Sprintf("more than %d goroutines", max)))
+	}
+
+	ctx, _ := VarContext.Deref().(context.Context)
+	go func() {
+		defer runningGoroutines.Add(-1)
+		if ctx != nil && ctx.Done() != nil {
+			PushContext(ctx)
+			defer PopContext()
+		}
+		f()
+	}()
+}
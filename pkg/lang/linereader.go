@@ -0,0 +1,45 @@
+package lang
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// ReadLine is a shim for clojure.core's use of Java's readLine()
+// method. It reads a line from r without its line terminator, and
+// returns nil at the end of input. Readers without a ReadString method
+// are read a byte at a time, so that no input past the line is
+// consumed.
+func ReadLine(r io.Reader) interface{} {
+	var line string
+	var err error
+	if sr, ok := r.(interface{ ReadString(byte) (string, error) }); ok {
+		line, err = sr.ReadString('\n')
+	} else {
+		var sb strings.Builder
+		buf := make([]byte, 1)
+		for {
+			var n int
+			n, err = r.Read(buf)
+			if n > 0 {
+				sb.WriteByte(buf[0])
+				if buf[0] == '\n' {
+					break
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+		line = sb.String()
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		panic(err)
+	}
+	if err != nil && line == "" {
+		return nil
+	}
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r")
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -18,6 +19,9 @@ type Namespace struct {
 	aliases  atomic.Value
 
 	meta IPersistentMap
+
+	usedVarsMtx sync.Mutex
+	usedVars    map[*Var]struct{}
 }
 
 var (
@@ -141,7 +145,7 @@ func (ns *Namespace) Intern(sym *Symbol) *Var {
 		if v == nil {
 			v = NewVar(ns, sym)
 		}
-		newMap := mb.val.(IPersistentMap).Assoc(sym, v)
+		newMap := assocUnchecked(mb.val.(IPersistentMap), sym, v)
 		ns.mappings.CompareAndSwap(mb, NewBox(newMap))
 		mb = ns.mappingsBox()
 	}
@@ -152,7 +156,7 @@ func (ns *Namespace) Intern(sym *Symbol) *Var {
 		v = NewVar(ns, sym)
 	}
 	if ns.checkReplacement(sym, o, v) {
-		for !ns.mappings.CompareAndSwap(mb, NewBox(mb.val.(IPersistentMap).Assoc(sym, v))) {
+		for !ns.mappings.CompareAndSwap(mb, NewBox(assocUnchecked(mb.val.(IPersistentMap), sym, v))) {
 			mb = ns.mappingsBox()
 		}
 		return v
@@ -236,7 +240,7 @@ func (ns *Namespace) AddAlias(alias *Symbol, ns2 *Namespace) {
 	}
 	ab := ns.aliasesBox()
 	for !ab.val.(IPersistentMap).ContainsKey(alias) {
-		newAliases := ab.val.(IPersistentMap).Assoc(alias, ns2)
+		newAliases := assocUnchecked(ab.val.(IPersistentMap), alias, ns2)
 		ns.aliases.CompareAndSwap(ab, NewBox(newAliases))
 		ab = ns.aliasesBox()
 	}
@@ -273,7 +277,7 @@ func (ns *Namespace) reference(sym *Symbol, v interface{}) interface{} {
 		if o != nil {
 			break
 		}
-		newMap := mb.val.(IPersistentMap).Assoc(sym, v)
+		newMap := assocUnchecked(mb.val.(IPersistentMap), sym, v)
 		ns.mappings.CompareAndSwap(mb, NewBox(newMap))
 		mb = ns.mappingsBox()
 	}
@@ -294,7 +298,7 @@ func (ns *Namespace) reference(sym *Symbol, v interface{}) interface{} {
 	}
 
 	if ns.checkReplacement(sym, o, v) {
-		for !ns.mappings.CompareAndSwap(mb, NewBox(mb.val.(IPersistentMap).Assoc(sym, v))) {
+		for !ns.mappings.CompareAndSwap(mb, NewBox(assocUnchecked(mb.val.(IPersistentMap), sym, v))) {
 			mb = ns.mappingsBox()
 		}
 		return v
@@ -303,6 +307,33 @@ func (ns *Namespace) reference(sym *Symbol, v interface{}) interface{} {
 	return o
 }
 
+// AddUsedVar records that code in this namespace refers to v.
+func (ns *Namespace) AddUsedVar(v *Var) {
+	ns.usedVarsMtx.Lock()
+	defer ns.usedVarsMtx.Unlock()
+
+	if ns.usedVars == nil {
+		ns.usedVars = map[*Var]struct{}{}
+	}
+	ns.usedVars[v] = struct{}{}
+}
+
+// UsedVars returns the vars that code analyzed in this namespace
+// refers to, including its own, sorted by name.
+func (ns *Namespace) UsedVars() []*Var {
+	ns.usedVarsMtx.Lock()
+	vars := make([]*Var, 0, len(ns.usedVars))
+	for v := range ns.usedVars {
+		vars = append(vars, v)
+	}
+	ns.usedVarsMtx.Unlock()
+
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].String() < vars[j].String()
+	})
+	return vars
+}
+
 func (ns *Namespace) Meta() IPersistentMap {
 	return ns.meta
 }
@@ -195,11 +195,11 @@ func (nm *NumberMethods) Floats(x any) []float32 {
 
 func (nm *NumberMethods) FloatArray(sizeOrSeq any) []float32 {
 	if IsNumber(sizeOrSeq) {
-		return make([]float32, MustAsInt(sizeOrSeq))
+		return make([]float32, arraySize(sizeOrSeq))
 	}
 	s := Seq(sizeOrSeq)
 	size := Count(s)
-	ret := make([]float32, size)
+	ret := make([]float32, arraySize(size))
 	for i := 0; i < len(ret) && s != nil; i, s = i+1, s.Next() {
 		ret[i] = float32(AsFloat64(s.First()))
 	}
@@ -207,7 +207,7 @@ func (nm *NumberMethods) FloatArray(sizeOrSeq any) []float32 {
 }
 
 func (nm *NumberMethods) FloatArrayInit(size int, init any) []float32 {
-	ret := make([]float32, size)
+	ret := make([]float32, arraySize(size))
 	if IsNumber(init) {
 		f := AsFloat64(init)
 		for i := 0; i < size; i++ {
@@ -228,11 +228,11 @@ func (nm *NumberMethods) Doubles(x any) []float64 {
 
 func (nm *NumberMethods) DoubleArray(sizeOrSeq any) []float64 {
 	if IsNumber(sizeOrSeq) {
-		return make([]float64, MustAsInt(sizeOrSeq))
+		return make([]float64, arraySize(sizeOrSeq))
 	}
 	s := Seq(sizeOrSeq)
 	size := Count(s)
-	ret := make([]float64, size)
+	ret := make([]float64, arraySize(size))
 	for i := 0; i < len(ret) && s != nil; i, s = i+1, s.Next() {
 		ret[i] = AsFloat64(s.First())
 	}
@@ -240,7 +240,7 @@ func (nm *NumberMethods) DoubleArray(sizeOrSeq any) []float64 {
 }
 
 func (nm *NumberMethods) DoubleArrayInit(size int, init any) []float64 {
-	ret := make([]float64, size)
+	ret := make([]float64, arraySize(size))
 	if IsNumber(init) {
 		f := AsFloat64(init)
 		for i := 0; i < size; i++ {
@@ -261,11 +261,11 @@ func (nm *NumberMethods) Ints(x any) []int {
 
 func (nm *NumberMethods) IntArray(sizeOrSeq any) []int {
 	if IsNumber(sizeOrSeq) {
-		return make([]int, MustAsInt(sizeOrSeq))
+		return make([]int, arraySize(sizeOrSeq))
 	}
 	s := Seq(sizeOrSeq)
 	size := Count(s)
-	ret := make([]int, size)
+	ret := make([]int, arraySize(size))
 	for i := 0; i < len(ret) && s != nil; i, s = i+1, s.Next() {
 		ret[i] = MustAsInt(s.First())
 	}
@@ -273,7 +273,7 @@ func (nm *NumberMethods) IntArray(sizeOrSeq any) []int {
 }
 
 func (nm *NumberMethods) IntArrayInit(size int, init any) []int {
-	ret := make([]int, size)
+	ret := make([]int, arraySize(size))
 	if IsNumber(init) {
 		n := MustAsInt(init)
 		for i := 0; i < size; i++ {
@@ -294,11 +294,11 @@ func (nm *NumberMethods) Chars(x any) []Char {
 
 func (nm *NumberMethods) CharArray(sizeOrSeq any) []Char {
 	if IsNumber(sizeOrSeq) {
-		return make([]Char, MustAsInt(sizeOrSeq))
+		return make([]Char, arraySize(sizeOrSeq))
 	}
 	s := Seq(sizeOrSeq)
 	size := Count(s)
-	ret := make([]Char, size)
+	ret := make([]Char, arraySize(size))
 	for i := 0; i < len(ret) && s != nil; i, s = i+1, s.Next() {
 		ret[i] = s.First().(Char)
 	}
@@ -306,7 +306,7 @@ func (nm *NumberMethods) CharArray(sizeOrSeq any) []Char {
 }
 
 func (nm *NumberMethods) CharArrayInit(size int, init any) []Char {
-	ret := make([]Char, size)
+	ret := make([]Char, arraySize(size))
 	if f, ok := init.(Char); ok {
 		for i := 0; i < size; i++ {
 			ret[i] = f
@@ -326,11 +326,11 @@ func (nm *NumberMethods) Bytes(x any) []byte {
 
 func (nm *NumberMethods) ByteArray(sizeOrSeq any) []byte {
 	if IsNumber(sizeOrSeq) {
-		return make([]byte, MustAsInt(sizeOrSeq))
+		return make([]byte, arraySize(sizeOrSeq))
 	}
 	s := Seq(sizeOrSeq)
 	size := Count(sizeOrSeq)
-	ret := make([]byte, size)
+	ret := make([]byte, arraySize(size))
 	for i := 0; i < size && s != nil; i, s = i+1, s.Next() {
 		ret[i] = AsByte(s.First())
 	}
@@ -338,7 +338,7 @@ func (nm *NumberMethods) ByteArray(sizeOrSeq any) []byte {
 }
 
 func (nm *NumberMethods) ByteArrayInit(size int, init any) []byte {
-	ret := make([]byte, size)
+	ret := make([]byte, arraySize(size))
 	if b, ok := init.(byte); ok {
 		for i := 0; i < size; i++ {
 			ret[i] = b
@@ -358,11 +358,11 @@ func (nm *NumberMethods) Shorts(x any) []int16 {
 
 func (nm *NumberMethods) ShortArray(sizeOrSeq any) []int16 {
 	if IsNumber(sizeOrSeq) {
-		return make([]int16, MustAsInt(sizeOrSeq))
+		return make([]int16, arraySize(sizeOrSeq))
 	}
 	s := Seq(sizeOrSeq)
 	size := Count(sizeOrSeq)
-	ret := make([]int16, size)
+	ret := make([]int16, arraySize(size))
 	for i := 0; i < size && s != nil; i, s = i+1, s.Next() {
 		ret[i] = int16(AsInt64(s.First()))
 	}
@@ -370,7 +370,7 @@ func (nm *NumberMethods) ShortArray(sizeOrSeq any) []int16 {
 }
 
 func (nm *NumberMethods) ShortArrayInit(size int, init any) []int16 {
-	ret := make([]int16, size)
+	ret := make([]int16, arraySize(size))
 	if b, ok := init.(int16); ok {
 		for i := 0; i < size; i++ {
 			ret[i] = b
@@ -390,11 +390,11 @@ func (nm *NumberMethods) Longs(x any) []int64 {
 
 func (nm *NumberMethods) LongArray(sizeOrSeq any) []int64 {
 	if IsNumber(sizeOrSeq) {
-		return make([]int64, MustAsInt(sizeOrSeq))
+		return make([]int64, arraySize(sizeOrSeq))
 	}
 	s := Seq(sizeOrSeq)
 	size := Count(sizeOrSeq)
-	ret := make([]int64, size)
+	ret := make([]int64, arraySize(size))
 	for i := 0; i < size && s != nil; i, s = i+1, s.Next() {
 		ret[i] = AsInt64(s.First())
 	}
@@ -402,7 +402,7 @@ func (nm *NumberMethods) LongArray(sizeOrSeq any) []int64 {
 }
 
 func (nm *NumberMethods) LongArrayInit(size int, init any) []int64 {
-	ret := make([]int64, size)
+	ret := make([]int64, arraySize(size))
 	if IsNumber(init) {
 		n := AsInt64(init)
 		for i := 0; i < size; i++ {
@@ -423,11 +423,11 @@ func (nm *NumberMethods) Booleans(x any) []bool {
 
 func (nm *NumberMethods) BooleanArray(sizeOrSeq any) []bool {
 	if IsNumber(sizeOrSeq) {
-		return make([]bool, MustAsInt(sizeOrSeq))
+		return make([]bool, arraySize(sizeOrSeq))
 	}
 	s := Seq(sizeOrSeq)
 	size := Count(sizeOrSeq)
-	ret := make([]bool, size)
+	ret := make([]bool, arraySize(size))
 	for i := 0; i < size && s != nil; i, s = i+1, s.Next() {
 		ret[i] = s.First().(bool)
 	}
@@ -435,7 +435,7 @@ func (nm *NumberMethods) BooleanArray(sizeOrSeq any) []bool {
 }
 
 func (nm *NumberMethods) BooleanArrayInit(size int, init any) []bool {
-	ret := make([]bool, size)
+	ret := make([]bool, arraySize(size))
 	if b, ok := init.(bool); ok {
 		for i := 0; i < size; i++ {
 			ret[i] = b
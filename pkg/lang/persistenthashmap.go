@@ -118,6 +118,16 @@ func (m *PersistentHashMap) WithMeta(meta IPersistentMap) any {
 }
 
 func (m *PersistentHashMap) Assoc(key, val any) Associative {
+	res := m.assoc(key, val)
+	if res.count > m.count {
+		checkCollectionSize(res.count)
+	}
+	return res
+}
+
+// assoc is Assoc without the collection size limit, for maps the
+// runtime maintains itself.
+func (m *PersistentHashMap) assoc(key, val any) *PersistentHashMap {
 	addedLeaf := &Box{}
 	var newroot, t Node
 	if m.root == nil {
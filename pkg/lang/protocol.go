@@ -0,0 +1,299 @@
+package lang
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Protocol is a named set of methods whose implementations are chosen
+// by the type of their first argument, as defined by defprotocol.
+//
+// A protocol is extended to a type with Extend. A value's type uses
+// the implementation extended to it, or else that of the most
+// specific extended Go interface it implements, so extending a
+// protocol to io.Reader covers all readers. The protocol's methods
+// cache the implementation found for each type.
+type Protocol struct {
+	v                 *Var
+	sigs              IPersistentMap
+	extendViaMetadata bool
+
+	mu sync.Mutex
+	// impls maps each extended type, or nil, to the method map it was
+	// extended with. types holds the extended types in the order they
+	// were first extended.
+	impls map[reflect.Type]protocolImpl
+	types []reflect.Type
+
+	// cache maps a reflect.Type to the *protocolImpl found for it, or
+	// to noProtocolImpl. It is replaced when the protocol is extended.
+	cache   atomic.Pointer[sync.Map]
+	methods map[Keyword]*ProtocolFn
+}
+
+type protocolImpl struct {
+	mmap IPersistentMap
+	fns  map[Keyword]IFn
+}
+
+// ProtocolFn is a method of a protocol.
+type ProtocolFn struct {
+	proto *Protocol
+	kw    Keyword
+	// sym is the qualified symbol that names the method in the
+	// metadata of values extending the protocol via metadata.
+	sym *Symbol
+}
+
+var (
+	_ IFn     = (*ProtocolFn)(nil)
+	_ ILookup = (*Protocol)(nil)
+
+	noProtocolImpl = &protocolImpl{}
+
+	kwVar                = NewKeyword("var")
+	kwSigs               = NewKeyword("sigs")
+	kwImpls              = NewKeyword("impls")
+	kwExtendViaMetadata  = NewKeyword("extend-via-metadata")
+	kwProtocolMethodName = NewKeyword("name")
+)
+
+// NewProtocol returns a protocol named by v, the var that holds it.
+// sigs maps the keyword of each method to a map describing it, with
+// at least its :name. If extendViaMetadata is true, values can
+// implement the methods in their metadata, keyed by the methods'
+// qualified symbols.
+func NewProtocol(v *Var, sigs IPersistentMap, extendViaMetadata bool) *Protocol {
+	p := &Protocol{
+		v:                 v,
+		sigs:              sigs,
+		extendViaMetadata: extendViaMetadata,
+		impls:             map[reflect.Type]protocolImpl{},
+		methods:           map[Keyword]*ProtocolFn{},
+	}
+	p.cache.Store(&sync.Map{})
+	for s := Seq(sigs); s != nil; s = s.Next() {
+		entry := s.First().(IMapEntry)
+		kw := entry.Key().(Keyword)
+		name := Get(entry.Val(), kwProtocolMethodName).(*Symbol)
+		p.methods[kw] = &ProtocolFn{
+			proto: p,
+			kw:    kw,
+			sym:   InternSymbol(v.Namespace().Name().Name(), name.Name()),
+		}
+	}
+	return p
+}
+
+// Var returns the var that holds the protocol.
+func (p *Protocol) Var() *Var {
+	return p.v
+}
+
+// Method returns the method of the protocol named by kw.
+func (p *Protocol) Method(kw Keyword) *ProtocolFn {
+	fn, ok := p.methods[kw]
+	if !ok {
+		panic(NewIllegalArgumentError(fmt.Sprintf("no method %v in protocol %v", kw, p.v)))
+	}
+	return fn
+}
+
+// Extend extends the protocol to atype, which is a reflect.Type or
+// nil, with the implementations in mmap, a map from method keywords
+// to fns. Extending a type again replaces its implementations.
+func (p *Protocol) Extend(atype interface{}, mmap IPersistentMap) {
+	var typ reflect.Type
+	if atype != nil {
+		t, ok := atype.(reflect.Type)
+		if !ok {
+			panic(NewIllegalArgumentError(fmt.Sprintf("can't extend protocol %v to %v: not a type", p.v, atype)))
+		}
+		typ = t
+	}
+
+	impl := protocolImpl{mmap: mmap, fns: map[Keyword]IFn{}}
+	for s := Seq(mmap); s != nil; s = s.Next() {
+		entry := s.First().(IMapEntry)
+		kw, ok := entry.Key().(Keyword)
+		if !ok || p.methods[kw] == nil {
+			panic(NewIllegalArgumentError(fmt.Sprintf("%v is not a method of protocol %v", entry.Key(), p.v)))
+		}
+		impl.fns[kw] = entry.Val().(IFn)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.impls[typ]; !ok {
+		p.types = append(p.types, typ)
+	}
+	p.impls[typ] = impl
+	p.cache.Store(&sync.Map{})
+}
+
+// Extenders returns the types the protocol has been extended to, in
+// the order they were first extended. nil is included if the
+// protocol has been extended to nil.
+func (p *Protocol) Extenders() []interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	res := make([]interface{}, len(p.types))
+	for i, typ := range p.types {
+		if typ != nil {
+			res[i] = typ
+		}
+	}
+	return res
+}
+
+// Extends reports whether values of atype, a reflect.Type or nil,
+// have an implementation of the protocol, either extended to atype
+// itself or to an interface it implements.
+func (p *Protocol) Extends(atype interface{}) bool {
+	var typ reflect.Type
+	if atype != nil {
+		typ = atype.(reflect.Type)
+	}
+	return p.implFor(typ) != nil
+}
+
+// Satisfies reports whether x has an implementation of the protocol
+// by its type. Implementations in metadata are not considered.
+func (p *Protocol) Satisfies(x interface{}) bool {
+	return p.implFor(reflect.TypeOf(x)) != nil
+}
+
+// FindImpl returns the method map of the implementation of the
+// protocol for x by its type, or nil if there is none.
+func (p *Protocol) FindImpl(x interface{}) IPersistentMap {
+	impl := p.implFor(reflect.TypeOf(x))
+	if impl == nil {
+		return nil
+	}
+	return impl.mmap
+}
+
+// implFor returns the implementation for values of typ, or nil.
+func (p *Protocol) implFor(typ reflect.Type) *protocolImpl {
+	cache := p.cache.Load()
+	if impl, ok := cache.Load(typ); ok {
+		if impl == noProtocolImpl {
+			return nil
+		}
+		return impl.(*protocolImpl)
+	}
+
+	impl := p.findImpl(typ)
+	if impl == nil {
+		cache.Store(typ, noProtocolImpl)
+	} else {
+		cache.Store(typ, impl)
+	}
+	return impl
+}
+
+func (p *Protocol) findImpl(typ reflect.Type) *protocolImpl {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if impl, ok := p.impls[typ]; ok {
+		return &impl
+	}
+	if typ == nil {
+		return nil
+	}
+	// prefer the most specific of the interfaces typ implements,
+	// then the first extended.
+	var best reflect.Type
+	for _, iface := range p.types {
+		if iface == nil || iface.Kind() != reflect.Interface || !typ.Implements(iface) {
+			continue
+		}
+		if best == nil || (iface.Implements(best) && !best.Implements(iface)) {
+			best = iface
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	impl := p.impls[best]
+	return &impl
+}
+
+func (p *Protocol) ValAt(key interface{}) interface{} {
+	return p.ValAtDefault(key, nil)
+}
+
+func (p *Protocol) ValAtDefault(key, notFound interface{}) interface{} {
+	switch key {
+	case kwVar:
+		return p.v
+	case kwSigs:
+		return p.sigs
+	case kwExtendViaMetadata:
+		return p.extendViaMetadata
+	case kwImpls:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		impls := NewMap()
+		for _, typ := range p.types {
+			var key interface{}
+			if typ != nil {
+				key = typ
+			}
+			impls = impls.Assoc(key, p.impls[typ].mmap).(IPersistentMap)
+		}
+		return impls
+	}
+	return notFound
+}
+
+func (p *Protocol) String() string {
+	return fmt.Sprintf("#<Protocol %v>", p.v.Symbol())
+}
+
+// Protocol returns the protocol of the method.
+func (f *ProtocolFn) Protocol() *Protocol {
+	return f.proto
+}
+
+// FindFn returns the implementation of the method for x, or nil if
+// there is none.
+func (f *ProtocolFn) FindFn(x interface{}) IFn {
+	if f.proto.extendViaMetadata {
+		if m, ok := x.(IMeta); ok {
+			if fn := Get(m.Meta(), f.sym); fn != nil {
+				return fn.(IFn)
+			}
+		}
+	}
+	impl := f.proto.implFor(reflect.TypeOf(x))
+	if impl == nil {
+		return nil
+	}
+	return impl.fns[f.kw]
+}
+
+func (f *ProtocolFn) Invoke(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		panic(NewIllegalArgumentError(fmt.Sprintf("wrong number of args (0) passed to: %v", f.sym)))
+	}
+	fn := f.FindFn(args[0])
+	if fn == nil {
+		panic(NewIllegalArgumentError(fmt.Sprintf("No implementation of method: %v of protocol: %v found for type: %T", f.kw, f.proto.v, args[0])))
+	}
+	return fn.Invoke(args...)
+}
+
+func (f *ProtocolFn) ApplyTo(args ISeq) interface{} {
+	return f.Invoke(seqToSlice(args)...)
+}
+
+func (f *ProtocolFn) String() string {
+	return fmt.Sprintf("#<ProtocolFn %v>", f.sym)
+}
@@ -0,0 +1,71 @@
+package lang
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestProtocol(extendViaMetadata bool, methods ...string) *Protocol {
+	ns := NewNamespace(NewSymbol("protocol.test"))
+	sigs := NewMap()
+	for _, m := range methods {
+		sigs = sigs.Assoc(NewKeyword(m), NewMap(kwProtocolMethodName, NewSymbol(m))).(IPersistentMap)
+	}
+	return NewProtocol(NewVar(ns, NewSymbol("P")), sigs, extendViaMetadata)
+}
+
+func constFn(v interface{}) IFn {
+	return IFnFunc(func(args ...interface{}) interface{} { return v })
+}
+
+func TestProtocolDispatch(t *testing.T) {
+	p := newTestProtocol(false, "name")
+	name := p.Method(NewKeyword("name"))
+
+	p.Extend(reflect.TypeOf((*io.Reader)(nil)).Elem(), NewMap(NewKeyword("name"), constFn("reader")))
+	p.Extend(reflect.TypeOf((*io.ReadWriter)(nil)).Elem(), NewMap(NewKeyword("name"), constFn("read-writer")))
+	p.Extend(nil, NewMap(NewKeyword("name"), constFn("nil")))
+
+	for _, tc := range []struct {
+		x    interface{}
+		want string
+	}{
+		{strings.NewReader("x"), "reader"},
+		// the most specific interface wins.
+		{&bytes.Buffer{}, "read-writer"},
+		{nil, "nil"},
+	} {
+		if got := name.Invoke(tc.x); got != tc.want {
+			t.Errorf("name(%T) = %v, want %v", tc.x, got, tc.want)
+		}
+	}
+
+	// extending a type replaces the cached implementation.
+	p.Extend(reflect.TypeOf(&bytes.Buffer{}), NewMap(NewKeyword("name"), constFn("buffer")))
+	if got := name.Invoke(&bytes.Buffer{}); got != "buffer" {
+		t.Errorf("name(*bytes.Buffer) = %v after extend, want buffer", got)
+	}
+
+	if p.Satisfies(1) || name.FindFn(1) != nil {
+		t.Error("int satisfies the protocol")
+	}
+	if got := len(p.Extenders()); got != 4 {
+		t.Errorf("expected 4 extenders, got %d", got)
+	}
+}
+
+func TestProtocolMetadata(t *testing.T) {
+	p := newTestProtocol(true, "name")
+	name := p.Method(NewKeyword("name"))
+
+	x := NewVector().WithMeta(NewMap(NewSymbol("protocol.test/name"), constFn("meta")))
+	if got := name.Invoke(x); got != "meta" {
+		t.Errorf("name(x) = %v, want meta", got)
+	}
+	if p.Satisfies(x) {
+		t.Error("metadata implementation satisfies the protocol")
+	}
+}
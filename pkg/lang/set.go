@@ -72,6 +72,7 @@ func (s *Set) Cons(v interface{}) Conser {
 	if s.Contains(v) {
 		return s
 	}
+	checkCollectionSize(len(s.vals) + 1)
 	return NewSet(append(s.vals, v)...)
 }
 
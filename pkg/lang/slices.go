@@ -3,6 +3,7 @@ package lang
 import (
 	"fmt"
 	"reflect"
+	"sort"
 )
 
 func SliceSet(slc any, idx int, val any) {
@@ -21,6 +22,9 @@ func ToSlice(x any) []any {
 		}
 		return res
 	}
+	if s, ok := x.(Seqable); ok {
+		return ToSlice(s.Seq())
+	}
 	xVal := reflect.ValueOf(x)
 	if xVal.Kind() == reflect.Slice || xVal.Kind() == reflect.Array {
 		res := make([]interface{}, xVal.Len())
@@ -31,3 +35,18 @@ func ToSlice(x any) []any {
 	}
 	panic(fmt.Errorf("ToSlice not supported on type: %T", x))
 }
+
+// SortSlice sorts s in place, stably, with comp as a comparator: a
+// function of two arguments that returns a negative, zero or positive
+// number, or a predicate that is true if its first argument comes
+// before its second.
+func SortSlice(s []any, comp IFn) {
+	sort.SliceStable(s, func(i, j int) bool {
+		switch res := comp.Invoke(s[i], s[j]).(type) {
+		case bool:
+			return res
+		default:
+			return AsInt64(res) < 0
+		}
+	})
+}
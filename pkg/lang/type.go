@@ -7,7 +7,9 @@ import (
 )
 
 var (
-	Throwable = reflect.TypeOf((interface{})(nil))
+	// Throwable matches any value thrown, as Go panics may hold any
+	// value.
+	Throwable = reflect.TypeOf((*interface{})(nil)).Elem()
 
 	// TODO: convert use of 'matcher' in core.glj to fit go's
 	// regexps. This supresses errors but doesn't actually work.
@@ -54,10 +54,12 @@ var (
 	VarPrintReadably    = InternVarReplaceRoot(NSCore, NewSymbol("*print-readably*"), true).SetDynamic()
 	VarOut              = InternVarReplaceRoot(NSCore, NewSymbol("*out*"), os.Stdout).SetDynamic()
 	VarIn               = InternVarReplaceRoot(NSCore, NewSymbol("*in*"), os.Stdin).SetDynamic()
+	VarErr              = InternVarReplaceRoot(NSCore, NewSymbol("*err*"), os.Stderr).SetDynamic()
 	VarAssert           = InternVarReplaceRoot(NSCore, NewSymbol("*assert*"), false).SetDynamic()
 	VarCompileFiles     = InternVarReplaceRoot(NSCore, NewSymbol("*compile-files*"), false).SetDynamic()
 	VarFile             = InternVarReplaceRoot(NSCore, NewSymbol("*file*"), "NO_SOURCE_FILE").SetDynamic()
 	VarDataReaders      = InternVarReplaceRoot(NSCore, NewSymbol("*data-readers*"), emptyMap).SetDynamic()
+	VarPrintGoStack     = InternVarReplaceRoot(NSCore, NewSymbol("*print-go-stack*"), false).SetDynamic()
 
 	// TODO: use variant of InternVar that doesn't replace root.
 	VarPrintInitialized = InternVarName(NSCore.Name(), NewSymbol("print-initialized"))
@@ -149,6 +151,9 @@ func (v *Var) Set(val interface{}) interface{} {
 	if b == nil {
 		panic(fmt.Sprintf("can't change/establish root binding of: %s", v))
 	}
+	if v == VarContext {
+		cancelableContexts.Add(cancelable(val) - cancelable(b.val))
+	}
 	b.val = val
 	return val
 }
@@ -159,7 +164,7 @@ func (v *Var) Meta() IPersistentMap {
 
 func (v *Var) SetMeta(meta IPersistentMap) {
 	// TODO: ResetMeta
-	meta = Assoc(meta, KWNS, v.ns).(IPersistentMap)
+	meta = Assoc(Assoc(meta, KWName, v.sym), KWNS, v.ns).(IPersistentMap)
 	v.meta.Store(NewBox(meta))
 }
 
@@ -200,6 +205,12 @@ func (v *Var) SetDynamic() *Var {
 	return v
 }
 
+// IsThreadBound reports whether v has a binding in the current
+// goroutine, which Set changes.
+func (v *Var) IsThreadBound() bool {
+	return v.getDynamicBinding() != nil
+}
+
 func (v *Var) Deref() interface{} {
 	if b := v.getDynamicBinding(); b != nil {
 		return b.val
@@ -315,6 +326,7 @@ func PushThreadBindings(bindings IPersistentMap) {
 		vr.dynamicBound.Store(true)
 		store[vr] = &Box{val: val}
 	}
+	cancelableContexts.Add(cancelableBindings(store))
 }
 
 func PopThreadBindings() {
@@ -323,6 +335,7 @@ func PopThreadBindings() {
 	storage := glsBindings[gid]
 	glsBindingsMtx.RUnlock()
 
+	cancelableContexts.Add(-cancelableBindings(storage.bindings[len(storage.bindings)-1]))
 	if len(storage.bindings) > 1 {
 		storage.bindings = storage.bindings[:len(storage.bindings)-1]
 		return
@@ -333,16 +346,58 @@ func PopThreadBindings() {
 	glsBindingsMtx.Unlock()
 }
 
+// CloneThreadBindingFrame returns the bindings of the current
+// goroutine, to be installed in another with ResetThreadBindingFrame.
+// Bindings pushed by either goroutine afterwards are not seen by the
+// other.
 func CloneThreadBindingFrame() interface{} {
 	gid := getGoroutineID()
 	glsBindingsMtx.RLock()
 	defer glsBindingsMtx.RUnlock()
-	return glsBindings[gid]
+	storage := glsBindings[gid]
+	if storage == nil {
+		return (*glStorage)(nil)
+	}
+	return &glStorage{bindings: append([]varBindings(nil), storage.bindings...)}
 }
 
+// ResetThreadBindingFrame replaces the bindings of the current
+// goroutine with frame, as returned by CloneThreadBindingFrame.
 func ResetThreadBindingFrame(frame interface{}) {
 	gid := getGoroutineID()
 	glsBindingsMtx.Lock()
 	defer glsBindingsMtx.Unlock()
-	glsBindings[gid] = frame.(*glStorage)
+	if old := glsBindings[gid]; old != nil {
+		for _, store := range old.bindings {
+			cancelableContexts.Add(-cancelableBindings(store))
+		}
+	}
+	if storage := frame.(*glStorage); storage != nil {
+		glsBindings[gid] = &glStorage{bindings: append([]varBindings(nil), storage.bindings...)}
+		for _, store := range storage.bindings {
+			cancelableContexts.Add(cancelableBindings(store))
+		}
+	} else {
+		delete(glsBindings, gid)
+	}
+}
+
+// GetThreadBindings returns a map of the vars bound in the current
+// goroutine to their values.
+func GetThreadBindings() IPersistentMap {
+	gid := getGoroutineID()
+	glsBindingsMtx.RLock()
+	storage := glsBindings[gid]
+	glsBindingsMtx.RUnlock()
+
+	var res IPersistentMap = emptyMap
+	if storage == nil {
+		return res
+	}
+	for _, store := range storage.bindings {
+		for vr, b := range store {
+			res = Assoc(res, vr, b.val).(IPersistentMap)
+		}
+	}
+	return res
 }
@@ -0,0 +1,78 @@
+package lang
+
+import "testing"
+
+func newDynamicVar(name string, root interface{}) *Var {
+	return NewVarWithRoot(FindOrCreateNamespace(NewSymbol("lang.test")), NewSymbol(name), root).SetDynamic()
+}
+
+func TestThreadBindingFrames(t *testing.T) {
+	v := newDynamicVar("*frame*", 0)
+
+	// bindings pushed by the goroutine a frame is conveyed to are not
+	// seen by the one it was cloned from, nor the other way around.
+	PushThreadBindings(NewMap(v, 1))
+	defer PopThreadBindings()
+	frame := CloneThreadBindingFrame()
+	pushed, checked := make(chan struct{}), make(chan interface{})
+	go func() {
+		ResetThreadBindingFrame(frame)
+		PushThreadBindings(NewMap(v, 2))
+		pushed <- struct{}{}
+		<-pushed
+		checked <- v.Deref()
+	}()
+	<-pushed
+	if got := v.Deref(); got != 1 {
+		t.Errorf("expected the cloned binding 1, got %v", got)
+	}
+	PushThreadBindings(NewMap(v, 3))
+	pushed <- struct{}{}
+	if got := <-checked; got != 2 {
+		t.Errorf("expected the conveyed goroutine's binding 2, got %v", got)
+	}
+	PopThreadBindings()
+
+	// a frame without bindings can be conveyed and bound in.
+	go func() {
+		frame := CloneThreadBindingFrame()
+		go func() {
+			ResetThreadBindingFrame(frame)
+			PushThreadBindings(NewMap(v, 4))
+			defer PopThreadBindings()
+			checked <- v.Deref()
+		}()
+	}()
+	if got := <-checked; got != 4 {
+		t.Errorf("expected the binding 4, got %v", got)
+	}
+}
+
+func TestGetThreadBindings(t *testing.T) {
+	v, w := newDynamicVar("*a*", 0), newDynamicVar("*b*", 0)
+	if got := GetThreadBindings(); got.Count() != 0 {
+		t.Errorf("expected no bindings, got %v", got)
+	}
+	PushThreadBindings(NewMap(v, 1, w, 2))
+	defer PopThreadBindings()
+	PushThreadBindings(NewMap(v, 3))
+	defer PopThreadBindings()
+	if got := GetThreadBindings(); !Equals(got, NewMap(v, 3, w, 2)) {
+		t.Errorf("expected the innermost bindings, got %v", got)
+	}
+}
+
+func TestVarSetMeta(t *testing.T) {
+	v := newDynamicVar("meta", 0)
+	v.SetMeta(NewMap(KWDoc, "doc"))
+	meta := v.Meta()
+	if got := meta.ValAt(KWName); got != v.sym {
+		t.Errorf("expected :name %v, got %v", v.sym, got)
+	}
+	if got := meta.ValAt(KWNS); got != v.ns {
+		t.Errorf("expected :ns %v, got %v", v.ns, got)
+	}
+	if got := meta.ValAt(KWDoc); got != "doc" {
+		t.Errorf("expected :doc \"doc\", got %v", got)
+	}
+}
@@ -70,6 +70,7 @@ func (v *Vector) Length() int {
 }
 
 func (v *Vector) Cons(x any) Conser {
+	checkCollectionSize(v.Count() + 1)
 	return &Vector{
 		meta: v.meta,
 		vec:  v.vec.Conj(x),
@@ -302,6 +303,7 @@ func toSlice(x any) []any {
 // TransientVector
 
 func (t *TransientVector) Conj(o any) Conjer {
+	checkCollectionSize(t.vec.Count() + 1)
 	t.vec = t.vec.Conj(o)
 	return t
 }
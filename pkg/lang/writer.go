@@ -43,3 +43,19 @@ func WriteWriter(w io.Writer, v interface{}) io.Writer {
 	}
 	return w
 }
+
+// FlushWriter is a shim for clojure.core's use of Java's flush()
+// method. Writers with a Flush or Sync method are flushed; all others
+// are left as is.
+func FlushWriter(w io.Writer) {
+	switch w := w.(type) {
+	case interface{ Flush() error }:
+		if err := w.Flush(); err != nil {
+			panic(err)
+		}
+	case interface{ Sync() error }:
+		// Sync fails on terminals and pipes, which have nothing to
+		// flush, so errors are ignored.
+		w.Sync()
+	}
+}
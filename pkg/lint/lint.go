@@ -0,0 +1,530 @@
+// Package lint reports likely mistakes in Glojure source code by
+// analyzing it without evaluating it.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/glojurelang/glojure/pkg/ast"
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/pkgmap"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// The checks that diagnostics are reported by.
+const (
+	// CheckError reports forms that can't be read or analyzed.
+	CheckError = "error"
+	// CheckUnresolvedSymbol reports symbols that don't refer to a
+	// local, a var, a namespace or a Go export.
+	CheckUnresolvedSymbol = "unresolved-symbol"
+	// CheckGoExport reports references to unknown exports of known Go
+	// packages.
+	CheckGoExport = "go-export"
+	// CheckArity reports calls to fns with a number of arguments that
+	// none of their methods take.
+	CheckArity = "arity"
+	// CheckUnusedBinding reports locals that are never referred to.
+	// Locals whose name starts with _ are ignored.
+	CheckUnusedBinding = "unused-binding"
+	// CheckShadowedVar reports locals and vars named as a var of
+	// glojure.core that the namespace refers to.
+	CheckShadowedVar = "shadowed-var"
+	// CheckRedefinedVar reports vars defined more than once.
+	CheckRedefinedVar = "redefined-var"
+)
+
+// Diagnostic is a problem found in source code.
+type Diagnostic struct {
+	Filename string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Check    string `json:"check"`
+	Message  string `json:"message"`
+}
+
+// String formats d as file:line:col: message.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", d.Filename, d.Line, d.Column, d.Message)
+}
+
+// Source analyzes the forms of src, the content of the file filename,
+// in env, and returns the problems found, ordered by position.
+//
+// The forms are not evaluated, except for those that later forms
+// need to be analyzed: ns, in-ns, require, use, import, refer,
+// refer-clojure and alias forms, which may load other namespaces, and
+// the definitions of macros and types. Vars defined by other forms
+// are interned but not bound. *ns* is restored when Source returns.
+func Source(env value.Environment, filename string, src string) []Diagnostic {
+	value.PushThreadBindings(value.NewMap(value.VarCurrentNS, value.VarCurrentNS.Deref()))
+	defer value.PopThreadBindings()
+
+	l := &linter{
+		env:      env,
+		filename: filename,
+		arities:  map[*value.Var][]arity{},
+		defs:     map[*value.Var]value.IPersistentMap{},
+		bindings: map[*value.Symbol]*binding{},
+	}
+	rdr := reader.New(strings.NewReader(src), reader.WithFilename(filename), reader.WithGetCurrentNS(env.CurrentNamespace))
+	for {
+		form, err := rdr.ReadOne()
+		if err == reader.ErrEOF {
+			break
+		}
+		if err != nil {
+			d := Diagnostic{Filename: filename, Check: CheckError, Message: err.Error()}
+			var readErr *reader.Error
+			if errors.As(err, &readErr) {
+				_, d.Line, d.Column = readErr.Position()
+				d.Message = errors.Unwrap(readErr).Error()
+			}
+			l.diags = append(l.diags, d)
+			break
+		}
+		l.form(form)
+	}
+	l.reportUnused()
+
+	sort.SliceStable(l.diags, func(i, j int) bool {
+		a, b := l.diags[i], l.diags[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.diags
+}
+
+type (
+	linter struct {
+		env      value.Environment
+		filename string
+		diags    []Diagnostic
+
+		// ns is the namespace of the form being linted, and mappings
+		// its mappings before the form was analyzed.
+		ns       *value.Namespace
+		mappings value.IPersistentMap
+
+		// arities holds the arities of the fns defined by the file.
+		arities map[*value.Var][]arity
+		// defs holds the positions of the vars defined by the file.
+		defs map[*value.Var]value.IPersistentMap
+		// bindings holds the locals of the file, by name, in order.
+		bindings map[*value.Symbol]*binding
+		locals   []*binding
+	}
+
+	arity struct {
+		fixed    int
+		variadic bool
+	}
+
+	binding struct {
+		name *value.Symbol
+		used bool
+	}
+)
+
+// evaluated holds the names of the macros of glojure.core whose
+// top-level calls are evaluated.
+var evaluated = map[string]bool{
+	"ns":            true,
+	"in-ns":         true,
+	"require":       true,
+	"use":           true,
+	"import":        true,
+	"refer":         true,
+	"refer-clojure": true,
+	"alias":         true,
+	"defmacro":      true,
+	"defprotocol":   true,
+	"deftype":       true,
+	"defrecord":     true,
+	"definterface":  true,
+}
+
+// form lints the top-level form.
+func (l *linter) form(form interface{}) {
+	l.ns = l.env.CurrentNamespace()
+	l.mappings = l.ns.Mappings()
+	if l.isEvaluated(form) {
+		if err := l.try(func() error { _, err := l.env.Eval(form); return err }); err != nil {
+			l.report(form, CheckError, "%s", runtime.ErrorMessage(err))
+		}
+		return
+	}
+	var n *ast.Node
+	err := l.try(func() (err error) {
+		n, err = runtime.Analyze(l.env, form)
+		return err
+	})
+	if err != nil {
+		l.report(form, CheckError, "%s", runtime.ErrorMessage(err))
+		return
+	}
+	l.walk(n, form)
+}
+
+// try calls f, converting a panic to an error.
+func (l *linter) try(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if rErr, ok := r.(error); ok {
+				err = rErr
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+	return f()
+}
+
+// isEvaluated reports whether the top-level form calls one of the
+// evaluated macros of glojure.core.
+func (l *linter) isEvaluated(form interface{}) bool {
+	seq, ok := form.(value.ISeq)
+	if !ok {
+		return false
+	}
+	sym, ok := seq.First().(*value.Symbol)
+	if !ok || !evaluated[sym.Name()] {
+		return false
+	}
+	var vr *value.Var
+	switch sym.Namespace() {
+	case "":
+		vr, _ = l.ns.GetMapping(sym).(*value.Var)
+	case value.NSCore.Name().Name():
+		vr = value.NSCore.FindInternedVar(value.NewSymbol(sym.Name()))
+	}
+	return vr != nil && vr.Namespace() == value.NSCore
+}
+
+// walk lints the node n and the nodes it holds. form is the nearest
+// enclosing form with a source position.
+func (l *linter) walk(n *ast.Node, form interface{}) {
+	if n == nil {
+		return
+	}
+	if l.inFile(n.Form) {
+		form = n.Form
+	}
+	switch n.Op {
+	case ast.OpMaybeClass:
+		l.maybeClass(n, form)
+	case ast.OpMaybeHostForm:
+		sub := n.Sub.(*ast.MaybeHostFormNode)
+		if l.inFile(n.Form) && !supportedHostForms[sub.Class+"/"+sub.Field.Name()] {
+			l.report(form, CheckUnresolvedSymbol, "unable to resolve symbol: %s/%s", sub.Class, sub.Field.Name())
+		}
+	case ast.OpLocal:
+		if b := l.bindings[n.Sub.(*ast.LocalNode).Name]; b != nil {
+			b.used = true
+		}
+	case ast.OpDef:
+		l.def(n, form)
+		sub := n.Sub.(*ast.DefNode)
+		l.walk(sub.Meta, form)
+		l.walk(sub.Init, form)
+	case ast.OpInvoke:
+		l.invoke(n, form)
+		sub := n.Sub.(*ast.InvokeNode)
+		l.walk(sub.Fn, form)
+		l.walkAll(sub.Args, form)
+	case ast.OpLet, ast.OpLoop:
+		for _, b := range n.Sub.(*ast.LetNode).Bindings {
+			l.walk(b.Sub.(*ast.BindingNode).Init, form)
+			l.bind(b, form)
+		}
+		l.walk(n.Sub.(*ast.LetNode).Body, form)
+	case ast.OpLetFn:
+		sub := n.Sub.(*ast.LetFnNode)
+		for _, b := range sub.Bindings {
+			l.bind(b, form)
+		}
+		for _, b := range sub.Bindings {
+			l.walk(b.Sub.(*ast.BindingNode).Init, form)
+		}
+		l.walk(sub.Body, form)
+	case ast.OpFn:
+		// the local name of a fn needn't be used.
+		l.walkAll(n.Sub.(*ast.FnNode).Methods, form)
+	case ast.OpFnMethod:
+		sub := n.Sub.(*ast.FnMethodNode)
+		for _, p := range sub.Params {
+			l.bind(p, form)
+		}
+		l.walk(sub.Body, form)
+	case ast.OpCatch:
+		sub := n.Sub.(*ast.CatchNode)
+		l.walk(sub.Class, form)
+		l.bind(sub.Local, form)
+		l.walk(sub.Body, form)
+	case ast.OpBinding:
+		l.walk(n.Sub.(*ast.BindingNode).Init, form)
+	case ast.OpConst:
+		l.walk(n.Sub.(*ast.ConstNode).Meta, form)
+	case ast.OpSetBang:
+		sub := n.Sub.(*ast.SetBangNode)
+		l.walk(sub.Target, form)
+		l.walk(sub.Val, form)
+	case ast.OpWithMeta:
+		sub := n.Sub.(*ast.WithMetaNode)
+		l.walk(sub.Meta, form)
+		l.walk(sub.Expr, form)
+	case ast.OpMap:
+		sub := n.Sub.(*ast.MapNode)
+		l.walkAll(sub.Keys, form)
+		l.walkAll(sub.Vals, form)
+	case ast.OpVector:
+		l.walkAll(n.Sub.(*ast.VectorNode).Items, form)
+	case ast.OpSet:
+		l.walkAll(n.Sub.(*ast.SetNode).Items, form)
+	case ast.OpDo:
+		l.walkAll(n.Sub.(*ast.DoNode).Statements, form)
+		l.walk(n.Sub.(*ast.DoNode).Ret, form)
+	case ast.OpHostCall:
+		sub := n.Sub.(*ast.HostCallNode)
+		l.walk(sub.Target, form)
+		l.walkAll(sub.Args, form)
+	case ast.OpHostInterop:
+		l.walk(n.Sub.(*ast.HostInteropNode).Target, form)
+	case ast.OpHostField:
+		l.walk(n.Sub.(*ast.HostFieldNode).Target, form)
+	case ast.OpGo:
+		l.walk(n.Sub.(*ast.GoNode).Invoke, form)
+	case ast.OpIf:
+		sub := n.Sub.(*ast.IfNode)
+		l.walk(sub.Test, form)
+		l.walk(sub.Then, form)
+		l.walk(sub.Else, form)
+	case ast.OpCase:
+		sub := n.Sub.(*ast.CaseNode)
+		l.walk(sub.Test, form)
+		l.walkAll(sub.Nodes, form)
+		l.walk(sub.Default, form)
+	case ast.OpCaseNode:
+		sub := n.Sub.(*ast.CaseNodeNode)
+		l.walkAll(sub.Tests, form)
+		l.walk(sub.Then, form)
+	case ast.OpRecur:
+		l.walkAll(n.Sub.(*ast.RecurNode).Exprs, form)
+	case ast.OpNew:
+		sub := n.Sub.(*ast.NewNode)
+		l.walk(sub.Class, form)
+		l.walkAll(sub.Args, form)
+	case ast.OpTry:
+		sub := n.Sub.(*ast.TryNode)
+		l.walk(sub.Body, form)
+		l.walkAll(sub.Catches, form)
+		l.walk(sub.Finally, form)
+	case ast.OpThrow:
+		l.walk(n.Sub.(*ast.ThrowNode).Exception, form)
+	}
+}
+
+func (l *linter) walkAll(nodes []*ast.Node, form interface{}) {
+	for _, n := range nodes {
+		l.walk(n, form)
+	}
+}
+
+// supportedHostForms holds the ns/name host forms the runtime
+// implements.
+var supportedHostForms = map[string]bool{
+	"glojure.lang.PersistentTreeSet/create": true,
+}
+
+// maybeClass reports a symbol that resolves to nothing but a Go
+// export, if it doesn't.
+func (l *linter) maybeClass(n *ast.Node, form interface{}) {
+	sym, ok := n.Sub.(*ast.MaybeClassNode).Class.(*value.Symbol)
+	if !ok {
+		return
+	}
+	if _, ok := pkgmap.Get(sym.FullName()); ok {
+		return
+	}
+	if pkg, _ := pkgmap.SplitExport(sym.FullName()); pkg != "" && pkgmap.HasPackage(pkg) {
+		l.report(form, CheckGoExport, "unknown Go export: %s", sym)
+		return
+	}
+	l.report(form, CheckUnresolvedSymbol, "unable to resolve symbol: %s", sym)
+}
+
+// def records the arities and position of the var defined by the def
+// node n, reporting redefinitions and shadowed vars. The :doc and
+// :arglists of the form are copied to the var, which isn't bound, so
+// that editors can show them.
+func (l *linter) def(n *ast.Node, form interface{}) {
+	sub := n.Sub.(*ast.DefNode)
+	meta := sub.Var.Meta()
+	for _, kw := range []value.Keyword{value.KWDoc, value.KWArglists} {
+		if v := value.Get(sub.Name.Meta(), kw); v != nil {
+			meta = value.Assoc(meta, kw, v).(value.IPersistentMap)
+		}
+	}
+	sub.Var.SetMeta(meta)
+	if fn := runtime.DefFn(n); fn != nil {
+		var arities []arity
+		for _, m := range fn.Sub.(*ast.FnNode).Methods {
+			method := m.Sub.(*ast.FnMethodNode)
+			arities = append(arities, arity{fixed: method.FixedArity, variadic: method.IsVariadic})
+		}
+		l.arities[sub.Var] = arities
+	}
+	if sub.Init == nil {
+		// declarations don't define the var.
+		return
+	}
+	if first, ok := l.defs[sub.Var]; ok {
+		l.report(form, CheckRedefinedVar, "redefinition of %s, first defined at line %v", varName(sub.Var), value.Get(first, value.KWLine))
+	} else {
+		l.defs[sub.Var] = formMeta(form)
+	}
+	if core := l.coreVar(sub.Name); core != nil {
+		l.report(form, CheckShadowedVar, "%s shadows %s", varName(sub.Var), varName(core))
+	}
+}
+
+// coreVar returns the var of glojure.core that the namespace of the
+// form being linted referred to by sym before the form, or nil.
+func (l *linter) coreVar(sym *value.Symbol) *value.Var {
+	if l.ns == value.NSCore {
+		return nil
+	}
+	vr, ok := value.Get(l.mappings, value.NewSymbol(sym.Name())).(*value.Var)
+	if !ok || vr.Namespace() != value.NSCore {
+		return nil
+	}
+	return vr
+}
+
+// bind records the local bound by the binding node n, if it is
+// written in the source.
+func (l *linter) bind(n *ast.Node, form interface{}) {
+	if n == nil {
+		return
+	}
+	name := n.Sub.(*ast.BindingNode).Name
+	if !l.inFile(name) || strings.HasPrefix(name.Name(), "_") {
+		// generated by a macro, or deliberately unused.
+		return
+	}
+	if l.bindings[name] != nil {
+		// bound more than once by the expansion of a macro.
+		return
+	}
+	b := &binding{name: name}
+	l.bindings[name] = b
+	l.locals = append(l.locals, b)
+	if core := l.coreVar(name); core != nil {
+		l.report(name, CheckShadowedVar, "local %s shadows %s", name, varName(core))
+	}
+}
+
+func (l *linter) reportUnused() {
+	for _, b := range l.locals {
+		if !b.used {
+			l.report(b.name, CheckUnusedBinding, "unused binding %s", b.name)
+		}
+	}
+}
+
+// invoke reports a call of a var with a number of arguments none of
+// its fn's methods take.
+func (l *linter) invoke(n *ast.Node, form interface{}) {
+	sub := n.Sub.(*ast.InvokeNode)
+	if sub.Fn.Op != ast.OpVar {
+		return
+	}
+	vr := sub.Fn.Sub.(*ast.VarNode).Var
+	arities, ok := l.arities[vr]
+	if !ok {
+		arities, ok = arglistsArities(vr.Meta())
+	}
+	if !ok {
+		return
+	}
+	argc := len(sub.Args)
+	for _, a := range arities {
+		if argc == a.fixed || (a.variadic && argc > a.fixed) {
+			return
+		}
+	}
+	l.report(form, CheckArity, "wrong number of args (%d) passed to %s", argc, varName(vr))
+}
+
+// arglistsArities returns the arities of the :arglists of the var
+// metadata meta, if it has any.
+func arglistsArities(meta value.IPersistentMap) ([]arity, bool) {
+	if value.IsTruthy(value.Get(meta, value.KWMacro)) {
+		return nil, false
+	}
+	arglists := value.Get(meta, value.KWArglists)
+	if seq, ok := arglists.(value.ISeq); ok && value.Equals(seq.First(), value.NewSymbol("quote")) {
+		arglists = value.First(seq.Next())
+	}
+	var arities []arity
+	for seq := value.Seq(arglists); seq != nil; seq = seq.Next() {
+		params, ok := seq.First().(value.IPersistentVector)
+		if !ok {
+			return nil, false
+		}
+		var a arity
+		for i := 0; i < params.Count(); i++ {
+			if value.Equals(params.Nth(i), value.NewSymbol("&")) {
+				a.variadic = true
+				break
+			}
+			a.fixed++
+		}
+		arities = append(arities, a)
+	}
+	return arities, len(arities) > 0
+}
+
+// inFile reports whether form has a position in the file being
+// linted, rather than none or one in the file of a macro.
+func (l *linter) inFile(form interface{}) bool {
+	meta := formMeta(form)
+	return value.Get(meta, value.KWLine) != nil && value.Get(meta, value.KWFile) == l.filename
+}
+
+// report adds a diagnostic at the position of form, unless it was
+// already reported, as for a form repeated by a macro.
+func (l *linter) report(form interface{}, check, format string, args ...interface{}) {
+	meta := formMeta(form)
+	d := Diagnostic{
+		Filename: l.filename,
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	d.Line, _ = value.Get(meta, value.KWLine).(int)
+	d.Column, _ = value.Get(meta, value.KWColumn).(int)
+	for _, prev := range l.diags {
+		if prev == d {
+			return
+		}
+	}
+	l.diags = append(l.diags, d)
+}
+
+// varName returns the namespace-qualified name of vr.
+func varName(vr *value.Var) string {
+	return vr.Namespace().Name().Name() + "/" + vr.Symbol().Name()
+}
+
+func formMeta(form interface{}) value.IPersistentMap {
+	if m, ok := form.(value.IMeta); ok {
+		return m.Meta()
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package lint_test
+
+import (
+	"strings"
+	"testing"
+
+	_ "github.com/glojurelang/glojure/pkg/glj"
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/lint"
+)
+
+const src = `(ns lint.test
+  (:require [glojure.string :as str]))
+
+(defmacro twice [x] ` + "`" + `(do ~x ~x))
+
+(defn greet [name unused]
+  (let [msg (str "hi " name)
+        _ignored 1
+        x 2]
+    (twice (strings.ToUppr msg))
+    (fmt.Println msg)
+    (undefined-fn msg)))
+
+(defn map [f xs] (glojure.core/map f xs))
+(defn greet [a] a)
+
+(greet 1 2)
+(inc 1 2)
+(str/join "," [1] 3)
+(nope/thing 1)
+(let [[a b] [1 2]] a)
+(letfn [(f [n] (g n)) (g [n] n)] (f 1))
+(fn [{:keys [k]}] 1)
+(let [x]
+`
+
+func TestSource(t *testing.T) {
+	ns := value.VarCurrentNS.Deref()
+	diags := lint.Source(value.GlobalEnv, "lint/test.glj", src)
+	if value.VarCurrentNS.Deref() != ns {
+		t.Errorf("expected *ns* to be restored")
+	}
+
+	var got []string
+	for _, d := range diags {
+		if d.Filename != "lint/test.glj" {
+			t.Errorf("unexpected file: %v", d)
+		}
+		got = append(got, d.String()[len(d.Filename)+1:]+" ["+d.Check+"]")
+	}
+	expected := []string{
+		"6:14: local name shadows glojure.core/name [shadowed-var]",
+		"6:19: unused binding unused [unused-binding]",
+		"9:9: unused binding x [unused-binding]",
+		"10:13: unknown Go export: strings.ToUppr [go-export]",
+		"12:6: unable to resolve symbol: undefined-fn [unresolved-symbol]",
+		"14:1: lint.test/map shadows glojure.core/map [shadowed-var]",
+		"15:1: redefinition of lint.test/greet, first defined at line 6 [redefined-var]",
+		"17:1: wrong number of args (2) passed to lint.test/greet [arity]",
+		"18:1: wrong number of args (2) passed to glojure.core/inc [arity]",
+		"19:1: wrong number of args (3) passed to glojure.string/join [arity]",
+		"20:2: unable to resolve symbol: nope/thing [unresolved-symbol]",
+		"21:10: unused binding b [unused-binding]",
+		"23:14: unused binding k [unused-binding]",
+		"24:9: error reading input: EOF [error]",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestSourceNoEvaluation(t *testing.T) {
+	diags := lint.Source(value.GlobalEnv, "lint/eval.glj", `(ns lint.eval)
+(def evaluated (atom false))
+(reset! evaluated true)
+`)
+	if len(diags) != 0 {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+	vr := value.FindNamespace(value.NewSymbol("lint.eval")).FindInternedVar(value.NewSymbol("evaluated"))
+	if vr == nil {
+		t.Fatal("expected evaluated to be interned")
+	}
+	if vr.HasRoot() {
+		t.Errorf("expected evaluated to be unbound, got %v", vr.Deref())
+	}
+}
@@ -0,0 +1,57 @@
+package lsp
+
+import "encoding/json"
+
+// The JSON-RPC error codes used by the server.
+const (
+	codeParseError     = -32700
+	codeInvalidParams  = -32602
+	codeMethodNotFound = -32601
+	codeInternalError  = -32603
+	// codeServerNotInitialized is returned for requests received
+	// before initialize.
+	codeServerNotInitialized = -32002
+	// codeInvalidRequest is returned for requests received after
+	// shutdown.
+	codeInvalidRequest = -32600
+)
+
+// message is a JSON-RPC 2.0 request or notification received by the
+// server. A request has an ID, a notification none.
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+// response is a successful response. Its result is written even when
+// it is null.
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result"`
+}
+
+// errorResponse is a response to a request that failed.
+type errorResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Error   *rpcError        `json:"error"`
+}
+
+// notification is a message sent by the server that has no response.
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
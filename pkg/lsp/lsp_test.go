@@ -0,0 +1,477 @@
+package lsp_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/glojurelang/glojure/pkg/glj"
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/lsp"
+)
+
+// workspace holds the files of the workspace of the tests.
+var workspace = map[string]string{
+	"app/util.glj": `(ns app.util)
+
+(defn greet
+  "Returns a greeting."
+  [name]
+  (str "hello " name))
+`,
+	"app/core.glj": `(ns app.core
+  (:require [app.util :as u]))
+
+(def greeting (u/greet "glojure"))
+
+(defn main []
+  (println (u/greet "world") greeting))
+`,
+}
+
+// client is a language client for tests, with its own framing of
+// messages.
+type client struct {
+	t     *testing.T
+	root  string
+	w     io.WriteCloser
+	msgs  chan map[string]interface{}
+	id    int
+	done  chan error
+	notes []map[string]interface{}
+}
+
+// startServer writes the workspace to a temporary directory and starts
+// a server for it, initialized by the client it returns.
+func startServer(t *testing.T) *client {
+	t.Helper()
+	root := t.TempDir()
+	for name, src := range workspace {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+	t.Setenv("LocalAppData", cache)
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &client{t: t, root: root, w: inW, msgs: make(chan map[string]interface{}, 100), done: make(chan error, 1)}
+	// The server is read from as it writes, so that neither side
+	// blocks writing to the pipes.
+	go c.readAll(bufio.NewReader(outR))
+	go func() {
+		err := lsp.NewServer(lang.GlobalEnv).Serve(inR, outW)
+		outW.Close()
+		c.done <- err
+	}()
+	t.Cleanup(func() { inW.Close() })
+
+	res := c.request("initialize", map[string]interface{}{"rootUri": c.uri("")})
+	if _, ok := res["capabilities"]; !ok {
+		t.Fatalf("expected capabilities, got %v", res)
+	}
+	c.notify("initialized", map[string]interface{}{})
+	return c
+}
+
+// uri returns the URI of the named file of the workspace.
+func (c *client) uri(name string) string {
+	path := filepath.ToSlash(filepath.Join(c.root, filepath.FromSlash(name)))
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+func (c *client) write(msg map[string]interface{}) {
+	msg["jsonrpc"] = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// readAll reads the messages of the server until it ends.
+func (c *client) readAll(r *bufio.Reader) {
+	defer close(c.msgs)
+	for {
+		length := -1
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			if line == "" {
+				break
+			}
+			if strings.HasPrefix(line, "Content-Length: ") {
+				length, _ = strconv.Atoi(strings.TrimPrefix(line, "Content-Length: "))
+			}
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return
+		}
+		c.msgs <- msg
+	}
+}
+
+func (c *client) read() map[string]interface{} {
+	c.t.Helper()
+	select {
+	case msg, ok := <-c.msgs:
+		if !ok {
+			c.t.Fatal("the server closed its output")
+		}
+		return msg
+	case <-time.After(30 * time.Second):
+		c.t.Fatal("timed out waiting for the server")
+	}
+	return nil
+}
+
+// call sends a request and returns its response, recording the
+// notifications received before it.
+func (c *client) call(method string, params interface{}) map[string]interface{} {
+	c.t.Helper()
+	c.id++
+	c.write(map[string]interface{}{"id": c.id, "method": method, "params": params})
+	for {
+		msg := c.read()
+		if _, ok := msg["id"]; !ok {
+			c.notes = append(c.notes, msg)
+			continue
+		}
+		if msg["id"] != float64(c.id) {
+			c.t.Fatalf("expected a response to %d, got %v", c.id, msg)
+		}
+		return msg
+	}
+}
+
+// request sends a request and returns its result, which must be an
+// object.
+func (c *client) request(method string, params interface{}) map[string]interface{} {
+	c.t.Helper()
+	msg := c.call(method, params)
+	if msg["error"] != nil {
+		c.t.Fatalf("%s: %v", method, msg["error"])
+	}
+	res, _ := msg["result"].(map[string]interface{})
+	return res
+}
+
+// requestList sends a request and returns its result, which must be
+// an array or null.
+func (c *client) requestList(method string, params interface{}) []interface{} {
+	c.t.Helper()
+	msg := c.call(method, params)
+	if msg["error"] != nil {
+		c.t.Fatalf("%s: %v", method, msg["error"])
+	}
+	res, _ := msg["result"].([]interface{})
+	return res
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.write(map[string]interface{}{"method": method, "params": params})
+}
+
+// diagnostics returns the diagnostics last published for uri, after
+// a round trip to the server.
+func (c *client) diagnostics(uri string) []interface{} {
+	c.t.Helper()
+	c.call("$/ping", nil)
+	for i := len(c.notes) - 1; i >= 0; i-- {
+		msg := c.notes[i]
+		params := msg["params"].(map[string]interface{})
+		if msg["method"] == "textDocument/publishDiagnostics" && params["uri"] == uri {
+			return params["diagnostics"].([]interface{})
+		}
+	}
+	c.t.Fatalf("no diagnostics published for %s", uri)
+	return nil
+}
+
+func (c *client) open(name string) string {
+	uri := c.uri(name)
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "glojure", "version": 1, "text": workspace[name]},
+	})
+	return uri
+}
+
+func position(uri string, line, char int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": line, "character": char},
+	}
+}
+
+// start returns the start of the range of a location, symbol or
+// diagnostic as "line:character".
+func start(v interface{}, key string) string {
+	rng := v.(map[string]interface{})[key].(map[string]interface{})
+	pos := rng["start"].(map[string]interface{})
+	return fmt.Sprintf("%v:%v", pos["line"], pos["character"])
+}
+
+func TestLifecycle(t *testing.T) {
+	c := startServer(t)
+	msg := c.call("workspace/unknown", nil)
+	if e, ok := msg["error"].(map[string]interface{}); !ok || e["code"] != float64(-32601) {
+		t.Errorf("expected a method not found error, got %v", msg)
+	}
+	if msg := c.call("shutdown", nil); msg["error"] != nil {
+		t.Errorf("shutdown: %v", msg["error"])
+	}
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		t.Errorf("expected Serve to return nil, got %v", err)
+	}
+
+	c = startServer(t)
+	c.notify("exit", nil)
+	if err := <-c.done; err == nil {
+		t.Error("expected an error when exiting before shutdown")
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	c := startServer(t)
+	uri := c.uri("app/bad.glj")
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "glojure", "version": 1,
+			"text": "(ns app.bad)\n\n(defn f [x]\n  (undefined-fn x))\n"},
+	})
+	diags := c.diagnostics(uri)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", diags)
+	}
+	d := diags[0].(map[string]interface{})
+	if d["code"] != "unresolved-symbol" || d["severity"] != float64(2) || start(d, "range") != "3:3" {
+		t.Errorf("unexpected diagnostic %v", d)
+	}
+	end := d["range"].(map[string]interface{})["end"].(map[string]interface{})
+	if end["character"] != float64(15) {
+		t.Errorf("expected the range to end after the symbol, got %v", end)
+	}
+
+	c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []interface{}{map[string]interface{}{"text": "(ns app.bad)\n\n(defn f [x]\n  (inc x)\n"}},
+	})
+	c.notify("textDocument/didSave", map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri}})
+	diags = c.diagnostics(uri)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", diags)
+	}
+	d = diags[0].(map[string]interface{})
+	if d["code"] != "error" || d["severity"] != float64(1) {
+		t.Errorf("expected a reader error, got %v", d)
+	}
+
+	c.notify("textDocument/didClose", map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri}})
+	if diags := c.diagnostics(uri); len(diags) != 0 {
+		t.Errorf("expected diagnostics to be cleared, got %v", diags)
+	}
+}
+
+func TestDefinition(t *testing.T) {
+	c := startServer(t)
+	uri := c.open("app/core.glj")
+	if diags := c.diagnostics(uri); len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics %v", diags)
+	}
+
+	locs := c.requestList("textDocument/definition", position(uri, 3, 18))
+	if len(locs) != 1 {
+		t.Fatalf("expected a location, got %v", locs)
+	}
+	loc := locs[0].(map[string]interface{})
+	if loc["uri"] != c.uri("app/util.glj") || start(loc, "range") != "2:6" {
+		t.Errorf("unexpected location of u/greet %v", loc)
+	}
+
+	// glojure.core is extracted from the standard library.
+	locs = c.requestList("textDocument/definition", position(uri, 6, 4))
+	if len(locs) != 1 {
+		t.Fatalf("expected a location, got %v", locs)
+	}
+	loc = locs[0].(map[string]interface{})
+	if u := loc["uri"].(string); !strings.HasSuffix(u, "/glojure/src/glojure/core.glj") {
+		t.Errorf("unexpected location of println %v", loc)
+	}
+	path, _ := url.Parse(loc["uri"].(string))
+	src, err := os.ReadFile(filepath.FromSlash(path.Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.Split(string(src), "\n")[int(loc["range"].(map[string]interface{})["start"].(map[string]interface{})["line"].(float64))]
+	if !strings.Contains(line, "println") {
+		t.Errorf("expected the definition of println, got %q", line)
+	}
+
+	// A namespace.
+	locs = c.requestList("textDocument/definition", position(uri, 1, 14))
+	if len(locs) != 1 || locs[0].(map[string]interface{})["uri"] != c.uri("app/util.glj") {
+		t.Errorf("unexpected location of app.util %v", locs)
+	}
+
+	// Vars of the document itself are found in the workspace.
+	locs = c.requestList("textDocument/definition", position(uri, 6, 36))
+	if len(locs) != 1 || start(locs[0], "range") != "3:5" {
+		t.Errorf("unexpected location of greeting %v", locs)
+	}
+
+	if locs := c.requestList("textDocument/definition", position(uri, 3, 24)); len(locs) != 0 {
+		t.Errorf("expected no location in a string, got %v", locs)
+	}
+}
+
+func TestHover(t *testing.T) {
+	c := startServer(t)
+	uri := c.open("app/core.glj")
+
+	for _, tc := range []struct {
+		line, char int
+		expected   []string
+	}{
+		{3, 18, []string{"app.util/greet", "[name]", "Returns a greeting."}},
+		{6, 4, []string{"glojure.core/println", "[& more]"}},
+		{1, 14, []string{"app.util"}},
+		{0, 1, []string{"glojure.core/ns", "Macro"}},
+	} {
+		res := c.request("textDocument/hover", position(uri, tc.line, tc.char))
+		contents, _ := res["contents"].(map[string]interface{})
+		value, _ := contents["value"].(string)
+		for _, s := range tc.expected {
+			if !strings.Contains(value, s) {
+				t.Errorf("expected the hover at %d:%d to contain %q, got %q", tc.line, tc.char, s, value)
+			}
+		}
+	}
+
+	// A fn defined by the document, which is analyzed but not
+	// evaluated, and words in strings and comments, which aren't
+	// symbols.
+	uri = c.uri("app/scratch.glj")
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "glojure", "version": 1,
+			"text": "(ns app.scratch)\n\n(defn twice\n  \"Doubles x.\"\n  [x]\n  (* 2 x))\n\n(twice \"twice\") ; twice\n"},
+	})
+	c.diagnostics(uri)
+	res := c.request("textDocument/hover", position(uri, 7, 2))
+	contents, _ := res["contents"].(map[string]interface{})
+	value, _ := contents["value"].(string)
+	for _, s := range []string{"app.scratch/twice", "[x]", "Doubles x."} {
+		if !strings.Contains(value, s) {
+			t.Errorf("expected the hover of twice to contain %q, got %q", s, value)
+		}
+	}
+	for _, char := range []int{10, 20} {
+		if res := c.request("textDocument/hover", position(uri, 7, char)); res != nil {
+			t.Errorf("expected no hover at 7:%d, got %v", char, res)
+		}
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	c := startServer(t)
+	uri := c.uri("app/scratch.glj")
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "glojure", "version": 1,
+			"text": "(ns app.scratch\n  (:require [app.util :as u]))\n\n(u/gr)\n(strings.HasPr)\n"},
+	})
+
+	for _, tc := range []struct {
+		line, char int
+		label      string
+		kind       float64
+	}{
+		{3, 5, "u/greet", 3},
+		{4, 14, "strings.HasPrefix", 3},
+		{0, 3, "ns", 3},
+	} {
+		items := c.requestList("textDocument/completion", position(uri, tc.line, tc.char))
+		var found map[string]interface{}
+		for _, item := range items {
+			if item := item.(map[string]interface{}); item["label"] == tc.label {
+				found = item
+			}
+		}
+		if found == nil {
+			t.Errorf("expected a completion %s at %d:%d, got %v", tc.label, tc.line, tc.char, items)
+			continue
+		}
+		if found["kind"] != tc.kind || start(found["textEdit"], "range") != fmt.Sprintf("%d:1", tc.line) {
+			t.Errorf("unexpected completion %v", found)
+		}
+	}
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	c := startServer(t)
+	syms := c.requestList("textDocument/documentSymbol", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": c.uri("app/core.glj")},
+	})
+	var got []string
+	for _, sym := range syms {
+		m := sym.(map[string]interface{})
+		got = append(got, fmt.Sprintf("%v %v %s %s", m["name"], m["kind"], start(m, "range"), start(m, "selectionRange")))
+	}
+	expected := []string{
+		"app.core 3 0:0 0:4",
+		"greeting 13 3:0 3:5",
+		"main 12 5:0 5:6",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected symbols\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestReferences(t *testing.T) {
+	c := startServer(t)
+	uri := c.open("app/util.glj")
+
+	refs := func(includeDeclaration bool) []string {
+		params := position(uri, 2, 7)
+		params["context"] = map[string]interface{}{"includeDeclaration": includeDeclaration}
+		var got []string
+		for _, loc := range c.requestList("textDocument/references", params) {
+			m := loc.(map[string]interface{})
+			got = append(got, strings.TrimPrefix(m["uri"].(string), c.uri(""))+" "+start(m, "range"))
+		}
+		return got
+	}
+	expected := []string{"/app/core.glj 3:15", "/app/core.glj 6:12", "/app/util.glj 2:6"}
+	if got := refs(true); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected references %v, got %v", expected, got)
+	}
+	if got := refs(false); strings.Join(got, ",") != strings.Join(expected[:2], ",") {
+		t.Errorf("expected references %v, got %v", expected[:2], got)
+	}
+}
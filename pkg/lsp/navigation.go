@@ -0,0 +1,355 @@
+package lsp
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/pkgmap"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/repl"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// defKinds maps the names of the macros and special forms that define
+// vars and types to the kinds of the symbols they define.
+var defKinds = map[string]int{
+	"ns":           SymbolNamespace,
+	"def":          SymbolVariable,
+	"defonce":      SymbolVariable,
+	"defn":         SymbolFunction,
+	"defn-":        SymbolFunction,
+	"defmacro":     SymbolFunction,
+	"defmulti":     SymbolFunction,
+	"deftype":      SymbolClass,
+	"defrecord":    SymbolClass,
+	"defprotocol":  SymbolInterface,
+	"definterface": SymbolInterface,
+}
+
+// defName returns the name defined by form, if it is a top-level def
+// form, and the name of the macro or special form that defines it.
+func defName(form interface{}) (*value.Symbol, string) {
+	seq, ok := form.(value.ISeq)
+	if !ok {
+		return nil, ""
+	}
+	head, ok := seq.First().(*value.Symbol)
+	if !ok || (head.Namespace() != "" && head.Namespace() != "glojure.core") {
+		return nil, ""
+	}
+	if _, ok := defKinds[head.Name()]; !ok {
+		return nil, ""
+	}
+	name, ok := value.First(value.Next(seq)).(*value.Symbol)
+	if !ok {
+		return nil, ""
+	}
+	return name, head.Name()
+}
+
+// symbols returns the symbols of the top-level def forms of doc.
+func (s *Server) symbols(doc *document) []DocumentSymbol {
+	syms := []DocumentSymbol{}
+	for _, form := range s.readForms(doc) {
+		name, def := defName(form)
+		if name == nil {
+			continue
+		}
+		rng, ok := formRange(doc.text, form)
+		if !ok {
+			continue
+		}
+		selection, ok := formRange(doc.text, name)
+		if !ok {
+			selection = rng
+		}
+		syms = append(syms, DocumentSymbol{
+			Name:           name.Name(),
+			Detail:         def,
+			Kind:           defKinds[def],
+			Range:          rng,
+			SelectionRange: selection,
+		})
+	}
+	return syms
+}
+
+func (s *Server) documentSymbols(params DocumentSymbolParams) []DocumentSymbol {
+	return s.symbols(s.document(params.TextDocument.URI))
+}
+
+// symbolAt returns the symbol at pos in doc, and the range it spans,
+// or nil if there is none, as in strings, comments and regexes, or if
+// doc can't be read.
+func symbolAt(doc *document, pos Position) (*value.Symbol, Range) {
+	tree, err := reader.ReadSyntax(doc.text)
+	if err != nil {
+		return nil, Range{Start: pos, End: pos}
+	}
+	tok := symbolToken(tree, offset(doc.text, pos))
+	if tok == nil {
+		return nil, Range{Start: pos, End: pos}
+	}
+	return value.NewSymbol(tok.Text), Range{Start: offsetPosition(doc.text, tok.Start), End: offsetPosition(doc.text, tok.End)}
+}
+
+// symbolToken returns the symbol token of the tree n that spans the
+// byte offset off, including its end, or nil.
+func symbolToken(n *reader.SyntaxNode, off int) *reader.SyntaxNode {
+	if off < n.Start || off > n.End {
+		return nil
+	}
+	if n.Kind == reader.SyntaxSymbol {
+		return n
+	}
+	for _, child := range n.Children {
+		if tok := symbolToken(child, off); tok != nil {
+			return tok
+		}
+	}
+	return nil
+}
+
+// definition returns the location of the definition of the var or
+// namespace named by the symbol at the position: that of the :file,
+// :line and :column metadata of the var if it has them, or else that
+// of the def form of a document of the workspace.
+func (s *Server) definition(params TextDocumentPositionParams) []Location {
+	doc := s.document(params.TextDocument.URI)
+	sym, _ := symbolAt(doc, params.Position)
+	if sym == nil {
+		return nil
+	}
+	ns := s.namespace(doc)
+	if vr := repl.ResolveVar(ns, sym); vr != nil {
+		meta := vr.Meta()
+		if file, ok := value.Get(meta, value.KWFile).(string); ok {
+			line, _ := intMeta(meta, value.KWLine)
+			column, _ := intMeta(meta, value.KWColumn)
+			if loc, ok := s.location(file, line, column); ok {
+				return []Location{loc}
+			}
+		}
+		return s.workspaceDefinition(vr.Namespace().Name().Name(), vr.Symbol().Name())
+	}
+	if target := repl.NamespaceNamed(ns, sym); target != nil {
+		name := target.Name().Name()
+		if locs := s.workspaceDefinition(name, name); locs != nil {
+			return locs
+		}
+		file := strings.ReplaceAll(strings.ReplaceAll(name, "-", "_"), ".", "/") + ".glj"
+		if loc, ok := s.location(file, 1, 1); ok {
+			return []Location{loc}
+		}
+	}
+	return nil
+}
+
+// workspaceDefinition returns the location of the def form of name in
+// the documents of the workspace in the namespace nsName.
+func (s *Server) workspaceDefinition(nsName, name string) []Location {
+	for _, doc := range s.workspace() {
+		if s.namespaceName(doc) != nsName {
+			continue
+		}
+		for _, sym := range s.symbols(doc) {
+			if sym.Name == name {
+				return []Location{{URI: doc.uri, Range: sym.SelectionRange}}
+			}
+		}
+	}
+	return nil
+}
+
+// location returns the location of the line and column of file, as
+// named by the :file metadata of a var: an absolute path, a path
+// relative to the root of the workspace or the current directory, or
+// a path on the load path. Files on the load path that aren't in a
+// directory, such as those of the standard library, are extracted to
+// the user's cache directory so that editors can open them.
+func (s *Server) location(file string, line, column int) (Location, bool) {
+	var dirs []string
+	if s.root != "" {
+		dirs = append(dirs, s.root)
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, cwd)
+	}
+
+	var path string
+	var src []byte
+	if filepath.IsAbs(file) {
+		var err error
+		if src, err = os.ReadFile(file); err != nil {
+			return Location{}, false
+		}
+		path = file
+	} else {
+		for _, dir := range dirs {
+			p := filepath.Join(dir, filepath.FromSlash(file))
+			if b, err := os.ReadFile(p); err == nil {
+				path, src = p, b
+				break
+			}
+		}
+	}
+	if path == "" {
+		b, err := runtime.ReadSourceFile(file)
+		if err != nil {
+			return Location{}, false
+		}
+		if path, err = extract(file, b); err != nil {
+			return Location{}, false
+		}
+		src = b
+	}
+	pos := position(string(src), line, column)
+	return Location{URI: pathToURI(path), Range: Range{Start: pos, End: pos}}, true
+}
+
+// extract writes src, the content of file on the load path, to the
+// user's cache directory, unless it is already there, and returns
+// the path it is written to.
+func extract(file string, src []byte) (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(cache, "glojure", "src", filepath.FromSlash(file))
+	if b, err := os.ReadFile(path); err == nil && bytes.Equal(b, src) {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, src, 0644)
+}
+
+// hover returns the documentation of the var, namespace or Go export
+// named by the symbol at the position, or nil.
+func (s *Server) hover(params TextDocumentPositionParams) *Hover {
+	doc := s.document(params.TextDocument.URI)
+	sym, rng := symbolAt(doc, params.Position)
+	if sym == nil {
+		return nil
+	}
+	ns := s.namespace(doc)
+
+	var sb strings.Builder
+	if vr := repl.ResolveVar(ns, sym); vr != nil {
+		meta := vr.Meta()
+		fmt.Fprintf(&sb, "```clojure\n%s/%s\n", vr.Namespace().Name().Name(), vr.Symbol().Name())
+		for args := value.Seq(value.Get(meta, value.KWArglists)); args != nil; args = args.Next() {
+			fmt.Fprintf(&sb, "%s\n", value.PrintString(args.First()))
+		}
+		sb.WriteString("```\n")
+		if vr.IsMacro() {
+			sb.WriteString("\nMacro\n")
+		}
+		if doc, ok := value.Get(meta, value.KWDoc).(string); ok {
+			fmt.Fprintf(&sb, "\n%s\n", doc)
+		}
+	} else if target := repl.NamespaceNamed(ns, sym); target != nil {
+		fmt.Fprintf(&sb, "```clojure\n%s\n```\n", target.Name().Name())
+		if doc, ok := value.Get(target.Meta(), value.KWDoc).(string); ok {
+			fmt.Fprintf(&sb, "\n%s\n", doc)
+		}
+	} else if v, ok := pkgmap.Get(sym.FullName()); ok {
+		pkg, name := pkgmap.SplitExport(sym.FullName())
+		export := pkgmap.UnmungePkg(pkg) + "." + name
+		if t, ok := v.(reflect.Type); ok {
+			fmt.Fprintf(&sb, "```go\ntype %s %s\n```\n", export, t.Kind())
+		} else {
+			fmt.Fprintf(&sb, "```go\n%s %T\n```\n", export, v)
+		}
+	} else {
+		return nil
+	}
+	return &Hover{Contents: MarkupContent{Kind: "markdown", Value: sb.String()}, Range: &rng}
+}
+
+// completionKinds maps the types of completions to the kinds of
+// completion items.
+var completionKinds = map[string]int{
+	"function":     CompletionFunction,
+	"macro":        CompletionFunction,
+	"var":          CompletionVariable,
+	"class":        CompletionClass,
+	"namespace":    CompletionModule,
+	"package":      CompletionModule,
+	"keyword":      CompletionKeyword,
+	"special-form": CompletionKeyword,
+}
+
+// completion returns the completions of the symbol or keyword that
+// ends at the position, as the REPL completes them.
+func (s *Server) completion(params TextDocumentPositionParams) []CompletionItem {
+	doc := s.document(params.TextDocument.URI)
+	prefix, rng := tokenAt(doc.text, params.Position, true)
+	items := []CompletionItem{}
+	for _, c := range repl.Complete(s.namespace(doc), prefix, doc.text) {
+		detail := c.Type
+		if c.NS != "" {
+			detail = c.NS
+		}
+		items = append(items, CompletionItem{
+			Label:    c.Candidate,
+			Kind:     completionKinds[c.Type],
+			Detail:   detail,
+			TextEdit: &TextEdit{Range: rng, NewText: c.Candidate},
+		})
+	}
+	return items
+}
+
+// references returns the locations of the symbols of the documents
+// of the workspace that resolve to the var named by the symbol at the
+// position.
+func (s *Server) references(params ReferenceParams) []Location {
+	doc := s.document(params.TextDocument.URI)
+	sym, _ := symbolAt(doc, params.Position)
+	if sym == nil {
+		return nil
+	}
+	target := repl.ResolveVar(s.namespace(doc), sym)
+	if target == nil {
+		return nil
+	}
+
+	locs := []Location{}
+	for _, doc := range s.workspace() {
+		ns := s.namespace(doc)
+		for _, form := range s.readForms(doc) {
+			var decl *value.Symbol
+			if !params.Context.IncludeDeclaration {
+				decl, _ = defName(form)
+			}
+			walkSymbols(form, func(sym *value.Symbol) {
+				if sym == decl || repl.ResolveVar(ns, sym) != target {
+					return
+				}
+				if rng, ok := formRange(doc.text, sym); ok {
+					locs = append(locs, Location{URI: doc.uri, Range: rng})
+				}
+			})
+		}
+	}
+	return locs
+}
+
+// walkSymbols calls f with each symbol of form, in order.
+func walkSymbols(form interface{}, f func(*value.Symbol)) {
+	switch form := form.(type) {
+	case *value.Symbol:
+		f(form)
+	case value.IPersistentCollection:
+		for seq := value.Seq(form); seq != nil; seq = seq.Next() {
+			walkSymbols(seq.First(), f)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package lsp
+
+// The types of the Language Server Protocol used by the server, with
+// only the fields it reads or writes. See
+// https://microsoft.github.io/language-server-protocol/specification.
+
+type (
+	// Position is a zero-based line and character offset in UTF-16
+	// code units.
+	Position struct {
+		Line      int `json:"line"`
+		Character int `json:"character"`
+	}
+
+	Range struct {
+		Start Position `json:"start"`
+		End   Position `json:"end"`
+	}
+
+	Location struct {
+		URI   string `json:"uri"`
+		Range Range  `json:"range"`
+	}
+
+	TextDocumentIdentifier struct {
+		URI string `json:"uri"`
+	}
+
+	TextDocumentItem struct {
+		URI        string `json:"uri"`
+		LanguageID string `json:"languageId"`
+		Version    int    `json:"version"`
+		Text       string `json:"text"`
+	}
+
+	TextDocumentPositionParams struct {
+		TextDocument TextDocumentIdentifier `json:"textDocument"`
+		Position     Position               `json:"position"`
+	}
+
+	WorkspaceFolder struct {
+		URI  string `json:"uri"`
+		Name string `json:"name"`
+	}
+
+	InitializeParams struct {
+		RootURI          string            `json:"rootUri"`
+		RootPath         string            `json:"rootPath"`
+		WorkspaceFolders []WorkspaceFolder `json:"workspaceFolders"`
+	}
+
+	DidOpenTextDocumentParams struct {
+		TextDocument TextDocumentItem `json:"textDocument"`
+	}
+
+	DidChangeTextDocumentParams struct {
+		TextDocument   TextDocumentIdentifier `json:"textDocument"`
+		ContentChanges []struct {
+			Text string `json:"text"`
+		} `json:"contentChanges"`
+	}
+
+	DidSaveTextDocumentParams struct {
+		TextDocument TextDocumentIdentifier `json:"textDocument"`
+		Text         *string                `json:"text"`
+	}
+
+	DidCloseTextDocumentParams struct {
+		TextDocument TextDocumentIdentifier `json:"textDocument"`
+	}
+
+	ReferenceParams struct {
+		TextDocumentPositionParams
+		Context struct {
+			IncludeDeclaration bool `json:"includeDeclaration"`
+		} `json:"context"`
+	}
+
+	DocumentSymbolParams struct {
+		TextDocument TextDocumentIdentifier `json:"textDocument"`
+	}
+
+	Diagnostic struct {
+		Range    Range  `json:"range"`
+		Severity int    `json:"severity"`
+		Code     string `json:"code,omitempty"`
+		Source   string `json:"source"`
+		Message  string `json:"message"`
+	}
+
+	PublishDiagnosticsParams struct {
+		URI         string       `json:"uri"`
+		Diagnostics []Diagnostic `json:"diagnostics"`
+	}
+
+	MarkupContent struct {
+		Kind  string `json:"kind"`
+		Value string `json:"value"`
+	}
+
+	Hover struct {
+		Contents MarkupContent `json:"contents"`
+		Range    *Range        `json:"range,omitempty"`
+	}
+
+	TextEdit struct {
+		Range   Range  `json:"range"`
+		NewText string `json:"newText"`
+	}
+
+	CompletionItem struct {
+		Label    string    `json:"label"`
+		Kind     int       `json:"kind,omitempty"`
+		Detail   string    `json:"detail,omitempty"`
+		TextEdit *TextEdit `json:"textEdit,omitempty"`
+	}
+
+	DocumentSymbol struct {
+		Name           string `json:"name"`
+		Detail         string `json:"detail,omitempty"`
+		Kind           int    `json:"kind"`
+		Range          Range  `json:"range"`
+		SelectionRange Range  `json:"selectionRange"`
+	}
+)
+
+// Diagnostic severities.
+const (
+	SeverityError   = 1
+	SeverityWarning = 2
+)
+
+// Completion item kinds.
+const (
+	CompletionFunction = 3
+	CompletionVariable = 6
+	CompletionClass    = 7
+	CompletionModule   = 9
+	CompletionKeyword  = 14
+)
+
+// Symbol kinds.
+const (
+	SymbolNamespace = 3
+	SymbolClass     = 5
+	SymbolInterface = 11
+	SymbolFunction  = 12
+	SymbolVariable  = 13
+)
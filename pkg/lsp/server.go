@@ -0,0 +1,457 @@
+// Package lsp implements a Language Server Protocol server
+// (https://microsoft.github.io/language-server-protocol) for Glojure,
+// so that editors can report problems in, navigate and complete
+// Glojure code.
+//
+// Messages are JSON-RPC 2.0 requests and notifications, framed by a
+// Content-Length header, exchanged over a pair of streams, typically
+// the standard input and output of glj lsp. Documents are analyzed as
+// glj lint analyzes them: ns, require and macro definition forms are
+// evaluated, so that the vars of a document and of the namespaces it
+// requires can be resolved, and other forms are only analyzed.
+//
+// The server supports full text synchronization, diagnostics when a
+// document is opened or saved, go to definition, hover, completion,
+// document symbols and find references within the workspace.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/glojurelang/glojure/internal/framing"
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/lint"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// errNoShutdown is returned by Serve when the client exits without
+// shutting the server down first.
+var errNoShutdown = errors.New("exit before shutdown")
+
+// Server is a language server analyzing code in an environment.
+type Server struct {
+	env value.Environment
+	w   io.Writer
+
+	// root is the directory of the workspace, if any.
+	root string
+	// docs holds the open documents, by URI.
+	docs map[string]*document
+
+	initialized bool
+	shutdown    bool
+}
+
+// document is the text of a source file.
+type document struct {
+	uri  string
+	path string
+	text string
+}
+
+// NewServer returns a server that analyzes code in env.
+func NewServer(env value.Environment) *Server {
+	return &Server{env: env, docs: map[string]*document{}}
+}
+
+// Serve reads messages from r and writes responses and notifications
+// to w until the client sends the exit notification or r ends. It
+// returns an error if r fails, or if the client exits without
+// requesting a shutdown first.
+//
+// Output written to *out* by the code evaluated while analyzing
+// documents goes to standard error, so that it doesn't corrupt the
+// messages written to w.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.w = w
+	value.PushThreadBindings(value.NewMap(value.VarOut, os.Stderr))
+	defer value.PopThreadBindings()
+
+	br := bufio.NewReader(r)
+	for {
+		body, err := framing.Read(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			if err := s.reply(nil, nil, &rpcError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errNoShutdown
+			}
+			return nil
+		}
+		result, rpcErr := s.handle(&msg)
+		if msg.ID == nil {
+			// Notifications have no response.
+			continue
+		}
+		if err := s.reply(msg.ID, result, rpcErr); err != nil {
+			return err
+		}
+	}
+}
+
+// reply writes the response to the request with the given ID.
+func (s *Server) reply(id *json.RawMessage, result interface{}, rpcErr *rpcError) error {
+	if rpcErr != nil {
+		return framing.Write(s.w, errorResponse{JSONRPC: "2.0", ID: id, Error: rpcErr})
+	}
+	return framing.Write(s.w, response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+// notify writes a notification to the client.
+func (s *Server) notify(method string, params interface{}) error {
+	return framing.Write(s.w, notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// handle handles a request or notification, converting a panic to an
+// internal error.
+func (s *Server) handle(msg *message) (result interface{}, rpcErr *rpcError) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, rpcErr = nil, &rpcError{Code: codeInternalError, Message: fmt.Sprint(r)}
+		}
+	}()
+
+	switch {
+	case !s.initialized && msg.Method != "initialize":
+		if msg.ID == nil {
+			return nil, nil
+		}
+		return nil, &rpcError{Code: codeServerNotInitialized, Message: "server not initialized"}
+	case s.shutdown:
+		return nil, &rpcError{Code: codeInvalidRequest, Message: "server is shut down"}
+	}
+
+	var err error
+	switch msg.Method {
+	case "initialize":
+		var params InitializeParams
+		if err = unmarshalParams(msg.Params, &params); err == nil {
+			result = s.initialize(params)
+		}
+	case "initialized":
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err = unmarshalParams(msg.Params, &params); err == nil {
+			err = s.didOpen(params)
+		}
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err = unmarshalParams(msg.Params, &params); err == nil {
+			s.didChange(params)
+		}
+	case "textDocument/didSave":
+		var params DidSaveTextDocumentParams
+		if err = unmarshalParams(msg.Params, &params); err == nil {
+			err = s.didSave(params)
+		}
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err = unmarshalParams(msg.Params, &params); err == nil {
+			err = s.didClose(params)
+		}
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err = unmarshalParams(msg.Params, &params); err == nil {
+			result = s.definition(params)
+		}
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err = unmarshalParams(msg.Params, &params); err == nil {
+			result = s.hover(params)
+		}
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err = unmarshalParams(msg.Params, &params); err == nil {
+			result = s.completion(params)
+		}
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err = unmarshalParams(msg.Params, &params); err == nil {
+			result = s.documentSymbols(params)
+		}
+	case "textDocument/references":
+		var params ReferenceParams
+		if err = unmarshalParams(msg.Params, &params); err == nil {
+			result = s.references(params)
+		}
+	default:
+		// Notifications that aren't supported, such as
+		// $/cancelRequest, are ignored.
+		if msg.ID == nil {
+			return nil, nil
+		}
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+	}
+	if err != nil {
+		var rpcErr *rpcError
+		if errors.As(err, &rpcErr) {
+			return nil, rpcErr
+		}
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	return result, nil
+}
+
+// unmarshalParams decodes the parameters of a message into v.
+func unmarshalParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// initialize records the root of the workspace, adding it to the load
+// path so that the namespaces of the workspace can be required, and
+// returns the capabilities of the server.
+func (s *Server) initialize(params InitializeParams) interface{} {
+	s.initialized = true
+	switch {
+	case params.RootURI != "":
+		s.root = uriToPath(params.RootURI)
+	case len(params.WorkspaceFolders) > 0:
+		s.root = uriToPath(params.WorkspaceFolders[0].URI)
+	case params.RootPath != "":
+		s.root = params.RootPath
+	}
+	if s.root != "" {
+		if cwd, err := os.Getwd(); err != nil || !sameDir(cwd, s.root) {
+			runtime.AddLoadPath(os.DirFS(s.root))
+		}
+	}
+
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync": map[string]interface{}{
+				"openClose": true,
+				// Documents are synchronized by sending their full
+				// text.
+				"change": 1,
+				"save":   map[string]interface{}{"includeText": false},
+			},
+			"definitionProvider": true,
+			"hoverProvider":      true,
+			"completionProvider": map[string]interface{}{
+				"triggerCharacters": []string{"/", ".", ":"},
+			},
+			"documentSymbolProvider": true,
+			"referencesProvider":     true,
+		},
+		"serverInfo": map[string]interface{}{"name": "glj"},
+	}
+}
+
+// sameDir reports whether two paths name the same directory.
+func sameDir(a, b string) bool {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	return err == nil && os.SameFile(ai, bi)
+}
+
+func (s *Server) didOpen(params DidOpenTextDocumentParams) error {
+	item := params.TextDocument
+	doc := &document{uri: item.URI, path: uriToPath(item.URI), text: item.Text}
+	s.docs[item.URI] = doc
+	return s.publishDiagnostics(doc)
+}
+
+func (s *Server) didChange(params DidChangeTextDocumentParams) {
+	doc := s.docs[params.TextDocument.URI]
+	if doc == nil || len(params.ContentChanges) == 0 {
+		return
+	}
+	doc.text = params.ContentChanges[len(params.ContentChanges)-1].Text
+}
+
+func (s *Server) didSave(params DidSaveTextDocumentParams) error {
+	doc := s.docs[params.TextDocument.URI]
+	if doc == nil {
+		return nil
+	}
+	if params.Text != nil {
+		doc.text = *params.Text
+	}
+	return s.publishDiagnostics(doc)
+}
+
+func (s *Server) didClose(params DidCloseTextDocumentParams) error {
+	uri := params.TextDocument.URI
+	if s.docs[uri] == nil {
+		return nil
+	}
+	delete(s.docs, uri)
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: uri, Diagnostics: []Diagnostic{}})
+}
+
+// publishDiagnostics analyzes doc and sends the problems found to the
+// client. Reader and analysis errors are errors, the problems
+// reported by the other checks of glj lint warnings.
+func (s *Server) publishDiagnostics(doc *document) error {
+	diags := []Diagnostic{}
+	for _, d := range lint.Source(s.env, doc.path, doc.text) {
+		start := position(doc.text, d.Line, d.Column)
+		_, rng := tokenAt(doc.text, start, false)
+		if rng.Start != start || rng.End == start {
+			// The problem is with a form rather than a symbol.
+			rng = Range{Start: start, End: Position{Line: start.Line, Character: start.Character + 1}}
+		}
+		severity := SeverityWarning
+		if d.Check == lint.CheckError {
+			severity = SeverityError
+		}
+		diags = append(diags, Diagnostic{
+			Range:    rng,
+			Severity: severity,
+			Code:     d.Check,
+			Source:   "glj",
+			Message:  d.Message,
+		})
+	}
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: doc.uri, Diagnostics: diags})
+}
+
+// document returns the document with the given URI: its open text,
+// or else the content of its file.
+func (s *Server) document(uri string) *document {
+	if doc := s.docs[uri]; doc != nil {
+		return doc
+	}
+	path := uriToPath(uri)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return &document{uri: uri, path: path}
+	}
+	return &document{uri: uri, path: path, text: string(src)}
+}
+
+// workspace returns the documents of the workspace: the open
+// documents and the other .glj files under its root, ordered by URI.
+func (s *Server) workspace() []*document {
+	var docs []*document
+	for _, doc := range s.docs {
+		docs = append(docs, doc)
+	}
+	if s.root != "" {
+		filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if path != s.root && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) != ".glj" {
+				return nil
+			}
+			if uri := pathToURI(path); s.docs[uri] == nil {
+				docs = append(docs, s.document(uri))
+			}
+			return nil
+		})
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].uri < docs[j].uri })
+	return docs
+}
+
+// readForms returns the forms of doc that can be read, in order.
+func (s *Server) readForms(doc *document) []interface{} {
+	var forms []interface{}
+	rdr := reader.New(strings.NewReader(doc.text), reader.WithFilename(doc.path), reader.WithGetCurrentNS(s.env.CurrentNamespace))
+	for {
+		form, err := s.readForm(rdr)
+		if err != nil {
+			return forms
+		}
+		forms = append(forms, form)
+	}
+}
+
+// readForm reads a form, converting a panic to an error.
+func (s *Server) readForm(rdr *reader.Reader) (form interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return rdr.ReadOne()
+}
+
+// namespaceName returns the name of the namespace that the ns or
+// in-ns form of doc declares, or "".
+func (s *Server) namespaceName(doc *document) string {
+	for _, form := range s.readForms(doc) {
+		seq, ok := form.(value.ISeq)
+		if !ok {
+			continue
+		}
+		head, ok := seq.First().(*value.Symbol)
+		if !ok {
+			continue
+		}
+		name := value.First(value.Next(seq))
+		switch head.Name() {
+		case "ns":
+		case "in-ns":
+			// (in-ns 'name) reads as (in-ns (quote name)).
+			if q, ok := name.(value.ISeq); ok {
+				name = value.First(value.Next(q))
+			}
+		default:
+			continue
+		}
+		if sym, ok := name.(*value.Symbol); ok {
+			return sym.Name()
+		}
+		return ""
+	}
+	return ""
+}
+
+// namespace returns the namespace of doc, analyzing doc first if it
+// hasn't been loaded. Documents without an ns form are in the user
+// namespace.
+func (s *Server) namespace(doc *document) *value.Namespace {
+	name := s.namespaceName(doc)
+	if name == "" {
+		name = "user"
+	}
+	sym := value.NewSymbol(name)
+	if ns := value.FindNamespace(sym); ns != nil {
+		return ns
+	}
+	lint.Source(s.env, doc.path, doc.text)
+	if ns := value.FindNamespace(sym); ns != nil {
+		return ns
+	}
+	return s.env.CurrentNamespace()
+}
@@ -0,0 +1,195 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+)
+
+// uriToPath returns the path of a file URI, or uri itself if it is not
+// one.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	// A Windows path has a leading slash before its volume, as in
+	// file:///C:/src.
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
+
+// pathToURI returns the file URI of an absolute path.
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// lineAt returns the text of the zero-based line of text, without its
+// line terminator.
+func lineAt(text string, line int) string {
+	for i := 0; i < line; i++ {
+		j := strings.IndexByte(text, '\n')
+		if j < 0 {
+			return ""
+		}
+		text = text[j+1:]
+	}
+	if j := strings.IndexByte(text, '\n'); j >= 0 {
+		text = text[:j]
+	}
+	return strings.TrimSuffix(text, "\r")
+}
+
+// utf16Column returns the number of UTF-16 code units of the first n
+// runes of line.
+func utf16Column(line string, n int) int {
+	col := 0
+	for _, r := range line {
+		if n == 0 {
+			break
+		}
+		n--
+		l := utf16.RuneLen(r)
+		if l < 0 {
+			l = 1
+		}
+		col += l
+	}
+	return col + n
+}
+
+// runeColumn returns the number of runes of line that the first char
+// UTF-16 code units encode.
+func runeColumn(line string, char int) int {
+	n := 0
+	for _, r := range line {
+		if char <= 0 {
+			break
+		}
+		l := utf16.RuneLen(r)
+		if l < 0 {
+			l = 1
+		}
+		char -= l
+		n++
+	}
+	return n
+}
+
+// position converts a one-based line and rune column of the reader
+// into a position in text.
+func position(text string, line, column int) Position {
+	if line < 1 {
+		return Position{}
+	}
+	if column < 1 {
+		column = 1
+	}
+	return Position{Line: line - 1, Character: utf16Column(lineAt(text, line-1), column-1)}
+}
+
+// offset returns the byte offset of pos in text.
+func offset(text string, pos Position) int {
+	off := 0
+	for i := 0; i < pos.Line; i++ {
+		j := strings.IndexByte(text[off:], '\n')
+		if j < 0 {
+			return len(text)
+		}
+		off += j + 1
+	}
+	line := []rune(lineAt(text, pos.Line))
+	return off + len(string(line[:runeColumn(string(line), pos.Character)]))
+}
+
+// offsetPosition returns the position of the byte offset off in text.
+func offsetPosition(text string, off int) Position {
+	start := strings.LastIndexByte(text[:off], '\n') + 1
+	return Position{
+		Line:      strings.Count(text[:off], "\n"),
+		Character: utf16Column(text[start:off], utf8.RuneCountInString(text[start:off])),
+	}
+}
+
+// formRange returns the range of text that a form read from it spans,
+// from its metadata, and whether it has any.
+func formRange(text string, form interface{}) (Range, bool) {
+	meta := formMeta(form)
+	line, ok := intMeta(meta, value.KWLine)
+	if !ok {
+		return Range{}, false
+	}
+	column, _ := intMeta(meta, value.KWColumn)
+	endLine, ok := intMeta(meta, value.KWEndLine)
+	if !ok {
+		endLine = line
+	}
+	// The end column of the reader is that of the last rune of the
+	// form, and the end of a range is exclusive.
+	endColumn, _ := intMeta(meta, value.KWEndColumn)
+	return Range{Start: position(text, line, column), End: position(text, endLine, endColumn+1)}, true
+}
+
+// formMeta returns the metadata of form, or nil.
+func formMeta(form interface{}) value.IPersistentMap {
+	if m, ok := form.(value.IMeta); ok {
+		return m.Meta()
+	}
+	return nil
+}
+
+// intMeta returns the number mapped to kw in meta.
+func intMeta(meta value.IPersistentMap, kw value.Keyword) (int, bool) {
+	switch n := value.Get(meta, kw).(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+// isDelimiter reports whether r ends a symbol or keyword.
+func isDelimiter(r rune) bool {
+	return strings.ContainsRune(" \t\r\n,;()[]{}\"'`~@^\\", r)
+}
+
+// tokenAt returns the symbol or keyword of text at pos, and the range
+// it spans. If end is true, the token ends at pos, as the prefix of a
+// completion does.
+func tokenAt(text string, pos Position, end bool) (string, Range) {
+	line := []rune(lineAt(text, pos.Line))
+	at := runeColumn(string(line), pos.Character)
+	if at > len(line) {
+		at = len(line)
+	}
+	start, stop := at, at
+	for start > 0 && !isDelimiter(line[start-1]) {
+		start--
+	}
+	if !end {
+		for stop < len(line) && !isDelimiter(line[stop]) {
+			stop++
+		}
+	}
+	// #'x and #(...) leave a # before the symbol.
+	for start < stop && line[start] == '#' {
+		start++
+	}
+	rng := Range{
+		Start: Position{Line: pos.Line, Character: utf16Column(string(line), start)},
+		End:   Position{Line: pos.Line, Character: utf16Column(string(line), stop)},
+	}
+	return string(line[start:stop]), rng
+}
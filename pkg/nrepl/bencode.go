@@ -0,0 +1,167 @@
+package nrepl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Messages are encoded in bencode (http://bittorrent.org/beps/bep_0003.html).
+// Decoded byte strings are strings, integers are int64s, lists are
+// []interface{}s and dictionaries are map[string]interface{}s.
+
+// errMalformed is returned when the input is not valid bencode.
+var errMalformed = errors.New("malformed bencode")
+
+// maxStringLen is the length of the longest byte string decode reads,
+// so that a client can't make the server allocate more.
+const maxStringLen = 64 << 20
+
+// encode writes the bencode encoding of v to w. v may be a string,
+// []byte, int, int64, bool (encoded as 0 or 1), []string,
+// []interface{} or map[string]interface{}. Dictionary keys are written
+// in sorted order.
+func encode(w *bufio.Writer, v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		w.WriteString(strconv.Itoa(len(v)))
+		w.WriteByte(':')
+		w.WriteString(v)
+	case []byte:
+		w.WriteString(strconv.Itoa(len(v)))
+		w.WriteByte(':')
+		w.Write(v)
+	case int:
+		fmt.Fprintf(w, "i%de", v)
+	case int64:
+		fmt.Fprintf(w, "i%de", v)
+	case bool:
+		if v {
+			w.WriteString("i1e")
+		} else {
+			w.WriteString("i0e")
+		}
+	case []string:
+		w.WriteByte('l')
+		for _, s := range v {
+			if err := encode(w, s); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('e')
+	case []interface{}:
+		w.WriteByte('l')
+		for _, x := range v {
+			if err := encode(w, x); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('e')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.WriteByte('d')
+		for _, k := range keys {
+			if err := encode(w, k); err != nil {
+				return err
+			}
+			if err := encode(w, v[k]); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('e')
+	default:
+		return fmt.Errorf("can't encode %T in bencode", v)
+	}
+	return nil
+}
+
+// decode reads one bencoded value from r.
+func decode(r *bufio.Reader) (interface{}, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c == 'i':
+		s, err := r.ReadString('e')
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		n, err := strconv.ParseInt(s[:len(s)-1], 10, 64)
+		if err != nil {
+			return nil, errMalformed
+		}
+		return n, nil
+	case c == 'l':
+		list := []interface{}{}
+		for {
+			if c, err := r.ReadByte(); err != nil {
+				return nil, unexpectedEOF(err)
+			} else if c == 'e' {
+				return list, nil
+			}
+			r.UnreadByte()
+			x, err := decode(r)
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			list = append(list, x)
+		}
+	case c == 'd':
+		dict := map[string]interface{}{}
+		for {
+			if c, err := r.ReadByte(); err != nil {
+				return nil, unexpectedEOF(err)
+			} else if c == 'e' {
+				return dict, nil
+			}
+			r.UnreadByte()
+			k, err := decode(r)
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, errMalformed
+			}
+			if dict[key], err = decode(r); err != nil {
+				return nil, unexpectedEOF(err)
+			}
+		}
+	case c >= '0' && c <= '9':
+		r.UnreadByte()
+		s, err := r.ReadString(':')
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n < 0 {
+			return nil, errMalformed
+		}
+		if n > maxStringLen {
+			return nil, fmt.Errorf("bencode string of %d bytes exceeds the limit of %d", n, maxStringLen)
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		return string(buf), nil
+	}
+	return nil, errMalformed
+}
+
+// unexpectedEOF converts io.EOF within a value to
+// io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package nrepl
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBencode(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		encoded string
+		decoded interface{}
+	}{
+		{value: "spam", encoded: "4:spam"},
+		{value: "", encoded: "0:"},
+		{value: int64(-3), encoded: "i-3e"},
+		{value: []interface{}{"a", int64(1)}, encoded: "l1:ai1ee"},
+		{value: []string{"done"}, encoded: "l4:donee", decoded: []interface{}{"done"}},
+		{
+			value:   map[string]interface{}{"op": "eval", "code": "(+ 1 2)", "ids": []interface{}{}},
+			encoded: "d4:code7:(+ 1 2)3:idsle2:op4:evale",
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		if err := encode(w, tt.value); err != nil {
+			t.Fatal(err)
+		}
+		w.Flush()
+		if buf.String() != tt.encoded {
+			t.Errorf("encode(%v): expected %q, got %q", tt.value, tt.encoded, buf.String())
+		}
+
+		decoded, err := decode(bufio.NewReader(strings.NewReader(tt.encoded)))
+		if err != nil {
+			t.Errorf("decode(%q): %v", tt.encoded, err)
+			continue
+		}
+		expected := tt.decoded
+		if expected == nil {
+			expected = tt.value
+		}
+		if !reflect.DeepEqual(decoded, expected) {
+			t.Errorf("decode(%q): expected %#v, got %#v", tt.encoded, expected, decoded)
+		}
+	}
+
+	for _, bad := range []string{"x", "i12", "5:abc", "d1:ae", "di1e1:ae", "l", "67108865:", "9223372036854775807:"} {
+		if _, err := decode(bufio.NewReader(strings.NewReader(bad))); err == nil {
+			t.Errorf("decode(%q): expected an error", bad)
+		}
+	}
+}
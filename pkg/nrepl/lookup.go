@@ -0,0 +1,99 @@
+package nrepl
+
+import (
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/repl"
+)
+
+// completions replies to a completions message with the completions
+// of its prefix in its namespace. Keywords are also completed from its
+// context, the form being edited, which CIDER sends.
+func (s *Server) completions(t *transport, msg map[string]interface{}, sess *session) {
+	ns := sess.namespace(msg)
+	if ns == nil {
+		t.done(msg, "error", "namespace-not-found")
+		return
+	}
+	prefix, _ := msg["prefix"].(string)
+	input, _ := msg["context"].(string)
+	candidates := []interface{}{}
+	for _, c := range repl.Complete(ns, prefix, input) {
+		candidate := map[string]interface{}{"candidate": c.Candidate, "type": c.Type}
+		if c.NS != "" {
+			candidate["ns"] = c.NS
+		}
+		candidates = append(candidates, candidate)
+	}
+	t.reply(msg, map[string]interface{}{"completions": candidates, "status": []string{"done"}})
+}
+
+// lookup replies to a lookup or info message with the metadata of the
+// var or namespace named by its sym in its namespace. The metadata is
+// in an info dictionary for lookup, and in the reply itself for info,
+// as CIDER expects.
+func (s *Server) lookup(t *transport, msg map[string]interface{}, sess *session) {
+	ns := sess.namespace(msg)
+	if ns == nil {
+		t.done(msg, "error", "namespace-not-found")
+		return
+	}
+	name, _ := msg["sym"].(string)
+	if name == "" {
+		name, _ = msg["symbol"].(string)
+	}
+	info := symbolInfo(ns, name)
+	if msg["op"] == "lookup" {
+		t.reply(msg, map[string]interface{}{"info": info, "status": []string{"done"}})
+		return
+	}
+	if len(info) == 0 {
+		t.done(msg, "no-info")
+		return
+	}
+	info["status"] = []string{"done"}
+	t.reply(msg, info)
+}
+
+// symbolInfo returns the metadata of the var or namespace named by
+// name in ns, with string values, or an empty map.
+func symbolInfo(ns *lang.Namespace, name string) map[string]interface{} {
+	info := map[string]interface{}{}
+	if name == "" {
+		return info
+	}
+	sym := lang.NewSymbol(name)
+
+	var meta lang.IPersistentMap
+	if vr := repl.ResolveVar(ns, sym); vr != nil {
+		meta = vr.Meta()
+		info["ns"] = vr.Namespace().Name().Name()
+		info["name"] = vr.Symbol().Name()
+		if vr.IsMacro() {
+			info["macro"] = "true"
+		}
+	} else if target := repl.NamespaceNamed(ns, sym); target != nil {
+		meta = target.Meta()
+		info["ns"] = target.Name().Name()
+		info["name"] = target.Name().Name()
+	} else {
+		return info
+	}
+	if doc, ok := lang.Get(meta, lang.KWDoc).(string); ok {
+		info["doc"] = doc
+	}
+	if arglists := lang.Get(meta, lang.KWArglists); arglists != nil {
+		info["arglists-str"] = lang.PrintString(arglists)
+	}
+	if file, ok := lang.Get(meta, lang.KWFile).(string); ok {
+		info["file"] = file
+	}
+	for k, kw := range map[string]lang.Keyword{"line": lang.KWLine, "column": lang.KWColumn} {
+		switch n := lang.Get(meta, kw).(type) {
+		case int:
+			info[k] = n
+		case int64:
+			info[k] = n
+		}
+	}
+	return info
+}
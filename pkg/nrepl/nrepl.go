@@ -0,0 +1,276 @@
+// Package nrepl implements an nREPL server (https://nrepl.org) for
+// Glojure, so that editors such as CIDER, Calva and Conjure can
+// evaluate code in a running program.
+//
+// Messages are bencoded dictionaries exchanged over TCP. The server
+// supports the core nREPL ops: clone, close, describe, eval,
+// load-file, interrupt, ls-sessions, stdin, completions, lookup and
+// info. Each session has its own *ns*, *1, *2, *3 and *e, and output
+// written to *out* and *err* during an evaluation is streamed to the
+// client as it is written.
+package nrepl
+
+import (
+	"bufio"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/glojurelang/glojure/pkg/lang"
+)
+
+// Server is an nREPL server evaluating code in an environment.
+type Server struct {
+	env lang.Environment
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	conns    map[net.Conn]bool
+	lns      map[net.Listener]bool
+	closed   bool
+}
+
+// NewServer returns a server that evaluates code in env.
+func NewServer(env lang.Environment) *Server {
+	return &Server{
+		env:      env,
+		sessions: map[string]*session{},
+		conns:    map[net.Conn]bool{},
+		lns:      map[net.Listener]bool{},
+	}
+}
+
+// Serve accepts connections on ln and serves each in its own
+// goroutine. It returns once ln fails or the server is closed, in
+// which case it returns nil.
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ln.Close()
+	}
+	s.lns[ln] = true
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			delete(s.lns, ln)
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops the server's listeners, closes its connections and
+// interrupts the evaluations of its sessions.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	var err error
+	for ln := range s.lns {
+		if e := ln.Close(); err == nil {
+			err = e
+		}
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	for id, sess := range s.sessions {
+		sess.close()
+		delete(s.sessions, id)
+	}
+	return err
+}
+
+// transport sends the responses to the messages of a connection.
+type transport struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+// send writes msg to the connection. Errors are ignored, as the
+// connection is then closed and its reader sees the error.
+func (t *transport) send(msg map[string]interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := encode(t.w, msg); err == nil {
+		t.w.Flush()
+	}
+}
+
+// reply sends a response to req with the given fields, adding the id
+// and session of req.
+func (t *transport) reply(req map[string]interface{}, fields map[string]interface{}) {
+	res := map[string]interface{}{}
+	for _, k := range []string{"id", "session"} {
+		if v, ok := req[k].(string); ok {
+			res[k] = v
+		}
+	}
+	for k, v := range fields {
+		res[k] = v
+	}
+	t.send(res)
+}
+
+// done replies to req with the given statuses followed by done.
+func (t *transport) done(req map[string]interface{}, status ...string) {
+	t.reply(req, map[string]interface{}{"status": append(status, "done")})
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	t := &transport{w: bufio.NewWriter(conn)}
+	r := bufio.NewReader(conn)
+	for {
+		v, err := decode(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				t.send(map[string]interface{}{"status": []string{"error", "done"}, "err": err.Error()})
+			}
+			return
+		}
+		msg, ok := v.(map[string]interface{})
+		if !ok {
+			t.send(map[string]interface{}{"status": []string{"error", "done"}, "err": "message is not a dictionary"})
+			return
+		}
+		s.handle(t, msg)
+	}
+}
+
+// ops are the supported ops, described by the describe op.
+var ops = []string{
+	"clone", "close", "completions", "describe", "eval", "info",
+	"interrupt", "load-file", "lookup", "ls-sessions", "stdin",
+}
+
+// handle handles msg. Evaluations run in their session's goroutine;
+// all other ops are handled before handle returns.
+func (s *Server) handle(t *transport, msg map[string]interface{}) {
+	op, _ := msg["op"].(string)
+
+	var sess *session
+	if id, ok := msg["session"].(string); ok {
+		s.mu.Lock()
+		sess = s.sessions[id]
+		s.mu.Unlock()
+		if sess == nil {
+			t.done(msg, "error", "unknown-session")
+			return
+		}
+	}
+
+	switch op {
+	case "clone":
+		clone := s.newSession(sess)
+		t.reply(msg, map[string]interface{}{"new-session": clone.id, "status": []string{"done"}})
+	case "close":
+		if sess == nil {
+			t.done(msg, "error", "unknown-session")
+			return
+		}
+		s.mu.Lock()
+		delete(s.sessions, sess.id)
+		s.mu.Unlock()
+		sess.close()
+		t.done(msg, "session-closed")
+	case "ls-sessions":
+		s.mu.Lock()
+		ids := make([]string, 0, len(s.sessions))
+		for id := range s.sessions {
+			ids = append(ids, id)
+		}
+		s.mu.Unlock()
+		t.reply(msg, map[string]interface{}{"sessions": ids, "status": []string{"done"}})
+	case "describe":
+		opMap := map[string]interface{}{}
+		for _, op := range ops {
+			opMap[op] = map[string]interface{}{}
+		}
+		t.reply(msg, map[string]interface{}{
+			"ops": opMap,
+			"versions": map[string]interface{}{
+				"nrepl": map[string]interface{}{
+					"major": 1, "minor": 0, "incremental": 0, "version-string": "1.0.0",
+				},
+			},
+			"aux":    map[string]interface{}{"current-ns": s.sessionOrEphemeral(sess).currentNS()},
+			"status": []string{"done"},
+		})
+	case "eval", "load-file":
+		sess = s.sessionOrEphemeral(sess)
+		sess.enqueue(func() { sess.eval(t, msg) })
+	case "interrupt":
+		if sess == nil {
+			t.done(msg, "error", "unknown-session")
+			return
+		}
+		id, _ := msg["interrupt-id"].(string)
+		t.done(msg, sess.interrupt(id)...)
+	case "stdin":
+		if sess == nil {
+			t.done(msg, "error", "unknown-session")
+			return
+		}
+		in, _ := msg["stdin"].(string)
+		sess.in.write(in)
+		t.done(msg)
+	case "completions":
+		s.completions(t, msg, s.sessionOrEphemeral(sess))
+	case "lookup", "info":
+		s.lookup(t, msg, s.sessionOrEphemeral(sess))
+	default:
+		t.done(msg, "error", "unknown-op")
+	}
+}
+
+// sessionOrEphemeral returns sess, or if it is nil a new session that
+// is not registered with the server, for messages without a session.
+func (s *Server) sessionOrEphemeral(sess *session) *session {
+	if sess != nil {
+		return sess
+	}
+	return newSession(s.env, nil)
+}
+
+// newSession registers a new session, a copy of parent if it is not
+// nil.
+func (s *Server) newSession(parent *session) *session {
+	sess := newSession(s.env, parent)
+	s.mu.Lock()
+	s.sessions[sess.id] = sess
+	s.mu.Unlock()
+	return sess
+}
+
+// newID returns a random UUID, used for session ids.
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,357 @@
+package nrepl_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/glojurelang/glojure/pkg/glj"
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/nrepl"
+)
+
+// client is an nREPL client for tests, with a minimal bencode
+// implementation independent of the server's.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+	id   int
+}
+
+func startServer(t *testing.T) *client {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := nrepl.NewServer(lang.GlobalEnv)
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// send sends a message with the given key-value pairs and a new id,
+// which it returns.
+func (c *client) send(kvs ...string) string {
+	c.id++
+	id := fmt.Sprint(c.id)
+	kvs = append(kvs, "id", id)
+	var sb strings.Builder
+	sb.WriteString("d")
+	for i := 0; i < len(kvs); i += 2 {
+		fmt.Fprintf(&sb, "%d:%s%d:%s", len(kvs[i]), kvs[i], len(kvs[i+1]), kvs[i+1])
+	}
+	sb.WriteString("e")
+	if _, err := c.conn.Write([]byte(sb.String())); err != nil {
+		c.t.Fatal(err)
+	}
+	return id
+}
+
+// responses returns the responses to the message with id id, up to
+// the one with a done status.
+func (c *client) responses(id string) []map[string]interface{} {
+	c.t.Helper()
+	var res []map[string]interface{}
+	for {
+		msg := c.read().(map[string]interface{})
+		if msg["id"] != id {
+			c.t.Fatalf("unexpected response %v", msg)
+		}
+		res = append(res, msg)
+		if hasStatus(msg, "done") {
+			return res
+		}
+	}
+}
+
+// request sends a message and returns its responses.
+func (c *client) request(kvs ...string) []map[string]interface{} {
+	c.t.Helper()
+	return c.responses(c.send(kvs...))
+}
+
+func (c *client) read() interface{} {
+	c.t.Helper()
+	b, err := c.r.ReadByte()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	switch b {
+	case 'i':
+		s, _ := c.r.ReadString('e')
+		var n int64
+		fmt.Sscan(strings.TrimSuffix(s, "e"), &n)
+		return n
+	case 'l':
+		list := []interface{}{}
+		for {
+			if b, _ := c.r.Peek(1); b[0] == 'e' {
+				c.r.ReadByte()
+				return list
+			}
+			list = append(list, c.read())
+		}
+	case 'd':
+		dict := map[string]interface{}{}
+		for {
+			if b, _ := c.r.Peek(1); b[0] == 'e' {
+				c.r.ReadByte()
+				return dict
+			}
+			k := c.read().(string)
+			dict[k] = c.read()
+		}
+	default:
+		c.r.UnreadByte()
+		s, _ := c.r.ReadString(':')
+		var n int
+		fmt.Sscan(strings.TrimSuffix(s, ":"), &n)
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			c.t.Fatal(err)
+		}
+		return string(buf)
+	}
+}
+
+func hasStatus(msg map[string]interface{}, status string) bool {
+	list, _ := msg["status"].([]interface{})
+	for _, s := range list {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// collect returns the values of key in msgs, in order.
+func collect(msgs []map[string]interface{}, key string) []interface{} {
+	var res []interface{}
+	for _, msg := range msgs {
+		if v, ok := msg[key]; ok {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func (c *client) clone() string {
+	c.t.Helper()
+	res := c.request("op", "clone")
+	session, ok := res[0]["new-session"].(string)
+	if !ok {
+		c.t.Fatalf("clone: unexpected response %v", res)
+	}
+	return session
+}
+
+func TestDescribe(t *testing.T) {
+	c := startServer(t)
+	res := c.request("op", "describe")
+	ops, _ := res[0]["ops"].(map[string]interface{})
+	for _, op := range []string{"clone", "close", "eval", "load-file", "interrupt", "stdin", "completions", "lookup", "info"} {
+		if _, ok := ops[op]; !ok {
+			t.Errorf("describe: missing op %s in %v", op, ops)
+		}
+	}
+	if res := c.request("op", "frobnicate"); !hasStatus(res[0], "unknown-op") {
+		t.Errorf("unknown op: unexpected response %v", res)
+	}
+}
+
+func TestEval(t *testing.T) {
+	c := startServer(t)
+	session := c.clone()
+
+	tests := []struct {
+		code   string
+		values []interface{}
+		out    []interface{}
+		err    bool
+		ns     string
+	}{{
+		code:   `(+ 1 2) (* *1 2) (println "hello")`,
+		values: []interface{}{"3", "6", "nil"},
+		out:    []interface{}{"hello", "\n"},
+		ns:     "user",
+	}, {
+		code:   `(ns nrepl.test-eval) (def x 42)`,
+		values: []interface{}{"nil", "#'nrepl.test-eval/x"},
+		ns:     "nrepl.test-eval",
+	}, {
+		code:   `x`,
+		values: []interface{}{"42"},
+		ns:     "nrepl.test-eval",
+	}, {
+		code: `(/ 1 0)`,
+		err:  true,
+	}, {
+		code:   `(some? *e) [*1 *2 *3]`,
+		values: []interface{}{"true", "[true 42 #'nrepl.test-eval/x]"},
+		ns:     "nrepl.test-eval",
+	}}
+	for _, tt := range tests {
+		res := c.request("op", "eval", "session", session, "code", tt.code)
+		if values := collect(res, "value"); !reflect.DeepEqual(values, tt.values) {
+			t.Errorf("%s: expected values %v, got %v", tt.code, tt.values, values)
+		}
+		if out := collect(res, "out"); !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("%s: expected out %q, got %q", tt.code, tt.out, out)
+		}
+		if errored := hasStatus(res[len(res)-1], "eval-error"); errored != tt.err {
+			t.Errorf("%s: expected eval-error %v, got %v", tt.code, tt.err, res)
+		}
+		if tt.err && len(collect(res, "err")) == 0 {
+			t.Errorf("%s: expected err output, got %v", tt.code, res)
+		}
+		if tt.ns != "" {
+			if ns := collect(res, "ns"); ns[len(ns)-1] != tt.ns {
+				t.Errorf("%s: expected ns %s, got %v", tt.code, tt.ns, ns)
+			}
+		}
+	}
+
+	// sessions have their own bindings.
+	other := c.clone()
+	res := c.request("op", "eval", "session", other, "code", `[(str *ns*) *1]`)
+	if values := collect(res, "value"); !reflect.DeepEqual(values, []interface{}{`["user" nil]`}) {
+		t.Errorf("other session: unexpected values %v", values)
+	}
+	res = c.request("op", "eval", "session", other, "code", `x`, "ns", "nrepl.test-eval")
+	if values := collect(res, "value"); !reflect.DeepEqual(values, []interface{}{"42"}) {
+		t.Errorf("eval with ns: unexpected values %v", values)
+	}
+
+	res = c.request("op", "close", "session", other)
+	if !hasStatus(res[0], "session-closed") {
+		t.Errorf("close: unexpected response %v", res)
+	}
+	res = c.request("op", "eval", "session", other, "code", `1`)
+	if !hasStatus(res[0], "unknown-session") {
+		t.Errorf("eval in closed session: unexpected response %v", res)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	c := startServer(t)
+	session := c.clone()
+	res := c.request("op", "load-file", "session", session,
+		"file", "(ns nrepl.test-load)\n(defn f [] :loaded)\n(f)",
+		"file-path", "nrepl/test_load.glj", "file-name", "test_load.glj")
+	if values := collect(res, "value"); len(values) == 0 || values[len(values)-1] != ":loaded" {
+		t.Errorf("load-file: unexpected response %v", res)
+	}
+	res = c.request("op", "eval", "session", session, "code", `(:file (meta #'f))`)
+	if values := collect(res, "value"); !reflect.DeepEqual(values, []interface{}{`"nrepl/test_load.glj"`}) {
+		t.Errorf("load-file: unexpected :file %v", values)
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	c := startServer(t)
+	session := c.clone()
+
+	res := c.request("op", "interrupt", "session", session)
+	if !hasStatus(res[0], "session-idle") {
+		t.Errorf("interrupt idle session: unexpected response %v", res)
+	}
+
+	id := c.send("op", "eval", "session", session, "code", `(println "start") (loop [] (recur))`)
+	if msg := c.read().(map[string]interface{}); msg["out"] != "start" {
+		t.Fatalf("unexpected response %v", msg)
+	}
+	c.read() // newline
+	interruptID := c.send("op", "interrupt", "session", session, "interrupt-id", id)
+
+	// the reply to the interrupt may come before or after those to
+	// the eval.
+	var evalRes []map[string]interface{}
+	interrupted := false
+	for len(evalRes) == 0 || !hasStatus(evalRes[len(evalRes)-1], "done") || !interrupted {
+		msg := c.read().(map[string]interface{})
+		switch msg["id"] {
+		case id:
+			evalRes = append(evalRes, msg)
+		case interruptID:
+			interrupted = hasStatus(msg, "done")
+		}
+	}
+	if !hasStatus(evalRes[len(evalRes)-1], "interrupted") {
+		t.Errorf("eval: expected interrupted status, got %v", evalRes)
+	}
+
+	res = c.request("op", "eval", "session", session, "code", `:after`)
+	if values := collect(res, "value"); !reflect.DeepEqual(values, []interface{}{":after"}) {
+		t.Errorf("eval after interrupt: unexpected values %v", values)
+	}
+}
+
+func TestStdin(t *testing.T) {
+	c := startServer(t)
+	session := c.clone()
+
+	id := c.send("op", "eval", "session", session, "code", `[(read-line) (read-line)]`)
+	if msg := c.read().(map[string]interface{}); !hasStatus(msg, "need-input") {
+		t.Fatalf("expected need-input, got %v", msg)
+	}
+	if res := c.request("op", "stdin", "session", session, "stdin", "one\ntwo\n"); !hasStatus(res[0], "done") {
+		t.Errorf("stdin: unexpected response %v", res)
+	}
+	res := c.responses(id)
+	if values := collect(res, "value"); !reflect.DeepEqual(values, []interface{}{`["one" "two"]`}) {
+		t.Errorf("read-line: unexpected values %v", values)
+	}
+}
+
+func TestCompletionsAndLookup(t *testing.T) {
+	c := startServer(t)
+	session := c.clone()
+	c.request("op", "eval", "session", session, "code",
+		`(ns nrepl.test-lookup (:require [glojure.string :as str])) (defn my-fn "Does things." [a b] a)`)
+
+	res := c.request("op", "completions", "session", session, "prefix", "my-")
+	want := []interface{}{map[string]interface{}{"candidate": "my-fn", "ns": "nrepl.test-lookup", "type": "function"}}
+	if got := res[0]["completions"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("completions: expected %v, got %v", want, got)
+	}
+	res = c.request("op", "completions", "session", session, "prefix", "str/upper")
+	want = []interface{}{map[string]interface{}{"candidate": "str/upper-case", "ns": "glojure.string", "type": "function"}}
+	if got := res[0]["completions"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("alias completions: expected %v, got %v", want, got)
+	}
+
+	res = c.request("op", "lookup", "session", session, "sym", "my-fn")
+	info, _ := res[0]["info"].(map[string]interface{})
+	for k, v := range map[string]interface{}{
+		"ns":           "nrepl.test-lookup",
+		"name":         "my-fn",
+		"doc":          "Does things.",
+		"arglists-str": "([a b])",
+	} {
+		if info[k] != v {
+			t.Errorf("lookup: expected %s %q, got %v", k, v, info[k])
+		}
+	}
+
+	res = c.request("op", "info", "session", session, "sym", "when")
+	if res[0]["macro"] != "true" || res[0]["ns"] != "glojure.core" {
+		t.Errorf("info: unexpected response %v", res[0])
+	}
+	res = c.request("op", "info", "session", session, "sym", "no-such-var")
+	if !hasStatus(res[0], "no-info") {
+		t.Errorf("info: unexpected response %v", res[0])
+	}
+}
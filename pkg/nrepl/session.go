@@ -0,0 +1,350 @@
+package nrepl
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/repl"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// sessionVars returns the vars bound in each session, whose values
+// are kept between evaluations. New sessions bind them to their
+// current values, except for the REPL's *1, *2, *3 and *e, which they
+// bind to nil.
+func sessionVars() (vars, replVars []*lang.Var) {
+	vars = []*lang.Var{
+		lang.VarCurrentNS,
+		lang.VarWarnOnReflection,
+		lang.VarUncheckedMath,
+		lang.VarDataReaders,
+		lang.VarPrintGoStack,
+	}
+	replVars = []*lang.Var{repl.CoreVar("*1"), repl.CoreVar("*2"), repl.CoreVar("*3"), repl.CoreVar("*e")}
+	return vars, replVars
+}
+
+// session is an nREPL session. Its evaluations run one at a time, in
+// the order they were received, each with the session's bindings.
+type session struct {
+	id  string
+	env lang.Environment
+	in  *stdin
+
+	mu       sync.Mutex
+	bindings lang.IPersistentMap
+	queue    []func()
+	running  bool
+	// evalID is the id of the message being evaluated and cancel
+	// interrupts it.
+	evalID string
+	cancel context.CancelFunc
+	closed bool
+}
+
+// newSession returns a session with the bindings of parent, or if
+// parent is nil with *ns* bound to the user namespace.
+func newSession(env lang.Environment, parent *session) *session {
+	sess := &session{id: newID(), env: env}
+	sess.in = newStdin()
+	if parent != nil {
+		parent.mu.Lock()
+		sess.bindings = parent.bindings
+		parent.mu.Unlock()
+		return sess
+	}
+	vars, replVars := sessionVars()
+	kvs := make([]interface{}, 0, 2*(len(vars)+len(replVars)))
+	for _, vr := range vars {
+		kvs = append(kvs, vr, vr.Deref())
+	}
+	for _, vr := range replVars {
+		kvs = append(kvs, vr, nil)
+	}
+	sess.bindings = lang.Assoc(lang.NewMap(kvs...), lang.VarCurrentNS, userNamespace(env)).(lang.IPersistentMap)
+	return sess
+}
+
+// userNamespace returns the user namespace, first evaluating (ns user)
+// if it does not exist so that it refers to glojure.core.
+func userNamespace(env lang.Environment) *lang.Namespace {
+	sym := lang.NewSymbol("user")
+	if ns := lang.FindNamespace(sym); ns != nil {
+		return ns
+	}
+	lang.PushThreadBindings(lang.NewMap(lang.VarCurrentNS, lang.VarCurrentNS.Deref()))
+	defer lang.PopThreadBindings()
+	env.Eval(lang.NewList(lang.NewSymbol("ns"), sym))
+	return lang.FindOrCreateNamespace(sym)
+}
+
+// currentNS returns the name of the session's namespace.
+func (s *session) currentNS() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ns, ok := s.bindings.ValAt(lang.VarCurrentNS).(*lang.Namespace); ok {
+		return ns.Name().Name()
+	}
+	return "user"
+}
+
+// namespace returns the namespace named by the ns field of msg, or the
+// session's namespace if it has none.
+func (s *session) namespace(msg map[string]interface{}) *lang.Namespace {
+	name, _ := msg["ns"].(string)
+	if name == "" {
+		name = s.currentNS()
+	}
+	return lang.FindNamespace(lang.NewSymbol(name))
+}
+
+// enqueue runs task after the session's earlier tasks.
+func (s *session) enqueue(task func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, task)
+	if s.running {
+		return
+	}
+	s.running = true
+	go func() {
+		for {
+			s.mu.Lock()
+			if len(s.queue) == 0 {
+				s.running = false
+				s.mu.Unlock()
+				return
+			}
+			task := s.queue[0]
+			s.queue = s.queue[1:]
+			s.mu.Unlock()
+			task()
+		}
+	}()
+}
+
+// interrupt interrupts the evaluation of the message with id id, or
+// that in progress if id is empty, and returns the status of the
+// reply to the interrupt message.
+func (s *session) interrupt(id string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.cancel == nil:
+		return []string{"session-idle"}
+	case id != "" && id != s.evalID:
+		return []string{"interrupt-id-mismatch"}
+	}
+	s.cancel()
+	return nil
+}
+
+// close interrupts the session's evaluation, drops those queued and
+// ends its input.
+func (s *session) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.queue = nil
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+	s.in.close()
+}
+
+// eval evaluates the code of an eval or load-file message, replying
+// with the value of each form, or an error, and then done.
+func (s *session) eval(t *transport, msg map[string]interface{}) {
+	code, filename := msg["code"], "NO_SOURCE_FILE"
+	if msg["op"] == "load-file" {
+		code = msg["file"]
+		if path, ok := msg["file-path"].(string); ok {
+			filename = path
+		} else if name, ok := msg["file-name"].(string); ok {
+			filename = name
+		}
+	} else if path, ok := msg["file"].(string); ok {
+		filename = path
+	}
+	src, ok := code.(string)
+	if !ok {
+		t.done(msg, "error", "no-code")
+		return
+	}
+	ns := s.namespace(msg)
+	if ns == nil {
+		t.done(msg, "error", "namespace-not-found")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.env.Context())
+	defer cancel()
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	id, _ := msg["id"].(string)
+	s.evalID, s.cancel = id, cancel
+	bindings := s.bindings
+	s.mu.Unlock()
+	s.in.setRequest(t, msg)
+
+	lang.PushThreadBindings(bindings)
+	lang.VarCurrentNS.Set(ns)
+	errOut := &output{t: t, req: msg, key: "err"}
+	lang.PushThreadBindings(lang.NewMap(
+		lang.VarOut, &output{t: t, req: msg, key: "out"},
+		lang.VarErr, errOut,
+		lang.VarIn, s.in.r,
+		lang.VarFile, filename,
+	))
+	lang.PushContext(ctx)
+
+	status := s.evalForms(t, msg, src, filename, errOut)
+
+	lang.PopContext()
+	lang.PopThreadBindings()
+	s.mu.Lock()
+	s.bindings = lang.GetThreadBindings()
+	s.evalID, s.cancel = "", nil
+	s.mu.Unlock()
+	lang.PopThreadBindings()
+	s.in.setRequest(nil, nil)
+
+	t.done(msg, status...)
+}
+
+// evalForms reads and evaluates the forms of src in turn, replying
+// with their values, and returns the status of the final reply. It
+// stops at the first error, which it prints to errOut.
+func (s *session) evalForms(t *transport, msg map[string]interface{}, src, filename string, errOut io.Writer) []string {
+	rdr := reader.New(strings.NewReader(src), reader.WithFilename(filename), reader.WithGetCurrentNS(s.env.CurrentNamespace))
+	for {
+		form, err := rdr.ReadOne()
+		if errors.Is(err, reader.ErrEOF) {
+			return nil
+		}
+		var val interface{}
+		if err == nil {
+			val, err = s.evalForm(form)
+		}
+		if err != nil {
+			var canceled *lang.CanceledError
+			if errors.As(err, &canceled) {
+				return []string{"interrupted"}
+			}
+			repl.CoreVar("*e").Set(err)
+			runtime.PrintError(errOut, err)
+			root := err
+			for errors.Unwrap(root) != nil {
+				root = errors.Unwrap(root)
+			}
+			t.reply(msg, map[string]interface{}{
+				"ex":      fmt.Sprintf("%T", err),
+				"root-ex": fmt.Sprintf("%T", root),
+			})
+			return []string{"eval-error"}
+		}
+		var1, var2, var3 := repl.CoreVar("*1"), repl.CoreVar("*2"), repl.CoreVar("*3")
+		var3.Set(var2.Deref())
+		var2.Set(var1.Deref())
+		var1.Set(val)
+		t.reply(msg, map[string]interface{}{
+			"value": lang.PrintString(val),
+			"ns":    s.env.CurrentNamespace().Name().Name(),
+		})
+	}
+}
+
+// evalForm evaluates form, converting a panic to an error.
+func (s *session) evalForm(form interface{}) (val interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if err, ok = r.(error); !ok {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+	return s.env.Eval(form)
+}
+
+// output is the *out* or *err* of an evaluation, whose writes are sent
+// to the client as they are made.
+type output struct {
+	t   *transport
+	req map[string]interface{}
+	key string
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.t.reply(o.req, map[string]interface{}{o.key: string(p)})
+	return len(p), nil
+}
+
+// stdin is the input of a session, bound to *in* during its
+// evaluations and written by stdin messages. A read that would block
+// asks the client for input with a need-input status.
+type stdin struct {
+	r *bufio.Reader
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    []byte
+	closed bool
+	t      *transport
+	req    map[string]interface{}
+}
+
+func newStdin() *stdin {
+	in := &stdin{}
+	in.cond = sync.NewCond(&in.mu)
+	in.r = bufio.NewReader(in)
+	return in
+}
+
+// setRequest sets the eval message whose client is asked for input.
+func (in *stdin) setRequest(t *transport, req map[string]interface{}) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.t, in.req = t, req
+}
+
+func (in *stdin) write(s string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.buf = append(in.buf, s...)
+	in.cond.Broadcast()
+}
+
+func (in *stdin) close() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.closed = true
+	in.cond.Broadcast()
+}
+
+func (in *stdin) Read(p []byte) (int, error) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	for len(in.buf) == 0 && !in.closed {
+		if in.t != nil {
+			in.t.reply(in.req, map[string]interface{}{"status": []string{"need-input"}})
+		}
+		in.cond.Wait()
+	}
+	if len(in.buf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, in.buf)
+	in.buf = in.buf[n:]
+	return n, nil
+}
@@ -7,6 +7,8 @@ import (
 
 var (
 	pkgMap = map[string]interface{}{}
+	// pkgs holds the munged names of the packages with exports.
+	pkgs = map[string]bool{}
 	// TODO: lock-free map
 	mtx sync.RWMutex
 )
@@ -19,6 +21,16 @@ func Set(export string, value interface{}) {
 	defer mtx.Unlock()
 
 	pkgMap[mungePkg(pkg)+"."+name] = value
+	pkgs[mungePkg(pkg)] = true
+}
+
+// HasPackage reports whether any export of the given package has been
+// set.
+func HasPackage(pkg string) bool {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	return pkgs[mungePkg(pkg)]
 }
 
 // Get returns the value of the given package and export name and
@@ -33,6 +45,36 @@ func Get(export string) (interface{}, bool) {
 	return v, ok
 }
 
+// Packages returns the munged names of the packages with exports, in
+// no particular order.
+func Packages() []string {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	res := make([]string, 0, len(pkgs))
+	for pkg := range pkgs {
+		res = append(res, pkg)
+	}
+	return res
+}
+
+// Exports returns the names of the exports of the given package, in
+// no particular order.
+func Exports(pkg string) []string {
+	prefix := mungePkg(pkg) + "."
+
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	var res []string
+	for export := range pkgMap {
+		if name := strings.TrimPrefix(export, prefix); name != export && !strings.Contains(name, ".") {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
 func SplitExport(export string) (string, string) {
 	lastDot := strings.LastIndex(export, ".")
 	if lastDot == -1 {
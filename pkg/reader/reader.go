@@ -95,6 +95,12 @@ func (e *Error) Unwrap() error {
 	return e.wrapped
 }
 
+// Position returns the filename, line, and column at which the error
+// occurred.
+func (e *Error) Position() (filename string, line, column int) {
+	return e.pos.Filename, e.pos.Line, e.pos.Column
+}
+
 func newTrackingRuneScanner(rs io.RuneScanner, filename string) *trackingRuneScanner {
 	if filename == "" {
 		filename = "<unknown-file>"
@@ -0,0 +1,496 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxKind is the kind of a node of a concrete syntax tree.
+type SyntaxKind int
+
+const (
+	// SyntaxFile is the root of the tree of a whole input.
+	SyntaxFile SyntaxKind = iota
+
+	// Tokens, which have no children.
+
+	// SyntaxWhitespace is a run of whitespace other than commas.
+	SyntaxWhitespace
+	SyntaxComma
+	// SyntaxComment is a line comment, from ';' to the end of the line,
+	// excluding the newline.
+	SyntaxComment
+	// SyntaxOpen is an opening delimiter: "(", "[", "{", "#(" or "#{".
+	SyntaxOpen
+	SyntaxClose
+	// SyntaxPrefix is the token of a reader macro that applies to the
+	// next form: "'", "`", "~", "~@", "@", "^", "#^", "#'", "#_", "##"
+	// or the "#:ns" of a namespaced map.
+	SyntaxPrefix
+	// SyntaxSymbol is a symbol, including nil, true, false and the %
+	// arguments of fn literals.
+	SyntaxSymbol
+	SyntaxKeyword
+	SyntaxNumber
+	SyntaxString
+	SyntaxChar
+	SyntaxRegex
+
+	// Forms built of other nodes.
+
+	SyntaxList
+	SyntaxVector
+	SyntaxMap
+	SyntaxSet
+	SyntaxFn
+	SyntaxQuote
+	SyntaxSyntaxQuote
+	SyntaxUnquote
+	SyntaxUnquoteSplicing
+	SyntaxDeref
+	SyntaxVar
+	// SyntaxMeta is a form with metadata, whose children are the "^"
+	// prefix, the metadata and the form.
+	SyntaxMeta
+	// SyntaxDiscard is a form ignored by the reader, prefixed by "#_".
+	SyntaxDiscard
+	// SyntaxSymbolic is a symbolic value such as ##Inf.
+	SyntaxSymbolic
+	SyntaxNamespacedMap
+)
+
+var syntaxKindNames = [...]string{
+	SyntaxFile:            "File",
+	SyntaxWhitespace:      "Whitespace",
+	SyntaxComma:           "Comma",
+	SyntaxComment:         "Comment",
+	SyntaxOpen:            "Open",
+	SyntaxClose:           "Close",
+	SyntaxPrefix:          "Prefix",
+	SyntaxSymbol:          "Symbol",
+	SyntaxKeyword:         "Keyword",
+	SyntaxNumber:          "Number",
+	SyntaxString:          "String",
+	SyntaxChar:            "Char",
+	SyntaxRegex:           "Regex",
+	SyntaxList:            "List",
+	SyntaxVector:          "Vector",
+	SyntaxMap:             "Map",
+	SyntaxSet:             "Set",
+	SyntaxFn:              "Fn",
+	SyntaxQuote:           "Quote",
+	SyntaxSyntaxQuote:     "SyntaxQuote",
+	SyntaxUnquote:         "Unquote",
+	SyntaxUnquoteSplicing: "UnquoteSplicing",
+	SyntaxDeref:           "Deref",
+	SyntaxVar:             "Var",
+	SyntaxMeta:            "Meta",
+	SyntaxDiscard:         "Discard",
+	SyntaxSymbolic:        "Symbolic",
+	SyntaxNamespacedMap:   "NamespacedMap",
+}
+
+func (k SyntaxKind) String() string {
+	if k >= 0 && int(k) < len(syntaxKindNames) {
+		return syntaxKindNames[k]
+	}
+	return fmt.Sprintf("SyntaxKind(%d)", int(k))
+}
+
+// SyntaxNode is a node of a concrete syntax tree, as returned by
+// ReadSyntax. The tree holds every byte of its input: the text of a
+// node is the concatenation of the text of its children, and that of
+// the root is the whole input.
+type SyntaxNode struct {
+	Kind SyntaxKind
+	// Text is the exact text of a token. It is empty for other nodes.
+	Text string
+	// Start and End are the byte offsets of the node in the input.
+	Start, End int
+	// Children are the nodes of a form, including its delimiters,
+	// prefix tokens and trivia, in input order.
+	Children []*SyntaxNode
+}
+
+// IsToken reports whether n is a token, which has no children.
+func (n *SyntaxNode) IsToken() bool {
+	return n.Kind > SyntaxFile && n.Kind < SyntaxList
+}
+
+// IsTrivia reports whether n is ignored by the reader: whitespace,
+// commas, comments and discarded forms.
+func (n *SyntaxNode) IsTrivia() bool {
+	switch n.Kind {
+	case SyntaxWhitespace, SyntaxComma, SyntaxComment, SyntaxDiscard:
+		return true
+	}
+	return false
+}
+
+// IsForm reports whether n is a form that the reader reads as a
+// value.
+func (n *SyntaxNode) IsForm() bool {
+	switch n.Kind {
+	case SyntaxFile, SyntaxOpen, SyntaxClose, SyntaxPrefix:
+		return false
+	}
+	return !n.IsTrivia()
+}
+
+// Forms returns the children of n that are forms, such as the
+// elements of a collection or the target of a reader macro.
+func (n *SyntaxNode) Forms() []*SyntaxNode {
+	var forms []*SyntaxNode
+	for _, child := range n.Children {
+		if child.IsForm() {
+			forms = append(forms, child)
+		}
+	}
+	return forms
+}
+
+// String returns the source text of n.
+func (n *SyntaxNode) String() string {
+	var sb strings.Builder
+	n.WriteTo(&sb)
+	return sb.String()
+}
+
+// WriteTo writes the source text of n to w.
+func (n *SyntaxNode) WriteTo(w io.Writer) (int64, error) {
+	if n.IsToken() {
+		written, err := io.WriteString(w, n.Text)
+		return int64(written), err
+	}
+	var total int64
+	for _, child := range n.Children {
+		written, err := child.WriteTo(w)
+		total += written
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// ReadSyntax reads src into a concrete syntax tree, rooted at a node
+// of kind SyntaxFile, that preserves every token of the input with its
+// exact text, including whitespace, comments and discarded forms, so
+// that printing the tree reproduces src byte for byte. Forms are not
+// evaluated or resolved, so the only options used are WithFilename,
+// for the positions of errors.
+func ReadSyntax(src string, opts ...Option) (*SyntaxNode, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	filename := o.filename
+	if filename == "" {
+		filename = "<unknown-file>"
+	}
+	p := &syntaxParser{src: src, filename: filename}
+	root := &SyntaxNode{Kind: SyntaxFile}
+	for {
+		if err := p.readTrivia(root); err != nil {
+			return nil, err
+		}
+		if p.offset == len(src) {
+			break
+		}
+		form, err := p.readForm()
+		if err != nil {
+			return nil, err
+		}
+		root.Children = append(root.Children, form)
+	}
+	root.End = len(src)
+	return root, nil
+}
+
+type syntaxParser struct {
+	src      string
+	filename string
+	offset   int
+}
+
+// error returns an error at the byte offset of the input, with its
+// line and column.
+func (p *syntaxParser) error(offset int, format string, args ...interface{}) error {
+	line := 1 + strings.Count(p.src[:offset], "\n")
+	column := 1 + utf8.RuneCountInString(p.src[strings.LastIndex(p.src[:offset], "\n")+1:offset])
+	return &Error{
+		pos:     pos{Filename: p.filename, Line: line, Column: column},
+		wrapped: fmt.Errorf(format, args...),
+	}
+}
+
+// peek returns the next rune of the input, or -1 at its end.
+func (p *syntaxParser) peek() rune {
+	if p.offset == len(p.src) {
+		return -1
+	}
+	rn, _ := utf8.DecodeRuneInString(p.src[p.offset:])
+	return rn
+}
+
+// token returns a token of the input from start to the current
+// offset.
+func (p *syntaxParser) token(kind SyntaxKind, start int) *SyntaxNode {
+	return &SyntaxNode{Kind: kind, Text: p.src[start:p.offset], Start: start, End: p.offset}
+}
+
+// scanWhile advances the offset past the runes for which f is true.
+func (p *syntaxParser) scanWhile(f func(rune) bool) {
+	for p.offset < len(p.src) {
+		rn, size := utf8.DecodeRuneInString(p.src[p.offset:])
+		if !f(rn) {
+			return
+		}
+		p.offset += size
+	}
+}
+
+// readTrivia appends the whitespace, commas, comments and discarded
+// forms at the current offset to the children of parent.
+func (p *syntaxParser) readTrivia(parent *SyntaxNode) error {
+	for p.offset < len(p.src) {
+		start := p.offset
+		switch rn := p.peek(); {
+		case rn == ',':
+			p.offset++
+			parent.Children = append(parent.Children, p.token(SyntaxComma, start))
+		case unicode.IsSpace(rn):
+			p.scanWhile(func(rn rune) bool { return rn != ',' && unicode.IsSpace(rn) })
+			parent.Children = append(parent.Children, p.token(SyntaxWhitespace, start))
+		case rn == ';':
+			p.scanWhile(func(rn rune) bool { return rn != '\n' })
+			parent.Children = append(parent.Children, p.token(SyntaxComment, start))
+		case strings.HasPrefix(p.src[start:], "#_"):
+			p.offset += 2
+			discard, err := p.readPrefixed(SyntaxDiscard, start)
+			if err != nil {
+				return err
+			}
+			parent.Children = append(parent.Children, discard)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// readForm reads the form at the current offset, which must not be
+// trivia or the end of the input.
+func (p *syntaxParser) readForm() (*SyntaxNode, error) {
+	start := p.offset
+	rn := p.peek()
+	switch rn {
+	case '(':
+		p.offset++
+		return p.readColl(SyntaxList, start, ')')
+	case '[':
+		p.offset++
+		return p.readColl(SyntaxVector, start, ']')
+	case '{':
+		p.offset++
+		return p.readColl(SyntaxMap, start, '}')
+	case ')', ']', '}':
+		return nil, p.error(start, "unexpected '%c'", rn)
+	case '"':
+		if err := p.scanString(); err != nil {
+			return nil, err
+		}
+		return p.token(SyntaxString, start), nil
+	case '\\':
+		p.offset++
+		if p.offset == len(p.src) {
+			return nil, p.error(start, "error reading character: %w", io.EOF)
+		}
+		// the first rune is part of the character even if it's a
+		// delimiter, as in \( or \space.
+		_, size := utf8.DecodeRuneInString(p.src[p.offset:])
+		p.offset += size
+		p.scanWhile(isTokenRune)
+		return p.token(SyntaxChar, start), nil
+	case ':':
+		p.offset++
+		p.scanWhile(isTokenRune)
+		return p.token(SyntaxKeyword, start), nil
+	case '\'':
+		p.offset++
+		return p.readPrefixed(SyntaxQuote, start)
+	case '`':
+		p.offset++
+		return p.readPrefixed(SyntaxSyntaxQuote, start)
+	case '~':
+		p.offset++
+		if p.peek() == '@' {
+			p.offset++
+			return p.readPrefixed(SyntaxUnquoteSplicing, start)
+		}
+		return p.readPrefixed(SyntaxUnquote, start)
+	case '@':
+		p.offset++
+		return p.readPrefixed(SyntaxDeref, start)
+	case '^':
+		p.offset++
+		return p.readMeta(start)
+	case '#':
+		return p.readDispatch()
+	}
+
+	p.scanWhile(isTokenRune)
+	text := p.src[start:p.offset]
+	if numPrefixRegex.MatchString(text) {
+		// numbers end at the runes that end the reader's numbers, which
+		// are otherwise valid in symbols.
+		p.offset = start + strings.IndexFunc(text+" ", func(rn rune) bool { return !isValidNumberCharacter(rn) })
+		return p.token(SyntaxNumber, start), nil
+	}
+	if text == "" {
+		return nil, p.error(start, "error reading symbol")
+	}
+	return p.token(SyntaxSymbol, start), nil
+}
+
+// isTokenRune reports whether rn can be part of a symbol, keyword or
+// number.
+func isTokenRune(rn rune) bool {
+	return !isSpace(rn) && !isSyntaxRune(rn)
+}
+
+// scanString advances the offset past the string or regex whose
+// opening quote is at the current offset.
+func (p *syntaxParser) scanString() error {
+	start := p.offset
+	p.offset++
+	for p.offset < len(p.src) {
+		switch p.src[p.offset] {
+		case '\\':
+			p.offset += 2
+		case '"':
+			p.offset++
+			return nil
+		default:
+			p.offset++
+		}
+	}
+	p.offset = len(p.src)
+	return p.error(start, "error reading string: %w", io.EOF)
+}
+
+// readColl reads the elements and trivia of a collection whose
+// opening delimiter ends at the current offset, up to its closing
+// delimiter.
+func (p *syntaxParser) readColl(kind SyntaxKind, start int, closer rune) (*SyntaxNode, error) {
+	n := &SyntaxNode{Kind: kind, Start: start}
+	n.Children = append(n.Children, p.token(SyntaxOpen, start))
+	for {
+		if err := p.readTrivia(n); err != nil {
+			return nil, err
+		}
+		rn := p.peek()
+		if rn == -1 {
+			return nil, p.error(start, "unterminated %s: missing '%c'", strings.ToLower(kind.String()), closer)
+		}
+		if rn == closer {
+			closeStart := p.offset
+			p.offset++
+			n.Children = append(n.Children, p.token(SyntaxClose, closeStart))
+			n.End = p.offset
+			return n, nil
+		}
+		form, err := p.readForm()
+		if err != nil {
+			return nil, err
+		}
+		n.Children = append(n.Children, form)
+	}
+}
+
+// readPrefixed reads the form following the prefix token that ends at
+// the current offset, and any trivia before it.
+func (p *syntaxParser) readPrefixed(kind SyntaxKind, start int) (*SyntaxNode, error) {
+	n := &SyntaxNode{Kind: kind, Start: start}
+	n.Children = append(n.Children, p.token(SyntaxPrefix, start))
+	if err := p.appendForm(n); err != nil {
+		return nil, err
+	}
+	n.End = p.offset
+	return n, nil
+}
+
+// appendForm appends the trivia at the current offset and the form
+// following it to the children of n.
+func (p *syntaxParser) appendForm(n *SyntaxNode) error {
+	if err := p.readTrivia(n); err != nil {
+		return err
+	}
+	if p.offset == len(p.src) {
+		return p.error(n.Start, "error reading input: %w", io.EOF)
+	}
+	form, err := p.readForm()
+	if err != nil {
+		return err
+	}
+	n.Children = append(n.Children, form)
+	return nil
+}
+
+// readMeta reads the metadata and the form following the "^" or "#^"
+// prefix that ends at the current offset.
+func (p *syntaxParser) readMeta(start int) (*SyntaxNode, error) {
+	n := &SyntaxNode{Kind: SyntaxMeta, Start: start}
+	n.Children = append(n.Children, p.token(SyntaxPrefix, start))
+	if err := p.appendForm(n); err != nil {
+		return nil, err
+	}
+	if err := p.appendForm(n); err != nil {
+		return nil, err
+	}
+	n.End = p.offset
+	return n, nil
+}
+
+// readDispatch reads the form at the current offset, which starts
+// with '#'.
+func (p *syntaxParser) readDispatch() (*SyntaxNode, error) {
+	start := p.offset
+	p.offset++
+	rn := p.peek()
+	switch rn {
+	case '{':
+		p.offset++
+		return p.readColl(SyntaxSet, start, '}')
+	case '(':
+		p.offset++
+		return p.readColl(SyntaxFn, start, ')')
+	case '"':
+		if err := p.scanString(); err != nil {
+			return nil, err
+		}
+		return p.token(SyntaxRegex, start), nil
+	case '\'':
+		p.offset++
+		return p.readPrefixed(SyntaxVar, start)
+	case '^':
+		p.offset++
+		return p.readMeta(start)
+	case '#':
+		p.offset++
+		return p.readPrefixed(SyntaxSymbolic, start)
+	case '_':
+		p.offset++
+		return p.readPrefixed(SyntaxDiscard, start)
+	case ':':
+		// the prefix token holds the namespace, as in #:ns or #::.
+		p.scanWhile(isTokenRune)
+		return p.readPrefixed(SyntaxNamespacedMap, start)
+	case -1:
+		return nil, p.error(start, "error reading input: %w", io.EOF)
+	}
+	return nil, p.error(start, "invalid dispatch character: %c", rn)
+}
@@ -0,0 +1,141 @@
+package reader
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+)
+
+func TestReadSyntaxRoundTrip(t *testing.T) {
+	paths, err := filepath.Glob("testdata/reader/*.glj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the standard library exercises most of the syntax.
+	err = filepath.WalkDir("../stdlib", func(path string, d fs.DirEntry, err error) error {
+		if err == nil && strings.HasSuffix(path, ".glj") {
+			paths = append(paths, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ns := value.FindOrCreateNamespace(value.NewSymbol("user"))
+	for _, path := range paths {
+		path := path
+		t.Run(path, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			src := string(data)
+			tree, err := ReadSyntax(src, WithFilename(path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tree.String(); got != src {
+				t.Fatalf("round trip mismatch:\n%s", got)
+			}
+			checkOffsets(t, src, tree)
+
+			forms, err := New(strings.NewReader(src), WithGetCurrentNS(func() *value.Namespace { return ns })).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(tree.Forms()); got != len(forms) {
+				t.Errorf("expected %d forms, got %d", len(forms), got)
+			}
+		})
+	}
+}
+
+// checkOffsets checks that the children of n cover its text without
+// gaps, and that the text of each token is that of its offsets.
+func checkOffsets(t *testing.T, src string, n *SyntaxNode) {
+	t.Helper()
+	if n.IsToken() {
+		if n.Text != src[n.Start:n.End] {
+			t.Errorf("%s token %q at %d:%d has text %q", n.Kind, src[n.Start:n.End], n.Start, n.End, n.Text)
+		}
+		return
+	}
+	offset := n.Start
+	for _, child := range n.Children {
+		if child.Start != offset {
+			t.Errorf("%s child of %s at %d starts at %d", child.Kind, n.Kind, offset, child.Start)
+		}
+		checkOffsets(t, src, child)
+		offset = child.End
+	}
+	if offset != n.End {
+		t.Errorf("%s at %d:%d ends at %d", n.Kind, n.Start, n.End, offset)
+	}
+}
+
+func TestReadSyntax(t *testing.T) {
+	src := "(def ^:private x ; the answer\n  0x2A) #_(ignored) [\\newline #\"a\\\"b\" 'y, @z]"
+	tree, err := ReadSyntax(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	var walk func(n *SyntaxNode)
+	walk = func(n *SyntaxNode) {
+		if n.IsToken() {
+			kinds = append(kinds, n.Kind.String()+" "+n.Text)
+			return
+		}
+		kinds = append(kinds, n.Kind.String())
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(tree)
+	expected := []string{
+		"File",
+		"List", "Open (", "Symbol def", "Whitespace  ",
+		"Meta", "Prefix ^", "Keyword :private", "Whitespace  ", "Symbol x",
+		"Whitespace  ", "Comment ; the answer", "Whitespace \n  ", "Number 0x2A", "Close )",
+		"Whitespace  ",
+		"Discard", "Prefix #_", "List", "Open (", "Symbol ignored", "Close )",
+		"Whitespace  ",
+		"Vector", "Open [", "Char \\newline", "Whitespace  ", "Regex #\"a\\\"b\"", "Whitespace  ",
+		"Quote", "Prefix '", "Symbol y", "Comma ,", "Whitespace  ",
+		"Deref", "Prefix @", "Symbol z", "Close ]",
+	}
+	if strings.Join(kinds, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(kinds, "\n"))
+	}
+	if n := len(tree.Forms()); n != 2 {
+		t.Errorf("expected 2 forms, got %d", n)
+	}
+}
+
+func TestReadSyntaxErrors(t *testing.T) {
+	for src, msg := range map[string]string{
+		"(foo":        "1:1: unterminated list: missing ')'",
+		"[a\n b)":     "2:3: unexpected ')'",
+		"}":           "1:1: unexpected '}'",
+		"\"abc":       "1:1: error reading string: EOF",
+		"'":           "1:1: error reading input: EOF",
+		"(a #_)":      "1:6: unexpected ')'",
+		"#!shebang":   "1:1: invalid dispatch character: !",
+		"^:meta":      "1:1: error reading input: EOF",
+		"(\\":         "1:2: error reading character: EOF",
+		"#{1 2 #(3)}": "",
+	} {
+		_, err := ReadSyntax(src, WithFilename("test.glj"))
+		switch {
+		case msg == "" && err != nil:
+			t.Errorf("%q: unexpected error: %v", src, err)
+		case msg != "" && (err == nil || err.Error() != "test.glj:"+msg):
+			t.Errorf("%q: expected error %q, got %v", src, msg, err)
+		}
+	}
+}
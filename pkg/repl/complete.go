@@ -0,0 +1,230 @@
+package repl
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/pkgmap"
+)
+
+// Completion is a candidate completion of a symbol prefix.
+type Completion struct {
+	// Candidate is the completed symbol.
+	Candidate string
+	// NS is the name of the namespace of the var it names, if any.
+	NS string
+	// Type is one of "function", "macro", "var", "class",
+	// "namespace", "package", "keyword" or "special-form".
+	Type string
+}
+
+// specialForms are the special forms offered as completions.
+var specialForms = []string{
+	"catch", "case*", "def", "do", "finally", "fn*", "if", "let*",
+	"letfn*", "loop*", "new", "quote", "recur", "set!", "throw", "try",
+	"var",
+}
+
+// Complete returns the completions of prefix in ns, sorted by
+// candidate: the symbols mapped in ns, the public vars of namespaces
+// named by prefixes of the form alias/name or ns-name/name, special
+// forms, the names and aliases of namespaces, the names of Go
+// packages and the exports of those named by prefixes of the form
+// pkg.Name, and for prefixes starting with a colon, the keywords in
+// the metadata of vars and those written in input, the text being
+// edited.
+func Complete(ns *value.Namespace, prefix, input string) []Completion {
+	var res []Completion
+	seen := map[string]bool{}
+	add := func(c Completion) {
+		if !seen[c.Candidate] && strings.HasPrefix(c.Candidate, prefix) {
+			seen[c.Candidate] = true
+			res = append(res, c)
+		}
+	}
+
+	if strings.HasPrefix(prefix, ":") {
+		// the keyword being completed is itself written in input.
+		seen[prefix] = true
+		for _, kw := range inputKeywords(input) {
+			add(Completion{Candidate: kw, Type: "keyword"})
+		}
+		for _, kw := range metaKeywords() {
+			if !strings.HasPrefix(prefix, "::") {
+				add(Completion{Candidate: kw.String(), Type: "keyword"})
+			} else if kw.Namespace() == ns.Name().Name() {
+				add(Completion{Candidate: "::" + kw.Name(), Type: "keyword"})
+			}
+		}
+	} else if i := strings.IndexByte(prefix, '/'); i > 0 {
+		nsName := prefix[:i]
+		target := ns.LookupAlias(value.NewSymbol(nsName))
+		if target == nil {
+			target = value.FindNamespace(value.NewSymbol(nsName))
+		}
+		if target != nil {
+			for seq := value.Seq(target.Mappings()); seq != nil; seq = seq.Next() {
+				entry := seq.First().(value.IMapEntry)
+				vr, ok := entry.Val().(*value.Var)
+				if !ok || vr.Namespace() != target || !vr.IsPublic() {
+					continue
+				}
+				c := varCompletion(vr)
+				c.Candidate = nsName + "/" + c.Candidate
+				add(c)
+			}
+		}
+	} else {
+		for seq := value.Seq(ns.Mappings()); seq != nil; seq = seq.Next() {
+			entry := seq.First().(value.IMapEntry)
+			name := entry.Key().(*value.Symbol).Name()
+			if vr, ok := entry.Val().(*value.Var); ok {
+				if vr.Namespace() == ns || vr.IsPublic() {
+					add(varCompletion(vr))
+				}
+				continue
+			}
+			add(Completion{Candidate: name, Type: "class"})
+		}
+		for _, name := range specialForms {
+			add(Completion{Candidate: name, Type: "special-form"})
+		}
+		for seq := value.Seq(ns.Aliases()); seq != nil; seq = seq.Next() {
+			entry := seq.First().(value.IMapEntry)
+			add(Completion{Candidate: entry.Key().(*value.Symbol).Name(), Type: "namespace"})
+		}
+		for _, n := range value.Namespaces() {
+			add(Completion{Candidate: n.Name().Name(), Type: "namespace"})
+		}
+		for _, pkg := range pkgmap.Packages() {
+			add(Completion{Candidate: pkg, Type: "package"})
+		}
+		if i := strings.LastIndexByte(prefix, '.'); i > 0 && pkgmap.HasPackage(prefix[:i]) {
+			for _, name := range pkgmap.Exports(prefix[:i]) {
+				add(exportCompletion(prefix[:i], name))
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Candidate < res[j].Candidate
+	})
+	return res
+}
+
+// metaKeywords returns the keywords in the metadata of the vars of
+// the loaded namespaces, as the keys of :arglists destructuring maps.
+// Keywords are collected when completing, rather than recorded as
+// they are created, so that those no longer used can be collected.
+func metaKeywords() []value.Keyword {
+	var kws []value.Keyword
+	var walk func(x interface{})
+	walk = func(x interface{}) {
+		switch x := x.(type) {
+		case value.Keyword:
+			kws = append(kws, x)
+		case value.IPersistentMap:
+			for seq := value.Seq(x); seq != nil; seq = seq.Next() {
+				entry := seq.First().(value.IMapEntry)
+				walk(entry.Key())
+				walk(entry.Val())
+			}
+		case value.IPersistentVector, value.IPersistentSet, value.IPersistentList:
+			for seq := value.Seq(x); seq != nil; seq = seq.Next() {
+				walk(seq.First())
+			}
+		}
+	}
+	for _, ns := range value.Namespaces() {
+		for seq := value.Seq(ns.Mappings()); seq != nil; seq = seq.Next() {
+			vr, ok := seq.First().(value.IMapEntry).Val().(*value.Var)
+			if ok && vr.Namespace() == ns {
+				walk(vr.Meta())
+			}
+		}
+	}
+	return kws
+}
+
+// inputKeywords returns the keywords written in input, outside of
+// strings and comments, as they are written.
+func inputKeywords(input string) []string {
+	var kws []string
+	line := []rune(input)
+	for i := 0; i < len(line); i++ {
+		switch r := line[i]; {
+		case r == '\\':
+			i++
+		case r == ';':
+			for i < len(line) && line[i] != '\n' {
+				i++
+			}
+		case r == '"':
+			for i++; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+		case r == ':' && (i == 0 || isDelimiter(line[i-1])):
+			end := i
+			for end < len(line) && !isDelimiter(line[end]) {
+				end++
+			}
+			if kw := string(line[i:end]); strings.TrimLeft(kw, ":") != "" {
+				kws = append(kws, kw)
+			}
+			i = end - 1
+		}
+	}
+	return kws
+}
+
+// exportCompletion returns the completion of the export name of the
+// Go package pkg: a function, a type (a class) or a variable.
+func exportCompletion(pkg, name string) Completion {
+	c := Completion{Candidate: pkg + "." + name, Type: "var"}
+	v, _ := pkgmap.Get(pkg + "." + name)
+	switch v.(type) {
+	case reflect.Type:
+		c.Type = "class"
+	default:
+		if v != nil && reflect.TypeOf(v).Kind() == reflect.Func {
+			c.Type = "function"
+		}
+	}
+	return c
+}
+
+func varCompletion(vr *value.Var) Completion {
+	c := Completion{
+		Candidate: vr.Symbol().Name(),
+		NS:        vr.Namespace().Name().Name(),
+		Type:      "var",
+	}
+	switch {
+	case vr.IsMacro():
+		c.Type = "macro"
+	case vr.IsBound():
+		if _, ok := vr.Get().(value.IFn); ok {
+			c.Type = "function"
+		}
+	}
+	return c
+}
+
+// symbolStart returns the index in line of the start of the symbol or
+// keyword that ends at pos.
+func symbolStart(line []rune, pos int) int {
+	start := pos
+	for start > 0 && !isDelimiter(line[start-1]) {
+		start--
+	}
+	return start
+}
+
+// isDelimiter reports whether r ends a symbol or keyword.
+func isDelimiter(r rune) bool {
+	return strings.ContainsRune(" \t\n,;()[]{}\"'`~@^\\", r)
+}
@@ -0,0 +1,50 @@
+package repl_test
+
+import (
+	"testing"
+
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/repl"
+)
+
+func TestComplete(t *testing.T) {
+	ns := lang.FindOrCreateNamespace(lang.NewSymbol("complete-test"))
+	ns.Intern(lang.NewSymbol("f")).SetMeta(lang.NewMap(
+		lang.NewKeyword("complete-test/local"), true,
+		lang.KWArglists, lang.NewList(lang.NewVector(lang.NewMap(lang.NewKeyword("complete-test-opt"), lang.NewSymbol("x")))),
+	))
+	input := `(f :complete-test-kw "a :complete-test-string") ; :complete-test-comment`
+
+	tests := []struct {
+		prefix string
+		want   repl.Completion
+	}{
+		{":complete-test-k", repl.Completion{Candidate: ":complete-test-kw", Type: "keyword"}},
+		{":complete-test-o", repl.Completion{Candidate: ":complete-test-opt", Type: "keyword"}},
+		{":complete-test/", repl.Completion{Candidate: ":complete-test/local", Type: "keyword"}},
+		{"::lo", repl.Completion{Candidate: "::local", Type: "keyword"}},
+		{"net$ht", repl.Completion{Candidate: "net$http", Type: "package"}},
+		{"strings.HasPre", repl.Completion{Candidate: "strings.HasPrefix", Type: "function"}},
+		{"strings.Build", repl.Completion{Candidate: "strings.Builder", Type: "class"}},
+		{"glojure.core/ma", repl.Completion{Candidate: "glojure.core/map", NS: "glojure.core", Type: "function"}},
+	}
+	for _, test := range tests {
+		var found bool
+		for _, c := range repl.Complete(ns, test.prefix, input) {
+			if c == test.want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("completions of %q do not include %+v", test.prefix, test.want)
+		}
+	}
+
+	// keywords in strings and comments, and the one being completed,
+	// are not offered.
+	for _, prefix := range []string{":complete-test-s", ":complete-test-c", ":complete-test-kw"} {
+		if got := repl.Complete(ns, prefix, input); len(got) != 0 {
+			t.Errorf("expected no completions of %q, got %+v", prefix, got)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// kwQuit is read to end a REPL.
+var kwQuit = value.NewKeyword("repl/quit")
+
+// CoreVar returns the var of glojure.core named name. The vars of the
+// REPL are defined by the core library, which is loaded after this
+// package is initialized.
+func CoreVar(name string) *value.Var {
+	return value.NSCore.FindInternedVar(value.NewSymbol(name))
+}
+
+// replRequires are required in the namespace of each REPL, as
+// clojure.main/repl-requires are, for the REPL utilities.
+const replRequires = `(require '[glojure.repl :refer [source apropos dir pst doc find-doc]] 'glojure.stacktrace)`
+
+// pushBindings binds the vars that may be set! in a REPL to their
+// current values, and *1, *2, *3 and *e to nil, in the current
+// goroutine, then switches to the namespace named ns and evaluates
+// replRequires in it. Unless it returns an error, the caller must call
+// value.PopThreadBindings when the REPL ends.
+func pushBindings(env value.Environment, ns string) error {
+	kvs := make([]interface{}, 0, 18)
+	for _, vr := range []*value.Var{value.VarCurrentNS, value.VarWarnOnReflection, value.VarUncheckedMath, value.VarDataReaders, value.VarPrintGoStack} {
+		kvs = append(kvs, vr, vr.Deref())
+	}
+	for _, name := range []string{"*1", "*2", "*3", "*e"} {
+		kvs = append(kvs, CoreVar(name), nil)
+	}
+	value.PushThreadBindings(value.NewMap(kvs...))
+	_, err := env.Eval(value.NewList(value.NewSymbol("ns"), value.NewSymbol(ns)))
+	if err == nil {
+		var form interface{}
+		if form, err = reader.New(strings.NewReader(replRequires)).ReadOne(); err == nil {
+			_, err = env.Eval(form)
+		}
+	}
+	if err != nil {
+		value.PopThreadBindings()
+	}
+	return err
+}
+
+// evalForm evaluates form, converting a panic to an error, and records
+// its value in *1, shifting the previous ones to *2 and *3, or its
+// error in *e.
+func evalForm(env value.Environment, form interface{}, eval func(value.Environment, interface{}) (interface{}, error)) (val interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if err, ok = r.(error); !ok {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+		if err != nil {
+			CoreVar("*e").Set(err)
+			return
+		}
+		var1, var2, var3 := CoreVar("*1"), CoreVar("*2"), CoreVar("*3")
+		var3.Set(var2.Deref())
+		var2.Set(var1.Deref())
+		var1.Set(val)
+	}()
+	return eval(env, form)
+}
+
+// runeScanner returns *in as a rune scanner for the reader.
+func runeScanner() io.RuneScanner {
+	in := value.VarIn.Deref()
+	if rs, ok := in.(io.RuneScanner); ok {
+		return rs
+	}
+	return bufio.NewReader(in.(io.Reader))
+}
+
+// Loop runs a REPL on the streams bound to *in*, *out* and *err*, as
+// the accept function of a socket REPL server does. It evaluates in
+// the user namespace, with its own bindings of *ns*, *1, *2, *3 and
+// *e, and returns at the end of *in* or when :repl/quit is read.
+func Loop() error {
+	env := value.GlobalEnv
+	if err := pushBindings(env, "user"); err != nil {
+		return err
+	}
+	defer value.PopThreadBindings()
+
+	rdr := reader.New(runeScanner(), reader.WithFilename("NO_SOURCE_FILE"), reader.WithGetCurrentNS(env.CurrentNamespace))
+	for {
+		out := value.VarOut.Deref().(io.Writer)
+		fmt.Fprintf(out, "%s=> ", env.CurrentNamespace().Name())
+		flush(out)
+
+		form, err := rdr.ReadOne()
+		if errors.Is(err, reader.ErrEOF) || errors.Is(err, io.EOF) || value.Equals(form, kwQuit) {
+			return nil
+		}
+		var val interface{}
+		if err == nil {
+			val, err = evalForm(env, form, value.Environment.Eval)
+		}
+		if err != nil {
+			errOut := value.VarErr.Deref().(io.Writer)
+			runtime.PrintError(errOut, err)
+			flush(errOut)
+			continue
+		}
+		out = value.VarOut.Deref().(io.Writer)
+		fmt.Fprintln(out, value.PrintString(val))
+	}
+}
+
+// flush flushes w if it is buffered.
+func flush(w io.Writer) {
+	if f, ok := w.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+}
@@ -7,10 +7,11 @@ import (
 )
 
 type options struct {
-	stdin     io.Reader
-	stdout    io.Writer
-	namespace string
-	env       value.Environment
+	stdin       io.Reader
+	stdout      io.Writer
+	namespace   string
+	env         value.Environment
+	historyFile string
 }
 
 // Option is a functional option for the REPL.
@@ -36,3 +37,12 @@ func WithEnvironment(env value.Environment) Option {
 		o.env = env
 	}
 }
+
+// WithHistoryFile sets the file in which the REPL saves the lines it
+// reads and from which it loads them at start, ~/.glj_history by
+// default. The empty string disables history.
+func WithHistoryFile(path string) Option {
+	return func(o *options) {
+		o.historyFile = path
+	}
+}
@@ -0,0 +1,216 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// The keys and tags of the maps sent by Prepl.
+var (
+	kwTag       = value.NewKeyword("tag")
+	kwVal       = value.NewKeyword("val")
+	kwNS        = value.NewKeyword("ns")
+	kwMS        = value.NewKeyword("ms")
+	kwForm      = value.NewKeyword("form")
+	kwException = value.NewKeyword("exception")
+	kwRet       = value.NewKeyword("ret")
+	kwOut       = value.NewKeyword("out")
+	kwErr       = value.NewKeyword("err")
+	kwTap       = value.NewKeyword("tap")
+
+	kwCause   = value.NewKeyword("cause")
+	kwVia     = value.NewKeyword("via")
+	kwType    = value.NewKeyword("type")
+	kwMessage = value.NewKeyword("message")
+	kwTrace   = value.NewKeyword("trace")
+	kwPhase   = value.NewKeyword("phase")
+
+	kwReadSource = value.NewKeyword("read-source")
+	kwExecution  = value.NewKeyword("execution")
+)
+
+// Prepl runs a REPL for programs: it reads forms from in and evaluates
+// them in the user namespace, with its own bindings of *ns*, *1, *2,
+// *3 and *e, calling outFn with a map for each result instead of
+// printing it. The maps are
+//
+//	{:tag :ret :val val :ns ns-name :ms elapsed-ms :form source}
+//
+// for each form, with :exception true and the data of the error as
+// returned by ErrorMap as :val if it fails, {:tag :out :val string}
+// and {:tag :err :val string} for each write to *out* and *err*, and
+// {:tag :tap :val x} for each value sent by tap>. *in* is bound to
+// stdin, or to in if it is nil. Prepl returns at the end of in, when
+// :repl/quit is read, or with the error of outFn if it fails.
+func Prepl(in io.Reader, outFn value.IFn, stdin io.Reader) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if err, ok = r.(error); !ok {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+
+	env := value.GlobalEnv
+	rs, ok := in.(io.RuneScanner)
+	if !ok {
+		rs = bufio.NewReader(in)
+	}
+	if stdin == nil {
+		stdin = rs.(io.Reader)
+	}
+	value.PushThreadBindings(value.NewMap(
+		value.VarIn, stdin,
+		value.VarOut, &preplWriter{outFn: outFn, tag: kwOut},
+		value.VarErr, &preplWriter{outFn: outFn, tag: kwErr},
+	))
+	defer value.PopThreadBindings()
+	if err := pushBindings(env, "user"); err != nil {
+		return err
+	}
+	defer value.PopThreadBindings()
+
+	tapFn := &tapper{outFn: outFn}
+	CoreVar("add-tap").Invoke(tapFn)
+	defer CoreVar("remove-tap").Invoke(tapFn)
+
+	src := &recorder{rs: rs}
+	rdr := reader.New(src, reader.WithFilename("NO_SOURCE_FILE"), reader.WithGetCurrentNS(env.CurrentNamespace))
+	for {
+		src.reset()
+		form, err := rdr.ReadOne()
+		if errors.Is(err, reader.ErrEOF) || errors.Is(err, io.EOF) || value.Equals(form, kwQuit) {
+			return nil
+		}
+		text := strings.TrimSpace(src.String())
+		if err != nil {
+			outFn.Invoke(value.NewMap(
+				kwTag, kwRet,
+				kwVal, ErrorMap(err, kwReadSource),
+				kwNS, env.CurrentNamespace().Name().Name(),
+				kwMS, int64(0),
+				kwForm, text,
+				kwException, true,
+			))
+			continue
+		}
+
+		start := time.Now()
+		val, err := evalForm(env, form, value.Environment.Eval)
+		ms := time.Since(start).Milliseconds()
+		ret := value.NewMap(
+			kwTag, kwRet,
+			kwVal, val,
+			kwNS, env.CurrentNamespace().Name().Name(),
+			kwMS, ms,
+			kwForm, text,
+		)
+		if err != nil {
+			ret = value.Assoc(ret, kwVal, ErrorMap(err, kwExecution)).(value.IPersistentMap)
+			ret = value.Assoc(ret, kwException, true).(value.IPersistentMap)
+		}
+		outFn.Invoke(ret)
+	}
+}
+
+// ErrorMap returns the data of err as a map, in the form of
+// Throwable->map: :via is a vector of maps of the :type and :message
+// of err and each error it wraps, :cause is the message of the last of
+// them and :trace is a vector of [ns fn file line] vectors of the
+// frames of its stack trace, most recent first, where fn is nil for
+// top-level forms. phase, if it is not nil, is added as :phase.
+func ErrorMap(err error, phase interface{}) value.IPersistentMap {
+	var via []interface{}
+	var cause string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		cause = runtime.ErrorMessage(e)
+		via = append(via, value.NewMap(
+			kwType, value.NewSymbol(fmt.Sprintf("%T", e)),
+			kwMessage, cause,
+		))
+	}
+	var trace []interface{}
+	for _, frame := range runtime.StackTrace(err) {
+		var fn interface{}
+		if frame.FunctionName != "" {
+			fn = value.NewSymbol(frame.FunctionName)
+		}
+		trace = append(trace, value.NewVector(value.NewSymbol(frame.Namespace), fn, frame.Filename, frame.Line))
+	}
+	m := value.NewMap(
+		kwCause, cause,
+		kwVia, value.NewVector(via...),
+		kwTrace, value.NewVector(trace...),
+	)
+	if phase != nil {
+		m = value.Assoc(m, kwPhase, phase).(value.IPersistentMap)
+	}
+	return m
+}
+
+// preplWriter is the *out* or *err* of a prepl, which sends each write
+// to its out function.
+type preplWriter struct {
+	outFn value.IFn
+	tag   value.Keyword
+}
+
+func (w *preplWriter) Write(p []byte) (int, error) {
+	w.outFn.Invoke(value.NewMap(kwTag, w.tag, kwVal, string(p)))
+	return len(p), nil
+}
+
+// tapper is the tap of a prepl, which sends the values sent by tap>
+// to its out function. Taps are kept in a set, so it is a pointer.
+type tapper struct {
+	outFn value.IFn
+}
+
+func (t *tapper) Invoke(args ...interface{}) interface{} {
+	t.outFn.Invoke(value.NewMap(kwTag, kwTap, kwVal, args[0]))
+	return nil
+}
+
+func (t *tapper) ApplyTo(args value.ISeq) interface{} {
+	return t.Invoke(value.ToSlice(args)...)
+}
+
+// recorder records the runes read from a rune scanner, to give the
+// source of each form read by a prepl.
+type recorder struct {
+	rs  io.RuneScanner
+	buf []rune
+}
+
+func (r *recorder) ReadRune() (rune, int, error) {
+	c, size, err := r.rs.ReadRune()
+	if err == nil {
+		r.buf = append(r.buf, c)
+	}
+	return c, size, err
+}
+
+func (r *recorder) UnreadRune() error {
+	err := r.rs.UnreadRune()
+	if err == nil && len(r.buf) > 0 {
+		r.buf = r.buf[:len(r.buf)-1]
+	}
+	return err
+}
+
+func (r *recorder) reset() {
+	r.buf = r.buf[:0]
+}
+
+func (r *recorder) String() string {
+	return string(r.buf)
+}
@@ -3,48 +3,29 @@
 package repl
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
-	"runtime/debug"
-	"runtime/pprof"
+	"os/signal"
+	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/chzyer/readline"
 
 	value "github.com/glojurelang/glojure/pkg/lang"
 	"github.com/glojurelang/glojure/pkg/reader"
 	"github.com/glojurelang/glojure/pkg/runtime"
-
-	// pprof
-	"net/http"
-	_ "net/http/pprof"
 )
 
-const debugMode = false
-const cpuProfile = false
-
-func init() {
-	// start pprof
-	if debugMode {
-		go func() {
-			if err := http.ListenAndServe("localhost:6060", nil); err != nil {
-				fmt.Println("pprof start failed:", err)
-			}
-		}()
-		// shell command to examine pprof profile with a web ui:
-		// $ go tool pprof -http=:8080 http://localhost:6060/debug/pprof/profile
-	}
-}
-
 // Start starts the REPL.
 func Start(opts ...Option) {
 	o := options{
-		stdin:     os.Stdin,
-		stdout:    os.Stdout,
-		namespace: "user",
+		stdin:       os.Stdin,
+		stdout:      os.Stdout,
+		namespace:   "user",
+		historyFile: defaultHistoryFile(),
 	}
 	for _, opt := range opts {
 		opt(&o)
@@ -53,15 +34,12 @@ func Start(opts ...Option) {
 	if o.env == nil {
 		o.env = initEnv(o.stdout)
 	}
-	{ // set namespace
-		_, err := o.env.Eval(value.NewList(
-			value.NewSymbol("ns"),
-			value.NewSymbol(o.namespace),
-		))
-		if err != nil {
-			panic(err)
-		}
+	// *1, *2, *3 hold the last values and *e the last error, which
+	// (pst) prints.
+	if err := pushBindings(o.env, o.namespace); err != nil {
+		panic(err)
 	}
+	defer value.PopThreadBindings()
 
 	defaultPrompt := func() string {
 		curNS := "?"
@@ -70,11 +48,14 @@ func Start(opts ...Option) {
 		return curNS + "=> "
 	}
 
+	comp := &completer{}
 	rl, err := readline.NewEx(&readline.Config{
 		Prompt: defaultPrompt(),
 		//DisableAutoSaveHistory: true,
-		Stdin:  io.NopCloser(o.stdin),
-		Stdout: o.stdout,
+		HistoryFile:  o.historyFile,
+		AutoComplete: comp,
+		Stdin:        io.NopCloser(o.stdin),
+		Stdout:       o.stdout,
 	})
 	if err != nil {
 		panic(err)
@@ -84,6 +65,7 @@ func Start(opts ...Option) {
 	var expr string
 
 	for {
+		comp.ns = o.env.CurrentNamespace()
 		line, err := rl.Readline()
 		if err != nil {
 			break
@@ -104,31 +86,67 @@ func Start(opts ...Option) {
 		}
 		expr = ""
 		for _, val := range vals {
-			out, err := func() (out string, err error) {
-				defer func() {
-					if panicErr := recover(); panicErr != nil {
-						err = fmt.Errorf("panic: %v\nstacktrace:\n%s", panicErr, string(debug.Stack()))
-					}
-				}()
-
-				//runtime.Debug = true
-				val, err := o.env.Eval(val)
-				runtime.Debug = false
-				if err != nil {
-					return "", err
-				}
-				return value.PrintString(val), nil
-			}()
+			val, err := evalForm(o.env, val, evalInterruptible)
 			if err != nil {
-				fmt.Fprintln(o.stdout, err)
+				runtime.PrintError(o.stdout, err)
 				continue
 			}
-			fmt.Fprintln(o.stdout, out)
+			fmt.Fprintln(o.stdout, value.PrintString(val))
 		}
 		rl.SetPrompt(defaultPrompt())
 	}
 }
 
+// defaultHistoryFile returns the path of ~/.glj_history, or "" if the
+// home directory is unknown.
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".glj_history")
+}
+
+// completer completes the symbol or keyword before the cursor with
+// Complete in ns, the current namespace when the line was read, and
+// the line being edited. It is called in a goroutine of readline,
+// where *ns* is not bound.
+type completer struct {
+	ns *value.Namespace
+}
+
+func (c *completer) Do(line []rune, pos int) ([][]rune, int) {
+	start := symbolStart(line, pos)
+	prefix := string(line[start:pos])
+	var res [][]rune
+	for _, comp := range Complete(c.ns, prefix, string(line)) {
+		res = append(res, []rune(comp.Candidate[len(prefix):]))
+	}
+	return res, pos - start
+}
+
+// evalInterruptible evaluates form, canceling the evaluation if an
+// interrupt signal (Ctrl-C) is received before it completes.
+func evalInterruptible(env value.Environment, form interface{}) (interface{}, error) {
+	ctx, cancel := context.WithCancel(env.Context())
+	defer cancel()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	defer signal.Stop(sigs)
+	go func() {
+		select {
+		case <-sigs:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	value.PushContext(ctx)
+	defer value.PopContext()
+	return env.Eval(form)
+}
+
 func readLine(r io.Reader) (string, error) {
 	var line string
 	for {
@@ -145,16 +163,6 @@ func readLine(r io.Reader) (string, error) {
 }
 
 func initEnv(stdout io.Writer) value.Environment {
-	if cpuProfile {
-		f, err := os.Create("./gljInitEnvCpu.prof")
-		if err != nil {
-			panic(err)
-		}
-		pprof.StartCPUProfile(f)
-		defer pprof.StopCPUProfile()
-	}
-	startTime := time.Now()
-
 	// TODO: clean up this code. copied from rtcompat.go.
 	kvs := make([]interface{}, 0, 3)
 	for _, vr := range []*value.Var{value.VarCurrentNS, value.VarWarnOnReflection, value.VarUncheckedMath, value.VarDataReaders} {
@@ -162,10 +170,5 @@ func initEnv(stdout io.Writer) value.Environment {
 	}
 	value.PushThreadBindings(value.NewMap(kvs...))
 
-	env := runtime.NewEnvironment(runtime.WithStdout(stdout))
-	if debugMode {
-		fmt.Printf("Environment created in %v\n", time.Since(startTime))
-	}
-
-	return env
+	return runtime.NewEnvironment(runtime.WithStdout(stdout))
 }
@@ -9,18 +9,13 @@ import (
 	"io"
 	"os"
 	"runtime/debug"
-	"runtime/pprof"
 	"strings"
-	"time"
 
 	value "github.com/glojurelang/glojure/pkg/lang"
 	"github.com/glojurelang/glojure/pkg/reader"
 	"github.com/glojurelang/glojure/pkg/runtime"
 )
 
-const debugMode = false
-const cpuProfile = false
-
 // Start starts the REPL.
 func Start(opts ...Option) {
 	o := options{
@@ -85,9 +80,7 @@ func Start(opts ...Option) {
 					}
 				}()
 
-				//runtime.Debug = true
 				val, err := o.env.Eval(val)
-				runtime.Debug = false
 				if err != nil {
 					return "", err
 				}
@@ -119,16 +112,6 @@ func readLine(r io.Reader) (string, error) {
 }
 
 func initEnv(stdout io.Writer) value.Environment {
-	if cpuProfile {
-		f, err := os.Create("./gljInitEnvCpu.prof")
-		if err != nil {
-			panic(err)
-		}
-		pprof.StartCPUProfile(f)
-		defer pprof.StopCPUProfile()
-	}
-	startTime := time.Now()
-
 	// TODO: clean up this code. copied from rtcompat.go.
 	kvs := make([]interface{}, 0, 3)
 	for _, vr := range []*value.Var{value.VarCurrentNS, value.VarWarnOnReflection, value.VarUncheckedMath, value.VarDataReaders} {
@@ -136,10 +119,5 @@ func initEnv(stdout io.Writer) value.Environment {
 	}
 	value.PushThreadBindings(value.NewMap(kvs...))
 
-	env := runtime.NewEnvironment(runtime.WithStdout(stdout))
-	if debugMode {
-		fmt.Printf("Environment created in %v\n", time.Since(startTime))
-	}
-
-	return env
+	return runtime.NewEnvironment(runtime.WithStdout(stdout))
 }
@@ -0,0 +1,32 @@
+package repl
+
+import (
+	"strings"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+)
+
+// ResolveVar returns the var named by sym in ns, or nil.
+func ResolveVar(ns *value.Namespace, sym *value.Symbol) *value.Var {
+	if sym.Namespace() != "" {
+		target := value.NamespaceFor(ns, sym)
+		if target == nil {
+			return nil
+		}
+		return target.FindInternedVar(value.NewSymbol(sym.Name()))
+	}
+	vr, _ := ns.GetMapping(sym).(*value.Var)
+	return vr
+}
+
+// NamespaceNamed returns the namespace named or aliased by sym in ns,
+// or nil.
+func NamespaceNamed(ns *value.Namespace, sym *value.Symbol) *value.Namespace {
+	if sym.Namespace() != "" || strings.HasSuffix(sym.Name(), "/") {
+		return nil
+	}
+	if target := ns.LookupAlias(sym); target != nil {
+		return target
+	}
+	return value.FindNamespace(sym)
+}
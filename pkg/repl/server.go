@@ -0,0 +1,184 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// The options of StartServer.
+var (
+	kwName    = value.NewKeyword("name")
+	kwPort    = value.NewKeyword("port")
+	kwHost    = value.NewKeyword("host")
+	kwAccept  = value.NewKeyword("accept")
+	kwArgs    = value.NewKeyword("args")
+	kwBindErr = value.NewKeyword("bind-err")
+	kwServer  = value.NewKeyword("server")
+	kwClient  = value.NewKeyword("client")
+)
+
+// servers are the running socket servers, by name.
+var (
+	serversMu sync.Mutex
+	servers   = map[string]net.Listener{}
+)
+
+// StartServer starts a socket server with the options in opts and
+// returns its listener. Each connection to the server is served in
+// its own goroutine by calling its accept function with *in*, *out*
+// and *err* bound to the connection. The options are:
+//
+//	:name     the name of the server, which must be unique (required)
+//	:port     the port to listen on, or 0 for any free port (required)
+//	:host     the address to listen on, 127.0.0.1 by default
+//	:accept   the qualified symbol of the accept function, whose
+//	          namespace is required if needed (required)
+//	:args     a sequence of arguments to the accept function
+//	:bind-err whether to bind *err* to the connection, true by default
+//
+// glojure.core.server/*session* is bound to a map of the :server name
+// and the :client id of the connection.
+func StartServer(opts value.IPersistentMap) (net.Listener, error) {
+	name, ok := value.Get(opts, kwName).(string)
+	if !ok {
+		return nil, errors.New("server :name must be a string")
+	}
+	port, ok := value.AsInt(value.Get(opts, kwPort))
+	if !ok {
+		return nil, fmt.Errorf("server %s: :port must be an integer", name)
+	}
+	host := "127.0.0.1"
+	if h, ok := value.Get(opts, kwHost).(string); ok {
+		host = h
+	}
+	accept, err := resolveAccept(value.Get(opts, kwAccept))
+	if err != nil {
+		return nil, fmt.Errorf("server %s: %w", name, err)
+	}
+	args := value.ToSlice(value.Get(opts, kwArgs))
+	bindErr := true
+	if b := value.Get(opts, kwBindErr); b != nil {
+		bindErr = value.IsTruthy(b)
+	}
+
+	serversMu.Lock()
+	defer serversMu.Unlock()
+	if _, ok := servers[name]; ok {
+		return nil, fmt.Errorf("server %s already exists", name)
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	servers[name] = ln
+
+	go func() {
+		for client := 1; ; client++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				serversMu.Lock()
+				if servers[name] == ln {
+					delete(servers, name)
+				}
+				serversMu.Unlock()
+				return
+			}
+			go serveConn(conn, name, strconv.Itoa(client), accept, args, bindErr)
+		}
+	}()
+	return ln, nil
+}
+
+// StopServer stops the server named name, and reports whether it was
+// running. Its connections are not closed.
+func StopServer(name string) (bool, error) {
+	serversMu.Lock()
+	ln, ok := servers[name]
+	delete(servers, name)
+	serversMu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	return true, ln.Close()
+}
+
+// StopServers stops all running servers.
+func StopServers() {
+	serversMu.Lock()
+	defer serversMu.Unlock()
+	for name, ln := range servers {
+		ln.Close()
+		delete(servers, name)
+	}
+}
+
+// resolveAccept returns the var named by sym, a qualified symbol,
+// first requiring its namespace if it is not loaded.
+func resolveAccept(sym interface{}) (*value.Var, error) {
+	s, ok := sym.(*value.Symbol)
+	if !ok || s.Namespace() == "" {
+		return nil, fmt.Errorf(":accept must be a qualified symbol, got %v", value.PrintString(sym))
+	}
+	nsSym := value.NewSymbol(s.Namespace())
+	ns := value.FindNamespace(nsSym)
+	if ns == nil {
+		value.PushThreadBindings(value.NewMap(value.VarCurrentNS, value.VarCurrentNS.Deref()))
+		_, err := value.GlobalEnv.Eval(value.NewList(value.NewSymbol("require"), value.NewList(value.NewSymbol("quote"), nsSym)))
+		value.PopThreadBindings()
+		if err != nil {
+			return nil, err
+		}
+		ns = value.FindNamespace(nsSym)
+	}
+	var vr *value.Var
+	if ns != nil {
+		vr = ns.FindInternedVar(value.NewSymbol(s.Name()))
+	}
+	if vr == nil {
+		return nil, fmt.Errorf("unable to resolve :accept %s", s)
+	}
+	return vr, nil
+}
+
+// serveConn calls accept with args to serve conn, the connection with
+// id client to the server named name.
+func serveConn(conn net.Conn, name, client string, accept *value.Var, args []interface{}, bindErr bool) {
+	defer conn.Close()
+
+	kvs := []interface{}{
+		value.VarIn, bufio.NewReader(conn),
+		value.VarOut, conn,
+	}
+	if bindErr {
+		kvs = append(kvs, value.VarErr, conn)
+	}
+	if ns := value.FindNamespace(value.NewSymbol("glojure.core.server")); ns != nil {
+		if session := ns.FindInternedVar(value.NewSymbol("*session*")); session != nil {
+			kvs = append(kvs, session, value.NewMap(kwServer, name, kwClient, client))
+		}
+	}
+	value.PushThreadBindings(value.NewMap(kvs...))
+	defer value.PopThreadBindings()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = fmt.Errorf("%v", r)
+			}
+			if !errors.Is(err, net.ErrClosed) {
+				fmt.Fprintf(os.Stderr, "server %s: client %s: ", name, client)
+				runtime.PrintError(os.Stderr, err)
+			}
+		}
+	}()
+	value.Apply(accept, args)
+}
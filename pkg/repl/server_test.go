@@ -0,0 +1,113 @@
+package repl_test
+
+import (
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/glojurelang/glojure/pkg/glj"
+	"github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/repl"
+)
+
+// session sends input to a new connection to a server with the given
+// accept function, and returns all its output.
+func session(t *testing.T, name, accept, input string) string {
+	t.Helper()
+	ln, err := repl.StartServer(lang.NewMap(
+		lang.NewKeyword("name"), name,
+		lang.NewKeyword("port"), 0,
+		lang.NewKeyword("accept"), lang.NewSymbol(accept),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repl.StopServer(name)
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	if _, err := io.WriteString(conn, input); err != nil {
+		t.Fatal(err)
+	}
+	conn.(*net.TCPConn).CloseWrite()
+	out, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestSocketREPL(t *testing.T) {
+	out := session(t, "test-repl", "glojure.core.server/repl", strings.Join([]string{
+		`(+ 1 2)`,
+		`(println "hi")`,
+		`(inc *2)`,
+		`(:server glojure.core.server/*session*)`,
+		`(ns other)`,
+		`(/ 1 0)`,
+		`(some? *e)`,
+		`:repl/quit`,
+		`:not-read`,
+	}, "\n"))
+	for _, want := range []string{
+		"user=> 3\n",
+		"user=> hi\nnil\n",
+		"user=> 4\n",
+		"user=> \"test-repl\"\n",
+		"other=> Execution error at other (NO_SOURCE_FILE:6:1):\ndivide by zero\n",
+		"other=> true\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, ":not-read") {
+		t.Errorf("read past :repl/quit:\n%s", out)
+	}
+}
+
+func TestIOPrepl(t *testing.T) {
+	out := session(t, "test-prepl", "glojure.core.server/io-prepl", "(+ 1 2)\n(print \"hi\")\n(ns other)\n(/ 1 0)\n")
+	for _, want := range []string{
+		`{:tag :ret, :val "3", :ns "user", :ms `,
+		`:form "(+ 1 2)"}`,
+		`{:tag :out, :val "hi"}`,
+		`{:tag :ret, :val "nil", :ns "user", :ms `,
+		`:form "(print \"hi\")"}`,
+		`:form "(/ 1 0)", :exception true}`,
+		`:cause \"divide by zero\"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestStopServer(t *testing.T) {
+	opts := lang.NewMap(
+		lang.NewKeyword("name"), "test-stop",
+		lang.NewKeyword("port"), 0,
+		lang.NewKeyword("accept"), lang.NewSymbol("glojure.core.server/repl"),
+	)
+	if _, err := repl.StartServer(opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repl.StartServer(opts); err == nil {
+		t.Error("started a second server with the same name")
+	}
+	if ok, err := repl.StopServer("test-stop"); !ok || err != nil {
+		t.Errorf("StopServer = %v, %v, want true, nil", ok, err)
+	}
+	if ok, _ := repl.StopServer("test-stop"); ok {
+		t.Error("stopped a server twice")
+	}
+	if _, err := repl.StartServer(lang.Assoc(opts, lang.NewKeyword("accept"), lang.NewSymbol("repl")).(lang.IPersistentMap)); err == nil {
+		t.Error("started a server with an unqualified accept function")
+	}
+}
@@ -0,0 +1,33 @@
+package repl
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+// Source returns the source text of the form that starts at line of
+// file, as named by the :file and :line metadata of a var. file is an
+// absolute path or a path on the load path, such as those of the
+// standard library.
+func Source(file string, line int) (string, error) {
+	src, err := runtime.ReadSourceFile(file)
+	if err != nil {
+		return "", err
+	}
+	for i := 1; i < line; i++ {
+		j := bytes.IndexByte(src, '\n')
+		if j < 0 {
+			return "", fmt.Errorf("%s has no line %d", file, line)
+		}
+		src = src[j+1:]
+	}
+	rec := &recorder{rs: bytes.NewReader(src)}
+	if _, err := reader.New(rec, reader.WithFilename(file)).ReadOne(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(rec.String()), nil
+}
@@ -0,0 +1,1127 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/glojurelang/glojure/pkg/ast"
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/pkgmap"
+)
+
+// An AST is compiled once into a tree of closures, one per node. Vars,
+// constants, locals and fn arities are resolved during compilation,
+// so evaluating a node does no dispatch on its op and no lookup of its
+// locals by name.
+//
+// Locals live in slots of a frame, one per invocation of a fn method
+// or evaluation of a top-level form. A fn copies the locals it closes
+// over from the frame in which it is created.
+
+type (
+	// code evaluates a compiled node in frame f.
+	code func(f *frame) (interface{}, error)
+
+	frame struct {
+		slots  []interface{}
+		closed []interface{}
+		fn     *Fn
+		// debug is the frame's frame of a debugger thread, if the code
+		// evaluated in it was compiled with a debugger attached.
+		debug *debugFrame
+	}
+
+	codeCompiler struct {
+		env   *environment
+		scope *frameScope
+		// recur is the target of recur forms in the node being
+		// compiled, or nil if there is none.
+		recur *recurTarget
+		// frame is the stack frame of the code being compiled, as
+		// returned by FnFrame, without a source position.
+		frame value.StackFrame
+		// defFn is the fn node that is the value of the def node being
+		// compiled, and defName the name of its var.
+		defFn   *ast.Node
+		defName *value.Symbol
+	}
+
+	// frameScope is the compile-time scope of the locals of a frame.
+	frameScope struct {
+		// closure is the fn the frame belongs to, or nil for top-level
+		// forms.
+		closure  *closure
+		locals   []scopeLocal
+		numSlots int
+	}
+
+	scopeLocal struct {
+		name string
+		slot int
+	}
+
+	// closure records the locals a fn closes over.
+	closure struct {
+		parent *frameScope
+		// self is the name of the fn, if it has one.
+		self     string
+		captures []localRef
+		names    []string
+	}
+
+	localKind int
+
+	localRef struct {
+		kind localKind
+		idx  int
+	}
+
+	recurTarget struct {
+		slots []int
+		// err, if set, is the error of a recur to this target.
+		err string
+	}
+)
+
+const (
+	localSlot localKind = iota
+	localClosed
+	localSelf
+)
+
+// errRecur is returned by a recur form, after assigning the slots of
+// its target, to restart the target's body.
+var errRecur = errors.New("recur")
+
+// compileTopLevel compiles a top-level node, returning its code and
+// the number of slots of the frame to evaluate it in.
+func (env *environment) compileTopLevel(n *ast.Node) (code, int, error) {
+	c := &codeCompiler{env: env, scope: &frameScope{}}
+	c.frame.Namespace = env.CurrentNamespace().Name().Name()
+	cd, err := c.compile(n)
+	if err != nil {
+		return nil, 0, err
+	}
+	if d := c.debugger(); d != nil {
+		cd = c.debugFrameCode(d, n, cd)
+	}
+	return cd, c.scope.numSlots, nil
+}
+
+func (s *frameScope) bind(name *value.Symbol) int {
+	slot := s.numSlots
+	s.numSlots++
+	s.locals = append(s.locals, scopeLocal{name: name.Name(), slot: slot})
+	return slot
+}
+
+func (s *frameScope) lookup(name string) (localRef, bool) {
+	for i := len(s.locals) - 1; i >= 0; i-- {
+		if s.locals[i].name == name {
+			return localRef{kind: localSlot, idx: s.locals[i].slot}, true
+		}
+	}
+	if s.closure == nil {
+		return localRef{}, false
+	}
+	return s.closure.lookup(name)
+}
+
+func (cl *closure) lookup(name string) (localRef, bool) {
+	if cl.self != "" && cl.self == name {
+		return localRef{kind: localSelf}, true
+	}
+	for i, n := range cl.names {
+		if n == name {
+			return localRef{kind: localClosed, idx: i}, true
+		}
+	}
+	ref, ok := cl.parent.lookup(name)
+	if !ok {
+		return localRef{}, false
+	}
+	cl.captures = append(cl.captures, ref)
+	cl.names = append(cl.names, name)
+	return localRef{kind: localClosed, idx: len(cl.captures) - 1}, true
+}
+
+func (f *frame) load(ref localRef) interface{} {
+	switch ref.kind {
+	case localSlot:
+		return f.slots[ref.idx]
+	case localClosed:
+		return f.closed[ref.idx]
+	default:
+		return f.fn
+	}
+}
+
+// withLocals calls fn, then unbinds the locals bound during the call.
+func (c *codeCompiler) withLocals(fn func() (code, error)) (code, error) {
+	numLocals := len(c.scope.locals)
+	defer func() {
+		c.scope.locals = c.scope.locals[:numLocals]
+	}()
+	return fn()
+}
+
+// compile compiles n, recording its evaluations in the coverage of the
+// environment and letting the attached debugger stop before it is
+// evaluated, if they are set.
+func (c *codeCompiler) compile(n *ast.Node) (code, error) {
+	cd, err := c.compileNode(n)
+	if err != nil {
+		return nil, err
+	}
+	if cov := c.env.coverage.Load(); cov != nil {
+		cd = c.coverForm(cov, n, cd)
+	}
+	if d := c.debugger(); d != nil {
+		cd = c.debugForm(d, n, cd)
+	}
+	return cd, nil
+}
+
+func (c *codeCompiler) compileNode(n *ast.Node) (code, error) {
+	switch n.Op {
+	case ast.OpConst:
+		return constCode(n.Sub.(*ast.ConstNode).Value), nil
+	case ast.OpDef:
+		return c.compileDef(n)
+	case ast.OpSetBang:
+		return c.compileSetBang(n)
+	case ast.OpMaybeClass:
+		return c.compileMaybeClass(n)
+	case ast.OpWithMeta:
+		return c.compileWithMeta(n)
+	case ast.OpFn:
+		return c.compileFn(n)
+	case ast.OpMap:
+		return c.compileMap(n)
+	case ast.OpVector:
+		return c.compileVector(n)
+	case ast.OpSet:
+		return c.compileSet(n)
+	case ast.OpDo:
+		return c.compileDo(n)
+	case ast.OpLet:
+		return c.compileLet(n, false)
+	case ast.OpLoop:
+		return c.compileLet(n, true)
+	case ast.OpLetFn:
+		return c.compileLetFn(n)
+	case ast.OpInvoke:
+		return c.compileInvoke(n)
+	case ast.OpQuote:
+		return constCode(n.Sub.(*ast.QuoteNode).Expr.Sub.(*ast.ConstNode).Value), nil
+	case ast.OpVar:
+		return c.compileVar(n)
+	case ast.OpLocal:
+		return c.compileLocal(n)
+	case ast.OpGoBuiltin:
+		return constCode(n.Sub.(*ast.GoBuiltinNode).Value), nil
+	case ast.OpGo:
+		return c.compileGo(n)
+	case ast.OpHostCall:
+		return c.compileHostCall(n)
+	case ast.OpHostInterop:
+		return c.compileHostInterop(n)
+	case ast.OpMaybeHostForm:
+		return c.compileMaybeHostForm(n)
+	case ast.OpIf:
+		return c.compileIf(n)
+	case ast.OpCase:
+		return c.compileCase(n)
+	case ast.OpTheVar:
+		return constCode(n.Sub.(*ast.TheVarNode).Var), nil
+	case ast.OpRecur:
+		return c.compileRecur(n)
+	case ast.OpNew:
+		return c.compileNew(n)
+	case ast.OpTry:
+		return c.compileTry(n)
+	case ast.OpThrow:
+		return c.compileThrow(n)
+	default:
+		panic(fmt.Errorf("unimplemented op: %d. Form: %s", n.Op, value.ToString(n.Form)))
+	}
+}
+
+func (c *codeCompiler) compileAll(nodes []*ast.Node) ([]code, error) {
+	codes := make([]code, len(nodes))
+	for i, n := range nodes {
+		cd, err := c.compile(n)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = cd
+	}
+	return codes, nil
+}
+
+func evalAll(f *frame, codes []code) ([]interface{}, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	vals := make([]interface{}, len(codes))
+	for i, cd := range codes {
+		val, err := cd(f)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = val
+	}
+	return vals, nil
+}
+
+func constCode(v interface{}) code {
+	return func(*frame) (interface{}, error) {
+		return v, nil
+	}
+}
+
+func (c *codeCompiler) compileDef(n *ast.Node) (code, error) {
+	defNode := n.Sub.(*ast.DefNode)
+	if value.IsNil(defNode.Init) {
+		if value.IsNil(defNode.Meta) {
+			return constCode(defNode.Var), nil
+		}
+		metaCode, err := c.compile(defNode.Meta)
+		if err != nil {
+			return nil, err
+		}
+		return func(f *frame) (interface{}, error) {
+			metaVal, err := metaCode(f)
+			if err != nil {
+				return nil, err
+			}
+			return declare(defNode.Var, metaVal), nil
+		}, nil
+	}
+
+	c.defFn, c.defName = DefFn(n), defNode.Name
+	initCode, err := c.compile(defNode.Init)
+	if err != nil {
+		return nil, err
+	}
+	var metaCode code
+	if !value.IsNil(defNode.Meta) {
+		metaCode, err = c.compile(defNode.Meta)
+		if err != nil {
+			return nil, err
+		}
+	}
+	env := c.env
+	return func(f *frame) (interface{}, error) {
+		initVal, err := initCode(f)
+		if err != nil {
+			return nil, err
+		}
+		var metaVal interface{}
+		if metaCode != nil {
+			metaVal, err = metaCode(f)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return env.def(defNode.Var, initVal, metaVal)
+	}, nil
+}
+
+// def binds the root of vr, defined by a def form, to init, and sets
+// its metadata to meta, the evaluated metadata of its symbol, if it is
+// not nil. vr is the var interned when the form was analyzed, so a def
+// evaluated after *ns* has changed still defines it.
+func (env *environment) def(vr *value.Var, init, meta interface{}) (interface{}, error) {
+	vr.BindRoot(init)
+	if m, ok := meta.(value.IPersistentMap); ok {
+		vr.SetMeta(m)
+	}
+	if RT.BooleanCast(value.Get(vr.Meta(), value.KWDynamic)) {
+		vr.SetDynamic()
+	}
+	return vr, nil
+}
+
+// declare sets the metadata of vr, defined without an init, to meta,
+// the evaluated metadata of its symbol. Its root is left unchanged.
+func declare(vr *value.Var, meta interface{}) *value.Var {
+	if m, ok := meta.(value.IPersistentMap); ok {
+		vr.SetMeta(m)
+		if RT.BooleanCast(value.Get(m, value.KWDynamic)) {
+			vr.SetDynamic()
+		}
+	}
+	return vr
+}
+
+func (c *codeCompiler) compileSetBang(n *ast.Node) (code, error) {
+	setBangNode := n.Sub.(*ast.SetBangNode)
+
+	valCode, err := c.compile(setBangNode.Val)
+	if err != nil {
+		return nil, err
+	}
+	target := setBangNode.Target
+	switch target.Op {
+	case ast.OpVar:
+		tgtVar := target.Sub.(*ast.VarNode).Var
+		return func(f *frame) (interface{}, error) {
+			val, err := valCode(f)
+			if err != nil {
+				return nil, err
+			}
+			return tgtVar.Set(val), nil
+		}, nil
+	case ast.OpHostInterop:
+		if c.env.sandbox.Load() != nil {
+			return nil, c.env.errorf(n.Form, "%w", value.NewIllegalAccessError("setting Go fields is not allowed in this sandbox"))
+		}
+		interopNode := target.Sub.(*ast.HostInteropNode)
+		tgtCode, err := c.compile(interopNode.Target)
+		if err != nil {
+			return nil, err
+		}
+		field := interopNode.MOrF
+		return func(f *frame) (interface{}, error) {
+			val, err := valCode(f)
+			if err != nil {
+				return nil, err
+			}
+			interopTargetVal, err := tgtCode(f)
+			if err != nil {
+				return nil, err
+			}
+			return setField(interopTargetVal, field, val)
+		}, nil
+	default:
+		return func(*frame) (interface{}, error) {
+			return nil, fmt.Errorf("unsupported assign target: %v", target.Form)
+		}, nil
+	}
+}
+
+func setField(target interface{}, field *value.Symbol, val interface{}) (interface{}, error) {
+	targetV := reflect.ValueOf(target)
+	if targetV.Kind() == reflect.Ptr {
+		targetV = targetV.Elem()
+	}
+	fieldVal := targetV.FieldByName(field.Name())
+	if !fieldVal.IsValid() {
+		return nil, fmt.Errorf("no such field %s", field.Name())
+	}
+	if !fieldVal.CanSet() {
+		return nil, fmt.Errorf("cannot set field %s", field.Name())
+	}
+	valV := reflect.ValueOf(val)
+	if !valV.IsValid() {
+		switch fieldVal.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice, reflect.UnsafePointer:
+			fieldVal.Set(reflect.Zero(fieldVal.Type()))
+		default:
+			return nil, fmt.Errorf("cannot set field %s to nil", field.Name())
+		}
+	} else {
+		fieldVal.Set(valV)
+	}
+	return val, nil
+}
+
+func (c *codeCompiler) compileMaybeClass(n *ast.Node) (code, error) {
+	sym := n.Sub.(*ast.MaybeClassNode).Class.(*value.Symbol)
+	if _, ok := pkgmap.Get(sym.FullName()); ok {
+		if err := c.env.CheckExport(sym.FullName()); err != nil {
+			return nil, c.env.errorf(n.Form, "%w", err)
+		}
+	}
+	return func(*frame) (interface{}, error) {
+		return maybeClass(sym)
+	}, nil
+}
+
+func (c *codeCompiler) compileMaybeHostForm(n *ast.Node) (code, error) {
+	hostFormNode := n.Sub.(*ast.MaybeHostFormNode)
+	field := hostFormNode.Field
+	// TODO: implement this for real
+	switch hostFormNode.Class {
+	case "glojure.lang.PersistentTreeSet":
+		switch field.Name() {
+		case "create":
+			return constCode(func(keys interface{}) interface{} {
+				var ks []interface{}
+				for seq := value.Seq(keys); seq != nil; seq = seq.Next() {
+					ks = append(ks, seq.First())
+				}
+				return value.NewSet(ks...)
+			}), nil
+		}
+	}
+
+	return func(*frame) (interface{}, error) {
+		// TODO: how to handle?
+		panic("EvalASTMaybeHostForm: " + hostFormNode.Class + "/" + field.Name())
+	}, nil
+}
+
+func (c *codeCompiler) compileHostCall(n *ast.Node) (code, error) {
+	hostCallNode := n.Sub.(*ast.HostCallNode)
+
+	tgtCode, err := c.compile(hostCallNode.Target)
+	if err != nil {
+		return nil, err
+	}
+	argCodes, err := c.compileAll(hostCallNode.Args)
+	if err != nil {
+		return nil, err
+	}
+	site := newHostSite(hostCallNode.Method, hostCallNode.GoTry, warnOnReflection(), func() string {
+		return callLocation(n.Form)
+	})
+	stackFrame := CallFrame(c.frame, n)
+	return func(f *frame) (res interface{}, err error) {
+		tgtVal, err := tgtCode(f)
+		if err != nil {
+			return nil, err
+		}
+		argVals, err := evalAll(f, argCodes)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				err = invokeError(stackFrame, r)
+			}
+		}()
+		res, err = site.call(tgtVal, argVals)
+		if err != nil {
+			return nil, withFrame(stackFrame, err)
+		}
+		return res, nil
+	}, nil
+}
+
+func (c *codeCompiler) compileHostInterop(n *ast.Node) (code, error) {
+	hostInteropNode := n.Sub.(*ast.HostInteropNode)
+
+	tgtCode, err := c.compile(hostInteropNode.Target)
+	if err != nil {
+		return nil, err
+	}
+	site := newHostSite(hostInteropNode.MOrF, hostInteropNode.GoTry, warnOnReflection(), func() string {
+		return callLocation(n.Form)
+	})
+	stackFrame := CallFrame(c.frame, n)
+	return func(f *frame) (res interface{}, err error) {
+		tgtVal, err := tgtCode(f)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				err = invokeError(stackFrame, r)
+			}
+		}()
+		res, err = site.interop(tgtVal)
+		if err != nil {
+			return nil, withFrame(stackFrame, err)
+		}
+		return res, nil
+	}, nil
+}
+
+// warnOnReflection reports whether *warn-on-reflection* is set, in
+// which case the host forms being compiled warn when they become
+// megamorphic.
+func warnOnReflection() bool {
+	return value.IsTruthy(value.VarWarnOnReflection.Deref())
+}
+
+func (c *codeCompiler) compileGo(n *ast.Node) (code, error) {
+	invokeNode := n.Sub.(*ast.GoNode).Invoke.Sub.(*ast.InvokeNode)
+
+	fnCode, err := c.compile(invokeNode.Fn)
+	if err != nil {
+		return nil, err
+	}
+	argCodes, err := c.compileAll(invokeNode.Args)
+	if err != nil {
+		return nil, err
+	}
+	return func(f *frame) (interface{}, error) {
+		fnVal, err := fnCode(f)
+		if err != nil {
+			return nil, err
+		}
+		argVals, err := evalAll(f, argCodes)
+		if err != nil {
+			return nil, err
+		}
+
+		value.Go(func() { value.Apply(fnVal, argVals) })
+		return nil, nil
+	}, nil
+}
+
+func (c *codeCompiler) compileWithMeta(n *ast.Node) (code, error) {
+	wmNode := n.Sub.(*ast.WithMetaNode)
+
+	exprCode, err := c.compile(wmNode.Expr)
+	if err != nil {
+		return nil, err
+	}
+	metaCode, err := c.compile(wmNode.Meta)
+	if err != nil {
+		return nil, err
+	}
+	return func(f *frame) (interface{}, error) {
+		exprVal, err := exprCode(f)
+		if err != nil {
+			return nil, err
+		}
+		metaVal, err := metaCode(f)
+		if err != nil {
+			return nil, err
+		}
+		return value.WithMeta(exprVal, metaVal.(value.IPersistentMap))
+	}, nil
+}
+
+func (c *codeCompiler) compileMap(n *ast.Node) (code, error) {
+	mapNode := n.Sub.(*ast.MapNode)
+
+	keyCodes, err := c.compileAll(mapNode.Keys)
+	if err != nil {
+		return nil, err
+	}
+	valCodes, err := c.compileAll(mapNode.Vals)
+	if err != nil {
+		return nil, err
+	}
+	return func(f *frame) (interface{}, error) {
+		res := value.NewMap()
+		for i, keyCode := range keyCodes {
+			keyVal, err := keyCode(f)
+			if err != nil {
+				return nil, err
+			}
+			valVal, err := valCodes[i](f)
+			if err != nil {
+				return nil, err
+			}
+			res = value.Assoc(res, keyVal, valVal).(value.IPersistentMap)
+		}
+		return res, nil
+	}, nil
+}
+
+func (c *codeCompiler) compileVector(n *ast.Node) (code, error) {
+	itemCodes, err := c.compileAll(n.Sub.(*ast.VectorNode).Items)
+	if err != nil {
+		return nil, err
+	}
+	return func(f *frame) (interface{}, error) {
+		vals, err := evalAll(f, itemCodes)
+		if err != nil {
+			return nil, err
+		}
+		return value.NewVector(vals...), nil
+	}, nil
+}
+
+func (c *codeCompiler) compileSet(n *ast.Node) (code, error) {
+	itemCodes, err := c.compileAll(n.Sub.(*ast.SetNode).Items)
+	if err != nil {
+		return nil, err
+	}
+	return func(f *frame) (interface{}, error) {
+		vals, err := evalAll(f, itemCodes)
+		if err != nil {
+			return nil, err
+		}
+		return value.NewSet(vals...), nil
+	}, nil
+}
+
+func (c *codeCompiler) compileIf(n *ast.Node) (code, error) {
+	ifNode := n.Sub.(*ast.IfNode)
+
+	testCode, err := c.compile(ifNode.Test)
+	if err != nil {
+		return nil, err
+	}
+	thenCode, err := c.compile(ifNode.Then)
+	if err != nil {
+		return nil, err
+	}
+	elseCode, err := c.compile(ifNode.Else)
+	if err != nil {
+		return nil, err
+	}
+	return func(f *frame) (interface{}, error) {
+		testVal, err := testCode(f)
+		if err != nil {
+			return nil, err
+		}
+		if value.IsTruthy(testVal) {
+			return thenCode(f)
+		}
+		return elseCode(f)
+	}, nil
+}
+
+func (c *codeCompiler) compileCase(n *ast.Node) (code, error) {
+	caseNode := n.Sub.(*ast.CaseNode)
+
+	testCode, err := c.compile(caseNode.Test)
+	if err != nil {
+		return nil, err
+	}
+	type clause struct {
+		tests []code
+		then  code
+	}
+	clauses := make([]clause, len(caseNode.Nodes))
+	for i, node := range caseNode.Nodes {
+		caseNodeNode := node.Sub.(*ast.CaseNodeNode)
+		clauses[i].tests, err = c.compileAll(caseNodeNode.Tests)
+		if err != nil {
+			return nil, err
+		}
+		clauses[i].then, err = c.compile(caseNodeNode.Then)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defaultCode, err := c.compile(caseNode.Default)
+	if err != nil {
+		return nil, err
+	}
+	return func(f *frame) (interface{}, error) {
+		testVal, err := testCode(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, cl := range clauses {
+			for _, test := range cl.tests {
+				caseTestVal, err := test(f)
+				if err != nil {
+					return nil, err
+				}
+				if value.Equals(testVal, caseTestVal) {
+					return cl.then(f)
+				}
+			}
+		}
+		return defaultCode(f)
+	}, nil
+}
+
+func (c *codeCompiler) compileDo(n *ast.Node) (code, error) {
+	doNode := n.Sub.(*ast.DoNode)
+
+	stmtCodes, err := c.compileAll(doNode.Statements)
+	if err != nil {
+		return nil, err
+	}
+	retCode, err := c.compile(doNode.Ret)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmtCodes) == 0 {
+		return retCode, nil
+	}
+	return func(f *frame) (interface{}, error) {
+		for _, stmt := range stmtCodes {
+			if _, err := stmt(f); err != nil {
+				return nil, err
+			}
+		}
+		return retCode(f)
+	}, nil
+}
+
+func (c *codeCompiler) compileLet(n *ast.Node, isLoop bool) (code, error) {
+	letNode := n.Sub.(*ast.LetNode)
+
+	return c.withLocals(func() (code, error) {
+		slots := make([]int, len(letNode.Bindings))
+		initCodes := make([]code, len(letNode.Bindings))
+		for i, binding := range letNode.Bindings {
+			bindingNode := binding.Sub.(*ast.BindingNode)
+			initCode, err := c.compile(bindingNode.Init)
+			if err != nil {
+				return nil, err
+			}
+			initCodes[i] = initCode
+			slots[i] = c.scope.bind(bindingNode.Name)
+		}
+
+		if isLoop {
+			defer func(recur *recurTarget) { c.recur = recur }(c.recur)
+			c.recur = &recurTarget{slots: slots}
+		}
+		bodyCode, err := c.compile(letNode.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(f *frame) (interface{}, error) {
+			for i, initCode := range initCodes {
+				initVal, err := initCode(f)
+				if err != nil {
+					return nil, err
+				}
+				f.slots[slots[i]] = initVal
+			}
+			for {
+				res, err := bodyCode(f)
+				if isLoop && err == errRecur {
+					if err := value.CheckCanceled(); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				return res, err
+			}
+		}, nil
+	})
+}
+
+func (c *codeCompiler) compileLetFn(n *ast.Node) (code, error) {
+	letFnNode := n.Sub.(*ast.LetFnNode)
+
+	return c.withLocals(func() (code, error) {
+		slots := make([]int, len(letFnNode.Bindings))
+		for i, binding := range letFnNode.Bindings {
+			slots[i] = c.scope.bind(binding.Sub.(*ast.BindingNode).Name)
+		}
+		initCodes := make([]code, len(letFnNode.Bindings))
+		for i, binding := range letFnNode.Bindings {
+			initCode, err := c.compile(binding.Sub.(*ast.BindingNode).Init)
+			if err != nil {
+				return nil, err
+			}
+			initCodes[i] = initCode
+		}
+		bodyCode, err := c.compile(letFnNode.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(f *frame) (interface{}, error) {
+			for i, initCode := range initCodes {
+				fnVal, err := initCode(f)
+				if err != nil {
+					return nil, err
+				}
+				f.slots[slots[i]] = fnVal
+			}
+			// the fns may refer to each other, so capture their locals
+			// again now that all are bound.
+			for _, slot := range slots {
+				if fn, ok := f.slots[slot].(*Fn); ok {
+					fn.capture(f)
+				}
+			}
+			return bodyCode(f)
+		}, nil
+	})
+}
+
+func (c *codeCompiler) compileInvoke(n *ast.Node) (code, error) {
+	invokeNode := n.Sub.(*ast.InvokeNode)
+
+	fnCode, err := c.compile(invokeNode.Fn)
+	if err != nil {
+		return nil, err
+	}
+	argCodes, err := c.compileAll(invokeNode.Args)
+	if err != nil {
+		return nil, err
+	}
+	stackFrame := CallFrame(c.frame, n)
+	return func(f *frame) (res interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = invokeError(stackFrame, r)
+			}
+		}()
+
+		fnVal, err := fnCode(f)
+		if err != nil {
+			return nil, err
+		}
+		argVals, err := evalAll(f, argCodes)
+		if err != nil {
+			return nil, err
+		}
+
+		if invokeNode.GoTry {
+			res, err := applyGoTry(callLocation(n.Form), fnVal, argVals)
+			if err != nil {
+				return nil, withFrame(stackFrame, err)
+			}
+			return res, nil
+		}
+		return value.Apply(fnVal, argVals), nil
+	}, nil
+}
+
+func (c *codeCompiler) compileVar(n *ast.Node) (code, error) {
+	v := n.Sub.(*ast.VarNode).Var
+	return func(*frame) (interface{}, error) {
+		return VarValue(v)
+	}, nil
+}
+
+func (c *codeCompiler) compileLocal(n *ast.Node) (code, error) {
+	sym := n.Sub.(*ast.LocalNode).Name
+	ref, ok := c.scope.lookup(sym.Name())
+	if !ok {
+		return nil, c.env.errorf(n.Form, "unable to resolve local symbol: %s", sym)
+	}
+	switch ref.kind {
+	case localSlot:
+		return func(f *frame) (interface{}, error) {
+			return f.slots[ref.idx], nil
+		}, nil
+	case localClosed:
+		return func(f *frame) (interface{}, error) {
+			return f.closed[ref.idx], nil
+		}, nil
+	default:
+		return func(f *frame) (interface{}, error) {
+			return f.fn, nil
+		}, nil
+	}
+}
+
+func (c *codeCompiler) compileRecur(n *ast.Node) (code, error) {
+	target := c.recur
+	if target == nil {
+		return nil, c.env.errorf(n.Form, "recur outside of loop")
+	}
+	if target.err != "" {
+		return nil, c.env.errorf(n.Form, "%s", target.err)
+	}
+
+	exprs := n.Sub.(*ast.RecurNode).Exprs
+	if len(exprs) != len(target.slots) {
+		return nil, c.env.errorf(n.Form, "invalid recur, expected %d arguments, got %d", len(target.slots), len(exprs))
+	}
+
+	c.recur = nil
+	exprCodes, err := c.compileAll(exprs)
+	c.recur = target
+	if err != nil {
+		return nil, err
+	}
+	slots := target.slots
+	return func(f *frame) (interface{}, error) {
+		vals, err := evalAll(f, exprCodes)
+		if err != nil {
+			return nil, err
+		}
+		for i, slot := range slots {
+			f.slots[slot] = vals[i]
+		}
+		return nil, errRecur
+	}, nil
+}
+
+func (c *codeCompiler) compileNew(n *ast.Node) (code, error) {
+	newNode := n.Sub.(*ast.NewNode)
+
+	classCode, err := c.compile(newNode.Class)
+	if err != nil {
+		return nil, err
+	}
+	hasArgs := len(newNode.Args) > 0
+	return func(f *frame) (interface{}, error) {
+		classVal, err := classCode(f)
+		if err != nil {
+			return nil, err
+		}
+		if hasArgs {
+			return nil, errors.New("new with args unsupported")
+		}
+		return NewValue(classVal)
+	}, nil
+}
+
+func (c *codeCompiler) compileTry(n *ast.Node) (code, error) {
+	tryNode := n.Sub.(*ast.TryNode)
+
+	defer func(recur *recurTarget) { c.recur = recur }(c.recur)
+	if c.recur != nil {
+		c.recur = &recurTarget{err: "cannot recur across try"}
+	}
+
+	bodyCode, err := c.compile(tryNode.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	type catchClause struct {
+		class code
+		slot  int
+		body  code
+	}
+	catches := make([]catchClause, len(tryNode.Catches))
+	for i, cn := range tryNode.Catches {
+		catch := cn.Sub.(*ast.CatchNode)
+		classCode, err := c.compile(catch.Class)
+		if err != nil {
+			return nil, err
+		}
+		catches[i].class = classCode
+		catches[i].body, err = c.withLocals(func() (code, error) {
+			catches[i].slot = c.scope.bind(catch.Local.Sub.(*ast.BindingNode).Name)
+			return c.compile(catch.Body)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var finallyCode code
+	if tryNode.Finally != nil {
+		finallyCode, err = c.compile(tryNode.Finally)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(f *frame) (res interface{}, err error) {
+		if finallyCode != nil {
+			defer func() {
+				_, ferr := finallyCode(f)
+				if ferr != nil {
+					err = ferr
+				}
+			}()
+		}
+		if len(catches) > 0 {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				for _, catch := range catches {
+					classVal, classErr := catch.class(f)
+					if classErr != nil {
+						panic(classErr)
+					}
+
+					if !catchMatches(r, classVal) {
+						continue
+					}
+
+					f.slots[catch.slot] = r
+					res, err = catch.body(f)
+					if err != nil {
+						panic(err)
+					}
+					return
+				}
+				// re-throw if no catch matches
+				panic(r)
+			}()
+		}
+
+		res, err = bodyCode(f)
+		if err != nil {
+			panic(err)
+		}
+		return res, nil
+	}, nil
+}
+
+func (c *codeCompiler) compileThrow(n *ast.Node) (code, error) {
+	exceptionCode, err := c.compile(n.Sub.(*ast.ThrowNode).Exception)
+	if err != nil {
+		return nil, err
+	}
+	return func(f *frame) (interface{}, error) {
+		exception, err := exceptionCode(f)
+		if err != nil {
+			return nil, err
+		}
+		panic(exception)
+	}, nil
+}
+
+func (c *codeCompiler) compileFn(n *ast.Node) (code, error) {
+	fnNode := n.Sub.(*ast.FnNode)
+
+	cl := &closure{parent: c.scope}
+	if fnNode.Local != nil {
+		cl.self = fnNode.Local.Sub.(*ast.BindingNode).Name.Name()
+	}
+	var def *value.Symbol
+	if n == c.defFn {
+		def = c.defName
+	}
+	fc := &fnCode{
+		node:          n,
+		isVariadic:    fnNode.IsVariadic,
+		maxFixedArity: fnNode.MaxFixedArity,
+	}
+	for _, method := range fnNode.Methods {
+		mc, err := c.compileFnMethod(cl, method, FnFrame(c.frame, n, method, def))
+		if err != nil {
+			return nil, err
+		}
+		if mc.variadic {
+			fc.variadic = mc
+			continue
+		}
+		for len(fc.fixed) <= mc.fixedArity {
+			fc.fixed = append(fc.fixed, nil)
+		}
+		fc.fixed[mc.fixedArity] = mc
+	}
+	fc.captures = cl.captures
+
+	return func(f *frame) (interface{}, error) {
+		fn := &Fn{code: fc}
+		if len(fc.captures) > 0 {
+			fn.closed = make([]interface{}, len(fc.captures))
+			fn.capture(f)
+		}
+		return fn, nil
+	}, nil
+}
+
+func (c *codeCompiler) compileFnMethod(cl *closure, n *ast.Node, stackFrame value.StackFrame) (*methodCode, error) {
+	methodNode := n.Sub.(*ast.FnMethodNode)
+
+	outer, outerRecur, outerFrame := c.scope, c.recur, c.frame
+	defer func() {
+		c.scope, c.recur, c.frame = outer, outerRecur, outerFrame
+	}()
+	c.scope = &frameScope{closure: cl}
+	c.frame = stackFrame
+
+	var slots []int
+	for _, param := range methodNode.Params {
+		slots = append(slots, c.scope.bind(param.Sub.(*ast.BindingNode).Name))
+	}
+	c.recur = &recurTarget{slots: slots}
+
+	bodyCode, err := c.compile(methodNode.Body)
+	if err != nil {
+		return nil, err
+	}
+	if d := c.debugger(); d != nil {
+		bodyCode = c.debugFrameCode(d, n, bodyCode)
+	}
+	bodyCode = c.profileFrameCode(bodyCode)
+	return &methodCode{
+		fixedArity: methodNode.FixedArity,
+		variadic:   methodNode.IsVariadic,
+		numSlots:   c.scope.numSlots,
+		body:       bodyCode,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package runtime_test
+
+import (
+	"strings"
+	"testing"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+func benchmarkEval(b *testing.B, setup, expr string) {
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	env := runtime.NewEnvironment()
+	runtime.ReadEval(setup, runtime.WithEnv(env))
+	form := runtime.ReadEval("(quote "+expr+")", runtime.WithEnv(env))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := env.Eval(form); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEvalFib(b *testing.B) {
+	benchmarkEval(b,
+		`(defn fib [n] (if (< n 2) n (+ (fib (- n 1)) (fib (- n 2)))))`,
+		`(fib 20)`)
+}
+
+func BenchmarkEvalLoop(b *testing.B) {
+	benchmarkEval(b, ``,
+		`(loop [i 0 acc 0] (if (< i 10000) (recur (inc i) (+ acc i)) acc))`)
+}
+
+func BenchmarkEvalClosures(b *testing.B) {
+	benchmarkEval(b, ``,
+		`(reduce (fn [acc f] (+ acc (f))) 0 (map (fn [i] (fn [] (* i i))) (range 1000)))`)
+}
+
+func BenchmarkEvalHostCall(b *testing.B) {
+	benchmarkEval(b, ``,
+		`(let [sb (new strings.Builder)] (dotimes [i 1000] (.WriteString sb "x")) (.Len sb))`)
+}
+
+func TestMegamorphicHostCallWarning(t *testing.T) {
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	var stderr strings.Builder
+	env := runtime.NewEnvironment(runtime.WithStderr(&stderr))
+	runtime.ReadEval(`
+(set! *warn-on-reflection* true)
+(defn error-string [x] (.Error x))
+(set! *warn-on-reflection* false)
+(defn quiet-error-string [x] (.Error x))`, runtime.WithEnv(env))
+
+	// six distinct error types
+	errs := `[(errors.New "a") (fmt.Errorf "b %w" io.EOF) (fmt.Errorf "%w %w" io.EOF io.EOF)
+            (os.NewSyscallError "c" io.EOF) context.DeadlineExceeded (nth (strconv.Atoi "d") 1)]`
+	runtime.ReadEval(`(doseq [e `+errs+`] (quiet-error-string e))`, runtime.WithEnv(env))
+	if stderr.Len() != 0 {
+		t.Fatalf("unexpected warning: %s", stderr.String())
+	}
+	for i := 0; i < 2; i++ {
+		runtime.ReadEval(`(doseq [e `+errs+`] (error-string e))`, runtime.WithEnv(env))
+	}
+	if got := strings.Count(stderr.String(), "Reflection warning"); got != 1 {
+		t.Fatalf("expected one warning, got %q", stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "Error is megamorphic") {
+		t.Errorf("unexpected warning: %s", stderr.String())
+	}
+}
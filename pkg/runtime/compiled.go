@@ -0,0 +1,412 @@
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/glojurelang/glojure/pkg/ast"
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/pkgmap"
+)
+
+// This file implements the runtime support of Go code generated by
+// glj compile (see package aot). The generated code evaluates the
+// analyzed top-level forms of a file the way the closures of
+// compile.go do, and calls into this file wherever the closures call
+// unexported helpers, so that both report the same values and errors.
+
+type (
+	// CompiledFile is a source file compiled ahead of time to Go. The
+	// generated code registers it with RegisterCompiledFile, after
+	// which loading the file evaluates its compiled forms instead of
+	// reading and analyzing its source.
+	CompiledFile struct {
+		// Name is the name of the file on the load path.
+		Name string
+		// Hash is the hex-encoded SHA-256 hash of the source the file
+		// was compiled from.
+		Hash string
+		// Version is the image version of the encoded constants and
+		// units of the forms.
+		Version int
+		// SymCounter and RTID are the counters of the compiling
+		// environment, restored as they are from images.
+		SymCounter int32
+		RTID       int32
+
+		Forms []CompiledForm
+	}
+
+	// CompiledForm is a top-level form of a compiled file.
+	CompiledForm struct {
+		// Consts holds the constants of the form, encoded with
+		// EncodeConstants.
+		Consts string
+		// Eval evaluates the form, given its decoded constants.
+		Eval func(consts []interface{}) (interface{}, error)
+		// UsedVars holds the vars the form uses, encoded with
+		// EncodeConstants, which are recorded as used by the current
+		// namespace before it is evaluated.
+		UsedVars string
+		// Unit is the image unit of a form that could not be compiled,
+		// evaluated by the interpreter when Eval is nil.
+		Unit string
+	}
+
+	// CompiledMethod is a fn method compiled to Go.
+	CompiledMethod struct {
+		FixedArity int
+		Variadic   bool
+		// Body evaluates the method. fn is the fn invoked, and args
+		// holds the fixed arguments followed, for a variadic method,
+		// by a list of the rest or nil.
+		Body func(fn *Fn, args []interface{}) (interface{}, error)
+	}
+
+	// CallSite describes an invoke, host call or host interop form
+	// compiled to Go, for the errors reported by its evaluation.
+	CallSite struct {
+		// Frame is the frame of the form in stack traces.
+		Frame value.StackFrame
+		// Location is the file:line:column position of the form.
+		Location string
+		// GoTry is set when a trailing Go error result is thrown.
+		GoTry bool
+
+		// host is the site of a host call or interop form, created
+		// when it is first evaluated.
+		host atomic.Pointer[hostSite]
+	}
+)
+
+var (
+	compiledFiles     = map[string]*CompiledFile{}
+	compiledFilesLock sync.Mutex
+)
+
+// RegisterCompiledFile makes a compiled file available to loads. It
+// is called by the init functions of generated code.
+func RegisterCompiledFile(f *CompiledFile) {
+	compiledFilesLock.Lock()
+	defer compiledFilesLock.Unlock()
+
+	compiledFiles[f.Name] = f
+}
+
+// findCompiledFile returns the compiled file of the given name, unless
+// its source is on the load path and differs from the source it was
+// compiled from.
+func findCompiledFile(name string) *CompiledFile {
+	compiledFilesLock.Lock()
+	f := compiledFiles[name]
+	compiledFilesLock.Unlock()
+
+	if f == nil {
+		return nil
+	}
+	if src, err := readLoadPath(name); err == nil && SourceHash(src) != f.Hash {
+		return nil
+	}
+	return f
+}
+
+// SourceHash returns the hash of a source file recorded in compiled
+// files.
+func SourceHash(src []byte) string {
+	hash := sha256.Sum256(src)
+	return hex.EncodeToString(hash[:])
+}
+
+func (env *environment) loadCompiledFile(f *CompiledFile) error {
+	if f.Version != imageVersion {
+		return fmt.Errorf("%v was compiled with image version %d, expected %d; recompile it", f.Name, f.Version, imageVersion)
+	}
+	env.restoreCounters(f.SymCounter, f.RTID)
+
+	for _, form := range f.Forms {
+		if form.Eval == nil {
+			if err := env.evalUnit(f.Name, []byte(form.Unit)); err != nil {
+				return err
+			}
+			continue
+		}
+		consts, err := DecodeConstants(form.Consts)
+		if err != nil {
+			return fmt.Errorf("error restoring %v: %w", f.Name, err)
+		}
+		used, err := DecodeConstants(form.UsedVars)
+		if err != nil {
+			return fmt.Errorf("error restoring %v: %w", f.Name, err)
+		}
+		for _, v := range used {
+			env.CurrentNamespace().AddUsedVar(v.(*value.Var))
+		}
+		if _, err := form.Eval(consts); err != nil {
+			return fmt.Errorf("error evaluating %v: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// EncodeConstants encodes the constants of a compiled form. It
+// returns an error wrapping ErrUnsupportedValue if a value can't be
+// encoded.
+func EncodeConstants(consts []interface{}) (string, error) {
+	e := newImageEncoder()
+	err := e.try(func() {
+		e.uint(uint64(len(consts)))
+		for _, c := range consts {
+			e.value(c)
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(e.buf), nil
+}
+
+// DecodeConstants decodes constants encoded with EncodeConstants.
+// Vars are interned as they are decoded.
+func DecodeConstants(s string) (consts []interface{}, err error) {
+	d := &imageDecoder{buf: []byte(s)}
+	defer func() {
+		if r := recover(); r != nil {
+			rErr, ok := r.(error)
+			if !ok {
+				rErr = fmt.Errorf("%v", r)
+			}
+			err = fmt.Errorf("corrupt constants: %w", rErr)
+		}
+	}()
+	return d.values(int(d.uint())), nil
+}
+
+// ErrUnsupportedValue is wrapped by the errors of EncodeConstants for
+// values that can't be encoded.
+var ErrUnsupportedValue = errUnsupportedValue
+
+// ImageVersion is the version of the encoding of images, constants
+// and the AST.
+const ImageVersion = imageVersion
+
+// FileHash returns the hex-encoded hash of the source of a file held
+// by the image.
+func (img *Image) FileHash(name string) string {
+	f := img.file(name)
+	if f == nil {
+		return ""
+	}
+	return hex.EncodeToString(f.hash[:])
+}
+
+// Units returns the encoded top-level forms of a file held by the
+// image, in load order.
+func (img *Image) Units(name string) [][]byte {
+	f := img.file(name)
+	if f == nil {
+		return nil
+	}
+	return f.units
+}
+
+// Counters returns the counters of the environment that recorded the
+// image.
+func (img *Image) Counters() (symCounter, rtID int32) {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	return img.symCounter, img.rtID
+}
+
+// DecodeUnit decodes a unit returned by Image.Units, returning its
+// node and the vars it uses. It returns a nil node for a unit holding
+// a form as read, which is analyzed when loaded.
+func DecodeUnit(unit []byte) (*ast.Node, []*value.Var, error) {
+	n, used, _, err := decodeUnit(unit)
+	return n, used, err
+}
+
+// NodeCallSite returns the call site of an invoke, host call or host
+// interop node, in code whose frames are fn, as for CallFrame.
+func NodeCallSite(n *ast.Node, fn value.StackFrame) *CallSite {
+	site := &CallSite{Location: callLocation(n.Form), Frame: CallFrame(fn, n)}
+	switch sub := n.Sub.(type) {
+	case *ast.InvokeNode:
+		site.GoTry = sub.GoTry
+	case *ast.HostCallNode:
+		site.GoTry = sub.GoTry
+	case *ast.HostInteropNode:
+		site.GoTry = sub.GoTry
+	}
+	return site
+}
+
+// Invoke evaluates an invoke form with thunk, converting a panic to
+// an error as the interpreter does.
+func (s *CallSite) Invoke(thunk func() (interface{}, error)) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = invokeError(s.Frame, r)
+		}
+	}()
+	return thunk()
+}
+
+// Apply applies fn to args.
+func (s *CallSite) Apply(fn interface{}, args ...interface{}) (interface{}, error) {
+	if s.GoTry {
+		res, err := applyGoTry(s.Location, fn, args)
+		if err != nil {
+			return nil, withFrame(s.Frame, err)
+		}
+		return res, nil
+	}
+	return value.Apply(fn, args), nil
+}
+
+// GoError annotates the error result of a Go function called by the
+// form with the form's location.
+func (s *CallSite) GoError(err error) error {
+	return withFrame(s.Frame, fmt.Errorf("%s: %w", s.Location, err))
+}
+
+// HostCall calls the method of tgt named method with args.
+func (s *CallSite) HostCall(tgt interface{}, method *value.Symbol, args ...interface{}) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = invokeError(s.Frame, r)
+		}
+	}()
+	res, err = s.hostSite(method).call(tgt, args)
+	if err != nil {
+		return nil, withFrame(s.Frame, err)
+	}
+	return res, nil
+}
+
+// HostInterop returns the field of tgt named mOrF, or the result of
+// calling its method of that name.
+func (s *CallSite) HostInterop(tgt interface{}, mOrF *value.Symbol) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = invokeError(s.Frame, r)
+		}
+	}()
+	res, err = s.hostSite(mOrF).interop(tgt)
+	if err != nil {
+		return nil, withFrame(s.Frame, err)
+	}
+	return res, nil
+}
+
+// hostSite returns the site of the host form naming the member sym.
+// Compiled sites don't print reflection warnings.
+func (s *CallSite) hostSite(sym *value.Symbol) *hostSite {
+	if host := s.host.Load(); host != nil {
+		return host
+	}
+	s.host.CompareAndSwap(nil, newHostSite(sym, s.GoTry, false, s.location))
+	return s.host.Load()
+}
+
+func (s *CallSite) location() string {
+	return s.Location
+}
+
+// NewCompiledFn returns a fn with the given methods.
+func NewCompiledFn(methods ...CompiledMethod) *Fn {
+	fc := &fnCode{}
+	for _, m := range methods {
+		body := m.Body
+		mc := &methodCode{
+			fixedArity: m.FixedArity,
+			variadic:   m.Variadic,
+			numSlots:   m.FixedArity,
+			body: func(f *frame) (interface{}, error) {
+				return body(f.fn, f.slots)
+			},
+		}
+		if m.Variadic {
+			mc.numSlots++
+			fc.variadic = mc
+			fc.isVariadic = true
+			continue
+		}
+		for len(fc.fixed) <= m.FixedArity {
+			fc.fixed = append(fc.fixed, nil)
+		}
+		fc.fixed[m.FixedArity] = mc
+		if m.FixedArity > fc.maxFixedArity {
+			fc.maxFixedArity = m.FixedArity
+		}
+	}
+	return &Fn{code: fc}
+}
+
+// VarValue returns the value of a var referred to by a compiled form.
+func VarValue(v *value.Var) (interface{}, error) {
+	if v.IsMacro() {
+		return nil, fmt.Errorf("can't take value of a macro: %v", v)
+	}
+	return v.Get(), nil
+}
+
+// Def evaluates a def form of a compiled form, defining v. meta is the
+// evaluated metadata of the var's symbol, or nil.
+func Def(v *value.Var, init, meta interface{}) (interface{}, error) {
+	return value.GlobalEnv.(*environment).def(v, init, meta)
+}
+
+// Declare sets the metadata of v, defined by a def form without an
+// init, to meta, leaving its root unchanged.
+func Declare(v *value.Var, meta interface{}) interface{} {
+	return declare(v, meta)
+}
+
+// SetHostField sets the field of target named field to val.
+func SetHostField(target interface{}, field *value.Symbol, val interface{}) (interface{}, error) {
+	return setField(target, field, val)
+}
+
+// MaybeClass returns the value of a Go package member named by a
+// symbol, or an error if the sandbox of the global environment doesn't
+// allow code to refer to it.
+func MaybeClass(sym *value.Symbol) (interface{}, error) {
+	if _, ok := pkgmap.Get(sym.FullName()); ok {
+		if err := value.GlobalEnv.CheckExport(sym.FullName()); err != nil {
+			return nil, err
+		}
+	}
+	return maybeClass(sym)
+}
+
+// maybeClass is MaybeClass for code whose references were checked
+// against the sandbox as it was compiled.
+func maybeClass(sym *value.Symbol) (interface{}, error) {
+	v, ok := pkgmap.Get(sym.FullName())
+	if ok {
+		return v, nil
+	}
+	return nil, errors.New("unable to resolve symbol: " + value.ToString(sym))
+}
+
+// NewValue returns a pointer to a new zero value of class, which must
+// be a reflect.Type.
+func NewValue(class interface{}) (interface{}, error) {
+	classTyp, ok := class.(reflect.Type)
+	if !ok {
+		return nil, fmt.Errorf("new value must be a reflect.Type, got %T", class)
+	}
+	return reflect.New(classTyp).Interface(), nil
+}
+
+// CatchMatches reports whether a catch clause of the given class
+// catches r.
+func CatchMatches(r, class interface{}) bool {
+	return catchMatches(r, class)
+}
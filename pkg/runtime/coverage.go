@@ -0,0 +1,153 @@
+package runtime
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/glojurelang/glojure/pkg/ast"
+	value "github.com/glojurelang/glojure/pkg/lang"
+)
+
+// Coverage records how many times the forms of the code compiled while
+// it is set on an environment are evaluated, to report which forms
+// tests exercise. The recorded forms are the lists read from source
+// files, identified by their :file, :line and :column metadata, so a
+// form compiled again, as when its file is reloaded, keeps its count.
+type Coverage struct {
+	// Include reports whether the forms of the namespace named ns are
+	// recorded. If it is nil, those of all namespaces but glojure and
+	// the namespaces under it are.
+	Include func(ns string) bool
+
+	mu     sync.Mutex
+	blocks map[coverPos]*coverBlock
+}
+
+// CoverBlock is a form recorded by a Coverage.
+type CoverBlock struct {
+	File      string
+	Namespace string
+	// Line, Column, EndLine and EndColumn are the position of the
+	// form, EndColumn being that of its last character.
+	Line, Column, EndLine, EndColumn int
+	// Count is the number of times the form was evaluated.
+	Count int64
+}
+
+type (
+	coverPos struct {
+		file         string
+		line, column int
+	}
+
+	coverBlock struct {
+		ns                 string
+		endLine, endColumn int
+		count              atomic.Int64
+	}
+)
+
+// WithCoverage records the evaluations of the code compiled in the
+// environment once the core library has been loaded in cov. See
+// SetCoverage.
+func WithCoverage(cov *Coverage) EvalOption {
+	return func(opts *evalOptions) {
+		opts.coverage = cov
+	}
+}
+
+// SetCoverage records the evaluations of the code compiled in env from
+// now on in cov, or stops recording those of the code compiled from now
+// on if cov is nil. Code compiled while a coverage was set keeps
+// recording in it.
+func SetCoverage(env value.Environment, cov *Coverage) {
+	env.(*environment).coverage.Store(cov)
+}
+
+// Blocks returns the recorded forms, sorted by file and position.
+func (cov *Coverage) Blocks() []CoverBlock {
+	cov.mu.Lock()
+	defer cov.mu.Unlock()
+	blocks := make([]CoverBlock, 0, len(cov.blocks))
+	for pos, b := range cov.blocks {
+		blocks = append(blocks, CoverBlock{
+			File:      pos.file,
+			Namespace: b.ns,
+			Line:      pos.line,
+			Column:    pos.column,
+			EndLine:   b.endLine,
+			EndColumn: b.endColumn,
+			Count:     b.count.Load(),
+		})
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		a, b := blocks[i], blocks[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return blocks
+}
+
+func (cov *Coverage) includes(ns string) bool {
+	if cov.Include != nil {
+		return cov.Include(ns)
+	}
+	return ns != "glojure" && !strings.HasPrefix(ns, "glojure.")
+}
+
+// block returns the block of the form with the metadata meta, compiled
+// in the namespace named ns, or nil if it isn't recorded.
+func (cov *Coverage) block(ns string, meta value.IPersistentMap) *coverBlock {
+	file, _ := value.Get(meta, value.KWFile).(string)
+	line, _ := value.Get(meta, value.KWLine).(int)
+	if file == "" || file == "NO_SOURCE_FILE" || line == 0 || !cov.includes(ns) {
+		return nil
+	}
+	pos := coverPos{file: file, line: line}
+	pos.column, _ = value.Get(meta, value.KWColumn).(int)
+
+	cov.mu.Lock()
+	defer cov.mu.Unlock()
+	if cov.blocks == nil {
+		cov.blocks = map[coverPos]*coverBlock{}
+	}
+	b := cov.blocks[pos]
+	if b == nil {
+		b = &coverBlock{ns: ns}
+		b.endLine, _ = value.Get(meta, value.KWEndLine).(int)
+		b.endColumn, _ = value.Get(meta, value.KWEndColumn).(int)
+		cov.blocks[pos] = b
+	}
+	return b
+}
+
+// coverForm returns code that counts the evaluations of cd, the code
+// of n, in cov, if n is a form with a source position in a namespace
+// that cov records.
+func (c *codeCompiler) coverForm(cov *Coverage, n *ast.Node, cd code) code {
+	form := sourceForm(n)
+	if _, ok := form.(value.ISeq); !ok {
+		return cd
+	}
+	ns := c.frame.Namespace
+	// the ns form of a file is compiled in the namespace the file is
+	// loaded from, but belongs to the one it declares.
+	if sym := InNamespace(n); sym != nil {
+		ns = sym.Name()
+	}
+	b := cov.block(ns, formMeta(form))
+	if b == nil {
+		return cd
+	}
+	return func(f *frame) (interface{}, error) {
+		b.count.Add(1)
+		return cd(f)
+	}
+}
@@ -0,0 +1,102 @@
+package runtime_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+func TestCoverage(t *testing.T) {
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	cov := &runtime.Coverage{}
+	env := runtime.NewEnvironment(runtime.WithCoverage(cov))
+	defer runtime.SetCoverage(env, nil)
+	runtime.ReadEval(`(ns cov.test)
+(defn f [x]
+  (if (pos? x)
+    (inc x)
+    (dec x)))
+(f 1)
+(f 2)
+`, runtime.WithEnv(env), runtime.WithFilename("cov/test.glj"))
+	// the glojure namespaces aren't recorded.
+	runtime.ReadEval("(ns glojure.cov-test)\n(defn g [] 1)\n(g)\n", runtime.WithEnv(env), runtime.WithFilename("glojure/cov_test.glj"))
+
+	var blocks []string
+	for _, b := range cov.Blocks() {
+		blocks = append(blocks, fmt.Sprintf("%s %s %d:%d-%d:%d %d", b.File, b.Namespace, b.Line, b.Column, b.EndLine, b.EndColumn, b.Count))
+	}
+	expected := []string{
+		"cov/test.glj cov.test 1:1-1:13 1",
+		"cov/test.glj cov.test 2:1-5:13 1",
+		"cov/test.glj cov.test 3:3-5:12 2",
+		"cov/test.glj cov.test 3:7-3:14 2",
+		"cov/test.glj cov.test 4:5-4:11 2",
+		"cov/test.glj cov.test 5:5-5:11 0",
+		"cov/test.glj cov.test 6:1-6:5 1",
+		"cov/test.glj cov.test 7:1-7:5 1",
+	}
+	if got := strings.Join(blocks, "\n"); got != strings.Join(expected, "\n") {
+		t.Fatalf("expected blocks\n%s\ngot\n%s", strings.Join(expected, "\n"), got)
+	}
+
+	var lcov strings.Builder
+	if err := cov.WriteLCOV(&lcov); err != nil {
+		t.Fatal(err)
+	}
+	expectedLCOV := "TN:\nSF:cov/test.glj\nDA:1,1\nDA:2,1\nDA:3,2\nDA:4,2\nDA:5,0\nDA:6,1\nDA:7,1\nLF:7\nLH:6\nend_of_record\n"
+	if lcov.String() != expectedLCOV {
+		t.Errorf("expected LCOV\n%s\ngot\n%s", expectedLCOV, lcov.String())
+	}
+
+	var page strings.Builder
+	if err := cov.WriteHTML(&page); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page.String(), "cov/test.glj (87.5%)") {
+		t.Errorf("expected the file with its coverage, got\n%s", page.String())
+	}
+
+	// forms compiled while the coverage isn't set aren't recorded.
+	runtime.SetCoverage(env, nil)
+	runtime.ReadEval("(ns cov.other)\n(inc 1)\n", runtime.WithEnv(env), runtime.WithFilename("cov/other.glj"))
+	if n := len(cov.Blocks()); n != len(expected) {
+		t.Errorf("expected %d blocks, got %d", len(expected), n)
+	}
+}
+
+func TestCoverageHTML(t *testing.T) {
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	src := "(ns cov.html)\n(defn h [x] (when (< x 0) (str \"<\" x)))\n(h 1)\n"
+	path := filepath.Join(t.TempDir(), "html.glj")
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cov := &runtime.Coverage{Include: func(ns string) bool { return ns == "cov.html" }}
+	env := runtime.NewEnvironment(runtime.WithCoverage(cov))
+	defer runtime.SetCoverage(env, nil)
+	runtime.ReadEval(src, runtime.WithEnv(env), runtime.WithFilename(path))
+
+	var page strings.Builder
+	if err := cov.WriteHTML(&page); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<span class="cov1" title="1">(defn h [x] </span>`,
+		`<span class="cov1" title="1">(&lt; x 0)</span>`,
+		`<span class="cov0" title="0">(str &#34;&lt;&#34; x)</span>`,
+	} {
+		if !strings.Contains(page.String(), expected) {
+			t.Errorf("expected %s in\n%s", expected, page.String())
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package runtime
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WriteLCOV writes the line coverage of the recorded forms to w in the
+// LCOV tracefile format, which genhtml and coverage services read. A
+// line is instrumented if a form starts on it, and its count is the
+// largest count of those forms. Files are named by their path if they
+// are found from the working directory, and by their :file metadata
+// otherwise.
+func (cov *Coverage) WriteLCOV(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, file := range groupBlocks(cov.Blocks()) {
+		counts := map[int]int64{}
+		var lines []int
+		for _, b := range file.blocks {
+			count, ok := counts[b.Line]
+			if !ok {
+				lines = append(lines, b.Line)
+			}
+			if !ok || b.Count > count {
+				counts[b.Line] = b.Count
+			}
+		}
+		sort.Ints(lines)
+
+		fmt.Fprintf(bw, "TN:\nSF:%s\n", coverPath(file.name))
+		hit := 0
+		for _, line := range lines {
+			fmt.Fprintf(bw, "DA:%d,%d\n", line, counts[line])
+			if counts[line] > 0 {
+				hit++
+			}
+		}
+		fmt.Fprintf(bw, "LF:%d\nLH:%d\nend_of_record\n", len(lines), hit)
+	}
+	return bw.Flush()
+}
+
+// WriteHTML writes a page to w showing the source of the files of the
+// recorded forms, with the forms that were evaluated in green and
+// those that weren't in red, and their counts as tooltips. A
+// character is shown as the innermost form it is in. The sources are
+// read as ReadSourceFile reads them.
+func (cov *Coverage) WriteHTML(w io.Writer) error {
+	var files []htmlCoverFile
+	for _, file := range groupBlocks(cov.Blocks()) {
+		covered := 0
+		for _, b := range file.blocks {
+			if b.Count > 0 {
+				covered++
+			}
+		}
+		f := htmlCoverFile{
+			Name:    file.name,
+			Percent: 100 * float64(covered) / float64(len(file.blocks)),
+		}
+		if src, err := ReadSourceFile(file.name); err != nil {
+			f.Source = template.HTML(html.EscapeString(err.Error()))
+		} else {
+			f.Source = annotateSource(string(src), file.blocks)
+		}
+		files = append(files, f)
+	}
+	return coverTemplate.Execute(w, files)
+}
+
+type (
+	// blockFile holds the blocks of a file.
+	blockFile struct {
+		name   string
+		blocks []CoverBlock
+	}
+
+	htmlCoverFile struct {
+		Name    string
+		Percent float64
+		Source  template.HTML
+	}
+)
+
+// groupBlocks groups blocks, sorted by file, by file.
+func groupBlocks(blocks []CoverBlock) []blockFile {
+	var files []blockFile
+	for _, b := range blocks {
+		if len(files) == 0 || files[len(files)-1].name != b.File {
+			files = append(files, blockFile{name: b.File})
+		}
+		f := &files[len(files)-1]
+		f.blocks = append(f.blocks, b)
+	}
+	return files
+}
+
+// coverPath returns the path of the file named file by the :file
+// metadata of forms, if it is found from the working directory, or
+// file.
+func coverPath(file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	if _, err := os.Stat(file); err == nil {
+		if path, err := filepath.Abs(file); err == nil {
+			return path
+		}
+	}
+	return file
+}
+
+// annotateSource returns src as HTML, with the characters of each
+// block of blocks in a span of a class for its count.
+func annotateSource(src string, blocks []CoverBlock) template.HTML {
+	runes := []rune(src)
+	lineStarts := []int{0}
+	for i, r := range runes {
+		if r == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	offset := func(line, column int) int {
+		if line < 1 || line > len(lineStarts) {
+			return -1
+		}
+		if column < 1 {
+			column = 1
+		}
+		off := lineStarts[line-1] + column - 1
+		if off >= len(runes) {
+			off = len(runes) - 1
+		}
+		return off
+	}
+
+	// each character is owned by the innermost block it is in, the
+	// blocks being painted from the outermost.
+	type span struct{ start, end, block int }
+	var spans []span
+	for i, b := range blocks {
+		start := offset(b.Line, b.Column)
+		end := offset(b.EndLine, b.EndColumn)
+		if start < 0 {
+			continue
+		}
+		if end < start {
+			end = start
+		}
+		spans = append(spans, span{start, end, i})
+	}
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].end-spans[i].start > spans[j].end-spans[j].start
+	})
+	owners := make([]int, len(runes))
+	for i := range owners {
+		owners[i] = -1
+	}
+	for _, s := range spans {
+		for i := s.start; i <= s.end; i++ {
+			owners[i] = s.block
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && owners[j] == owners[i] {
+			j++
+		}
+		text := html.EscapeString(string(runes[i:j]))
+		if owner := owners[i]; owner < 0 {
+			sb.WriteString(text)
+		} else {
+			class := "cov1"
+			if blocks[owner].Count == 0 {
+				class = "cov0"
+			}
+			fmt.Fprintf(&sb, `<span class="%s" title="%d">%s</span>`, class, blocks[owner].Count, text)
+		}
+		i = j
+	}
+	return template.HTML(sb.String())
+}
+
+var coverTemplate = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Glojure coverage</title>
+<style>
+body { background: black; color: rgb(80, 80, 80); font-family: Menlo, Monaco, Consolas, monospace; }
+#nav { position: fixed; top: 0; left: 0; right: 0; padding: 8px; background: black; }
+pre { margin-top: 40px; font-size: 14px; }
+.cov0 { color: rgb(192, 0, 0); }
+.cov1 { color: rgb(44, 212, 149); }
+</style>
+</head>
+<body>
+<div id="nav">
+<select id="files" onchange="show(this.value)">
+{{range $i, $f := .}}<option value="file{{$i}}">{{$f.Name}} ({{printf "%.1f" $f.Percent}}%)</option>
+{{end}}</select>
+<span class="cov0">not evaluated</span> <span class="cov1">evaluated</span>
+</div>
+{{range $i, $f := .}}<pre class="file" id="file{{$i}}"{{if $i}} style="display: none"{{end}}>{{$f.Source}}</pre>
+{{end}}<script>
+function show(id) {
+  for (const pre of document.querySelectorAll("pre.file")) {
+    pre.style.display = pre.id === id ? "block" : "none";
+  }
+}
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,658 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/glojurelang/glojure/internal/goid"
+	"github.com/glojurelang/glojure/pkg/ast"
+	value "github.com/glojurelang/glojure/pkg/lang"
+)
+
+// Debugger stops the evaluation of code at breakpoints, on errors and
+// when asked to, and lets the threads it stops be inspected and
+// stepped through. A thread is a goroutine evaluating debugged code.
+//
+// Only code compiled while the debugger is attached to an environment
+// with SetDebugger can be stopped, so the code to debug must be
+// loaded after it is attached. Code is stopped before the evaluation
+// of forms with a source position: lists read from source files, such
+// as calls and special forms.
+type Debugger struct {
+	env value.Environment
+	// stopped is called by each thread that stops, in its goroutine,
+	// before it waits to be resumed.
+	stopped func(*Thread)
+
+	mu      sync.Mutex
+	threads map[int64]*Thread
+	nextID  int
+
+	// breakpoints holds the lines of the breakpoints by the cleaned
+	// paths of their files. It is replaced rather than updated.
+	breakpoints     atomic.Pointer[map[string]map[int]bool]
+	breakOnRaised   atomic.Bool
+	breakOnUncaught atomic.Bool
+	// pause is set to stop the next thread to evaluate a form.
+	pause    atomic.Bool
+	detached atomic.Bool
+}
+
+// Thread is a goroutine evaluating code compiled with a debugger
+// attached. Its methods other than ID may only be called while it is
+// stopped.
+type Thread struct {
+	id int
+	d  *Debugger
+
+	// frames holds the frames of the debugged code the thread is
+	// evaluating, innermost last.
+	frames []*debugFrame
+
+	// step is the kind of step the thread takes since it was last
+	// resumed, from the frame depth and position it was stopped at.
+	step      stepKind
+	stepDepth int
+	stepFile  string
+	stepLine  int
+
+	// suspended is set while the thread evaluates code for the
+	// debugger, which doesn't stop.
+	suspended bool
+	// raised is the last error the thread stopped on or passed by.
+	raised interface{}
+
+	mu sync.Mutex
+	// isStopped is set while the thread is stopped, waiting for
+	// commands.
+	isStopped bool
+	cmds      chan threadCmd
+	reason    string
+	err       error
+}
+
+// DebugFrame is a frame of the stack of a stopped thread.
+type DebugFrame struct {
+	// StackFrame holds the position of the form being evaluated in
+	// the frame.
+	value.StackFrame
+	// Locals holds the locals in scope at the form, innermost last.
+	Locals []Local
+}
+
+// Local is a local binding of a stopped frame.
+type Local struct {
+	Name  string
+	Value interface{}
+}
+
+// The reasons for which a thread stops.
+const (
+	StopBreakpoint = "breakpoint"
+	StopStep       = "step"
+	StopPause      = "pause"
+	StopException  = "exception"
+)
+
+// ErrNotStopped is returned when a thread that is running is asked to
+// step or evaluate code.
+var ErrNotStopped = errors.New("thread is not stopped")
+
+type (
+	stepKind int
+
+	// debugFrame is a frame of a thread: an invocation of a fn
+	// method or the evaluation of a top-level form.
+	debugFrame struct {
+		thread *Thread
+		// stack is the stack frame of the code of the frame, without
+		// a source position.
+		stack value.StackFrame
+		f     *frame
+		// point is the position of the form being evaluated.
+		point *debugPoint
+		// file and line are the position of the last form the
+		// thread reached in the frame, so that a breakpoint stops
+		// the thread once per visit of its line.
+		file string
+		line int
+	}
+
+	// debugPoint is a compiled form at which threads can stop.
+	debugPoint struct {
+		file         string
+		line, column int
+		// locals are the locals in scope at the form, and closure the
+		// fn whose locals its frame closes over, if any.
+		locals  []scopeLocal
+		closure *closure
+	}
+
+	threadCmd struct {
+		run    func()
+		resume bool
+	}
+)
+
+const (
+	stepNone stepKind = iota
+	stepIn
+	stepOver
+	stepOut
+)
+
+// NewDebugger returns a debugger that calls stopped, in the goroutine
+// of each thread that stops, before the thread waits to be resumed.
+func NewDebugger(stopped func(*Thread)) *Debugger {
+	d := &Debugger{
+		stopped: stopped,
+		threads: map[int64]*Thread{},
+	}
+	d.breakpoints.Store(&map[string]map[int]bool{})
+	return d
+}
+
+// SetDebugger attaches d to env, so that the code compiled in env from
+// now on can be debugged, or detaches the debugger attached to env if
+// d is nil. Code compiled while a debugger was attached no longer
+// stops once it is detached, and threads stopped by it are resumed.
+func SetDebugger(env value.Environment, d *Debugger) {
+	e := env.(*environment)
+	if d != nil {
+		d.env = env
+	}
+	if old := e.debugger.Swap(d); old != nil && old != d {
+		old.detach()
+	}
+}
+
+// detach disables d and resumes the threads it stopped.
+func (d *Debugger) detach() {
+	d.detached.Store(true)
+	for _, t := range d.Threads() {
+		t.Continue()
+	}
+}
+
+// SetBreakpoints replaces the breakpoints of the file at path with
+// breakpoints at lines. path names the file as the :file metadata of
+// its forms does, or is an absolute path, which matches files loaded
+// from the load path by their path relative to it.
+func (d *Debugger) SetBreakpoints(path string, lines []int) {
+	old := *d.breakpoints.Load()
+	bps := make(map[string]map[int]bool, len(old)+1)
+	for k, v := range old {
+		bps[k] = v
+	}
+	path = filepath.Clean(path)
+	if len(lines) == 0 {
+		delete(bps, path)
+	} else {
+		set := map[int]bool{}
+		for _, line := range lines {
+			set[line] = true
+		}
+		bps[path] = set
+	}
+	d.breakpoints.Store(&bps)
+}
+
+// SetBreakOnError sets whether threads stop when an error is raised,
+// whether or not it is caught, and when an error escapes the
+// evaluation of a top-level form.
+func (d *Debugger) SetBreakOnError(raised, uncaught bool) {
+	d.breakOnRaised.Store(raised)
+	d.breakOnUncaught.Store(uncaught)
+}
+
+// Pause stops the next thread to evaluate a form.
+func (d *Debugger) Pause() {
+	d.pause.Store(true)
+}
+
+// Threads returns the threads evaluating debugged code, ordered by ID.
+func (d *Debugger) Threads() []*Thread {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	threads := make([]*Thread, 0, len(d.threads))
+	for _, t := range d.threads {
+		threads = append(threads, t)
+	}
+	for i := 1; i < len(threads); i++ {
+		for j := i; j > 0 && threads[j].id < threads[j-1].id; j-- {
+			threads[j], threads[j-1] = threads[j-1], threads[j]
+		}
+	}
+	return threads
+}
+
+// Thread returns the thread with the given ID, or nil.
+func (d *Debugger) Thread(id int) *Thread {
+	for _, t := range d.Threads() {
+		if t.id == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// enter returns the thread of the current goroutine, registering it
+// if it has no frames yet, or nil if the debugger is detached.
+func (d *Debugger) enter() *Thread {
+	if d.detached.Load() {
+		return nil
+	}
+	gid := goid.Get()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	t := d.threads[gid]
+	if t == nil {
+		d.nextID++
+		t = &Thread{id: d.nextID, d: d, cmds: make(chan threadCmd)}
+		d.threads[gid] = t
+	}
+	return t
+}
+
+// exit pops the innermost frame of t, unregistering it once it has
+// none left, unless it is stepping.
+func (t *Thread) exit() {
+	t.frames = t.frames[:len(t.frames)-1]
+	if len(t.frames) > 0 || t.step != stepNone {
+		return
+	}
+	d := t.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for gid, other := range d.threads {
+		if other == t {
+			delete(d.threads, gid)
+		}
+	}
+}
+
+// isBreakpoint reports whether a breakpoint is set at the line of p.
+func (d *Debugger) isBreakpoint(p *debugPoint) bool {
+	for path, lines := range *d.breakpoints.Load() {
+		if lines[p.line] && fileMatches(path, p.file) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileMatches reports whether the file at path is the file named by
+// the :file metadata of a form.
+func fileMatches(path, file string) bool {
+	file = filepath.Clean(filepath.FromSlash(file))
+	if path == file || filepath.IsAbs(file) {
+		return path == file
+	}
+	return strings.HasSuffix(path, string(filepath.Separator)+file)
+}
+
+// ID returns the number identifying the thread to its debugger.
+func (t *Thread) ID() int {
+	return t.id
+}
+
+// Reason returns the reason the thread stopped for, one of the Stop
+// constants.
+func (t *Thread) Reason() string {
+	return t.reason
+}
+
+// Err returns the error the thread stopped on, if it stopped for
+// StopException.
+func (t *Thread) Err() error {
+	return t.err
+}
+
+// Stack returns the frames of the stack of the thread, innermost
+// first.
+func (t *Thread) Stack() []DebugFrame {
+	frames := make([]DebugFrame, 0, len(t.frames))
+	for i := len(t.frames) - 1; i >= 0; i-- {
+		df := t.frames[i]
+		sf := df.stack
+		if p := df.point; p != nil {
+			sf.Filename, sf.Line, sf.Column = p.file, p.line, p.column
+		}
+		frames = append(frames, DebugFrame{StackFrame: sf, Locals: df.locals()})
+	}
+	return frames
+}
+
+// Continue resumes the thread until it next stops.
+func (t *Thread) Continue() error {
+	return t.resume(stepNone)
+}
+
+// StepIn resumes the thread until it reaches a form on another line
+// or in another frame.
+func (t *Thread) StepIn() error {
+	return t.resume(stepIn)
+}
+
+// StepOver resumes the thread until it reaches a form on another line
+// of the frame it stopped in, or returns from it.
+func (t *Thread) StepOver() error {
+	return t.resume(stepOver)
+}
+
+// StepOut resumes the thread until it returns from the frame it
+// stopped in.
+func (t *Thread) StepOut() error {
+	return t.resume(stepOut)
+}
+
+func (t *Thread) resume(step stepKind) error {
+	return t.do(threadCmd{resume: true, run: func() {
+		t.step = step
+		if len(t.frames) > 0 {
+			df := t.frames[len(t.frames)-1]
+			t.stepDepth, t.stepFile, t.stepLine = len(t.frames), df.file, df.line
+		}
+	}})
+}
+
+// Eval evaluates form in the namespace of the frame of the stack of
+// the thread at index frame, as returned by Stack, with the locals of
+// the frame bound. It is evaluated in the goroutine of the thread, so
+// with its bindings, and doesn't stop.
+func (t *Thread) Eval(frame int, form interface{}) (res interface{}, err error) {
+	if frame < 0 || frame >= len(t.frames) {
+		return nil, fmt.Errorf("no frame %d", frame)
+	}
+	df := t.frames[len(t.frames)-1-frame]
+	if doErr := t.Do(func() { res, err = t.evalIn(df, form) }); doErr != nil {
+		return nil, doErr
+	}
+	return res, err
+}
+
+// Do calls fn in the goroutine of the thread, so with its bindings,
+// and returns a panic it raises as an error. The thread doesn't stop
+// while fn runs, so fn may realize lazy sequences or call fns of the
+// debugged code.
+func (t *Thread) Do(fn func()) (err error) {
+	cmdErr := t.do(threadCmd{run: func() {
+		t.suspended = true
+		defer func() {
+			t.suspended = false
+			if r := recover(); r != nil {
+				var ok bool
+				if err, ok = r.(error); !ok {
+					err = fmt.Errorf("%v", r)
+				}
+			}
+		}()
+		fn()
+	}})
+	if cmdErr != nil {
+		return cmdErr
+	}
+	return err
+}
+
+// do sends cmd to the thread, which runs it in its goroutine, and
+// waits for it to have run.
+func (t *Thread) do(cmd threadCmd) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.isStopped {
+		return ErrNotStopped
+	}
+	if cmd.resume {
+		t.isStopped = false
+		t.cmds <- cmd
+		return nil
+	}
+	done := make(chan struct{})
+	run := cmd.run
+	cmd.run = func() {
+		defer close(done)
+		run()
+	}
+	t.cmds <- cmd
+	<-done
+	return nil
+}
+
+// stop stops the thread until a command resumes it.
+func (t *Thread) stop(reason string, err interface{}) {
+	t.mu.Lock()
+	t.reason, t.err = reason, nil
+	if err != nil {
+		var ok bool
+		if t.err, ok = err.(error); !ok {
+			t.err = fmt.Errorf("%v", err)
+		}
+	}
+	t.step = stepNone
+	t.isStopped = true
+	t.mu.Unlock()
+
+	t.d.stopped(t)
+	for cmd := range t.cmds {
+		cmd.run()
+		if cmd.resume {
+			return
+		}
+	}
+}
+
+// reached is called when the thread is about to evaluate the form at
+// p in frame df, and stops it if it should stop there.
+func (t *Thread) reached(df *debugFrame, p *debugPoint) {
+	df.point = p
+	if t.suspended || t.d.detached.Load() {
+		return
+	}
+	newLine := p.line != df.line || p.file != df.file
+	df.file, df.line = p.file, p.line
+
+	depth := len(t.frames)
+	moved := p.line != t.stepLine || p.file != t.stepFile
+	var reason string
+	switch {
+	case t.d.pause.CompareAndSwap(true, false):
+		reason = StopPause
+	case t.step == stepIn && (depth != t.stepDepth || moved),
+		t.step == stepOver && (depth < t.stepDepth || depth == t.stepDepth && moved),
+		t.step == stepOut && depth < t.stepDepth:
+		reason = StopStep
+	case newLine && t.d.isBreakpoint(p):
+		reason = StopBreakpoint
+	default:
+		return
+	}
+	t.stop(reason, nil)
+}
+
+// raise is called when err, an error returned or a value thrown,
+// escapes the form at p in frame df, and stops the thread if the
+// debugger breaks on raised errors and it has not stopped on or
+// passed by err before. If uncaught is set, err escapes a top-level
+// form.
+func (t *Thread) raise(df *debugFrame, err interface{}, uncaught bool) {
+	if t.suspended || t.d.detached.Load() {
+		return
+	}
+	seen := sameError(err, t.raised)
+	t.raised = err
+	switch {
+	case !seen && t.d.breakOnRaised.Load(),
+		uncaught && !t.d.breakOnRaised.Load() && t.d.breakOnUncaught.Load():
+		t.stop(StopException, err)
+	}
+}
+
+// sameError reports whether a and b are the same error, or wrap the
+// same error raised by a form.
+func sameError(a, b interface{}) bool {
+	cause := func(v interface{}) interface{} {
+		if e, ok := v.(*EvalError); ok {
+			return e.Err
+		}
+		return v
+	}
+	a, b = cause(a), cause(b)
+	if a == nil || b == nil || reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// locals returns the locals in scope at the current form of df.
+func (df *debugFrame) locals() []Local {
+	p := df.point
+	if p == nil {
+		return nil
+	}
+	var locals []Local
+	index := map[string]int{}
+	add := func(name string, val interface{}) {
+		if i, ok := index[name]; ok {
+			// an inner local shadows an outer one.
+			locals = append(locals[:i], locals[i+1:]...)
+			for n, j := range index {
+				if j > i {
+					index[n] = j - 1
+				}
+			}
+		}
+		index[name] = len(locals)
+		locals = append(locals, Local{Name: name, Value: val})
+	}
+	if cl := p.closure; cl != nil {
+		if cl.self != "" {
+			add(cl.self, df.f.fn)
+		}
+		for i, name := range cl.names {
+			if i < len(df.f.closed) {
+				add(name, df.f.closed[i])
+			}
+		}
+	}
+	for _, l := range p.locals {
+		add(l.name, df.f.slots[l.slot])
+	}
+	return locals
+}
+
+// evalIn evaluates form in the namespace of df with its locals bound,
+// as the body of a fn applied to their values.
+func (t *Thread) evalIn(df *debugFrame, form interface{}) (interface{}, error) {
+	var params, args []interface{}
+	for _, l := range df.locals() {
+		if l.Name == "&" {
+			continue
+		}
+		params = append(params, value.NewSymbol(l.Name))
+		args = append(args, l.Value)
+	}
+	ns := value.FindNamespace(value.NewSymbol(df.stack.Namespace))
+	if ns == nil {
+		ns = t.d.env.CurrentNamespace()
+	}
+	value.PushThreadBindings(value.NewMap(value.VarCurrentNS, ns))
+	defer value.PopThreadBindings()
+
+	fn, err := t.d.env.Eval(value.NewList(value.NewSymbol("fn*"), value.NewVector(params...), form))
+	if err != nil {
+		return nil, err
+	}
+	return value.Apply(fn, args), nil
+}
+
+// debugger returns the debugger attached to the environment of the
+// code being compiled, or nil.
+func (c *codeCompiler) debugger() *Debugger {
+	d := c.env.debugger.Load()
+	if d == nil || d.detached.Load() {
+		return nil
+	}
+	return d
+}
+
+// newDebugPoint returns the point of the source form of n, with the
+// locals in scope, or nil if it has no source position.
+func (c *codeCompiler) newDebugPoint(n *ast.Node) *debugPoint {
+	meta := formMeta(sourceForm(n))
+	line, _ := value.Get(meta, value.KWLine).(int)
+	if line == 0 {
+		return nil
+	}
+	p := &debugPoint{line: line, closure: c.scope.closure}
+	p.file, _ = value.Get(meta, value.KWFile).(string)
+	p.column, _ = value.Get(meta, value.KWColumn).(int)
+	p.locals = append([]scopeLocal(nil), c.scope.locals...)
+	return p
+}
+
+// debugForm returns code that lets d stop threads before cd, the code
+// of n, is evaluated, if n is a form with a source position.
+func (c *codeCompiler) debugForm(d *Debugger, n *ast.Node, cd code) code {
+	if _, ok := sourceForm(n).(value.ISeq); !ok {
+		return cd
+	}
+	p := c.newDebugPoint(n)
+	if p == nil {
+		return cd
+	}
+	return func(f *frame) (res interface{}, err error) {
+		df := f.debug
+		if df == nil {
+			return cd(f)
+		}
+		t := df.thread
+		prev := df.point
+		t.reached(df, p)
+		defer func() {
+			if r := recover(); r != nil {
+				t.raise(df, r, false)
+				panic(r)
+			}
+		}()
+		res, err = cd(f)
+		if err != nil && err != errRecur {
+			t.raise(df, err, false)
+			return nil, err
+		}
+		// the form is done, so the frame is back to evaluating the
+		// form enclosing it.
+		df.point = prev
+		return res, err
+	}
+}
+
+// debugFrameCode returns code that evaluates cd, the code of a fn
+// method body or a top-level form, in a frame of the thread of the
+// goroutine evaluating it. n is the fn method or top-level node.
+func (c *codeCompiler) debugFrameCode(d *Debugger, n *ast.Node, cd code) code {
+	stack := c.frame
+	p := c.newDebugPoint(n)
+	topLevel := c.scope.closure == nil
+	return func(f *frame) (interface{}, error) {
+		t := d.enter()
+		if t == nil {
+			return cd(f)
+		}
+		df := &debugFrame{thread: t, stack: stack, f: f, point: p}
+		f.debug = df
+		t.frames = append(t.frames, df)
+		defer t.exit()
+		res, err := cd(f)
+		if err != nil && err != errRecur && topLevel && len(t.frames) == 1 {
+			t.raise(df, err, true)
+		}
+		return res, err
+	}
+}
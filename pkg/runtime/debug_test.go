@@ -0,0 +1,212 @@
+package runtime_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+const debugSource = `(ns dbg.test)
+(defn inner [x]
+  (let [y (* x 2)]
+    (+ y 1)))
+(defn outer [n]
+  (let [a (inner n)]
+    (str a)))
+(defn fail []
+  (try
+    (throw (errors.New "boom"))
+    (catch go/error e :caught)))
+`
+
+// debugSession evaluates code in an environment with a debugger
+// attached, recording the threads that stop.
+type debugSession struct {
+	t       *testing.T
+	env     value.Environment
+	d       *runtime.Debugger
+	stopped chan *runtime.Thread
+}
+
+func newDebugSession(t *testing.T) *debugSession {
+	pushBindings()
+	t.Cleanup(value.PopThreadBindings)
+
+	s := &debugSession{t: t, env: runtime.NewEnvironment(), stopped: make(chan *runtime.Thread, 1)}
+	s.d = runtime.NewDebugger(func(th *runtime.Thread) { s.stopped <- th })
+	runtime.SetDebugger(s.env, s.d)
+	t.Cleanup(func() { runtime.SetDebugger(s.env, nil) })
+	runtime.ReadEval(debugSource, runtime.WithEnv(s.env), runtime.WithFilename("dbg/test.glj"))
+	return s
+}
+
+// read reads expr without evaluating anything, so that a paused
+// debugger doesn't stop the test goroutine.
+func (s *debugSession) read(expr string) interface{} {
+	s.t.Helper()
+	form, err := reader.New(strings.NewReader(expr)).ReadOne()
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return form
+}
+
+// eval evaluates expr in another goroutine, returning a channel that
+// receives its printed value.
+func (s *debugSession) eval(expr string) chan string {
+	res := make(chan string, 1)
+	form := s.read(expr)
+	go func() {
+		val, err := s.env.Eval(form)
+		if err != nil {
+			res <- err.Error()
+			return
+		}
+		res <- value.PrintString(val)
+	}()
+	return res
+}
+
+// stop waits for a thread to stop for reason at line in the fn named
+// fnName, and returns it.
+func (s *debugSession) stop(reason string, fnName string, line int) *runtime.Thread {
+	s.t.Helper()
+	select {
+	case th := <-s.stopped:
+		frame := th.Stack()[0]
+		if th.Reason() != reason || frame.FunctionName != fnName || frame.Line != line {
+			s.t.Fatalf("expected to stop for %s in %s at line %d, stopped for %s at %s", reason, fnName, line, th.Reason(), frame)
+		}
+		return th
+	case <-time.After(10 * time.Second):
+		s.t.Fatalf("expected to stop for %s in %s at line %d", reason, fnName, line)
+	}
+	return nil
+}
+
+func (s *debugSession) result(res chan string, expected string) {
+	s.t.Helper()
+	select {
+	case got := <-res:
+		if got != expected {
+			s.t.Errorf("expected %s, got %s", expected, got)
+		}
+	case th := <-s.stopped:
+		s.t.Fatalf("unexpected stop for %s at %s", th.Reason(), th.Stack()[0])
+	case <-time.After(10 * time.Second):
+		s.t.Fatal("timed out waiting for the result")
+	}
+}
+
+func locals(frame runtime.DebugFrame) string {
+	var parts []string
+	for _, l := range frame.Locals {
+		parts = append(parts, fmt.Sprintf("%s=%s", l.Name, value.PrintString(l.Value)))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestDebuggerBreakpoints(t *testing.T) {
+	s := newDebugSession(t)
+	path, err := filepath.Abs(filepath.FromSlash("src/dbg/test.glj"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.d.SetBreakpoints(path, []int{4})
+
+	res := s.eval("(dbg.test/outer 3)")
+	th := s.stop(runtime.StopBreakpoint, "inner", 4)
+	if threads := s.d.Threads(); len(threads) != 1 || threads[0] != th {
+		t.Errorf("expected the stopped thread, got %v", threads)
+	}
+	stack := th.Stack()
+	if len(stack) < 2 || stack[1].FunctionName != "outer" || stack[1].Line != 6 {
+		t.Fatalf("unexpected stack %v", stack)
+	}
+	if got := locals(stack[0]); got != "x=3 y=6" {
+		t.Errorf("unexpected locals of inner: %s", got)
+	}
+	if got := locals(stack[1]); got != "n=3" {
+		t.Errorf("unexpected locals of outer: %s", got)
+	}
+	for frame, expr := range map[int]string{0: "(+ x y)", 1: "(* n 3)"} {
+		val, err := th.Eval(frame, s.read(expr))
+		if err != nil || val != int64(9) {
+			t.Errorf("expected %s to be 9 in frame %d, got %v, %v", expr, frame, val, err)
+		}
+	}
+
+	if err := th.StepOut(); err != nil {
+		t.Fatal(err)
+	}
+	th = s.stop(runtime.StopStep, "outer", 7)
+	if got := locals(th.Stack()[0]); got != "n=3 a=7" {
+		t.Errorf("unexpected locals of outer: %s", got)
+	}
+	if err := th.Continue(); err != nil {
+		t.Fatal(err)
+	}
+	s.result(res, `"7"`)
+	if err := th.Continue(); err != runtime.ErrNotStopped {
+		t.Errorf("expected ErrNotStopped, got %v", err)
+	}
+
+	// the breakpoint stops each call.
+	res = s.eval("(+ (dbg.test/inner 1) (dbg.test/inner 2))")
+	s.stop(runtime.StopBreakpoint, "inner", 4).Continue()
+	s.stop(runtime.StopBreakpoint, "inner", 4).Continue()
+	s.result(res, "8")
+
+	s.d.SetBreakpoints(path, nil)
+	s.result(s.eval("(dbg.test/outer 1)"), `"3"`)
+}
+
+func TestDebuggerStepping(t *testing.T) {
+	s := newDebugSession(t)
+	s.d.SetBreakpoints("dbg/test.glj", []int{6})
+
+	res := s.eval("(dbg.test/outer 1)")
+	th := s.stop(runtime.StopBreakpoint, "outer", 6)
+	th.StepIn()
+	th = s.stop(runtime.StopStep, "inner", 3)
+	th.StepOver()
+	th = s.stop(runtime.StopStep, "inner", 4)
+	th.StepOver()
+	th = s.stop(runtime.StopStep, "outer", 7)
+	th.StepOver()
+	s.result(res, `"3"`)
+}
+
+func TestDebuggerErrorsAndPause(t *testing.T) {
+	s := newDebugSession(t)
+
+	s.d.SetBreakOnError(true, false)
+	res := s.eval("(dbg.test/fail)")
+	th := s.stop(runtime.StopException, "fail", 10)
+	if th.Err() == nil || !strings.Contains(th.Err().Error(), "boom") {
+		t.Errorf("expected the error boom, got %v", th.Err())
+	}
+	th.Continue()
+	s.result(res, ":caught")
+
+	s.d.SetBreakOnError(false, true)
+	s.result(s.eval("(dbg.test/fail)"), ":caught")
+	res = s.eval("(dbg.test/inner nil)")
+	th = s.stop(runtime.StopException, "", 1)
+	th.Continue()
+	if got := <-res; !strings.Contains(got, "nil") {
+		t.Errorf("expected an error, got %s", got)
+	}
+	s.d.SetBreakOnError(false, false)
+
+	s.d.Pause()
+	res = s.eval("(dbg.test/inner 1)")
+	s.stop(runtime.StopPause, "", 1).Continue()
+	s.result(res, "3")
+}
@@ -5,11 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strings"
 
 	value "github.com/glojurelang/glojure/pkg/lang"
-	"github.com/glojurelang/glojure/pkg/reader"
-	"github.com/glojurelang/glojure/pkg/stdlib"
 )
 
 type Program struct {
@@ -17,14 +14,28 @@ type Program struct {
 }
 
 type evalOptions struct {
-	stdout   io.Writer
-	stderr   io.Writer
-	loadPath []string
-	env      *environment
+	ctx           context.Context
+	stdout        io.Writer
+	stderr        io.Writer
+	loadPath      []string
+	env           *environment
+	imageRecorder *Image
+	noImage       bool
+	sandbox       *Sandbox
+	coverage      *Coverage
 }
 
 type EvalOption func(*evalOptions)
 
+// WithContext sets the context of evaluation in the environment,
+// which is the root binding of *context*. Evaluation stops with a
+// *lang.CanceledError once ctx is done.
+func WithContext(ctx context.Context) EvalOption {
+	return func(opts *evalOptions) {
+		opts.ctx = ctx
+	}
+}
+
 func WithStdout(w io.Writer) EvalOption {
 	return func(opts *evalOptions) {
 		opts.stdout = w
@@ -43,6 +54,23 @@ func WithLoadPath(path []string) EvalOption {
 	}
 }
 
+// WithImageRecorder records the files loaded in the environment,
+// including the core library, in img. Files are not restored from
+// images while recording.
+func WithImageRecorder(img *Image) EvalOption {
+	return func(opts *evalOptions) {
+		opts.imageRecorder = img
+	}
+}
+
+// WithoutImage disables restoring files from images, so that all
+// files are loaded from source.
+func WithoutImage() EvalOption {
+	return func(opts *evalOptions) {
+		opts.noImage = true
+	}
+}
+
 func withEnv(env value.Environment) EvalOption {
 	e := env.(*environment)
 	return func(opts *evalOptions) {
@@ -52,6 +80,7 @@ func withEnv(env value.Environment) EvalOption {
 
 func NewEnvironment(opts ...EvalOption) value.Environment {
 	options := &evalOptions{
+		ctx:    context.Background(),
 		stdout: os.Stdout,
 		stderr: os.Stderr,
 	}
@@ -61,11 +90,16 @@ func NewEnvironment(opts ...EvalOption) value.Environment {
 
 	env := options.env
 	if env == nil {
-		env = newEnvironment(context.Background(), options.stdout, options.stderr)
+		env = newEnvironment(options.ctx, options.stdout, options.stderr)
 		env.loadPath = options.loadPath
 	}
+	env.imageRecorder = options.imageRecorder
+	env.noImage = options.noImage
 	// TODO: this is rather rather hacky
 	value.GlobalEnv = env
+	value.VarOut.BindRoot(env.stdout)
+	value.VarErr.BindRoot(env.stderr)
+	value.SetRootContext(env.ctx)
 
 	// bootstrap namespace control
 	{
@@ -85,32 +119,15 @@ func NewEnvironment(opts ...EvalOption) value.Environment {
 		}))
 	}
 
-	{
-		// Add stdlib
-		evalFile := func(path string) {
-			core, err := stdlib.StdLib.ReadFile(path)
-			if err != nil {
-				panic(fmt.Sprintf("could not read stdlib core.glj: %v", err))
-			}
-			r := reader.New(strings.NewReader(string(core)), reader.WithFilename(path), reader.WithGetCurrentNS(func() *value.Namespace {
-				return env.CurrentNamespace()
-			}))
-
-			for {
-				expr, err := r.ReadOne()
-				if err == reader.ErrEOF {
-					break
-				}
-				if err != nil {
-					panic(fmt.Sprintf("error reading core lib %v: %v", path, err))
-				}
-				_, err = env.Eval(expr)
-				if err != nil {
-					panic(fmt.Sprintf("error evaluating core lib %v: %v", path, err))
-				}
-			}
-		}
-		evalFile("glojure/core.glj")
+	// Add stdlib
+	if err := env.loadFile("glojure/core.glj"); err != nil {
+		panic(fmt.Sprintf("could not load core lib: %v", err))
+	}
+	if options.sandbox != nil {
+		SetSandbox(env, options.sandbox)
+	}
+	if options.coverage != nil {
+		SetCoverage(env, options.coverage)
 	}
 
 	return env
@@ -40,15 +40,35 @@ type (
 		stderr io.Writer
 
 		loadPath []string
+
+		// imageRecorder, if set, records the files loaded in the
+		// environment. Images are not consulted while recording.
+		imageRecorder *Image
+		// noImage disables restoring files from images.
+		noImage bool
+
+		// sandbox, if set, restricts the code compiled in the
+		// environment. It is shared by the environment's copies.
+		sandbox *atomic.Pointer[Sandbox]
+		// debugger, if set, can stop the code compiled in the
+		// environment. It is shared by the environment's copies.
+		debugger *atomic.Pointer[Debugger]
+		// coverage, if set, records the evaluations of the code
+		// compiled in the environment. It is shared by the
+		// environment's copies.
+		coverage *atomic.Pointer[Coverage]
 	}
 )
 
 func newEnvironment(ctx context.Context, stdout, stderr io.Writer) *environment {
 	e := &environment{
-		ctx:    ctx,
-		scope:  newScope(),
-		stdout: stdout,
-		stderr: stderr,
+		ctx:      ctx,
+		scope:    newScope(),
+		stdout:   stdout,
+		stderr:   stderr,
+		sandbox:  new(atomic.Pointer[Sandbox]),
+		debugger: new(atomic.Pointer[Debugger]),
+		coverage: new(atomic.Pointer[Coverage]),
 	}
 	coreNS := value.NSCore
 
@@ -58,7 +78,6 @@ func newEnvironment(ctx context.Context, stdout, stderr io.Writer) *environment
 		"compile-path",
 		"unchecked-math",
 		"compiler-options",
-		"err",
 		"flush-on-newline",
 		"print-meta",
 		"print-dup",
@@ -67,13 +86,32 @@ func newEnvironment(ctx context.Context, stdout, stderr io.Writer) *environment
 		coreNS.InternWithValue(value.NewSymbol("*"+dyn+"*"), nil, true).SetDynamic()
 	}
 
-	// TODO: implement this
-	coreNS.InternWithValue(value.NewSymbol("load-file"), nil, true)
+	coreNS.InternWithValue(value.NewSymbol("load-file"), value.IFnFunc(func(args ...interface{}) interface{} {
+		if len(args) != 1 {
+			panic(value.NewIllegalArgumentError(fmt.Sprintf("wrong number of args (%v) passed to: load-file", len(args))))
+		}
+		name := args[0].(string)
+		f, err := os.Open(name)
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+		if err := LoadReader(e, f, name); err != nil {
+			panic(err)
+		}
+		return nil
+	}), true)
 
 	// bootstrap some vars
 	e.namespaceVar = coreNS.InternWithValue(SymbolNamespace,
 		value.IFnFunc(func(args ...interface{}) interface{} {
-			return coreNS
+			// expands to (in-ns 'name), ignoring references, so
+			// that the expansion holds when restored from an image.
+			// args are &form, &env and the name.
+			if len(args) < 3 {
+				return coreNS
+			}
+			return value.NewList(SymbolInNamespace, value.NewList(value.NewSymbol("quote"), args[2]))
 		}), true)
 	e.namespaceVar.SetMacro()
 
@@ -196,20 +234,32 @@ func (env *environment) Errorf(n interface{}, format string, args ...interface{}
 }
 
 func (env *environment) errorf(n interface{}, format string, args ...interface{}) error {
-	var filename, line, col string
+	return fmt.Errorf("%s: "+format, append([]interface{}{formLocation(n)}, args...)...)
+}
+
+// formLocation returns the file:line:column position of a form, from
+// its metadata.
+func formLocation(n interface{}) string {
+	return location(n, value.PrintString)
+}
+
+// callLocation returns the position of a form as formLocation does,
+// with the file unquoted, as go/try errors and reflection warnings
+// report the position of calls.
+func callLocation(n interface{}) string {
+	return location(n, value.ToString)
+}
+
+// location returns the file:line:column position of a form, from its
+// metadata, with each part formatted by format.
+func location(n interface{}, format func(interface{}) string) string {
 	var meta value.IPersistentMap
 	if n, ok := n.(value.IObj); ok {
 		meta = n.Meta()
 	}
 	get := func(m value.IPersistentMap, key string) string {
-		return value.PrintString(value.GetDefault(m, value.NewKeyword(key), "?"))
+		return format(value.GetDefault(m, value.NewKeyword(key), "?"))
 	}
 
-	filename = get(meta, "file")
-	line = get(meta, "line")
-	col = get(meta, "column")
-
-	location := fmt.Sprintf("%s:%s:%s", filename, line, col)
-
-	return fmt.Errorf("%s: "+format, append([]interface{}{location}, args...)...)
+	return fmt.Sprintf("%s:%s:%s", get(meta, "file"), get(meta, "line"), get(meta, "column"))
 }
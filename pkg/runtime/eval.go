@@ -3,12 +3,20 @@ package runtime
 import (
 	"fmt"
 
+	"github.com/glojurelang/glojure/pkg/ast"
 	"github.com/glojurelang/glojure/pkg/compiler"
 	"github.com/glojurelang/glojure/pkg/lang"
 	value "github.com/glojurelang/glojure/pkg/lang"
 )
 
 func (env *environment) Macroexpand1(form interface{}) (interface{}, error) {
+	return env.macroexpand1(form, nil)
+}
+
+// macroexpand1 expands form once if it is a macro call. cenv is the
+// analyzer environment of form, or nil outside of analysis. Locals in
+// cenv shadow macros of the same name.
+func (env *environment) macroexpand1(form interface{}, cenv compiler.Env) (interface{}, error) {
 	seq, ok := form.(value.ISeq)
 	if !ok {
 		return form, nil
@@ -24,32 +32,50 @@ func (env *environment) Macroexpand1(form interface{}) (interface{}, error) {
 	if len(symStr) > 1 && symStr[0] == '.' && symStr[1] != '.' {
 		fieldSym := value.NewSymbol(sym.String()[1:])
 		// rewrite the expression to a dot expression
-		dotExpr := value.NewCons(SymbolDot, value.NewCons(seq.Next().First(), value.NewCons(fieldSym, seq.Next().Next())))
-		return env.Macroexpand1(dotExpr)
+		var dotExpr interface{} = value.NewCons(SymbolDot, value.NewCons(seq.Next().First(), value.NewCons(fieldSym, seq.Next().Next())))
+		// keep the source position of the form for stack traces.
+		if meta := formMeta(form); meta != nil {
+			dotExpr = dotExpr.(value.IObj).WithMeta(meta)
+		}
+		return env.macroexpand1(dotExpr, cenv)
+	}
+
+	if sym.Namespace() == "" && value.Get(value.Get(cenv, value.KWLocals), sym) != nil {
+		return form, nil
 	}
 
-	macroVar := env.asMacro(sym)
+	macroVar, err := env.asMacro(sym)
+	if err != nil {
+		return nil, env.errorf(form, "%w", err)
+	}
 	if macroVar == nil {
 		return form, nil
 	}
+	if cenv != nil {
+		env.registerVar(macroVar)
+	}
 
 	applyer, ok := macroVar.Get().(value.IFn)
 	if !ok {
 		return nil, env.errorf(form, "macro %s is not a function (%T)", sym, macroVar.Get())
 	}
-	res, err := env.applyMacro(applyer, form.(value.ISeq))
+	var macroEnv value.IPersistentMap
+	if cenv != nil {
+		macroEnv = compiler.MacroEnv(cenv)
+	}
+	res, err := env.applyMacro(applyer, form.(value.ISeq), macroEnv)
 	if err != nil {
 		return nil, env.errorf(form, "error applying macro: %w", err)
 	}
 	return res, nil
 }
 
-func (env *environment) applyMacro(fn value.IFn, form value.ISeq) (interface{}, error) {
+func (env *environment) applyMacro(fn value.IFn, form value.ISeq, macroEnv value.IPersistentMap) (interface{}, error) {
 	argList := form.Next()
-	// two hidden arguments, $form and $env (nil for now).
-	// $form is the form that was passed to the macro
-	// $env is the environment that the macro was called in
-	return fn.ApplyTo(value.NewCons(form, value.NewCons(nil, argList))), nil
+	// two hidden arguments, &form and &env.
+	// &form is the form that was passed to the macro
+	// &env maps the locals in scope at the call to their bindings
+	return fn.ApplyTo(value.NewCons(form, value.NewCons(macroEnv, argList))), nil
 }
 
 func (env *environment) Eval(n interface{}) (interface{}, error) {
@@ -57,8 +83,16 @@ func (env *environment) Eval(n interface{}) (interface{}, error) {
 }
 
 func (env *environment) evalInternal(n interface{}) (interface{}, error) {
+	astNode, err := env.analyze(n)
+	if err != nil {
+		return nil, err
+	}
+	return env.EvalAST(astNode)
+}
+
+func (env *environment) analyze(n interface{}) (*ast.Node, error) {
 	analyzer := &compiler.Analyzer{
-		Macroexpand1: env.Macroexpand1,
+		Macroexpand1: env.macroexpand1,
 		CreateVar: func(sym *value.Symbol, e compiler.Env) (interface{}, error) {
 			vr := env.CurrentNamespace().Intern(sym)
 			return vr, nil
@@ -73,13 +107,16 @@ func (env *environment) evalInternal(n interface{}) (interface{}, error) {
 		},
 		FindNamespace: lang.FindNamespace,
 	}
-	astNode, err := analyzer.Analyze(n, value.NewMap(
+	return analyzer.Analyze(n, value.NewMap(
 		value.KWNS, env.CurrentNamespace().Name(),
 	))
-	if err != nil {
-		return nil, err
-	}
-	return env.EvalAST(astNode)
+}
+
+// Analyze analyzes form in env without evaluating it, expanding the
+// macros it calls. Vars defined by form are interned in the current
+// namespace, but not bound.
+func Analyze(env value.Environment, form interface{}) (*ast.Node, error) {
+	return env.(*environment).analyze(form)
 }
 
 // Helpers
@@ -127,20 +164,26 @@ func (env *environment) namespaceForSymbol(sym *value.Symbol) *value.Namespace {
 	return value.NamespaceFor(env.CurrentNamespace(), sym)
 }
 
+// registerVar records that the current namespace uses v.
 func (env *environment) registerVar(v *value.Var) {
-	// TODO: implement
+	env.CurrentNamespace().AddUsedVar(v)
 }
 
-func (env *environment) asMacro(sym *value.Symbol) *value.Var {
+// asMacro returns the macro var sym refers to, or nil if it does not
+// refer to a macro. It is an error to refer to a private macro of
+// another namespace.
+func (env *environment) asMacro(sym *value.Symbol) (*value.Var, error) {
 	vr, err := env.lookupVar(sym, false, false)
 	if vr == nil || err != nil {
-		return nil
+		return nil, nil
+	}
+	if !vr.IsMacro() {
+		return nil, nil
 	}
-	// TODO: implement check for public/private
-	if vr.IsMacro() {
-		return vr
+	if vr.Namespace() != env.CurrentNamespace() && !vr.IsPublic() {
+		return nil, fmt.Errorf("var: %s is not public", vr)
 	}
-	return nil
+	return vr, nil
 }
 
 // Misc. helpers
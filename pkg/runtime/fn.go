@@ -1,7 +1,6 @@
 package runtime
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/glojurelang/glojure/pkg/ast"
@@ -11,16 +10,50 @@ import (
 type Fn struct {
 	meta lang.IPersistentMap
 
-	astNode *ast.Node
-	env     lang.Environment
+	code *fnCode
+	// closed holds the values of the locals the fn closes over.
+	closed []interface{}
+}
+
+// fnCode is a compiled fn node.
+type fnCode struct {
+	node *ast.Node
+
+	// fixed holds the methods of fixed arity, indexed by arity.
+	fixed         []*methodCode
+	variadic      *methodCode
+	isVariadic    bool
+	maxFixedArity int
+
+	// captures locates the closed over locals in the frame in which
+	// the fn is created.
+	captures []localRef
+}
+
+type methodCode struct {
+	fixedArity int
+	variadic   bool
+	numSlots   int
+	body       code
 }
 
 var (
 	_ lang.IObj = (*Fn)(nil)
 )
 
+// NewFn returns the fn of a fn node that closes over no locals.
 func NewFn(astNode *ast.Node, env lang.Environment) *Fn {
-	return &Fn{astNode: astNode, env: env}
+	c := &codeCompiler{env: env.(*environment), scope: &frameScope{}}
+	c.frame.Namespace = env.CurrentNamespace().Name().Name()
+	fnCode, err := c.compileFn(astNode)
+	if err != nil {
+		panic(err)
+	}
+	fn, err := fnCode(&frame{})
+	if err != nil {
+		panic(err)
+	}
+	return fn.(*Fn)
 }
 
 func (fn *Fn) Meta() lang.IPersistentMap {
@@ -33,111 +66,61 @@ func (fn *Fn) WithMeta(meta lang.IPersistentMap) interface{} {
 	return &cpy
 }
 
-func (fn *Fn) Invoke(args ...interface{}) interface{} {
-	fnNode := fn.astNode.Sub.(*ast.FnNode)
-
-	methods := fnNode.Methods
-	variadic := fnNode.IsVariadic
-	maxArity := fnNode.MaxFixedArity
-
-	if !variadic && len(args) > maxArity {
-		panic(lang.NewIllegalArgumentError(fmt.Sprintf("too many arguments (%d)", len(args))))
+// capture copies the locals the fn closes over from f.
+func (fn *Fn) capture(f *frame) {
+	for i, ref := range fn.code.captures {
+		fn.closed[i] = f.load(ref)
 	}
+}
 
-	method, err := fn.findMethod(methods, args)
-	if err != nil {
+func (fn *Fn) Invoke(args ...interface{}) interface{} {
+	if err := lang.CheckCanceled(); err != nil {
 		panic(err)
 	}
-
-	fnEnv := fn.env.PushScope()
-	if fnNode.Local != nil {
-		localNode := fnNode.Local.Sub.(*ast.BindingNode)
-		fnEnv.BindLocal(localNode.Name, fn)
-	}
-
-	methodNode := method.Sub.(*ast.FnMethodNode)
-
-	fixedArity := methodNode.FixedArity
-	methodVariadic := methodNode.IsVariadic
-	body := methodNode.Body
-
-	bindingValues := args[:fixedArity]
-
-	arity := fixedArity
-	var bindingRestValue interface{}
-	if len(args) > len(bindingValues) {
-		arity++
-		bindingRestValue = lang.NewList(args[len(bindingValues):]...)
+	method, err := fn.code.findMethod(args)
+	if err != nil {
+		panic(err)
 	}
 
-Recur:
-
-	params := methodNode.Params
-	for i, paramValue := range bindingValues {
-		param := params[i]
-		paramNode := param.Sub.(*ast.BindingNode)
-		fnEnv.BindLocal(paramNode.Name, paramValue)
+	f := &frame{
+		slots:  make([]interface{}, method.numSlots),
+		closed: fn.closed,
+		fn:     fn,
 	}
-	if bindingRestValue != nil {
-		param := params[len(params)-1]
-		paramNode := param.Sub.(*ast.BindingNode)
-		fnEnv.BindLocal(paramNode.Name, bindingRestValue)
-	} else if methodVariadic {
-		param := params[len(params)-1]
-		paramNode := param.Sub.(*ast.BindingNode)
-		fnEnv.BindLocal(paramNode.Name, nil)
+	fixedArity := method.fixedArity
+	copy(f.slots, args[:fixedArity])
+	if len(args) > fixedArity {
+		f.slots[fixedArity] = lang.NewList(args[fixedArity:]...)
 	}
 
-	rt := lang.NewRecurTarget()
-	recurEnv := fnEnv.WithRecurTarget(rt)
-	recurErr := &lang.RecurError{Target: rt}
-	res, err := recurEnv.EvalAST(body)
-	if errors.As(err, &recurErr) {
-		if len(recurErr.Args) != arity {
-			panic("wrong number of arguments to recur")
+	for {
+		res, err := method.body(f)
+		if err == errRecur {
+			if err := lang.CheckCanceled(); err != nil {
+				panic(err)
+			}
+			continue
 		}
-		bindingRestValue = nil
-		bindingValues = recurErr.Args[:fixedArity]
-		if len(recurErr.Args) > fixedArity {
-			bindingRestValue = recurErr.Args[fixedArity]
+		if err != nil {
+			panic(err)
 		}
-		goto Recur
+		return res
 	}
-	if err != nil {
-		panic(errorWithStack(err, lang.StackFrame{})) // TODO: think through error stacks
-	}
-	return res
 }
 
-func (fn *Fn) findMethod(methods []*ast.Node, args []interface{}) (*ast.Node, error) {
-	var variadicMethod *ast.Node
-	for _, method := range methods {
-		methodNode := method.Sub.(*ast.FnMethodNode)
-		if methodNode.IsVariadic {
-			variadicMethod = method
-			continue
-		}
-		if methodNode.FixedArity == len(args) {
-			return method, nil
-		}
+func (fc *fnCode) findMethod(args []interface{}) (*methodCode, error) {
+	if !fc.isVariadic && len(args) > fc.maxFixedArity {
+		return nil, lang.NewIllegalArgumentError(fmt.Sprintf("too many arguments (%d)", len(args)))
+	}
+	if len(args) < len(fc.fixed) && fc.fixed[len(args)] != nil {
+		return fc.fixed[len(args)], nil
 	}
-	if variadicMethod == nil || len(args) < variadicMethod.Sub.(*ast.FnMethodNode).FixedArity {
+	if fc.variadic == nil || len(args) < fc.variadic.fixedArity {
 		return nil, lang.NewIllegalArgumentError(fmt.Sprintf("wrong number of arguments (%d)", len(args)))
 	}
-	return variadicMethod, nil
+	return fc.variadic, nil
 }
 
 func (fn *Fn) ApplyTo(args lang.ISeq) interface{} {
 	return fn.Invoke(seqToSlice(args)...)
 }
-
-func errorWithStack(err error, stackFrame lang.StackFrame) error {
-	if err == nil {
-		return nil
-	}
-	valErr, ok := err.(*lang.Error)
-	if !ok {
-		return lang.NewError(stackFrame, err)
-	}
-	return valErr.AddStack(stackFrame)
-}
@@ -0,0 +1,207 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+)
+
+// maxHostSiteTypes is the number of receiver types a host call or
+// interop site caches members of before it is megamorphic. Members of
+// further receiver types are looked up by name on every evaluation.
+const maxHostSiteTypes = 4
+
+// hostSite is a host call or host interop form. It holds an inline
+// cache of where the member it names was found on each receiver type
+// it has seen, so that evaluating it doesn't search the receiver's
+// methods and fields by name.
+type hostSite struct {
+	sym *value.Symbol
+	// name is the Go name of the member, with its first letter
+	// capitalized as by lang.FieldOrMethod.
+	name  string
+	goTry bool
+	// warn is set if a reflection warning is printed to *err* when
+	// the site becomes megamorphic.
+	warn     bool
+	location func() string
+
+	members     atomic.Pointer[[]hostMember]
+	megamorphic atomic.Bool
+}
+
+// hostMember is where a member was found on a receiver type.
+type hostMember struct {
+	typ reflect.Type
+	// method is the index of the member in the method set of typ, or
+	// -1 for a field.
+	method int
+	// derefs is the number of pointer indirections from typ to the
+	// struct whose field is at index field.
+	derefs int
+	field  []int
+}
+
+// newHostSite returns the site of a form naming the member sym. goTry
+// and location are as for applyGoTry.
+func newHostSite(sym *value.Symbol, goTry, warn bool, location func() string) *hostSite {
+	return &hostSite{
+		sym:      sym,
+		name:     exportedName(sym.Name()),
+		goTry:    goTry,
+		warn:     warn,
+		location: location,
+	}
+}
+
+func exportedName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	if !unicode.IsLower(r) {
+		return name
+	}
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// call calls the method of tgt named by the site with args.
+func (s *hostSite) call(tgt interface{}, args []interface{}) (interface{}, error) {
+	methodVal, ok := s.fieldOrMethod(tgt)
+	if !ok {
+		return nil, fmt.Errorf("no such field or method on %v (%T): %s", tgt, tgt, s.sym)
+	}
+	// if the field is not a function, return an error
+	if reflect.TypeOf(methodVal).Kind() != reflect.Func {
+		return nil, errors.New("not a method: " + value.ToString(tgt) + "." + s.sym.Name())
+	}
+
+	if s.goTry {
+		return applyGoTry(s.location(), methodVal, args)
+	}
+	return value.Apply(methodVal, args), nil
+}
+
+// interop returns the field of tgt named by the site, or the result of
+// calling its method of that name.
+func (s *hostSite) interop(tgt interface{}) (interface{}, error) {
+	mOrFVal, ok := s.fieldOrMethod(tgt)
+	if !ok {
+		return nil, fmt.Errorf("no such field or method on %T: %s", tgt, s.sym)
+	}
+	if mOrFVal == nil {
+		// Avoid panic in kind check below and just return if nil. It
+		// can't have been a method.
+		return mOrFVal, nil
+	}
+	switch reflect.TypeOf(mOrFVal).Kind() {
+	case reflect.Func:
+		if s.goTry {
+			return applyGoTry(s.location(), mOrFVal, nil)
+		}
+		return value.Apply(mOrFVal, nil), nil
+	default:
+		return mOrFVal, nil
+	}
+}
+
+// fieldOrMethod returns the field or method of tgt named by the site,
+// as lang.FieldOrMethod does.
+func (s *hostSite) fieldOrMethod(tgt interface{}) (interface{}, bool) {
+	typ := reflect.TypeOf(tgt)
+	if typ == nil {
+		return value.FieldOrMethod(tgt, s.name)
+	}
+	if members := s.members.Load(); members != nil {
+		for i := range *members {
+			if m := &(*members)[i]; m.typ == typ {
+				return m.get(reflect.ValueOf(tgt))
+			}
+		}
+	}
+	if s.megamorphic.Load() {
+		return value.FieldOrMethod(tgt, s.name)
+	}
+
+	m, ok := findHostMember(typ, s.name)
+	if !ok {
+		return value.FieldOrMethod(tgt, s.name)
+	}
+	s.add(m)
+	return m.get(reflect.ValueOf(tgt))
+}
+
+func (s *hostSite) add(m hostMember) {
+	for {
+		old := s.members.Load()
+		var members []hostMember
+		if old != nil {
+			members = *old
+		}
+		for _, cached := range members {
+			if cached.typ == m.typ {
+				return
+			}
+		}
+		if len(members) >= maxHostSiteTypes {
+			if s.megamorphic.CompareAndSwap(false, true) && s.warn {
+				s.warnMegamorphic()
+			}
+			return
+		}
+		next := append(members[:len(members):len(members)], m)
+		if s.members.CompareAndSwap(old, &next) {
+			return
+		}
+	}
+}
+
+func (s *hostSite) warnMegamorphic() {
+	w, ok := value.VarErr.Deref().(io.Writer)
+	if !ok {
+		return
+	}
+	fmt.Fprintf(w, "Reflection warning, %s - reference to %s is megamorphic (more than %d receiver types)\n",
+		s.location(), s.name, maxHostSiteTypes)
+}
+
+// findHostMember finds the member name of values of type typ as
+// lang.FieldOrMethod does: a method of typ, or else a field of the
+// struct typ is or points to.
+func findHostMember(typ reflect.Type, name string) (hostMember, bool) {
+	if method, ok := typ.MethodByName(name); ok {
+		return hostMember{typ: typ, method: method.Index}, true
+	}
+
+	m := hostMember{typ: typ, method: -1}
+	structType := typ
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+		m.derefs++
+	}
+	if structType.Kind() != reflect.Struct {
+		return hostMember{}, false
+	}
+	field, ok := structType.FieldByName(name)
+	if !ok {
+		return hostMember{}, false
+	}
+	m.field = field.Index
+	return m, true
+}
+
+func (m *hostMember) get(v reflect.Value) (interface{}, bool) {
+	if m.method >= 0 {
+		return v.Method(m.method).Interface(), true
+	}
+	for i := 0; i < m.derefs; i++ {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	return v.FieldByIndex(m.field).Interface(), true
+}
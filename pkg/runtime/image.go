@@ -0,0 +1,444 @@
+package runtime
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/reader"
+	"github.com/glojurelang/glojure/pkg/stdlib"
+)
+
+const (
+	imageMagic = "GLJIMG"
+	// imageVersion must be incremented whenever the encoding of
+	// images or the AST changes. Images of other versions are
+	// rejected.
+	imageVersion = 2
+)
+
+// Image holds the analyzed top-level forms of loaded source files.
+// Loading a file held by an image evaluates its forms without
+// reading, macroexpanding or analyzing them again.
+//
+// Images are built by recording the loads of an environment created
+// with WithImageRecorder, and are consulted by loads once added with
+// AddImage. An image of the core library is embedded in the stdlib
+// package and used by default; see CoreImage.
+type Image struct {
+	mu    sync.Mutex
+	files map[string]*imageFile
+
+	// counters of the recording environment, restored so that
+	// symbols generated after a restore don't collide with those in
+	// the image.
+	symCounter int32
+	rtID       int32
+
+	validOnce sync.Once
+	valid     bool
+}
+
+type imageFile struct {
+	name  string
+	hash  [sha256.Size]byte
+	units [][]byte
+}
+
+var (
+	images     []*Image
+	imagesLock sync.Mutex
+
+	coreImage     *Image
+	coreImageOnce sync.Once
+)
+
+// NewImage returns an empty image, to be filled with
+// WithImageRecorder.
+func NewImage() *Image {
+	return &Image{
+		files: map[string]*imageFile{},
+	}
+}
+
+// ReadImage reads an image written with Image.WriteTo.
+func ReadImage(r io.Reader) (*Image, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a glojure image: %w", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte(imageMagic)) {
+		return nil, errors.New("not a glojure image")
+	}
+	data = data[len(imageMagic):]
+
+	var readErr error
+	uvarint := func() uint64 {
+		x, n := binary.Uvarint(data)
+		if n <= 0 {
+			readErr = errors.New("corrupt image")
+			return 0
+		}
+		data = data[n:]
+		return x
+	}
+	bytesN := func(n uint64) []byte {
+		if readErr != nil {
+			return nil
+		}
+		if uint64(len(data)) < n {
+			readErr = errors.New("corrupt image")
+			return nil
+		}
+		b := data[:n:n]
+		data = data[n:]
+		return b
+	}
+
+	if version := uvarint(); readErr == nil && version != imageVersion {
+		return nil, fmt.Errorf("unsupported image version %d, expected %d", version, imageVersion)
+	}
+	img := NewImage()
+	img.symCounter = int32(uvarint())
+	img.rtID = int32(uvarint())
+	numFiles := uvarint()
+	for i := uint64(0); i < numFiles && readErr == nil; i++ {
+		f := &imageFile{
+			name: string(bytesN(uvarint())),
+		}
+		copy(f.hash[:], bytesN(sha256.Size))
+		numUnits := uvarint()
+		for j := uint64(0); j < numUnits && readErr == nil; j++ {
+			f.units = append(f.units, bytesN(uvarint()))
+		}
+		img.files[f.name] = f
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+	return img, nil
+}
+
+// WriteTo writes the image to w, compressed with gzip.
+func (img *Image) WriteTo(w io.Writer) (int64, error) {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	buf := []byte(imageMagic)
+	buf = binary.AppendUvarint(buf, imageVersion)
+	buf = binary.AppendUvarint(buf, uint64(img.symCounter))
+	buf = binary.AppendUvarint(buf, uint64(img.rtID))
+	buf = binary.AppendUvarint(buf, uint64(len(img.files)))
+	for _, name := range img.fileNames() {
+		f := img.files[name]
+		buf = binary.AppendUvarint(buf, uint64(len(f.name)))
+		buf = append(buf, f.name...)
+		buf = append(buf, f.hash[:]...)
+		buf = binary.AppendUvarint(buf, uint64(len(f.units)))
+		for _, unit := range f.units {
+			buf = binary.AppendUvarint(buf, uint64(len(unit)))
+			buf = append(buf, unit...)
+		}
+	}
+
+	cw := &countingWriter{w: w}
+	zw := gzip.NewWriter(cw)
+	if _, err := zw.Write(buf); err != nil {
+		return cw.n, err
+	}
+	err := zw.Close()
+	return cw.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// Files returns the names of the files held by the image, sorted.
+func (img *Image) Files() []string {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	return img.fileNames()
+}
+
+func (img *Image) fileNames() []string {
+	names := make([]string, 0, len(img.files))
+	for name := range img.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Remove removes the named files from the image.
+func (img *Image) Remove(names ...string) {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	for _, name := range names {
+		delete(img.files, name)
+	}
+}
+
+// Validate returns an error if the source of a file held by the image
+// is on the load path and differs from the source the image was built
+// from. An image is not used if it fails validation.
+func (img *Image) Validate() error {
+	var stale []string
+	for _, name := range img.Files() {
+		src, err := readLoadPath(name)
+		if err != nil {
+			// the image may be all there is of the file.
+			continue
+		}
+		if sha256.Sum256(src) != img.file(name).hash {
+			stale = append(stale, name)
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("image is out of date: %s changed", strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+func (img *Image) file(name string) *imageFile {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	return img.files[name]
+}
+
+func (img *Image) isValid() bool {
+	img.validOnce.Do(func() {
+		img.valid = img.Validate() == nil
+	})
+	return img.valid
+}
+
+func (img *Image) record(env *environment, name string, src []byte, units [][]byte) {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	img.files[name] = &imageFile{
+		name:  name,
+		hash:  sha256.Sum256(src),
+		units: units,
+	}
+	img.symCounter = atomic.LoadInt32(&env.symCounter)
+	img.rtID = RT.id.Load()
+}
+
+// AddImage makes the files held by img available to loads. Images are
+// consulted in the order they are added, after the core image.
+func AddImage(img *Image) {
+	imagesLock.Lock()
+	defer imagesLock.Unlock()
+
+	images = append(images, img)
+}
+
+// CoreImage returns the image of the core library embedded in the
+// stdlib package, or nil if there is none or it can't be read. It is
+// regenerated with go generate.
+func CoreImage() *Image {
+	coreImageOnce.Do(func() {
+		if len(stdlib.CoreImage) == 0 {
+			return
+		}
+		img, err := ReadImage(bytes.NewReader(stdlib.CoreImage))
+		if err != nil {
+			return
+		}
+		coreImage = img
+	})
+	return coreImage
+}
+
+// findImageFile returns the first valid image holding the named file.
+func findImageFile(name string) (*Image, *imageFile) {
+	imagesLock.Lock()
+	imgs := append([]*Image{CoreImage()}, images...)
+	imagesLock.Unlock()
+
+	for _, img := range imgs {
+		if img == nil {
+			continue
+		}
+		if f := img.file(name); f != nil && img.isValid() {
+			return img, f
+		}
+	}
+	return nil, nil
+}
+
+// loadFile loads the named file from the load path, or restores it
+// from a compiled file or an image holding it. The files loaded from
+// source, or from a compiled file of their source, are recorded for
+// RefreshPlan.
+func (env *environment) loadFile(name string) error {
+	value.PushThreadBindings(value.NewMap(value.VarFile, name))
+	defer value.PopThreadBindings()
+
+	if env.imageRecorder == nil && !env.noImage {
+		if f := findCompiledFile(name); f != nil {
+			if err := env.loadCompiledFile(f); err != nil {
+				return err
+			}
+			if src, err := readLoadPath(name); err == nil {
+				recordLoadedFile(name, src)
+			}
+			return nil
+		}
+		if img, f := findImageFile(name); f != nil {
+			return env.restoreFile(img, f)
+		}
+	}
+
+	src, err := readLoadPath(name)
+	if err != nil {
+		return err
+	}
+	if err := env.evalFile(name, src); err != nil {
+		return err
+	}
+	recordLoadedFile(name, src)
+	return nil
+}
+
+// evalFile evaluates the forms of a source file, recording them if
+// the environment has an image recorder.
+func (env *environment) evalFile(name string, src []byte) error {
+	r := reader.New(bytes.NewReader(src), reader.WithFilename(name), reader.WithGetCurrentNS(func() *value.Namespace {
+		return env.CurrentNamespace()
+	}))
+
+	var units [][]byte
+	for {
+		form, err := r.ReadOne()
+		if err == reader.ErrEOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading %v: %w", name, err)
+		}
+		var usedBefore []*value.Var
+		if env.imageRecorder != nil {
+			usedBefore = env.CurrentNamespace().UsedVars()
+		}
+		n, err := env.analyze(form)
+		if err != nil {
+			return fmt.Errorf("error evaluating %v: %w", name, err)
+		}
+		if env.imageRecorder != nil {
+			used := newlyUsedVars(usedBefore, env.CurrentNamespace().UsedVars())
+			unit, err := encodeUnit(form, n, used)
+			if err != nil {
+				return fmt.Errorf("error recording %v: %w", name, err)
+			}
+			units = append(units, unit)
+		}
+		if _, err := env.EvalAST(n); err != nil {
+			return fmt.Errorf("error evaluating %v: %w", name, err)
+		}
+	}
+	if env.imageRecorder != nil {
+		env.imageRecorder.record(env, name, src, units)
+	}
+	return nil
+}
+
+// newlyUsedVars returns the vars of after that are not in before.
+func newlyUsedVars(before, after []*value.Var) []*value.Var {
+	seen := make(map[*value.Var]bool, len(before))
+	for _, v := range before {
+		seen[v] = true
+	}
+	var used []*value.Var
+	for _, v := range after {
+		if !seen[v] {
+			used = append(used, v)
+		}
+	}
+	return used
+}
+
+func (env *environment) restoreFile(img *Image, f *imageFile) error {
+	env.restoreCounters(img.symCounter, img.rtID)
+
+	for _, unit := range f.units {
+		if err := env.evalUnit(f.name, unit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreCounters raises the counters of the environment to those of
+// the environment that recorded an image, so that symbols generated
+// after a restore don't collide with those in the image.
+func (env *environment) restoreCounters(symCounter, rtID int32) {
+	for {
+		cur := atomic.LoadInt32(&env.symCounter)
+		if cur >= symCounter || atomic.CompareAndSwapInt32(&env.symCounter, cur, symCounter) {
+			break
+		}
+	}
+	for {
+		cur := RT.id.Load()
+		if cur >= rtID || RT.id.CompareAndSwap(cur, rtID) {
+			break
+		}
+	}
+}
+
+// evalUnit evaluates an image unit of the named file.
+func (env *environment) evalUnit(name string, unit []byte) error {
+	n, used, form, err := decodeUnit(unit)
+	if err != nil {
+		return fmt.Errorf("error restoring %v: %w", name, err)
+	}
+	env.addUsedVars(used)
+	if n == nil {
+		n, err = env.analyze(form)
+		if err != nil {
+			return fmt.Errorf("error evaluating %v: %w", name, err)
+		}
+	}
+	if _, err := env.EvalAST(n); err != nil {
+		return fmt.Errorf("error evaluating %v: %w", name, err)
+	}
+	return nil
+}
+
+// addUsedVars records vars as used by the current namespace, as
+// analysis of the forms that use them would.
+func (env *environment) addUsedVars(used []*value.Var) {
+	if len(used) == 0 {
+		return
+	}
+	ns := env.CurrentNamespace()
+	for _, v := range used {
+		ns.AddUsedVar(v)
+	}
+}
@@ -0,0 +1,91 @@
+package runtime_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/glojurelang/glojure/pkg/gen/gljimports"
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+func pushBindings() {
+	kvs := make([]interface{}, 0, 8)
+	for _, vr := range []*value.Var{value.VarCurrentNS, value.VarWarnOnReflection, value.VarUncheckedMath, value.VarDataReaders} {
+		kvs = append(kvs, vr, vr.Deref())
+	}
+	value.PushThreadBindings(value.NewMap(kvs...))
+}
+
+func TestCoreImageUpToDate(t *testing.T) {
+	img := runtime.CoreImage()
+	if img == nil {
+		t.Fatal("no core image; run go generate ./pkg/stdlib")
+	}
+	if err := img.Validate(); err != nil {
+		t.Fatalf("%v; run go generate ./pkg/stdlib", err)
+	}
+}
+
+func TestImageRoundTrip(t *testing.T) {
+	runtime.AddLoadPath(fstest.MapFS{
+		"image/test/roundtrip.glj": &fstest.MapFile{Data: []byte(`
+(ns image.test.roundtrip)
+(defmacro twice [x] ` + "`" + `(do ~x ~x))
+(defn f [x] (let [n (atom 0)] (twice (swap! n + x)) @n))
+(def result (f 21))
+`)},
+	})
+
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	img := runtime.NewImage()
+	env := runtime.NewEnvironment(runtime.WithImageRecorder(img))
+	runtime.ReadEval(`(require 'image.test.roundtrip)`, runtime.WithEnv(env))
+	if res := runtime.ReadEval(`image.test.roundtrip/result`, runtime.WithEnv(env)); res != int64(42) {
+		t.Fatalf("expected 42, got %v", res)
+	}
+
+	var buf bytes.Buffer
+	if _, err := img.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := runtime.ReadImage(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.Files(), img.Files()) {
+		t.Errorf("expected files %v, got %v", img.Files(), read.Files())
+	}
+	if err := read.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	read.Remove("glojure/core.glj")
+	for _, name := range read.Files() {
+		if name == "glojure/core.glj" {
+			t.Errorf("expected glojure/core.glj to be removed")
+		}
+	}
+}
+
+func benchmarkNewEnvironment(b *testing.B, opts ...runtime.EvalOption) {
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	for i := 0; i < b.N; i++ {
+		runtime.NewEnvironment(opts...)
+	}
+}
+
+func BenchmarkNewEnvironment(b *testing.B) {
+	b.Run("image", func(b *testing.B) {
+		benchmarkNewEnvironment(b)
+	})
+	b.Run("source", func(b *testing.B) {
+		benchmarkNewEnvironment(b, runtime.WithoutImage())
+	})
+}
@@ -0,0 +1,1025 @@
+package runtime
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+
+	"github.com/glojurelang/glojure/pkg/ast"
+	value "github.com/glojurelang/glojure/pkg/lang"
+)
+
+// This file implements the encoding of analyzed top-level forms in
+// images. Each unit (one top-level form) is encoded independently,
+// with its own string, value and node tables, so units can be decoded
+// one at a time in load order.
+//
+// Node.Env and Node.RawForms are not encoded; they are only used
+// during analysis.
+
+// Value tags.
+const (
+	tagNil byte = iota
+	tagTrue
+	tagFalse
+	tagInt
+	tagInt64
+	tagInt32
+	tagInt16
+	tagInt8
+	tagUint
+	tagUint64
+	tagUint32
+	tagUint16
+	tagUint8
+	tagFloat64
+	tagFloat32
+	tagChar
+	tagString
+	tagKeyword
+	tagSymbol
+	tagBigInt
+	tagBigDecimal
+	tagRatio
+	tagRegexp
+	tagList
+	tagVector
+	tagMap
+	tagHashMap
+	tagSet
+	tagMapEntry
+	tagVar
+	tagNamespace
+	tagRef
+)
+
+// Node sub-struct tags.
+const (
+	subNone byte = iota
+	subLocal
+	subVar
+	subConst
+	subGoBuiltin
+	subGo
+	subMaybeHostForm
+	subMaybeClass
+	subVector
+	subMap
+	subSet
+	subDo
+	subLet
+	subBinding
+	subInvoke
+	subIf
+	subNew
+	subQuote
+	subSetBang
+	subTry
+	subCatch
+	subThrow
+	subDef
+	subHostCall
+	subHostField
+	subHostInterop
+	subLetFn
+	subRecur
+	subFn
+	subFnMethod
+	subWithMeta
+	subCase
+	subCaseNode
+	subTheVar
+)
+
+// Unit kinds.
+const (
+	// unitNode is an analyzed top-level form.
+	unitNode byte = iota
+	// unitForm is a top-level form as read, for forms whose analyzed
+	// representation can't be encoded. It is analyzed when restored.
+	unitForm
+)
+
+// errUnsupportedValue is returned when a value can't be encoded.
+var errUnsupportedValue = errors.New("unsupported value")
+
+type imageEncoder struct {
+	buf   []byte
+	strs  map[string]int
+	vals  map[interface{}]int
+	nvals int
+	nodes map[*ast.Node]int
+}
+
+func newImageEncoder() *imageEncoder {
+	return &imageEncoder{
+		strs:  map[string]int{},
+		vals:  map[interface{}]int{},
+		nodes: map[*ast.Node]int{},
+	}
+}
+
+// encodeUnit encodes an analyzed top-level form, along with the vars
+// its analysis first recorded as used by the current namespace. If
+// the node refers to a value that can't be encoded, the form as read
+// is encoded instead.
+func encodeUnit(form interface{}, n *ast.Node, used []*value.Var) ([]byte, error) {
+	e := newImageEncoder()
+	err := e.try(func() {
+		e.byte(unitNode)
+		e.uint(uint64(len(used)))
+		for _, v := range used {
+			e.value(v)
+		}
+		e.node(n)
+	})
+	if err == nil {
+		return e.buf, nil
+	}
+	if !errors.Is(err, errUnsupportedValue) {
+		return nil, err
+	}
+
+	e = newImageEncoder()
+	if err := e.try(func() {
+		e.byte(unitForm)
+		e.value(form)
+	}); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+func (e *imageEncoder) try(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			rErr, ok := r.(error)
+			if !ok {
+				panic(r)
+			}
+			err = rErr
+		}
+	}()
+	f()
+	return nil
+}
+
+func (e *imageEncoder) byte(b byte) {
+	e.buf = append(e.buf, b)
+}
+
+func (e *imageEncoder) bool(b bool) {
+	if b {
+		e.byte(1)
+	} else {
+		e.byte(0)
+	}
+}
+
+func (e *imageEncoder) uint(x uint64) {
+	e.buf = binary.AppendUvarint(e.buf, x)
+}
+
+func (e *imageEncoder) int(x int64) {
+	e.buf = binary.AppendVarint(e.buf, x)
+}
+
+func (e *imageEncoder) string(s string) {
+	if idx, ok := e.strs[s]; ok {
+		e.uint(uint64(idx + 1))
+		return
+	}
+	e.strs[s] = len(e.strs)
+	e.uint(0)
+	e.uint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *imageEncoder) bytes(b []byte) {
+	e.uint(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *imageEncoder) symbol(sym *value.Symbol) {
+	if sym == nil {
+		e.value(nil)
+		return
+	}
+	e.value(sym)
+}
+
+func (e *imageEncoder) keyword(kw value.Keyword) {
+	e.string(kw.String()[1:])
+}
+
+func (e *imageEncoder) value(v interface{}) {
+	isPtr := v != nil && reflect.TypeOf(v).Kind() == reflect.Pointer
+	if isPtr {
+		if idx, ok := e.vals[v]; ok {
+			e.byte(tagRef)
+			e.uint(uint64(idx))
+			return
+		}
+	}
+
+	if e.primitive(v) {
+		return
+	}
+
+	switch v := v.(type) {
+	case *value.Symbol:
+		e.byte(tagSymbol)
+		e.string(v.Namespace())
+		e.string(v.Name())
+		e.meta(v.Meta())
+	case *value.BigInt:
+		e.byte(tagBigInt)
+		e.string(v.String())
+	case *value.BigDecimal:
+		b, err := v.ToBigFloat().GobEncode()
+		if err != nil {
+			panic(err)
+		}
+		e.byte(tagBigDecimal)
+		e.bytes(b)
+	case *value.Ratio:
+		e.byte(tagRatio)
+		e.string(v.Numerator().String())
+		e.string(v.Denominator().String())
+	case *regexp.Regexp:
+		e.byte(tagRegexp)
+		e.string(v.String())
+	case *value.Var:
+		e.byte(tagVar)
+		e.string(v.Namespace().Name().String())
+		e.string(v.Symbol().Name())
+	case *value.Namespace:
+		e.byte(tagNamespace)
+		e.string(v.Name().String())
+	case *value.MapEntry:
+		e.byte(tagMapEntry)
+		e.value(v.Key())
+		e.value(v.Val())
+	case value.IPersistentVector:
+		e.byte(tagVector)
+		e.meta(metaOf(v))
+		e.uint(uint64(v.Count()))
+		for i := 0; i < v.Count(); i++ {
+			e.value(v.Nth(i))
+		}
+	case value.IPersistentMap:
+		if _, ok := v.(*value.PersistentHashMap); ok {
+			e.byte(tagHashMap)
+		} else if _, ok := v.(*value.Map); ok {
+			e.byte(tagMap)
+		} else {
+			panic(fmt.Errorf("%w: %T", errUnsupportedValue, v))
+		}
+		e.meta(metaOf(v))
+		e.uint(uint64(v.Count()))
+		for s := value.Seq(v); s != nil; s = s.Next() {
+			entry := s.First().(value.IMapEntry)
+			e.value(entry.Key())
+			e.value(entry.Val())
+		}
+	case value.IPersistentSet:
+		e.byte(tagSet)
+		e.meta(metaOf(v))
+		e.uint(uint64(v.Count()))
+		for s := value.Seq(v); s != nil; s = s.Next() {
+			e.value(s.First())
+		}
+	case value.ISeq:
+		// All seqs, including lazy ones returned by macros, are
+		// restored as lists.
+		items := seqToSlice(value.Seq(v))
+		e.byte(tagList)
+		e.meta(metaOf(v))
+		e.uint(uint64(len(items)))
+		for _, item := range items {
+			e.value(item)
+		}
+	default:
+		panic(fmt.Errorf("%w: %T", errUnsupportedValue, v))
+	}
+
+	// Every value encoded past this point is recorded in the value
+	// table, but only pointers can be referenced again.
+	if isPtr {
+		e.vals[v] = e.nvals
+	}
+	e.nvals++
+}
+
+// primitive encodes v if it is of a type that is not recorded in the
+// value table, and reports whether it did.
+func (e *imageEncoder) primitive(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		e.byte(tagNil)
+	case bool:
+		if v {
+			e.byte(tagTrue)
+		} else {
+			e.byte(tagFalse)
+		}
+	case int:
+		e.byte(tagInt)
+		e.int(int64(v))
+	case int64:
+		e.byte(tagInt64)
+		e.int(v)
+	case int32:
+		e.byte(tagInt32)
+		e.int(int64(v))
+	case int16:
+		e.byte(tagInt16)
+		e.int(int64(v))
+	case int8:
+		e.byte(tagInt8)
+		e.int(int64(v))
+	case uint:
+		e.byte(tagUint)
+		e.uint(uint64(v))
+	case uint64:
+		e.byte(tagUint64)
+		e.uint(v)
+	case uint32:
+		e.byte(tagUint32)
+		e.uint(uint64(v))
+	case uint16:
+		e.byte(tagUint16)
+		e.uint(uint64(v))
+	case uint8:
+		e.byte(tagUint8)
+		e.uint(uint64(v))
+	case float64:
+		e.byte(tagFloat64)
+		e.uint(math.Float64bits(v))
+	case float32:
+		e.byte(tagFloat32)
+		e.uint(uint64(math.Float32bits(v)))
+	case value.Char:
+		e.byte(tagChar)
+		e.int(int64(v))
+	case string:
+		e.byte(tagString)
+		e.string(v)
+	case value.Keyword:
+		e.byte(tagKeyword)
+		e.keyword(v)
+	default:
+		return false
+	}
+	return true
+}
+
+func (e *imageEncoder) meta(m value.IPersistentMap) {
+	if m == nil {
+		e.value(nil)
+		return
+	}
+	e.value(m)
+}
+
+func metaOf(v interface{}) value.IPersistentMap {
+	if m, ok := v.(value.IMeta); ok {
+		return m.Meta()
+	}
+	return nil
+}
+
+func (e *imageEncoder) nodeList(ns []*ast.Node) {
+	e.uint(uint64(len(ns)))
+	for _, n := range ns {
+		e.node(n)
+	}
+}
+
+func (e *imageEncoder) node(n *ast.Node) {
+	if n == nil {
+		e.uint(0)
+		return
+	}
+	if idx, ok := e.nodes[n]; ok {
+		e.uint(uint64(idx + 2))
+		return
+	}
+	e.nodes[n] = len(e.nodes)
+	e.uint(1)
+
+	e.uint(uint64(n.Op))
+	e.value(n.Form)
+	e.bool(n.IsLiteral)
+	e.bool(n.IsAssignable)
+
+	switch sub := n.Sub.(type) {
+	case nil:
+		e.byte(subNone)
+	case *ast.LocalNode:
+		e.byte(subLocal)
+		e.symbol(sub.Name)
+		e.keyword(sub.Local)
+		e.int(int64(sub.ArgID))
+		e.bool(sub.IsVariadic)
+	case *ast.VarNode:
+		e.byte(subVar)
+		e.value(sub.Var)
+	case *ast.ConstNode:
+		e.byte(subConst)
+		e.keyword(sub.Type)
+		e.value(sub.Value)
+		e.node(sub.Meta)
+	case *ast.GoBuiltinNode:
+		// The value is looked up again when decoding.
+		e.byte(subGoBuiltin)
+		e.symbol(sub.Sym)
+	case *ast.GoNode:
+		e.byte(subGo)
+		e.node(sub.Invoke)
+	case *ast.MaybeHostFormNode:
+		e.byte(subMaybeHostForm)
+		e.string(sub.Class)
+		e.symbol(sub.Field)
+	case *ast.MaybeClassNode:
+		e.byte(subMaybeClass)
+		e.value(sub.Class)
+	case *ast.VectorNode:
+		e.byte(subVector)
+		e.nodeList(sub.Items)
+	case *ast.MapNode:
+		e.byte(subMap)
+		e.nodeList(sub.Keys)
+		e.nodeList(sub.Vals)
+	case *ast.SetNode:
+		e.byte(subSet)
+		e.nodeList(sub.Items)
+	case *ast.DoNode:
+		e.byte(subDo)
+		e.nodeList(sub.Statements)
+		e.node(sub.Ret)
+		e.bool(sub.IsBody)
+	case *ast.LetNode:
+		e.byte(subLet)
+		e.node(sub.Body)
+		e.nodeList(sub.Bindings)
+		e.symbol(sub.LoopID)
+	case *ast.BindingNode:
+		e.byte(subBinding)
+		e.symbol(sub.Name)
+		e.node(sub.Init)
+		e.keyword(sub.Local)
+		e.int(int64(sub.ArgID))
+		e.bool(sub.IsVariadic)
+	case *ast.InvokeNode:
+		e.byte(subInvoke)
+		e.meta(sub.Meta)
+		e.node(sub.Fn)
+		e.nodeList(sub.Args)
+		e.bool(sub.GoTry)
+	case *ast.IfNode:
+		e.byte(subIf)
+		e.node(sub.Test)
+		e.node(sub.Then)
+		e.node(sub.Else)
+	case *ast.NewNode:
+		e.byte(subNew)
+		e.node(sub.Class)
+		e.nodeList(sub.Args)
+	case *ast.QuoteNode:
+		e.byte(subQuote)
+		e.node(sub.Expr)
+	case *ast.SetBangNode:
+		e.byte(subSetBang)
+		e.node(sub.Target)
+		e.node(sub.Val)
+	case *ast.TryNode:
+		e.byte(subTry)
+		e.node(sub.Body)
+		e.nodeList(sub.Catches)
+		e.node(sub.Finally)
+	case *ast.CatchNode:
+		e.byte(subCatch)
+		e.node(sub.Class)
+		e.node(sub.Local)
+		e.node(sub.Body)
+	case *ast.ThrowNode:
+		e.byte(subThrow)
+		e.node(sub.Exception)
+	case *ast.DefNode:
+		e.byte(subDef)
+		e.symbol(sub.Name)
+		e.value(sub.Var)
+		e.node(sub.Meta)
+		e.node(sub.Init)
+		e.value(sub.Doc)
+	case *ast.HostCallNode:
+		e.byte(subHostCall)
+		e.node(sub.Target)
+		e.symbol(sub.Method)
+		e.nodeList(sub.Args)
+		e.bool(sub.GoTry)
+	case *ast.HostFieldNode:
+		e.byte(subHostField)
+		e.node(sub.Target)
+		e.symbol(sub.Field)
+	case *ast.HostInteropNode:
+		e.byte(subHostInterop)
+		e.node(sub.Target)
+		e.symbol(sub.MOrF)
+		e.bool(sub.GoTry)
+	case *ast.LetFnNode:
+		e.byte(subLetFn)
+		e.nodeList(sub.Bindings)
+		e.node(sub.Body)
+	case *ast.RecurNode:
+		e.byte(subRecur)
+		e.nodeList(sub.Exprs)
+		e.symbol(sub.LoopID)
+	case *ast.FnNode:
+		e.byte(subFn)
+		e.bool(sub.IsVariadic)
+		e.int(int64(sub.MaxFixedArity))
+		e.nodeList(sub.Methods)
+		e.bool(sub.Once)
+		e.node(sub.Local)
+	case *ast.FnMethodNode:
+		e.byte(subFnMethod)
+		e.nodeList(sub.Params)
+		e.int(int64(sub.FixedArity))
+		e.node(sub.Body)
+		e.symbol(sub.LoopID)
+		e.bool(sub.IsVariadic)
+	case *ast.WithMetaNode:
+		e.byte(subWithMeta)
+		e.node(sub.Expr)
+		e.node(sub.Meta)
+	case *ast.CaseNode:
+		e.byte(subCase)
+		e.node(sub.Test)
+		e.nodeList(sub.Nodes)
+		e.node(sub.Default)
+	case *ast.CaseNodeNode:
+		e.byte(subCaseNode)
+		e.nodeList(sub.Tests)
+		e.node(sub.Then)
+	case *ast.TheVarNode:
+		e.byte(subTheVar)
+		e.value(sub.Var)
+	default:
+		panic(fmt.Errorf("unsupported node type %T", sub))
+	}
+}
+
+type imageDecoder struct {
+	buf   []byte
+	pos   int
+	strs  []string
+	vals  []interface{}
+	nodes []*ast.Node
+}
+
+// decodeUnit decodes a unit encoded with encodeUnit. It returns
+// either the analyzed node and the vars it uses or, for units holding
+// a form as read, the form.
+func decodeUnit(buf []byte) (n *ast.Node, used []*value.Var, form interface{}, err error) {
+	d := &imageDecoder{buf: buf}
+	defer func() {
+		if r := recover(); r != nil {
+			rErr, ok := r.(error)
+			if !ok {
+				rErr = fmt.Errorf("%v", r)
+			}
+			err = fmt.Errorf("corrupt image unit: %w", rErr)
+		}
+	}()
+
+	switch kind := d.byte(); kind {
+	case unitNode:
+		for _, v := range d.values(int(d.uint())) {
+			used = append(used, v.(*value.Var))
+		}
+		return d.node(), used, nil, nil
+	case unitForm:
+		return nil, nil, d.value(), nil
+	default:
+		return nil, nil, nil, fmt.Errorf("corrupt image unit: unknown kind %d", kind)
+	}
+}
+
+func (d *imageDecoder) byte() byte {
+	b := d.buf[d.pos]
+	d.pos++
+	return b
+}
+
+func (d *imageDecoder) bool() bool {
+	return d.byte() != 0
+}
+
+func (d *imageDecoder) uint() uint64 {
+	x, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		panic(errors.New("invalid uvarint"))
+	}
+	d.pos += n
+	return x
+}
+
+func (d *imageDecoder) int() int64 {
+	x, n := binary.Varint(d.buf[d.pos:])
+	if n <= 0 {
+		panic(errors.New("invalid varint"))
+	}
+	d.pos += n
+	return x
+}
+
+func (d *imageDecoder) bytes() []byte {
+	n := int(d.uint())
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *imageDecoder) string() string {
+	idx := d.uint()
+	if idx > 0 {
+		return d.strs[idx-1]
+	}
+	s := string(d.bytes())
+	d.strs = append(d.strs, s)
+	return s
+}
+
+func (d *imageDecoder) symbol() *value.Symbol {
+	sym, _ := d.value().(*value.Symbol)
+	return sym
+}
+
+func (d *imageDecoder) keyword() value.Keyword {
+	return value.NewKeyword(d.string())
+}
+
+func (d *imageDecoder) meta() value.IPersistentMap {
+	m, _ := d.value().(value.IPersistentMap)
+	return m
+}
+
+func (d *imageDecoder) value() interface{} {
+	var v interface{}
+	switch tag := d.byte(); tag {
+	case tagNil:
+		return nil
+	case tagTrue:
+		return true
+	case tagFalse:
+		return false
+	case tagInt:
+		return int(d.int())
+	case tagInt64:
+		return d.int()
+	case tagInt32:
+		return int32(d.int())
+	case tagInt16:
+		return int16(d.int())
+	case tagInt8:
+		return int8(d.int())
+	case tagUint:
+		return uint(d.uint())
+	case tagUint64:
+		return d.uint()
+	case tagUint32:
+		return uint32(d.uint())
+	case tagUint16:
+		return uint16(d.uint())
+	case tagUint8:
+		return uint8(d.uint())
+	case tagFloat64:
+		return math.Float64frombits(d.uint())
+	case tagFloat32:
+		return math.Float32frombits(uint32(d.uint()))
+	case tagChar:
+		return value.Char(d.int())
+	case tagString:
+		return d.string()
+	case tagKeyword:
+		return d.keyword()
+	case tagRef:
+		return d.vals[d.uint()]
+	case tagSymbol:
+		ns, name := d.string(), d.string()
+		sym := value.InternSymbol(ns, name)
+		if meta := d.meta(); meta != nil {
+			sym = sym.WithMeta(meta).(*value.Symbol)
+		}
+		v = sym
+	case tagBigInt:
+		bi, err := value.NewBigInt(d.string())
+		if err != nil {
+			panic(err)
+		}
+		v = bi
+	case tagBigDecimal:
+		bf := new(big.Float)
+		if err := bf.GobDecode(d.bytes()); err != nil {
+			panic(err)
+		}
+		v = value.NewBigDecimalFromBigFloat(bf)
+	case tagRatio:
+		num, _ := new(big.Int).SetString(d.string(), 10)
+		den, _ := new(big.Int).SetString(d.string(), 10)
+		v = value.NewRatioGoBigInt(num, den)
+	case tagRegexp:
+		v = regexp.MustCompile(d.string())
+	case tagVar:
+		ns := value.FindOrCreateNamespace(value.NewSymbol(d.string()))
+		v = ns.Intern(value.NewSymbol(d.string()))
+	case tagNamespace:
+		v = value.FindOrCreateNamespace(value.NewSymbol(d.string()))
+	case tagMapEntry:
+		key := d.value()
+		v = value.NewMapEntry(key, d.value())
+	case tagVector:
+		meta := d.meta()
+		items := d.values(int(d.uint()))
+		vec := value.NewVector(items...)
+		if meta != nil {
+			v = vec.WithMeta(meta)
+		} else {
+			v = vec
+		}
+	case tagMap, tagHashMap:
+		meta := d.meta()
+		kvs := d.values(2 * int(d.uint()))
+		var m value.IPersistentMap
+		if tag == tagHashMap {
+			m = value.NewPersistentHashMap(kvs...)
+		} else {
+			m = value.NewMap(kvs...)
+		}
+		if meta != nil {
+			m = m.(value.IObj).WithMeta(meta).(value.IPersistentMap)
+		}
+		v = m
+	case tagSet:
+		meta := d.meta()
+		set := value.NewSet(d.values(int(d.uint()))...)
+		if meta != nil {
+			v = set.WithMeta(meta)
+		} else {
+			v = set
+		}
+	case tagList:
+		meta := d.meta()
+		list := value.NewList(d.values(int(d.uint()))...)
+		if meta != nil {
+			v = list.(value.IObj).WithMeta(meta)
+		} else {
+			v = list
+		}
+	default:
+		panic(fmt.Errorf("unknown value tag %d", tag))
+	}
+	d.vals = append(d.vals, v)
+	return v
+}
+
+func (d *imageDecoder) values(n int) []interface{} {
+	vals := make([]interface{}, n)
+	for i := range vals {
+		vals[i] = d.value()
+	}
+	return vals
+}
+
+func (d *imageDecoder) nodeList() []*ast.Node {
+	n := int(d.uint())
+	if n == 0 {
+		return nil
+	}
+	ns := make([]*ast.Node, n)
+	for i := range ns {
+		ns[i] = d.node()
+	}
+	return ns
+}
+
+func (d *imageDecoder) node() *ast.Node {
+	switch idx := d.uint(); idx {
+	case 0:
+		return nil
+	case 1:
+	default:
+		return d.nodes[idx-2]
+	}
+
+	n := &ast.Node{}
+	d.nodes = append(d.nodes, n)
+
+	n.Op = ast.NodeOp(d.uint())
+	n.Form = d.value()
+	n.IsLiteral = d.bool()
+	n.IsAssignable = d.bool()
+
+	switch tag := d.byte(); tag {
+	case subNone:
+	case subLocal:
+		n.Sub = &ast.LocalNode{
+			Name:       d.symbol(),
+			Local:      d.keyword(),
+			ArgID:      int(d.int()),
+			IsVariadic: d.bool(),
+		}
+	case subVar:
+		vr := d.value().(*value.Var)
+		n.Sub = &ast.VarNode{
+			Var:  vr,
+			Meta: vr.Meta(),
+		}
+	case subConst:
+		n.Sub = &ast.ConstNode{
+			Type:  d.keyword(),
+			Value: d.value(),
+			Meta:  d.node(),
+		}
+	case subGoBuiltin:
+		sym := d.symbol()
+		v, ok := value.Builtins[sym.Name()]
+		if !ok {
+			panic(fmt.Errorf("unknown go builtin: go/%s", sym.Name()))
+		}
+		n.Sub = &ast.GoBuiltinNode{
+			Sym:   sym,
+			Value: v,
+		}
+	case subGo:
+		n.Sub = &ast.GoNode{
+			Invoke: d.node(),
+		}
+	case subMaybeHostForm:
+		n.Sub = &ast.MaybeHostFormNode{
+			Class: d.string(),
+			Field: d.symbol(),
+		}
+	case subMaybeClass:
+		n.Sub = &ast.MaybeClassNode{
+			Class: d.value(),
+		}
+	case subVector:
+		n.Sub = &ast.VectorNode{
+			Items: d.nodeList(),
+		}
+	case subMap:
+		n.Sub = &ast.MapNode{
+			Keys: d.nodeList(),
+			Vals: d.nodeList(),
+		}
+	case subSet:
+		n.Sub = &ast.SetNode{
+			Items: d.nodeList(),
+		}
+	case subDo:
+		n.Sub = &ast.DoNode{
+			Statements: d.nodeList(),
+			Ret:        d.node(),
+			IsBody:     d.bool(),
+		}
+	case subLet:
+		n.Sub = &ast.LetNode{
+			Body:     d.node(),
+			Bindings: d.nodeList(),
+			LoopID:   d.symbol(),
+		}
+	case subBinding:
+		n.Sub = &ast.BindingNode{
+			Name:       d.symbol(),
+			Init:       d.node(),
+			Local:      d.keyword(),
+			ArgID:      int(d.int()),
+			IsVariadic: d.bool(),
+		}
+	case subInvoke:
+		n.Sub = &ast.InvokeNode{
+			Meta:  d.meta(),
+			Fn:    d.node(),
+			Args:  d.nodeList(),
+			GoTry: d.bool(),
+		}
+	case subIf:
+		n.Sub = &ast.IfNode{
+			Test: d.node(),
+			Then: d.node(),
+			Else: d.node(),
+		}
+	case subNew:
+		n.Sub = &ast.NewNode{
+			Class: d.node(),
+			Args:  d.nodeList(),
+		}
+	case subQuote:
+		n.Sub = &ast.QuoteNode{
+			Expr: d.node(),
+		}
+	case subSetBang:
+		n.Sub = &ast.SetBangNode{
+			Target: d.node(),
+			Val:    d.node(),
+		}
+	case subTry:
+		n.Sub = &ast.TryNode{
+			Body:    d.node(),
+			Catches: d.nodeList(),
+			Finally: d.node(),
+		}
+	case subCatch:
+		n.Sub = &ast.CatchNode{
+			Class: d.node(),
+			Local: d.node(),
+			Body:  d.node(),
+		}
+	case subThrow:
+		n.Sub = &ast.ThrowNode{
+			Exception: d.node(),
+		}
+	case subDef:
+		n.Sub = &ast.DefNode{
+			Name: d.symbol(),
+			Var:  d.value().(*value.Var),
+			Meta: d.node(),
+			Init: d.node(),
+			Doc:  d.value(),
+		}
+	case subHostCall:
+		n.Sub = &ast.HostCallNode{
+			Target: d.node(),
+			Method: d.symbol(),
+			Args:   d.nodeList(),
+			GoTry:  d.bool(),
+		}
+	case subHostField:
+		n.Sub = &ast.HostFieldNode{
+			Target: d.node(),
+			Field:  d.symbol(),
+		}
+	case subHostInterop:
+		n.Sub = &ast.HostInteropNode{
+			Target: d.node(),
+			MOrF:   d.symbol(),
+			GoTry:  d.bool(),
+		}
+	case subLetFn:
+		n.Sub = &ast.LetFnNode{
+			Bindings: d.nodeList(),
+			Body:     d.node(),
+		}
+	case subRecur:
+		n.Sub = &ast.RecurNode{
+			Exprs:  d.nodeList(),
+			LoopID: d.symbol(),
+		}
+	case subFn:
+		n.Sub = &ast.FnNode{
+			IsVariadic:    d.bool(),
+			MaxFixedArity: int(d.int()),
+			Methods:       d.nodeList(),
+			Once:          d.bool(),
+			Local:         d.node(),
+		}
+	case subFnMethod:
+		n.Sub = &ast.FnMethodNode{
+			Params:     d.nodeList(),
+			FixedArity: int(d.int()),
+			Body:       d.node(),
+			LoopID:     d.symbol(),
+			IsVariadic: d.bool(),
+		}
+	case subWithMeta:
+		n.Sub = &ast.WithMetaNode{
+			Expr: d.node(),
+			Meta: d.node(),
+		}
+	case subCase:
+		n.Sub = &ast.CaseNode{
+			Test:    d.node(),
+			Nodes:   d.nodeList(),
+			Default: d.node(),
+		}
+	case subCaseNode:
+		n.Sub = &ast.CaseNodeNode{
+			Tests: d.nodeList(),
+			Then:  d.node(),
+		}
+	case subTheVar:
+		n.Sub = &ast.TheVarNode{
+			Var: d.value().(*value.Var),
+		}
+	default:
+		panic(fmt.Errorf("unknown node tag %d", tag))
+	}
+	return n
+}
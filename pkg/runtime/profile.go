@@ -0,0 +1,110 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	goruntime "runtime"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+
+	"github.com/glojurelang/glojure/internal/goid"
+	value "github.com/glojurelang/glojure/pkg/lang"
+)
+
+// The keys of the pprof labels of the goroutines evaluating Glojure
+// fns while profile labels are enabled. LabelFn is the name of the fn,
+// qualified by its namespace as ns/fn like those of stack traces, and
+// LabelNS that of its namespace. Samples of a CPU profile can be
+// grouped by them with
+//
+//	go tool pprof -tagroot=glojure.fn cpu.pprof
+const (
+	LabelFn = "glojure.fn"
+	LabelNS = "glojure.ns"
+)
+
+var profileLabels atomic.Bool
+
+// SetProfileLabels enables or disables the pprof labels naming the fn
+// being evaluated on the goroutines evaluating fns, which CPU and
+// goroutine profiles record. StartCPUProfile enables them; they can be
+// enabled for profiles taken otherwise, as with net/http/pprof. Fns
+// called while they are enabled keep their labels until they return.
+// The labels of fns are added to those of the context bound to
+// *context*, so programs that set labels with pprof.Do should evaluate
+// code with its context, as with glj.Eval.
+func SetProfileLabels(enabled bool) {
+	profileLabels.Store(enabled)
+}
+
+// StartCPUProfile starts writing a CPU profile to w, as
+// pprof.StartCPUProfile does, with profile labels enabled.
+func StartCPUProfile(w io.Writer) error {
+	if err := pprof.StartCPUProfile(w); err != nil {
+		return err
+	}
+	SetProfileLabels(true)
+	return nil
+}
+
+// StopCPUProfile stops the CPU profile started by StartCPUProfile, and
+// disables profile labels.
+func StopCPUProfile() {
+	SetProfileLabels(false)
+	pprof.StopCPUProfile()
+}
+
+// WriteHeapProfile writes a profile of the live heap to w after a
+// garbage collection, as pprof.WriteHeapProfile does. Heap profiles
+// don't record labels, so its stacks are those of the Go functions of
+// the runtime.
+func WriteHeapProfile(w io.Writer) error {
+	goruntime.GC()
+	return pprof.WriteHeapProfile(w)
+}
+
+// labelCtxs holds the label context of the innermost fn evaluated by
+// each goroutine with profile labels, by goroutine id, so that its
+// caller's labels can be restored once it returns.
+var labelCtxs sync.Map
+
+// profileFrameCode returns code that evaluates cd, the code of a fn
+// method body, with the labels of the fn added to those of its caller
+// on the goroutine while profile labels are enabled, restoring the
+// caller's after. The outermost fn of a goroutine adds them to the
+// labels of *context*, as set by pprof.Do, and restores those.
+func (c *codeCompiler) profileFrameCode(cd code) code {
+	// the fns generated by macros in top-level forms are named by their
+	// namespace, as their frames are.
+	name := c.frame.Namespace
+	if c.frame.FunctionName != "" {
+		name += "/" + c.frame.FunctionName
+	}
+	labels := pprof.Labels(LabelFn, name, LabelNS, c.frame.Namespace)
+	return func(f *frame) (interface{}, error) {
+		if !profileLabels.Load() {
+			return cd(f)
+		}
+		gid := goid.Get()
+		prev, nested := labelCtxs.Load(gid)
+		if !nested {
+			prev, _ = value.VarContext.Deref().(context.Context)
+			if prev == nil {
+				prev = context.Background()
+			}
+		}
+		ctx := pprof.WithLabels(prev.(context.Context), labels)
+		labelCtxs.Store(gid, ctx)
+		pprof.SetGoroutineLabels(ctx)
+		defer func() {
+			if nested {
+				labelCtxs.Store(gid, prev)
+			} else {
+				labelCtxs.Delete(gid)
+			}
+			pprof.SetGoroutineLabels(prev.(context.Context))
+		}()
+		return cd(f)
+	}
+}
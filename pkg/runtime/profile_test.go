@@ -0,0 +1,69 @@
+package runtime_test
+
+import (
+	"context"
+	"io"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+func TestProfileLabels(t *testing.T) {
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	// labels returns the goroutine profile, which shows the labels of
+	// the goroutines.
+	labels := func() string {
+		var sb strings.Builder
+		pprof.Lookup("goroutine").WriteTo(&sb, 1)
+		return sb.String()
+	}
+	value.FindOrCreateNamespace(value.NewSymbol("prof.test")).InternWithValue(value.NewSymbol("labels"), labels, true)
+
+	env := runtime.NewEnvironment()
+	runtime.ReadEval(`(ns prof.test)
+(defn inner [] (labels))
+(defn outer [] [(inner) (labels)])
+`, runtime.WithEnv(env), runtime.WithFilename("prof/test.glj"))
+
+	outer := func() (string, string) {
+		res := runtime.ReadEval("(prof.test/outer)", runtime.WithEnv(env)).(value.IPersistentVector)
+		return res.Nth(0).(string), res.Nth(1).(string)
+	}
+	if in, out := outer(); strings.Contains(in, "glojure.fn") || strings.Contains(out, "glojure.fn") {
+		t.Errorf("expected no labels while they are disabled, got\n%s", in)
+	}
+
+	if err := runtime.StartCPUProfile(io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if err := runtime.StartCPUProfile(io.Discard); err == nil {
+		t.Error("expected an error starting a second CPU profile")
+	}
+	// the labels set by the program, with the context bound to
+	// *context*, are kept and restored once outer returns.
+	var in, out, after string
+	pprof.Do(context.Background(), pprof.Labels("program", "test"), func(ctx context.Context) {
+		value.PushContext(ctx)
+		defer value.PopContext()
+		in, out = outer()
+		after = labels()
+	})
+	runtime.StopCPUProfile()
+	if !strings.Contains(in, `"glojure.fn":"prof.test/inner"`) || strings.Contains(in, "prof.test/outer") {
+		t.Errorf("expected the labels of inner, got\n%s", in)
+	}
+	if !strings.Contains(out, `"glojure.fn":"prof.test/outer"`) || !strings.Contains(out, `"glojure.ns":"prof.test"`) || !strings.Contains(out, `"program":"test"`) || strings.Contains(out, "prof.test/inner") {
+		t.Errorf("expected the labels of outer once inner returned, got\n%s", out)
+	}
+	if !strings.Contains(after, `"program":"test"`) || strings.Contains(after, "glojure.fn") {
+		t.Errorf("expected the labels of the program once outer returned, got\n%s", after)
+	}
+	if after := labels(); strings.Contains(after, "glojure.fn") {
+		t.Errorf("expected the labels to be removed once outer returned, got\n%s", after)
+	}
+}
@@ -0,0 +1,241 @@
+package runtime
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/reader"
+)
+
+// loadedFile is a source file that declares a namespace, as loaded
+// from the load path.
+type loadedFile struct {
+	ns *value.Symbol
+	// deps are the libs that the ns form of the file requires or uses.
+	deps []*value.Symbol
+	// hash is the hash of the source that was loaded, or planned to be
+	// loaded by RefreshPlan.
+	hash string
+	// pending is set once RefreshPlan unloads the namespace, until it
+	// is loaded again.
+	pending bool
+}
+
+var (
+	// loadedFiles holds the loaded files by name.
+	loadedFiles     = map[string]*loadedFile{}
+	loadedFilesLock sync.Mutex
+)
+
+// recordLoadedFile records that the file name, of source src, was
+// loaded, if it declares a namespace.
+func recordLoadedFile(name string, src []byte) {
+	ns, deps := nsDecl(name, src)
+
+	loadedFilesLock.Lock()
+	defer loadedFilesLock.Unlock()
+	if ns == nil {
+		delete(loadedFiles, name)
+		return
+	}
+	loadedFiles[name] = &loadedFile{ns: ns, deps: deps, hash: SourceHash(src)}
+}
+
+// ChangedNamespaces returns the names of the namespaces of the source
+// files loaded from the load path whose source changed or was removed
+// since they were loaded, or planned to be loaded again by RefreshPlan,
+// sorted.
+func ChangedNamespaces() []*value.Symbol {
+	loadedFilesLock.Lock()
+	defer loadedFilesLock.Unlock()
+
+	var changed []*value.Symbol
+	for name, f := range loadedFiles {
+		if src, err := readLoadPath(name); err != nil || SourceHash(src) != f.hash {
+			changed = append(changed, f.ns)
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Name() < changed[j].Name() })
+	return changed
+}
+
+// RefreshPlan returns the namespaces to unload and then load again to
+// bring those of the source files loaded from the load path up to date,
+// as the map {:unload [ns...] :load [ns...]}. The namespaces are those
+// whose source changed or was removed, see ChangedNamespaces, and those
+// that require them, directly or not, as declared by their ns forms.
+// They are unloaded in dependency order, dependents first, and loaded
+// in the reverse order, except those whose source was removed.
+//
+// The namespaces are pending until they are loaded again, so that
+// they are planned again if a refresh fails before loading them.
+func RefreshPlan() value.IPersistentMap {
+	loadedFilesLock.Lock()
+	defer loadedFilesLock.Unlock()
+
+	byNS := map[string]*loadedFile{}
+	removed := map[string]bool{}
+	affected := map[string]bool{}
+	for name, f := range loadedFiles {
+		src, err := readLoadPath(name)
+		switch {
+		case err != nil:
+			removed[f.ns.Name()] = true
+			delete(loadedFiles, name)
+		case SourceHash(src) != f.hash:
+			// the new ns form orders the namespace among those it
+			// now requires.
+			if ns, deps := nsDecl(name, src); ns != nil && ns.Equals(f.ns) {
+				f.deps = deps
+			}
+			f.hash = SourceHash(src)
+		case f.pending:
+		default:
+			byNS[f.ns.Name()] = f
+			continue
+		}
+		byNS[f.ns.Name()] = f
+		affected[f.ns.Name()] = true
+	}
+
+	// the namespaces that require an affected one are affected.
+	for changed := true; changed; {
+		changed = false
+		for ns, f := range byNS {
+			if affected[ns] {
+				continue
+			}
+			for _, dep := range f.deps {
+				if affected[dep.Name()] {
+					affected[ns] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	// affected namespaces are loaded after those they require.
+	var order []string
+	visited := map[string]bool{}
+	var visit func(ns string)
+	visit = func(ns string) {
+		if visited[ns] {
+			return
+		}
+		visited[ns] = true
+		for _, dep := range byNS[ns].deps {
+			if affected[dep.Name()] {
+				visit(dep.Name())
+			}
+		}
+		order = append(order, ns)
+	}
+	names := make([]string, 0, len(affected))
+	for ns := range affected {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	for _, ns := range names {
+		visit(ns)
+	}
+
+	unload := make([]interface{}, 0, len(order))
+	load := make([]interface{}, 0, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		unload = append(unload, value.NewSymbol(order[i]))
+	}
+	for _, ns := range order {
+		if !removed[ns] {
+			load = append(load, value.NewSymbol(ns))
+		}
+		byNS[ns].pending = true
+	}
+	return value.NewMap(
+		value.NewKeyword("unload"), value.NewVector(unload...),
+		value.NewKeyword("load"), value.NewVector(load...),
+	)
+}
+
+// nsDecl returns the name of the namespace declared by the first ns
+// form of the file name, of source src, and the libs its :require and
+// :use clauses name, or nil if it has none.
+func nsDecl(name string, src []byte) (*value.Symbol, []*value.Symbol) {
+	r := reader.New(bytes.NewReader(src), reader.WithFilename(name))
+	for {
+		form, err := r.ReadOne()
+		if err != nil {
+			return nil, nil
+		}
+		seq, ok := form.(value.ISeq)
+		if !ok || !value.Equals(seq.First(), value.NewSymbol("ns")) {
+			continue
+		}
+		seq = seq.Next()
+		if seq == nil {
+			return nil, nil
+		}
+		ns, ok := seq.First().(*value.Symbol)
+		if !ok {
+			return nil, nil
+		}
+		var deps []*value.Symbol
+		for clauses := seq.Next(); clauses != nil; clauses = clauses.Next() {
+			clause, ok := clauses.First().(value.ISeq)
+			if !ok {
+				continue
+			}
+			kw, _ := clause.First().(value.Keyword)
+			if kw != value.NewKeyword("require") && kw != value.NewKeyword("use") {
+				continue
+			}
+			for specs := clause.Next(); specs != nil; specs = specs.Next() {
+				deps = appendLibs(deps, "", specs.First())
+			}
+		}
+		return ns, deps
+	}
+}
+
+// appendLibs appends the libs named by spec, a lib name, a libspec or
+// a prefix list, to libs. prefix is the prefix of the list spec is in.
+func appendLibs(libs []*value.Symbol, prefix string, spec interface{}) []*value.Symbol {
+	lib := func(sym *value.Symbol) *value.Symbol {
+		if prefix != "" {
+			return value.NewSymbol(prefix + "." + sym.Name())
+		}
+		return sym
+	}
+	switch spec := spec.(type) {
+	case *value.Symbol:
+		return append(libs, lib(spec))
+	case value.IPersistentVector, value.ISeq:
+		seq := value.Seq(spec)
+		if seq == nil {
+			return libs
+		}
+		first, ok := seq.First().(*value.Symbol)
+		if !ok {
+			return libs
+		}
+		rest := seq.Next()
+		if _, isVector := spec.(value.IPersistentVector); isVector && prefix == "" {
+			// a libspec is a vector of a lib and options.
+			if rest == nil {
+				return append(libs, lib(first))
+			}
+			if _, ok := rest.First().(value.Keyword); ok {
+				return append(libs, lib(first))
+			}
+		}
+		if prefix != "" {
+			return append(libs, lib(first))
+		}
+		for ; rest != nil; rest = rest.Next() {
+			libs = appendLibs(libs, first.Name(), rest.First())
+		}
+	}
+	return libs
+}
@@ -0,0 +1,61 @@
+package runtime_test
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+func TestRefreshPlan(t *testing.T) {
+	files := fstest.MapFS{
+		"reload/test/a.glj": &fstest.MapFile{Data: []byte("(ns reload.test.a (:require [reload.test.b :as b]))\n(defn f [] (b/g))\n")},
+		"reload/test/b.glj": &fstest.MapFile{Data: []byte("(ns reload.test.b)\n(defn g [] 1)\n")},
+		"reload/test/c.glj": &fstest.MapFile{Data: []byte("(ns reload.test.c (:require (reload.test [a :as a] d)))\n")},
+		"reload/test/d.glj": &fstest.MapFile{Data: []byte("(ns reload.test.d)\n")},
+	}
+	runtime.AddLoadPath(files)
+
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	env := runtime.NewEnvironment()
+	runtime.ReadEval(`(require 'reload.test.c)`, runtime.WithEnv(env))
+	plan := func() string {
+		return value.PrintString(runtime.RefreshPlan())
+	}
+	if p := plan(); p != "{:unload [], :load []}" {
+		t.Fatalf("expected an empty plan, got %s", p)
+	}
+
+	// the namespaces that require a changed one, directly or not, are
+	// reloaded after it.
+	files["reload/test/b.glj"].Data = []byte("(ns reload.test.b)\n(defn g [] 2)\n")
+	if changed := fmt.Sprint(runtime.ChangedNamespaces()); changed != "[reload.test.b]" {
+		t.Errorf("expected reload.test.b to have changed, got %s", changed)
+	}
+	expected := "{:unload [reload.test.c reload.test.a reload.test.b], :load [reload.test.b reload.test.a reload.test.c]}"
+	if p := plan(); p != expected {
+		t.Errorf("expected %s, got %s", expected, p)
+	}
+	// until they are loaded again, they are planned again, though they
+	// haven't changed since.
+	if changed := runtime.ChangedNamespaces(); len(changed) != 0 {
+		t.Errorf("expected no changes since the plan, got %v", changed)
+	}
+	if p := plan(); p != expected {
+		t.Errorf("expected %s again, got %s", expected, p)
+	}
+	runtime.ReadEval(`(require 'reload.test.c :reload-all)`, runtime.WithEnv(env))
+	if p := plan(); p != "{:unload [], :load []}" {
+		t.Errorf("expected an empty plan once reloaded, got %s", p)
+	}
+
+	// removed files are unloaded.
+	delete(files, "reload/test/d.glj")
+	if p := plan(); p != "{:unload [reload.test.c reload.test.d], :load [reload.test.c]}" {
+		t.Errorf("expected reload.test.d to be unloaded, got %s", p)
+	}
+}
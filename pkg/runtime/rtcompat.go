@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"sync"
@@ -132,23 +134,50 @@ func (rt *RTMethods) Load(scriptBase string) {
 	defer PopThreadBindings()
 
 	filename := scriptBase + ".glj"
+	if err := GlobalEnv.(*environment).loadFile(filename); err != nil {
+		panic(err)
+	}
+}
 
-	var buf []byte
-	var err error
+// LoadReader evaluates the forms of the source file filename, read
+// from r, as load-file does. *ns* and *file* are bound while it is
+// evaluated, so a change of namespace doesn't outlast it.
+func LoadReader(env value.Environment, r io.Reader, filename string) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	PushThreadBindings(NewMap(VarCurrentNS, VarCurrentNS.Deref(), VarFile, filename))
+	defer PopThreadBindings()
 
+	return env.(*environment).evalFile(filename, src)
+}
+
+// readLoadPath reads the named file from the first filesystem of the
+// load path that has it.
+func readLoadPath(filename string) ([]byte, error) {
 	loadPathLock.Lock()
 	lp := loadPath
 	loadPathLock.Unlock()
+
+	var buf []byte
+	var err error
 	for _, fs := range lp {
 		buf, err = readFile(fs, filename)
 		if err == nil {
 			break
 		}
 	}
-	if err != nil {
-		panic(err)
+	return buf, err
+}
+
+// ReadSourceFile reads the source file named by the :file metadata of
+// a var: an absolute path, or a path on the load path.
+func ReadSourceFile(filename string) ([]byte, error) {
+	if filepath.IsAbs(filename) {
+		return os.ReadFile(filename)
 	}
-	ReadEval(string(buf), WithFilename(filename))
+	return readLoadPath(filename)
 }
 
 func readFile(fs fs.FS, filename string) ([]byte, error) {
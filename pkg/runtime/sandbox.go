@@ -0,0 +1,175 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/pkgmap"
+)
+
+// Sandbox restricts what code evaluated in an environment can do, so
+// that partially trusted code can be evaluated. Code compiled in a
+// sandboxed environment can't refer to Go packages or exports the
+// sandbox doesn't allow, nor set the fields of Go values with set!,
+// and *read-eval* is false.
+//
+// Code loaded before the sandbox is set, such as glojure.core, is not
+// restricted. Require the libraries that sandboxed code needs before
+// setting the sandbox, since loading them later is subject to it.
+type Sandbox struct {
+	// Allow, if not empty, lists the Go packages, by import path, and
+	// exports, as "path.Name", that code can refer to. Code can't
+	// refer to others, except for those of the glojure runtime and
+	// pkg/lang that the macros of the standard library expand to.
+	// Whether or not Allow is set, code can't refer to the other
+	// exports of the runtime and pkg/lang.
+	Allow []string
+	// Deny lists Go packages and exports, as for Allow, that code
+	// can't refer to.
+	Deny []string
+
+	// MaxSteps, if positive, limits the number of steps the
+	// evaluation of each top-level form can take, where a step is a
+	// fn invocation, a loop iteration or the realization of a lazy
+	// seq. Evaluation that exceeds it is canceled.
+	MaxSteps int64
+	// MaxCollectionSize, if positive, limits the number of elements of
+	// collections, as lang.SetMaxCollectionSize does.
+	MaxCollectionSize int
+	// MaxGoroutines limits the number of goroutines started with go/go
+	// and future that can run at once, as lang.SetMaxGoroutines does.
+	// If it is negative, none can be started.
+	MaxGoroutines int
+}
+
+const (
+	langPkg    = "github.com/glojurelang/glojure/pkg/lang"
+	runtimePkg = "github.com/glojurelang/glojure/pkg/runtime"
+	pkgmapPkg  = "github.com/glojurelang/glojure/pkg/pkgmap"
+)
+
+// sandboxExports are the exports of the glojure runtime and of
+// pkg/lang that the macros of the standard library expand to. Code can
+// refer to them unless they are denied; it can't refer to the other
+// exports of those packages, which can change the state of the
+// process, as the limits of SetSandbox.
+var sandboxExports = map[string]bool{
+	runtimePkg + ".RT":           true,
+	runtimePkg + ".Compiler":     true,
+	runtimePkg + ".RTReadString": true,
+	runtimePkg + ".Fn":           true,
+
+	langPkg + ".MultiFn":                          true,
+	langPkg + ".Var":                              true,
+	langPkg + ".Abs":                              true,
+	langPkg + ".FindNamespace":                    true,
+	langPkg + ".ISeq":                             true,
+	langPkg + ".Identical":                        true,
+	langPkg + ".Import":                           true,
+	langPkg + ".IsReduced":                        true,
+	langPkg + ".LockingTransaction":               true,
+	langPkg + ".LongCast":                         true,
+	langPkg + ".NewDelay":                         true,
+	langPkg + ".NewIllegalArgumentError":          true,
+	langPkg + ".NewLazySeq":                       true,
+	langPkg + ".NewMultiFn":                       true,
+	langPkg + ".NewPersistentArrayMapAsIfByAssoc": true,
+	langPkg + ".NewProtocol":                      true,
+	langPkg + ".Numbers":                          true,
+	langPkg + ".PopThreadBindings":                true,
+	langPkg + ".PushThreadBindings":               true,
+	langPkg + ".Throwable":                        true,
+	langPkg + ".Volatile":                         true,
+}
+
+// WithSandbox sets the sandbox of the environment once the core
+// library has been loaded. See SetSandbox.
+func WithSandbox(sb *Sandbox) EvalOption {
+	return func(opts *evalOptions) {
+		opts.sandbox = sb
+	}
+}
+
+// SetSandbox restricts the code evaluated in env from now on to what
+// sb allows, or lifts the restrictions if sb is nil. Its limits on
+// collections and goroutines apply to the whole process.
+func SetSandbox(env value.Environment, sb *Sandbox) {
+	e := env.(*environment)
+	e.sandbox.Store(sb)
+
+	readEval := value.NSCore.FindInternedVar(value.NewSymbol("*read-eval*"))
+	if sb == nil {
+		value.SetMaxCollectionSize(0)
+		value.SetMaxGoroutines(0)
+		readEval.BindRoot(true)
+		return
+	}
+	value.SetMaxCollectionSize(sb.MaxCollectionSize)
+	value.SetMaxGoroutines(sb.MaxGoroutines)
+	readEval.BindRoot(false)
+}
+
+// allows reports whether the sandbox allows code to refer to the
+// export name of the package at import path pkg.
+func (sb *Sandbox) allows(pkg, name string) bool {
+	export := pkg + "." + name
+	matches := func(entries []string) bool {
+		for _, entry := range entries {
+			if entry == pkg || entry == export {
+				return true
+			}
+		}
+		return false
+	}
+	if matches(sb.Deny) {
+		return false
+	}
+	if sandboxExports[export] {
+		return true
+	}
+	// the rest of the runtime and the package map give access to any
+	// package, and the rest of pkg/lang to the state of the process.
+	if pkg == runtimePkg || pkg == pkgmapPkg || pkg == langPkg {
+		return false
+	}
+	if len(sb.Allow) == 0 {
+		return true
+	}
+	return matches(sb.Allow)
+}
+
+// CheckExport returns an *lang.IllegalAccessError if the sandbox of
+// the environment doesn't allow code to refer to export, a Go package
+// export named as "pkg.Name".
+func (env *environment) CheckExport(export string) error {
+	sb := env.sandbox.Load()
+	if sb == nil {
+		return nil
+	}
+	pkg, name := pkgmap.SplitExport(export)
+	// pointer types are named as pkg.*Name.
+	name = strings.TrimPrefix(name, "*")
+	if !sb.allows(pkgmap.UnmungePkg(pkg), name) {
+		return value.NewIllegalAccessError(fmt.Sprintf("%s is not allowed in this sandbox", export))
+	}
+	return nil
+}
+
+// withStepLimit calls f with *context* bound to a context limited to
+// the steps allowed by the sandbox, unless no limit is set or one is
+// already bound by an enclosing evaluation.
+func (env *environment) withStepLimit(f func() (interface{}, error)) (interface{}, error) {
+	sb := env.sandbox.Load()
+	if sb == nil || sb.MaxSteps <= 0 || value.HasStepLimit() {
+		return f()
+	}
+	ctx, ok := value.VarContext.Deref().(context.Context)
+	if !ok {
+		ctx = context.Background()
+	}
+	value.PushContext(value.WithStepLimit(ctx, sb.MaxSteps))
+	defer value.PopContext()
+	return f()
+}
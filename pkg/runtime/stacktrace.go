@@ -0,0 +1,356 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/glojurelang/glojure/pkg/ast"
+	value "github.com/glojurelang/glojure/pkg/lang"
+)
+
+// EvalError is an error raised while evaluating a form, with the
+// Glojure stack trace of its evaluation.
+type EvalError struct {
+	Err error
+	// GLJStack holds the frames of the forms being evaluated when Err
+	// was raised, most recent first.
+	GLJStack []value.StackFrame
+
+	// goPCs holds the program counters of the Go stack of the
+	// goroutine that raised Err, if it was raised by a panic.
+	goPCs []uintptr
+}
+
+var _ value.Stacker = (*EvalError)(nil)
+
+// Error returns the message of the error, without the stack traces
+// of the errors it wraps, followed by its stack trace. Go frames are
+// included if *print-go-stack* is true.
+func (e *EvalError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(ErrorMessage(e.Err))
+	sb.WriteString("\nStack trace (most recent call first):\n")
+	for _, line := range e.Trace(value.IsTruthy(value.VarPrintGoStack.Deref())) {
+		sb.WriteString("\t")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
+func (e *EvalError) Is(err error) bool {
+	_, ok := err.(*EvalError)
+	return ok
+}
+
+// Stack returns the frames of the stack trace of the error, most
+// recent first, including those elided from Trace.
+func (e *EvalError) Stack() []value.StackFrame {
+	return e.GLJStack
+}
+
+// Trace returns the lines of the stack trace of the error, most
+// recent first. Frames of forms generated by macros, which have no
+// source position, are elided. If goFrames is set and the error was
+// raised by a panic, the frames of the Go functions called by the
+// failing forms are interleaved with those of the forms.
+func (e *EvalError) Trace(goFrames bool) []string {
+	var lines []string
+	i := 0
+	addFrame := func() {
+		if f := e.GLJStack[i]; f.Line > 0 {
+			lines = append(lines, f.String())
+		}
+		i++
+	}
+	if goFrames && len(e.goPCs) > 0 {
+		frames := goruntime.CallersFrames(e.goPCs)
+		panicked := false
+		for more := true; more; {
+			var f goruntime.Frame
+			f, more = frames.Next()
+			switch {
+			case !panicked:
+				// skip the frames of the deferred call that recovered
+				// the panic.
+				panicked = f.Function == "runtime.gopanic"
+			case recordsFrame(f.Function):
+				if i < len(e.GLJStack) {
+					addFrame()
+				}
+			case !internalFrame(f.Function):
+				lines = append(lines, fmt.Sprintf("[go] %s (%s:%d)", f.Function, f.File, f.Line))
+			}
+		}
+	}
+	for i < len(e.GLJStack) {
+		addFrame()
+	}
+	return lines
+}
+
+const (
+	runtimeFuncPrefix = runtimePkg + "."
+	langFuncPrefix    = langPkg + "."
+)
+
+// recordsFrame reports whether the Go function named fn adds a frame
+// to the stack of the errors raised by the forms it evaluates.
+func recordsFrame(fn string) bool {
+	if !strings.HasPrefix(fn, runtimeFuncPrefix) {
+		return false
+	}
+	name := fn[len(runtimeFuncPrefix):]
+	for _, prefix := range []string{
+		"(*codeCompiler).compileInvoke.func",
+		"(*codeCompiler).compileHostCall.func",
+		"(*codeCompiler).compileHostInterop.func",
+	} {
+		// the closure of the form, not those defined in it.
+		if strings.HasPrefix(name, prefix) {
+			return !strings.Contains(name[len(prefix):], ".")
+		}
+	}
+	switch name {
+	case "(*CallSite).Invoke", "(*CallSite).HostCall", "(*CallSite).HostInterop":
+		return true
+	}
+	return false
+}
+
+// internalFrame reports whether the Go function named fn is part of
+// the Go or Glojure runtime, rather than Go code called by Glojure
+// code.
+func internalFrame(fn string) bool {
+	for _, prefix := range []string{"runtime.", "reflect.", runtimeFuncPrefix, langFuncPrefix} {
+		if strings.HasPrefix(fn, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// withFrame returns err with frame added to its stack, converting it
+// to an *EvalError if it isn't one. An *EvalError is copied, since it
+// may be held elsewhere, as by code that caught it. Other errors are
+// wrapped whole, keeping the messages of those between them and the
+// *EvalError they wrap, if any, whose stack is carried over.
+func withFrame(frame value.StackFrame, err error) error {
+	evalErr, ok := err.(*EvalError)
+	if !ok && !errors.As(err, &evalErr) {
+		return &EvalError{
+			Err:      err,
+			GLJStack: []value.StackFrame{frame},
+		}
+	}
+	stack := make([]value.StackFrame, len(evalErr.GLJStack), len(evalErr.GLJStack)+1)
+	copy(stack, evalErr.GLJStack)
+	if ok {
+		err = evalErr.Err
+	}
+	return &EvalError{
+		Err:      err,
+		GLJStack: append(stack, frame),
+		goPCs:    evalErr.goPCs,
+	}
+}
+
+// invokeError converts a value recovered from a panic raised by the
+// evaluation of a form to an EvalError, adding frame to its stack. It
+// must be called by the deferred function that recovered the panic.
+func invokeError(frame value.StackFrame, r interface{}) error {
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%v", r)
+	}
+	if errors.Is(err, &EvalError{}) {
+		return withFrame(frame, err)
+	}
+	pcs := make([]uintptr, 256)
+	n := goruntime.Callers(2, pcs)
+	return &EvalError{
+		Err:      err,
+		GLJStack: []value.StackFrame{frame},
+		goPCs:    pcs[:n],
+	}
+}
+
+// StackTrace returns the frames of the Glojure stack trace of err,
+// most recent first, or nil if it has none.
+func StackTrace(err error) []value.StackFrame {
+	var stacker value.Stacker
+	if !errors.As(err, &stacker) {
+		return nil
+	}
+	return stacker.Stack()
+}
+
+// ErrorMessage returns the message of err without the stack traces of
+// the *EvalErrors it wraps. The message of each error that wraps one
+// is taken to end with the message of the error it wraps, as those
+// formatted with %w do, which is replaced by its own message.
+func ErrorMessage(err error) string {
+	if evalErr, ok := err.(*EvalError); ok {
+		return ErrorMessage(evalErr.Err)
+	}
+	wrapped := errors.Unwrap(err)
+	if wrapped == nil || !errors.Is(wrapped, &EvalError{}) {
+		return err.Error()
+	}
+	msg, wrappedMsg := err.Error(), wrapped.Error()
+	if !strings.HasSuffix(msg, wrappedMsg) {
+		return msg
+	}
+	return msg[:len(msg)-len(wrappedMsg)] + ErrorMessage(wrapped)
+}
+
+// PrintError writes the message of err and the position of the form
+// that raised it, outside of glojure.core if possible, to w.
+// PrintStackTrace prints the whole stack trace.
+func PrintError(w io.Writer, err error) {
+	var at *value.StackFrame
+	stack := StackTrace(err)
+	for i := range stack {
+		if stack[i].Line == 0 {
+			continue
+		}
+		if at == nil || at.Namespace == "glojure.core" {
+			at = &stack[i]
+		}
+		if stack[i].Namespace != "glojure.core" {
+			break
+		}
+	}
+	if at != nil {
+		fmt.Fprintf(w, "Execution error at %s:\n", *at)
+	}
+	fmt.Fprintln(w, ErrorMessage(err))
+}
+
+// PrintStackTrace writes the message of err and up to n lines of its
+// stack trace to w, or all of them if n is not positive. Go frames
+// are included if *print-go-stack* is true.
+func PrintStackTrace(w io.Writer, err error, n int) {
+	fmt.Fprintln(w, ErrorMessage(err))
+	var evalErr *EvalError
+	if !errors.As(err, &evalErr) {
+		return
+	}
+	lines := evalErr.Trace(value.IsTruthy(value.VarPrintGoStack.Deref()))
+	if n > 0 && n < len(lines) {
+		lines = lines[:n]
+	}
+	for _, line := range lines {
+		fmt.Fprintf(w, " at %s\n", line)
+	}
+}
+
+// CallFrame returns the frame of the invoke, host call or host
+// interop node n, in code whose frames are those of fn: the frame of
+// the body of a fn method returned by FnFrame, or that of top-level
+// forms in a namespace.
+func CallFrame(fn value.StackFrame, n *ast.Node) value.StackFrame {
+	meta := formMeta(n.Form)
+	frame := fn
+	frame.Filename, _ = value.Get(meta, value.KWFile).(string)
+	frame.Line, _ = value.Get(meta, value.KWLine).(int)
+	frame.Column, _ = value.Get(meta, value.KWColumn).(int)
+	return frame
+}
+
+// FnFrame returns the frame of the forms in the body of method, a
+// method of the fn node fn that is defined in code whose frames are
+// those of outer. def is the name of the var that fn is the value of,
+// or nil.
+//
+// A fn is named by its var or its local name, qualified by the name
+// of the fn it is defined in. Fns generated by macros, whose forms
+// have no source position, have the frames of the code they are
+// defined in, so that the frames of code passed to a macro such as
+// for or lazy-seq are reported as those of the code that uses it.
+func FnFrame(outer value.StackFrame, fn, method *ast.Node, def *value.Symbol) value.StackFrame {
+	name := "fn"
+	if local := fn.Sub.(*ast.FnNode).Local; local != nil {
+		name = local.Sub.(*ast.BindingNode).Name.Name()
+	}
+	switch {
+	case def != nil:
+		name = def.Name()
+	case value.Get(formMeta(fn.Form), value.KWLine) == nil:
+		return outer
+	case outer.FunctionName != "":
+		name = outer.FunctionName + "/" + name
+	}
+	methodNode := method.Sub.(*ast.FnMethodNode)
+	return value.StackFrame{
+		Namespace:    outer.Namespace,
+		FunctionName: name,
+		Arity:        methodNode.FixedArity,
+		Variadic:     methodNode.IsVariadic,
+	}
+}
+
+// DefFn returns the fn node that is the value of the def node n, or
+// nil if its init is not a fn form.
+func DefFn(n *ast.Node) *ast.Node {
+	init := n.Sub.(*ast.DefNode).Init
+	for init != nil && init.Op == ast.OpWithMeta {
+		init = init.Sub.(*ast.WithMetaNode).Expr
+	}
+	if init == nil || init.Op != ast.OpFn {
+		return nil
+	}
+	return init
+}
+
+// InNamespace returns the name of the namespace that the top-level
+// node n switches to, if it is an in-ns or ns form, or nil.
+func InNamespace(n *ast.Node) *value.Symbol {
+	if n.Op == ast.OpDo {
+		doNode := n.Sub.(*ast.DoNode)
+		if len(doNode.Statements) == 0 {
+			return nil
+		}
+		n = doNode.Statements[0]
+	}
+	if n.Op != ast.OpInvoke {
+		return nil
+	}
+	invokeNode := n.Sub.(*ast.InvokeNode)
+	if invokeNode.Fn.Op != ast.OpVar || invokeNode.Fn.Sub.(*ast.VarNode).Var != value.VarInNS || len(invokeNode.Args) != 1 {
+		return nil
+	}
+	arg := invokeNode.Args[0]
+	for arg.Op == ast.OpQuote {
+		arg = arg.Sub.(*ast.QuoteNode).Expr
+	}
+	if arg.Op != ast.OpConst {
+		return nil
+	}
+	sym, _ := arg.Sub.(*ast.ConstNode).Value.(*value.Symbol)
+	return sym
+}
+
+// formMeta returns the metadata of form, or nil.
+func formMeta(form interface{}) value.IPersistentMap {
+	if m, ok := form.(value.IMeta); ok {
+		return m.Meta()
+	}
+	return nil
+}
+
+// sourceForm returns the form n was analyzed from as it was read: the
+// outermost macro form it expands, if any.
+func sourceForm(n *ast.Node) interface{} {
+	if len(n.RawForms) > 0 {
+		return n.RawForms[len(n.RawForms)-1]
+	}
+	return n.Form
+}
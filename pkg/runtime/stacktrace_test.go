@@ -0,0 +1,180 @@
+package runtime_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	value "github.com/glojurelang/glojure/pkg/lang"
+	"github.com/glojurelang/glojure/pkg/runtime"
+)
+
+func TestStackTrace(t *testing.T) {
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	env := runtime.NewEnvironment()
+	runtime.ReadEval(`(ns stack.test)
+(defn inner [x]
+  (+ x nil))
+(defn outer
+  ([] (outer 1))
+  ([x & more] (let [f (fn helper [y] (inner y))] (f x))))
+(defn lazy [xs]
+  (doall (for [x xs] (inner x))))
+(defn host [r]
+  (.Len r))
+`, runtime.WithEnv(env), runtime.WithFilename("stack/test.glj"))
+
+	tests := []struct {
+		expr  string
+		trace []string
+	}{
+		{
+			expr: `(stack.test/outer)`,
+			trace: []string{
+				"glojure.core/+ [2]",
+				"stack.test/inner [1] (stack/test.glj:3:3)",
+				"stack.test/outer/helper [1] (stack/test.glj:6:38)",
+				"stack.test/outer [1+] (stack/test.glj:6:50)",
+				"stack.test/outer [0] (stack/test.glj:5:7)",
+			},
+		},
+		{
+			// the body of the for is reported as part of lazy, rather
+			// than of the fns generated by the macro.
+			expr: `(stack.test/lazy [1])`,
+			trace: []string{
+				"glojure.core/+ [2]",
+				"stack.test/inner [1] (stack/test.glj:3:3)",
+				"stack.test/lazy [1] (stack/test.glj:8:22)",
+			},
+		},
+		{
+			expr: `(stack.test/host nil)`,
+			trace: []string{
+				"stack.test/host [1] (stack/test.glj:10:3)",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			form := runtime.ReadEval("(quote "+test.expr+")", runtime.WithEnv(env))
+			_, err := env.Eval(form)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			stack := runtime.StackTrace(err)
+			var trace []string
+			for _, frame := range stack {
+				if frame.Line == 0 && frame.Namespace != "glojure.core" {
+					continue
+				}
+				trace = append(trace, frame.String())
+			}
+			if len(trace) < len(test.trace) {
+				t.Fatalf("expected at least %d frames, got:\n%s", len(test.trace), strings.Join(trace, "\n"))
+			}
+			for i, want := range test.trace {
+				if !strings.HasPrefix(trace[i], want) {
+					t.Errorf("frame %d: expected %q, got %q", i, want, trace[i])
+				}
+			}
+
+			var buf bytes.Buffer
+			runtime.PrintStackTrace(&buf, err, 0)
+			if strings.Contains(buf.String(), ":?:?)") {
+				t.Errorf("expected frames without a position to be elided:\n%s", buf.String())
+			}
+			if last := test.trace[len(test.trace)-1]; !strings.Contains(buf.String(), " at "+last) {
+				t.Errorf("expected printed trace to contain %q:\n%s", last, buf.String())
+			}
+		})
+	}
+}
+
+func TestRethrownStackTrace(t *testing.T) {
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	env := runtime.NewEnvironment()
+	runtime.ReadEval(`(ns stack.rethrow)
+(defn fail [] (throw (errors.New "boom")))
+(def caught (try (fail) (catch go/any e e)))
+(defn rethrow [] (throw caught))
+`, runtime.WithEnv(env), runtime.WithFilename("stack/rethrow.glj"))
+
+	caught := runtime.ReadEval(`stack.rethrow/caught`, runtime.WithEnv(env)).(error)
+	before := len(runtime.StackTrace(caught))
+	form := runtime.ReadEval(`(quote (stack.rethrow/rethrow))`, runtime.WithEnv(env))
+	for i := 0; i < 2; i++ {
+		_, err := env.Eval(form)
+		if n := len(runtime.StackTrace(err)); n <= before {
+			t.Errorf("expected the rethrown error to have more than %d frames, got %d", before, n)
+		}
+	}
+	// the caught error is left as it was.
+	if n := len(runtime.StackTrace(caught)); n != before {
+		t.Errorf("expected the caught error to keep its %d frames, got %d", before, n)
+	}
+}
+
+func TestRequireStackTrace(t *testing.T) {
+	runtime.AddLoadPath(fstest.MapFS{
+		"stack/required.glj": &fstest.MapFile{Data: []byte("(ns stack.required)\n(+ 1 nil)\n")},
+	})
+
+	pushBindings()
+	defer value.PopThreadBindings()
+
+	env := runtime.NewEnvironment()
+	runtime.ReadEval(`(ns stack.require)
+(defn f [] (require 'stack.required))
+`, runtime.WithEnv(env), runtime.WithFilename("stack/require.glj"))
+
+	form := runtime.ReadEval(`(quote (stack.require/f))`, runtime.WithEnv(env))
+	_, err := env.Eval(form)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	// the error names the file being loaded, and its trace includes
+	// the frames of both the loaded file and the fn that required it.
+	if msg := runtime.ErrorMessage(err); !strings.Contains(msg, "error evaluating stack/required.glj: ") {
+		t.Errorf("expected the message to name stack/required.glj, got %q", msg)
+	}
+	var trace []string
+	for _, frame := range runtime.StackTrace(err) {
+		trace = append(trace, frame.String())
+	}
+	joined := strings.Join(trace, "\n")
+	for _, want := range []string{"(stack/required.glj:2:1)", "stack.require/f [0] (stack/require.glj:2:12)"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected a frame %q, got:\n%s", want, joined)
+		}
+	}
+	if n := strings.Count(err.Error(), "Stack trace"); n != 1 {
+		t.Errorf("expected one stack trace in the error, got %d:\n%s", n, err)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	stack := []value.StackFrame{{Namespace: "user", FunctionName: "f", Filename: "f.glj", Line: 1, Column: 1}}
+	inner := &runtime.EvalError{Err: errors.New("boom"), GLJStack: stack}
+	outer := &runtime.EvalError{Err: fmt.Errorf("calling g: %w", inner), GLJStack: stack}
+	for _, test := range []struct {
+		err  error
+		want string
+	}{
+		{errors.New("boom"), "boom"},
+		{inner, "boom"},
+		{fmt.Errorf("loading f.glj: %w", inner), "loading f.glj: boom"},
+		{fmt.Errorf("loading f.glj: %w", outer), "loading f.glj: calling g: boom"},
+	} {
+		if got := runtime.ErrorMessage(test.err); got != test.want {
+			t.Errorf("ErrorMessage(%q) = %q, want %q", test.err, got, test.want)
+		}
+	}
+}
@@ -5,5 +5,13 @@ import (
 	"embed"
 )
 
+//go:generate go run ../../cmd/gen-image -o core.img
+
 //go:embed glojure
 var StdLib embed.FS
+
+// CoreImage is the image of the analyzed core library. See
+// runtime.CoreImage.
+//
+//go:embed core.img
+var CoreImage []byte
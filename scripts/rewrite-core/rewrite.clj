@@ -288,13 +288,45 @@
                 (= 'def (first (z/sexpr %)))
                 (= 'default-data-readers (second (z/sexpr %)))))
 
-   ;; omit tap functions
-   (omitp #(and (z/list? %)
-                (= 'defonce (first (z/sexpr %)))
-                (= 'tap-loop (second (z/sexpr %)))))
-   (omitp #(and (z/list? %)
-                (= 'defonce (first (z/sexpr %)))
-                (= 'tapq (second (z/sexpr %)))))
+   (node-replace "(list 'new 'clojure.lang.Delay (list* `^{:once true} fn* [] body))"
+                 "(list 'github.com$glojurelang$glojure$pkg$lang.NewDelay (list* `^{:once true} fn* [] body))")
+   (sexpr-replace '(instance? clojure.lang.Delay x)
+                  '(instance? github.com$glojurelang$glojure$pkg$lang.*Delay x))
+   (sexpr-replace '(. clojure.lang.Delay (force x))
+                  '(github.com$glojurelang$glojure$pkg$lang.ForceDelay x))
+
+   (sexpr-replace '(. clojure.lang.Util (compare x y))
+                  '(github.com$glojurelang$glojure$pkg$lang.Compare x y))
+   (sexpr-replace '(clojure.lang.Namespace/all)
+                  '(seq (github.com$glojurelang$glojure$pkg$lang.Namespaces)))
+   (sexpr-replace '(.getAliases (the-ns ns)) '(.Aliases (the-ns ns)))
+   (node-replace "(. java.util.Arrays (sort a comp))"
+                 "(github.com$glojurelang$glojure$pkg$lang.SortSlice a comp)")
+   (node-replace "(. comp (compare (keyfn x) (keyfn y)))"
+                 "(comp (keyfn x) (keyfn y))")
+
+   ;; taps are sent on a Go channel, read by a goroutine
+   [(fn select [zloc] (and (z/list? zloc)
+                           (= 'defonce (first (z/sexpr zloc)))
+                           (= 'tapq (second (z/sexpr zloc)))))
+    (fn visit [zloc] (z/replace zloc (p/parse-string "(defonce ^:private tapq (go/make (go/chan-of go/any) 1024))")))]
+   [(fn select [zloc] (and (z/list? zloc)
+                           (= 'defonce (first (z/sexpr zloc)))
+                           (= 'tap-loop (second (z/sexpr zloc)))))
+    (fn visit [zloc] (z/replace zloc (p/parse-string "(defonce ^:private tap-loop
+  (delay
+   (go/go
+    ((fn []
+       (let [[t _] (go/recv tapq)
+             x (if (identical? ::tap-nil t) nil t)
+             taps @tapset]
+         (doseq [tap taps]
+           (try
+             (tap x)
+             (catch github.com$glojurelang$glojure$pkg$lang.Throwable ex)))
+         (recur)))))))")))]
+   (node-replace "(.offer tapq (if (nil? x) ::tap-nil x))"
+                 "(.TrySend (reflect.ValueOf tapq) (reflect.ValueOf (if (nil? x) ::tap-nil x)))")
 
    [(fn select [zloc] (and (z/list? zloc)
                            (= 'defn- (first (z/sexpr zloc)))
@@ -352,9 +384,22 @@
                   'github.com$glojurelang$glojure$pkg$lang.NewBigIntFromGoBigInt)
 
    (sexpr-replace '.equals '.Equals)
+   (sexpr-replace '.getThreadBinding '.IsThreadBound)
 
    (sexpr-replace '(clojure.lang.RT/load (.substring path 1))
                   '(. github.com$glojurelang$glojure$pkg$runtime.RT (Load (strings.TrimPrefix path "/"))))
+   ;; load errors are plain errors, as there is no CompilerException
+   [(fn select [zloc] (and (z/list? zloc)
+                           (= 'defn- (first (z/sexpr zloc)))
+                           (= 'throw-if (second (z/sexpr zloc)))))
+    (fn visit [zloc] (z/replace zloc (p/parse-string "(defn- throw-if
+  \"Throws an error with a message if pred is true\"
+  [pred fmt & args]
+  (when pred
+    (throw (errors.New (apply format fmt args)))))")))]
+   ;; a lib whose namespace was removed, as by refresh, is loaded again
+   (node-replace "(contains? @*loaded-libs* lib)"
+                 "(and (contains? @*loaded-libs* lib) (find-ns lib))")
    (sexpr-replace '(. s (substring start)) '(go/slice s start))
    (sexpr-replace '(. s (substring start end)) '(go/slice s start end))
 
@@ -714,6 +759,9 @@
    (sexpr-replace '(. clojure.lang.Var (popThreadBindings)) '(github.com$glojurelang$glojure$pkg$lang.PopThreadBindings))
    (sexpr-replace 'clojure.lang.Var/popThreadBindings 'github.com$glojurelang$glojure$pkg$lang.PopThreadBindings)
    (sexpr-replace 'clojure.lang.Var/pushThreadBindings 'github.com$glojurelang$glojure$pkg$lang.PushThreadBindings)
+   ;; pop bindings with a go call, which unlike a fn invocation isn't
+   ;; canceled by a done *context*.
+   (sexpr-replace '(pop-thread-bindings) '(github.com$glojurelang$glojure$pkg$lang.PopThreadBindings))
 
    ;; support pmap
    (sexpr-replace 'clojure.lang.Var/cloneThreadBindingFrame
@@ -797,7 +845,9 @@
    (sexpr-replace '(. *out* (append \space)) '(github.com$glojurelang$glojure$pkg$lang.AppendWriter *out* \space))
    (sexpr-replace '(. *out* (append system-newline))
                   '(github.com$glojurelang$glojure$pkg$lang.AppendWriter *out* system-newline))
-   (sexpr-replace '(. *out* (flush)) '(. *out* (Sync)))
+   (sexpr-replace '(. *out* (flush)) '(github.com$glojurelang$glojure$pkg$lang.FlushWriter *out*))
+   (sexpr-replace '(clojure.lang.Var/getThreadBindings)
+                  '(github.com$glojurelang$glojure$pkg$lang.GetThreadBindings))
 
    (omit-symbols '#{primitives-classnames})
 
@@ -858,12 +908,28 @@
    ;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;
    ;; test.clj
 
-   (sexpr-remove '[clojure.stacktrace :as stack])
+   (sexpr-replace '(instance? Throwable actual) '(instance? go/error actual))
+   (sexpr-replace '(stack/print-cause-trace actual *stack-trace-depth*)
+                  '(stack/print-stack-trace actual (or *stack-trace-depth* 0)))
 
+   ;; there are no stack traces to find the position of an assertion
+   ;; in, so is binds it for do-report.
    [(fn select [zloc] (and (z/list? zloc)
                            (= 'stacktrace-file-and-line
                               (first (z/sexpr zloc)))))
-    (fn visit [zloc] (z/replace zloc '{}))]
+    (fn visit [zloc] (z/replace zloc '*assertion-position*))]
+   [(fn select [zloc] (and (z/list? zloc)
+                           (= '(def *test-out* *out*) (z/sexpr zloc))))
+    (fn visit [zloc] (-> zloc
+                         (z/insert-left (p/parse-string "(def ^:dynamic *assertion-position* nil)"))
+                         (z/insert-newline-left)))]
+   (node-replace "([form] `(is ~form nil))"
+                 "([form] (with-meta `(is ~form nil) (meta &form)))")
+   (node-replace "([form msg] `(try-expr ~msg ~form))"
+                 "([form msg]
+   (let [{:keys [file line]} (meta &form)]
+     `(binding [*assertion-position* ~{:file (or file *file*) :line line}]
+        (try-expr ~msg ~form))))")
 
    ;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;
    ;; Regular Expressions
@@ -887,6 +953,11 @@
    (node-replace "(.pattern ^java.util.regex.Pattern p)"
                  "(.String ^regexp.*Regexp p)")
 
+   (node-replace "(if (instance? clojure.lang.LineNumberingPushbackReader *in*)
+    (.readLine ^clojure.lang.LineNumberingPushbackReader *in*)
+    (.readLine ^java.io.BufferedReader *in*))"
+                 "(github.com$glojurelang$glojure$pkg$lang.ReadLine *in*)")
+
    ])
 
 (defn rewrite-core [zloc]